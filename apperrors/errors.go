@@ -0,0 +1,73 @@
+// Package apperrors defines the error kinds shared by services and
+// controllers so a handler can map a failure to the right HTTP status.
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error kinds. Test for them with errors.Is; they survive fmt.Errorf %w wrapping.
+var (
+	// ErrValidation marks a request the caller has to fix
+	ErrValidation = errors.New("validation failed")
+	// ErrNotFound marks a missing position, order or other resource
+	ErrNotFound = errors.New("not found")
+	// ErrBroker marks a failure at the broker or market data provider
+	ErrBroker = errors.New("broker error")
+	// ErrRateLimited marks a request refused or throttled for rate limits
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// kindError tags an error with a kind while keeping its original message
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// Validation returns a formatted error of kind ErrValidation
+func Validation(format string, args ...any) error {
+	return &kindError{kind: ErrValidation, err: fmt.Errorf(format, args...)}
+}
+
+// NotFound returns a formatted error of kind ErrNotFound
+func NotFound(format string, args ...any) error {
+	return &kindError{kind: ErrNotFound, err: fmt.Errorf(format, args...)}
+}
+
+// Broker returns a formatted error of kind ErrBroker
+func Broker(format string, args ...any) error {
+	return &kindError{kind: ErrBroker, err: fmt.Errorf(format, args...)}
+}
+
+// RateLimited returns a formatted error of kind ErrRateLimited
+func RateLimited(format string, args ...any) error {
+	return &kindError{kind: ErrRateLimited, err: fmt.Errorf(format, args...)}
+}
+
+// HTTPStatus maps err to a response status, or fallback when it has no kind.
+// Upstream kinds win over validation, so "invalid request" wrapping a broker
+// failure still reports the broker.
+func HTTPStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrBroker):
+		return http.StatusBadGateway
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrValidation):
+		return http.StatusBadRequest
+	default:
+		return fallback
+	}
+}
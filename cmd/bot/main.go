@@ -24,6 +24,7 @@ func main() {
 	}
 
 	cfg := config.AppConfig
+	services.SetMoneyPrecision(cfg.MoneyDecimals, cfg.PriceDecimals)
 
 	// Initialize logger
 	logger := logrus.New()
@@ -68,6 +69,9 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to create storage service:", err)
 	}
+	if err := storageService.SetAccountID(cfg.AccountID); err != nil {
+		logger.WithError(err).Warn("Invalid ACCOUNT_ID, using the default account")
+	}
 
 	// Create order controller
 	orderController := controllers.NewOrderController(
@@ -76,15 +80,63 @@ func main() {
 		storageService,
 	)
 
+	if err := orderController.SetDefaultTimeframe(cfg.DefaultTimeframe); err != nil {
+		logger.WithError(err).Warn("Invalid DEFAULT_TIMEFRAME, using 1Day")
+	}
+
 	// Create news service and controller
 	newsService := services.NewNewsService()
+	if err := newsService.SetFetchConfig(services.NewsFetchConfig{
+		Concurrency: cfg.NewsFetchConcurrency,
+		FeedTimeout: time.Duration(cfg.NewsFeedTimeoutSeconds) * time.Second,
+	}); err != nil {
+		logger.WithError(err).Warn("Invalid news fetch config, using defaults")
+	}
+	if err := newsService.SetGoogleNewsThrottle(time.Duration(cfg.NewsGoogleIntervalMs) * time.Millisecond); err != nil {
+		logger.WithError(err).Warn("Invalid NEWS_GOOGLE_INTERVAL_MS, using 1000")
+	}
+	if err := newsService.SetKeywordFilter(cfg.NewsBlockKeywords, cfg.NewsAllowKeywords); err != nil {
+		logger.WithError(err).Warn("Invalid news keyword lists, not filtering headlines")
+	}
 	newsController := controllers.NewNewsController(newsService)
 
 	// Create Gemini service and intelligence controller
 	geminiService := services.NewGeminiService(cfg.GeminiAPIKey)
+	geminiService.SetPricing(cfg.GeminiPromptPricePer1K, cfg.GeminiCompletionPricePer1K)
+	geminiService.SetSourceWeights(cfg.NewsSourceWeights)
 	analysisService := services.NewTechnicalAnalysisService(dataService)
 	stockAnalysisService := services.NewStockAnalysisService(dataService, newsService, geminiService)
-	intelligenceController := controllers.NewIntelligenceController(newsService, geminiService, analysisService, stockAnalysisService, dataService)
+	stockAnalysisService.SetMultiTimeframe(cfg.AnalysisMultiTimeframe)
+	stockAnalysisService.SetYearRange(cfg.AnalysisYearRange, storageService)
+	if err := stockAnalysisService.SetCompositeWeights(services.CompositeWeights{
+		Technical: cfg.AnalysisWeightTechnical,
+		Catalyst:  cfg.AnalysisWeightCatalyst,
+		Volume:    cfg.AnalysisWeightVolume,
+	}); err != nil {
+		logger.WithError(err).Warn("Invalid composite score weights, using equal weights")
+	}
+	if err := stockAnalysisService.SetNeutralScore(cfg.AnalysisNeutralScore); err != nil {
+		logger.WithError(err).Warn("Invalid ANALYSIS_NEUTRAL_SCORE, using 5")
+	}
+	if err := stockAnalysisService.SetAnalysisCacheTTL(time.Duration(cfg.AnalysisCacheTTLSeconds) * time.Second); err != nil {
+		logger.WithError(err).Warn("Invalid ANALYSIS_CACHE_TTL_SECONDS, using 45")
+	}
+	stockAnalysisService.SetAnalysisBarFreshness(cfg.AnalysisCacheBarFreshness)
+	stockAnalysisService.SetIncludeNews(cfg.AnalysisIncludeNews)
+	if err := stockAnalysisService.SetIndicatorPeriods("1Day", services.IndicatorPeriods{
+		Trend:     cfg.AnalysisTrendPeriod,
+		RSI:       cfg.AnalysisRSIPeriod,
+		TrendBand: cfg.AnalysisTrendBand,
+	}); err != nil {
+		logger.WithError(err).Warn("Invalid daily indicator periods, using 10/14/5%")
+	}
+	if err := stockAnalysisService.SetScreenConcurrency(cfg.ScreenConcurrency); err != nil {
+		logger.WithError(err).Warn("Invalid SCREEN_CONCURRENCY, using 4")
+	}
+	breadthService := services.NewMarketBreadthService(dataService, analysisService, cfg.BreadthSymbols)
+	watchlistService := services.NewWatchlistService(storageService)
+	watchlistController := controllers.NewWatchlistController(watchlistService)
+	intelligenceController := controllers.NewIntelligenceController(newsService, geminiService, analysisService, stockAnalysisService, breadthService, watchlistService, dataService)
 
 	// Test account connection
 	logger.Info("Testing Alpaca connection...")
@@ -108,26 +160,179 @@ func main() {
 
 	// Create activity logger
 	activityLogger := services.NewActivityLogger("./activity_logs")
-	activityController := controllers.NewActivityController(activityLogger)
+	activityLogger.SetRetention(services.ActivityLogRetention{
+		ArchiveAfterDays: cfg.ActivityLogArchiveDays,
+		DeleteAfterDays:  cfg.ActivityLogDeleteDays,
+	})
+	if cfg.ReportWebhookURL != "" {
+		reportSender := services.NewWebhookReportSender(cfg.ReportWebhookURL)
+		activityLogger.SetReportSender(reportSender)
+		positionManager.SetAlertSender(reportSender)
+	}
+	activityLogger.SetSnapshotSource(stockAnalysisService)
+	activityController := controllers.NewActivityController(activityLogger, positionManager)
+	positionManager.SetActivityLogger(activityLogger)
+
+	feeModel := services.FeeModel{
+		PerShare:    cfg.FeePerShare,
+		PerTrade:    cfg.FeePerTrade,
+		PerContract: cfg.FeePerContract,
+		Percent:     cfg.FeePercent,
+	}
+	activityLogger.SetFeeModel(feeModel)
+	positionManager.SetFeeModel(feeModel)
+	stockAnalysisService.SetFeeModel(feeModel)
+	assetCache := services.NewAssetCache(tradingService, time.Duration(cfg.AssetCacheTTLMinutes)*time.Minute)
+	positionManager.SetAssetCache(assetCache)
+	orderController.SetAssetCache(assetCache)
+	optionsDataService := services.NewAlpacaOptionsDataService(cfg.AlpacaAPIKey, cfg.AlpacaSecretKey)
+	positionManager.SetOptionsData(optionsDataService)
+	liquidityGate := services.OptionLiquidityGate{
+		Mode:             cfg.OptionsLiquidityMode,
+		MinVolume:        int64(cfg.OptionsMinVolume),
+		MinOpenInterest:  int64(cfg.OptionsMinOpenInterest),
+		MaxSpreadPercent: cfg.OptionsMaxSpreadPercent,
+	}
+	if err := optionsDataService.SetLiquidityGate(liquidityGate); err != nil {
+		logger.WithError(err).Warn("Invalid options liquidity config, liquidity gate disabled")
+	} else {
+		orderController.SetOptionsLiquidityGate(liquidityGate)
+	}
+	orderController.SetOptionsChainCache(services.NewOptionsChainCache(tradingService,
+		time.Duration(cfg.OptionsChainCacheTTLSeconds)*time.Second,
+		time.Duration(cfg.OptionsChainMinIntervalMs)*time.Millisecond))
+	positionManager.SetEntryOrderTimeout(time.Duration(cfg.EntryOrderTimeoutMinutes) * time.Minute)
+	if err := positionManager.SetDayTradeFlatten(cfg.DayTradeFlattenMinutes); err != nil {
+		logger.WithError(err).Warn("Invalid DAY_TRADE_FLATTEN_MINUTES, auto-flatten disabled")
+	}
+	if err := positionManager.SetMinRiskReward(cfg.MinRiskReward); err != nil {
+		logger.WithError(err).Warn("Invalid MIN_RISK_REWARD, minimum disabled")
+	}
+	if err := positionManager.SetMaxOpenPositions(cfg.MaxOpenPositions); err != nil {
+		logger.WithError(err).Warn("Invalid MAX_OPEN_POSITIONS, no position cap")
+	}
+	if err := positionManager.SetBuyingPowerMultiplier(cfg.BuyingPowerMultiplier); err != nil {
+		logger.WithError(err).Warn("Invalid BUYING_POWER_MULTIPLIER, using broker buying power")
+	}
+	if err := positionManager.SetMonitorConcurrency(cfg.MonitorConcurrency); err != nil {
+		logger.WithError(err).Warn("Invalid MONITOR_CONCURRENCY, checking 4 positions at once")
+	}
+	if err := positionManager.SetClosedMonitorInterval(time.Duration(cfg.ClosedMonitorIntervalSeconds) * time.Second); err != nil {
+		logger.WithError(err).Warn("Invalid MONITOR_CLOSED_INTERVAL_SECONDS, checking every 5 minutes while closed")
+	}
+	if cfg.DryRun {
+		positionManager.SetDryRun(true)
+		logger.Warn("Dry run enabled: new managed positions are simulated against live quotes")
+	}
+	if err := positionManager.SetStopPlacement(services.StopPlacementConfig{
+		Attempts:   cfg.StopPlacementAttempts,
+		RetryDelay: time.Duration(cfg.StopPlacementRetryDelayMs) * time.Millisecond,
+	}); err != nil {
+		logger.WithError(err).Warn("Invalid stop placement config, using 3 attempts 1s apart")
+	}
+	if err := positionManager.SetAssetClassOverrides(cfg.AssetClassOverrides); err != nil {
+		logger.WithError(err).Warn("Invalid asset class overrides, detecting all asset classes")
+	}
+	if err := positionManager.SetStopFloor(services.StopFloorConfig{
+		Mode:        cfg.StopFloorMode,
+		MinPercent:  cfg.StopFloorPercent,
+		ATRMultiple: cfg.StopFloorATRMultiple,
+	}); err != nil {
+		logger.WithError(err).Warn("Invalid stop floor config, stop floor disabled")
+	}
+	if err := positionManager.SetSpreadGuard(services.SpreadGuardConfig{
+		Mode:       cfg.SpreadGuardMode,
+		MaxPercent: cfg.MaxSpreadPercent,
+	}); err != nil {
+		logger.WithError(err).Warn("Invalid spread guard config, spread guard disabled")
+	}
+	if err := positionManager.SetPriceReference(cfg.PriceReference); err != nil {
+		logger.WithError(err).Warn("Invalid ENTRY_PRICE_REFERENCE, using the quote mid")
+	}
+
+	if err := positionManager.SetPDTGuard(services.PDTGuardConfig{
+		Mode:         cfg.PDTGuardMode,
+		MaxDayTrades: cfg.PDTMaxDayTrades,
+		MinEquity:    cfg.PDTEquityThreshold,
+	}); err != nil {
+		logger.WithError(err).Warn("Invalid PDT guard config, using defaults")
+	}
+	if cfg.TradeReasoningEnabled && cfg.GeminiAPIKey != "" {
+		positionManager.SetTradeReasoning(stockAnalysisService, geminiService)
+	}
+
+	briefService := services.NewDailyBriefService(newsService, geminiService, stockAnalysisService, watchlistService, positionManager, activityLogger, services.DailyBriefConfig{
+		Watchlist: cfg.DailyBriefWatchlist,
+		TopSetups: cfg.DailyBriefTopSetups,
+	})
+	briefController := controllers.NewBriefController(briefService)
 
 	// Start trading session automatically
 	if account, err := orderController.GetAccount(); err == nil {
-		activityLogger.StartSession(ctx, account.PortfolioValue)
-		logger.Info("Activity logging session started")
+		if resumed, err := activityLogger.StartSession(ctx, account.PortfolioValue, false); err != nil {
+			logger.WithError(err).Warn("Failed to start activity logging session")
+		} else if resumed {
+			logger.Info("Activity logging session resumed")
+		} else {
+			logger.Info("Activity logging session started")
+		}
 	}
 
 	// Setup HTTP server
-	router := setupRouter(orderController, newsController, intelligenceController, positionController, activityController)
+	optionSnapshotRecorder := services.NewOptionSnapshotRecorder(optionsDataService, storageService, tradingService, positionManager,
+		cfg.OptionSnapshotSymbols, time.Duration(cfg.OptionSnapshotIntervalMinutes)*time.Minute)
+	optionSnapshotController := controllers.NewOptionSnapshotController(optionSnapshotRecorder)
+
+	barRecorder := services.NewBarRecorder(dataService, storageService, cfg.BarRecordSymbols,
+		time.Duration(cfg.BarRecordFlushSeconds)*time.Second, cfg.BarRecordBufferSize)
+	barRecorderController := controllers.NewBarRecorderController(barRecorder)
+
+	router := setupRouter(orderController, newsController, intelligenceController, positionController, activityController, watchlistController, briefController, optionSnapshotController, barRecorderController)
 
 	// Start data cleanup routine
 	go startDataCleanup(ctx, storageService, cfg.DataRetentionDays, logger)
 
+	// Archive/delete old activity logs on startup and daily
+	activityLogger.StartRetention(ctx, 24*time.Hour)
+
 	// Start position monitor
 	go startPositionMonitor(ctx, orderController, storageService, logger)
 
 	// Start managed position monitoring
 	go positionManager.MonitorPositions(ctx)
 
+	// Start scheduled watchlist analysis if configured
+	if cfg.ScheduledAnalysisWatchlist != "" {
+		scheduler := services.NewAnalysisScheduler(stockAnalysisService, watchlistService, activityLogger, services.AnalysisSchedulerConfig{
+			Watchlist: cfg.ScheduledAnalysisWatchlist,
+			Interval:  time.Duration(cfg.ScheduledAnalysisInterval) * time.Minute,
+			MinScore:  cfg.ScheduledAnalysisMinScore,
+		})
+		if err := scheduler.Start(ctx); err != nil {
+			logger.WithError(err).Error("Failed to start scheduled analysis")
+		} else {
+			defer scheduler.Stop()
+		}
+	}
+
+	// Capture option IV/greeks history for tracked contracts
+	if cfg.OptionSnapshotIntervalMinutes > 0 {
+		if err := optionSnapshotRecorder.Start(ctx); err != nil {
+			logger.WithError(err).Error("Failed to start option snapshot capture")
+		} else {
+			defer optionSnapshotRecorder.Stop()
+		}
+	}
+
+	// Persist live streamed bars for intraday history
+	if len(cfg.BarRecordSymbols) > 0 {
+		if err := barRecorder.Start(ctx); err != nil {
+			logger.WithError(err).Error("Failed to start bar recording")
+		} else {
+			defer barRecorder.Stop()
+		}
+	}
+
 	// Setup graceful shutdown
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
@@ -147,7 +352,7 @@ func main() {
 	}
 }
 
-func setupRouter(orderController *controllers.OrderController, newsController *controllers.NewsController, intelligenceController *controllers.IntelligenceController, positionController *controllers.PositionManagementController, activityController *controllers.ActivityController) *gin.Engine {
+func setupRouter(orderController *controllers.OrderController, newsController *controllers.NewsController, intelligenceController *controllers.IntelligenceController, positionController *controllers.PositionManagementController, activityController *controllers.ActivityController, watchlistController *controllers.WatchlistController, briefController *controllers.BriefController, optionSnapshotController *controllers.OptionSnapshotController, barRecorderController *controllers.BarRecorderController) *gin.Engine {
 	router := gin.Default()
 
 	// Enable CORS
@@ -167,6 +372,9 @@ func setupRouter(orderController *controllers.OrderController, newsController *c
 		c.JSON(200, gin.H{"status": "healthy"})
 	})
 
+	// Usage metrics
+	router.GET("/metrics", intelligenceController.HandleGetMetrics)
+
 	// Trading endpoints
 	api := router.Group("/api/v1")
 	{
@@ -175,21 +383,30 @@ func setupRouter(orderController *controllers.OrderController, newsController *c
 		api.POST("/orders/sell", orderController.HandleSell)
 		api.DELETE("/orders/:id", orderController.HandleCancelOrder)
 		api.GET("/orders", orderController.HandleGetOrders)
+		api.GET("/orders/open", orderController.HandleGetOpenOrders)
+		api.POST("/orders/cancel-all", orderController.HandleCancelAllOrders)
 
 		// Position and account endpoints
 		api.GET("/positions", orderController.HandleGetPositions)
 		api.GET("/account", orderController.HandleGetAccount)
+		api.POST("/account/snapshot", orderController.HandleSaveAccountSnapshot)
 
 		// Market data endpoints
 		api.GET("/market/quote/:symbol", orderController.HandleGetQuote)
 		api.GET("/market/bar/:symbol", orderController.HandleGetBar)
 		api.GET("/market/bars/:symbol", orderController.HandleGetBars)
+		api.GET("/market/bars/:symbol/recorded", barRecorderController.HandleGetRecordedBars)
+		api.GET("/market/bars/:symbol/recorded/session-vwap", barRecorderController.HandleGetSessionVWAP)
 
 		// Options trading endpoints
 		api.POST("/options/order", orderController.PlaceOptionsOrder)
 		api.GET("/options/positions", orderController.ListOptionsPositions)
 		api.GET("/options/position/:symbol", orderController.GetOptionsPosition)
 		api.GET("/options/chain/:symbol", orderController.GetOptionsChain)
+		api.GET("/options/chain/:symbol/table", orderController.GetOptionsChainTable)
+		api.GET("/options/snapshots", optionSnapshotController.HandleGetUnderlyingSnapshots)
+		api.GET("/options/snapshots/:symbol", optionSnapshotController.HandleGetContractSnapshots)
+		api.POST("/options/snapshots/capture", optionSnapshotController.HandleCaptureSnapshots)
 
 		// News endpoints
 		api.GET("/news", newsController.HandleGetNews)
@@ -208,21 +425,50 @@ func setupRouter(orderController *controllers.OrderController, newsController *c
 		api.POST("/intelligence/cleaned-news", intelligenceController.HandleGetCleanedNews)
 		api.GET("/intelligence/quick-market", intelligenceController.HandleGetQuickMarketIntelligence)
 		api.GET("/intelligence/analyze/:symbol", intelligenceController.HandleAnalyzeStock)
+		api.GET("/intelligence/analyze/:symbol/as-of", intelligenceController.HandleAnalyzeAsOf)
+		api.GET("/intelligence/score/:symbol", intelligenceController.HandleGetScore)
 		api.POST("/intelligence/analyze-multiple", intelligenceController.HandleAnalyzeMultipleStocks)
+		api.POST("/intelligence/screen", intelligenceController.HandleScreen)
+		api.GET("/intelligence/breadth", intelligenceController.HandleGetMarketBreadth)
+		api.GET("/intelligence/anchored-vwap/:symbol", intelligenceController.HandleGetAnchoredVWAP)
+		api.POST("/intelligence/indicators/:symbol", intelligenceController.HandleGetIndicators)
+		api.POST("/intelligence/splits/:symbol", intelligenceController.HandleApplySplit)
+		api.POST("/intelligence/backtest", intelligenceController.HandleBacktest)
+		api.POST("/intelligence/daily-brief", briefController.HandleGenerateDailyBrief)
+
+		// Watchlist endpoints
+		api.POST("/watchlists", watchlistController.HandleCreateWatchlist)
+		api.GET("/watchlists", watchlistController.HandleListWatchlists)
+		api.GET("/watchlists/:name", watchlistController.HandleGetWatchlist)
+		api.PUT("/watchlists/:name", watchlistController.HandleUpdateWatchlist)
+		api.DELETE("/watchlists/:name", watchlistController.HandleDeleteWatchlist)
 
 		// Position management endpoints
 		api.POST("/positions/managed", positionController.HandlePlaceManagedPosition)
+		api.POST("/positions/managed/batch", positionController.HandlePlaceManagedPositions)
+		api.POST("/positions/managed/import", positionController.HandleImportManagedPosition)
+		api.POST("/positions/managed/options", positionController.HandlePlaceManagedOptionsPosition)
 		api.GET("/positions/managed", positionController.HandleListManagedPositions)
+		api.GET("/positions/trades/stats", positionController.HandleGetTradeStats)
 		api.GET("/positions/managed/:id", positionController.HandleGetManagedPosition)
 		api.DELETE("/positions/managed/:id", positionController.HandleCloseManagedPosition)
+		api.PUT("/positions/managed/:id/trailing", positionController.HandleUpdateTrailingStop)
+		api.POST("/positions/managed/:id/reduce", positionController.HandleReduceManagedPosition)
+		api.POST("/positions/managed/:id/reverse", positionController.HandleReverseManagedPosition)
+		api.POST("/positions/managed/:id/notes", positionController.HandleAppendPositionNote)
+		api.GET("/positions/managed/:id/history", positionController.HandleGetManagedPositionHistory)
 
 		// Activity logging endpoints
 		api.GET("/activity/current", activityController.HandleGetCurrentActivity)
+		api.GET("/activity/report", activityController.HandleGetSessionReport)
+		api.POST("/activity/report/send", activityController.HandleSendSessionReport)
+		api.GET("/activity/replay", activityController.HandleReplaySession)
 		api.GET("/activity/:date", activityController.HandleGetActivityByDate)
 		api.GET("/activity", activityController.HandleListActivityLogs)
 		api.POST("/activity/session/start", activityController.HandleStartSession)
 		api.POST("/activity/session/end", activityController.HandleEndSession)
 		api.POST("/activity/log", activityController.HandleLogActivity)
+		api.POST("/activity/decision", activityController.HandleLogDecision)
 	}
 
 	// Serve dashboard
@@ -277,7 +523,7 @@ func startPositionMonitor(ctx context.Context, orderController *controllers.Orde
 
 			// Get and save account snapshot
 			if account, err := orderController.GetAccount(); err == nil {
-				if err := storage.SaveAccountSnapshot(account); err != nil {
+				if _, err := storage.SaveAccountSnapshot(account); err != nil {
 					logger.WithError(err).Error("Failed to save account snapshot")
 				}
 			}
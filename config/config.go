@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -14,10 +16,163 @@ type Config struct {
 	AlpacaPaper       bool
 	GeminiAPIKey      string
 	DatabasePath      string
+	AccountID         string // scopes stored orders, positions and trades when several accounts share a database
 	ServerPort        string
 	EnableLogging     bool
 	LogLevel          string
 	DataRetentionDays int
+
+	// Analysis
+	AnalysisMultiTimeframe bool
+	AnalysisYearRange      bool
+	// Relative weights of the technical/catalyst/volume sub-scores in the composite score
+	AnalysisWeightTechnical float64
+	AnalysisWeightCatalyst  float64
+	AnalysisWeightVolume    float64
+	// Technical/volume sub-score when their indicators can't be computed (0-10)
+	AnalysisNeutralScore int
+	// Seconds a stock analysis is reused per symbol (0 disables the cache)
+	AnalysisCacheTTLSeconds int
+	// Also expire a cached analysis when a newer latest bar arrives
+	AnalysisCacheBarFreshness bool
+	// Search news for catalysts (false = technical-only, neutral catalyst score)
+	AnalysisIncludeNews bool
+	// Daily-bar indicator lookbacks (other timeframes use scaled defaults)
+	AnalysisTrendPeriod int
+	AnalysisRSIPeriod   int
+	AnalysisTrendBand   float64
+	// Symbols analyzed at once by the screener
+	ScreenConcurrency int
+	DefaultTimeframe  string
+	BreadthSymbols    []string
+
+	// Parallel news feed fetching for the cleaned-news aggregator
+	NewsFetchConcurrency   int
+	NewsFeedTimeoutSeconds int
+	// Minimum milliseconds between Google News requests (0 = unthrottled)
+	NewsGoogleIntervalMs int
+	// Headline keyword lists: titles containing a block keyword (default:
+	// listicle and promotional phrases) are dropped; when allow keywords are
+	// set a headline must mention one of them
+	NewsBlockKeywords []string
+	NewsAllowKeywords []string
+
+	// Scheduled analysis (disabled when no watchlist is set)
+	ScheduledAnalysisWatchlist string
+	ScheduledAnalysisInterval  int // minutes
+	ScheduledAnalysisMinScore  int
+
+	// Option IV/greeks history: extra OCC symbols to capture alongside open
+	// options positions, and the capture interval (0 = no scheduled capture)
+	OptionSnapshotSymbols         []string
+	OptionSnapshotIntervalMinutes int
+
+	// Recording of live streamed minute bars (disabled when no symbols are
+	// set): seconds between flushes to storage and bars buffered before an
+	// early flush
+	BarRecordSymbols      []string
+	BarRecordFlushSeconds int
+	BarRecordBufferSize   int
+
+	// Liquidity gate for options chains ("off", "exclude", "flag"); zero thresholds are not checked
+	OptionsLiquidityMode    string
+	OptionsMinVolume        int
+	OptionsMinOpenInterest  int
+	OptionsMaxSpreadPercent float64
+
+	// Daily brief watchlist (defaults to the scheduled analysis watchlist) and setup count
+	DailyBriefWatchlist string
+	DailyBriefTopSetups int
+
+	// Generate entry/exit reasoning for managed positions with Gemini
+	TradeReasoningEnabled bool
+
+	// Activity log retention in days (0 = disabled)
+	ActivityLogArchiveDays int
+	ActivityLogDeleteDays  int
+
+	// Webhook receiving end-of-session reports (empty = no delivery)
+	ReportWebhookURL string
+
+	// Gemini pricing per 1K tokens (USD) for cost estimates
+	GeminiPromptPricePer1K     float64
+	GeminiCompletionPricePer1K float64
+
+	// Decimal places for dollar amounts and prices in API responses and storage
+	MoneyDecimals int
+	PriceDecimals int
+
+	// Minutes asset metadata (tick size, shortability, fractionability) is cached
+	AssetCacheTTLMinutes int
+
+	// Seconds a fetched options chain is reused (0 = no cache) and the minimum
+	// milliseconds between chain requests to the broker (0 = unlimited)
+	OptionsChainCacheTTLSeconds int
+	OptionsChainMinIntervalMs   int
+
+	// Minutes an entry order may stay unfilled before it is cancelled (0 = never)
+	EntryOrderTimeoutMinutes int
+
+	// Minutes before the close to flatten DAY_TRADE positions (0 = disabled)
+	DayTradeFlattenMinutes int
+
+	// Minimum target/stop distance ratio for new managed positions (0 = disabled)
+	MinRiskReward float64
+
+	// Maximum managed positions open at once (0 = no cap)
+	MaxOpenPositions int
+
+	// Spendable amount for new positions: 0 = broker buying power, otherwise
+	// cash times this multiplier (1 = cash only, 2 = Reg T margin)
+	BuyingPowerMultiplier float64
+
+	// Positions checked in parallel per monitoring cycle
+	MonitorConcurrency int
+
+	// Seconds between monitoring cycles while the market is closed
+	// (0 = every 10 seconds around the clock)
+	ClosedMonitorIntervalSeconds int
+
+	// Simulate every new managed position against live quotes (no broker orders)
+	DryRun bool
+
+	// Tries and delay between them when confirming a filled position and
+	// placing its stop loss
+	StopPlacementAttempts     int
+	StopPlacementRetryDelayMs int
+
+	// Asset class of symbols detection gets wrong, as "SYMBOL:class" pairs
+	// (equity, crypto, option), e.g. "BTCUSD:crypto"
+	AssetClassOverrides map[string]string
+
+	// Minimum stop distance for new managed positions ("off", "widen", "reject");
+	// the floor is the larger of the percent and the ATR multiple
+	StopFloorMode        string
+	StopFloorPercent     float64
+	StopFloorATRMultiple float64
+
+	// Bid-ask spread check on market entries ("off", "reject", or "limit" to
+	// enter at the mid instead) and the widest spread allowed, percent of mid
+	SpreadGuardMode  string
+	MaxSpreadPercent float64
+
+	// Current price new positions are sized and priced from when the request
+	// has no entry price: "bid", "ask", "mid" or "last"
+	PriceReference string
+
+	// Pattern-day-trader guard for DAY_TRADE positions ("off", "warn", "reject")
+	PDTGuardMode       string
+	PDTMaxDayTrades    int
+	PDTEquityThreshold float64
+
+	// Estimated trading fees subtracted from realized P&L (all default to 0)
+	FeePerShare    float64
+	FeePerTrade    float64
+	FeePerContract float64
+	FeePercent     float64
+
+	// News source reputation weights for Gemini news cleaning, e.g. "Reuters:2,Benzinga:0.5"
+	NewsSourceWeights map[string]float64
 }
 
 var AppConfig *Config
@@ -35,10 +190,109 @@ func Load() error {
 		AlpacaPaper:       getEnvOrDefault("ALPACA_PAPER", "true") == "true",
 		GeminiAPIKey:      os.Getenv("GEMINI_API_KEY"),
 		DatabasePath:      getEnvOrDefault("DATABASE_PATH", "./data/prophet_trader.db"),
+		AccountID:         getEnvOrDefault("ACCOUNT_ID", "default"),
 		ServerPort:        getEnvOrDefault("SERVER_PORT", "4534"),
 		EnableLogging:     getEnvOrDefault("ENABLE_LOGGING", "true") == "true",
 		LogLevel:          getEnvOrDefault("LOG_LEVEL", "info"),
 		DataRetentionDays: 90,
+
+		AnalysisMultiTimeframe:    getEnvOrDefault("ANALYSIS_MULTI_TIMEFRAME", "false") == "true",
+		AnalysisYearRange:         getEnvOrDefault("ANALYSIS_YEAR_RANGE", "false") == "true",
+		AnalysisWeightTechnical:   getEnvFloatOrDefault("ANALYSIS_WEIGHT_TECHNICAL", 1),
+		AnalysisWeightCatalyst:    getEnvFloatOrDefault("ANALYSIS_WEIGHT_CATALYST", 1),
+		AnalysisWeightVolume:      getEnvFloatOrDefault("ANALYSIS_WEIGHT_VOLUME", 1),
+		AnalysisNeutralScore:      getEnvIntOrDefault("ANALYSIS_NEUTRAL_SCORE", 5),
+		AnalysisCacheTTLSeconds:   getEnvIntOrDefault("ANALYSIS_CACHE_TTL_SECONDS", 45),
+		AnalysisCacheBarFreshness: getEnvOrDefault("ANALYSIS_CACHE_BAR_FRESHNESS", "false") == "true",
+		AnalysisIncludeNews:       getEnvOrDefault("ANALYSIS_INCLUDE_NEWS", "true") == "true",
+		ScreenConcurrency:         getEnvIntOrDefault("SCREEN_CONCURRENCY", 4),
+		AnalysisTrendPeriod:       getEnvIntOrDefault("ANALYSIS_TREND_PERIOD", 10),
+		AnalysisRSIPeriod:         getEnvIntOrDefault("ANALYSIS_RSI_PERIOD", 14),
+		AnalysisTrendBand:         getEnvFloatOrDefault("ANALYSIS_TREND_BAND_PERCENT", 5),
+		DefaultTimeframe:          getEnvOrDefault("DEFAULT_TIMEFRAME", "1Day"),
+		BreadthSymbols:            splitList(getEnvOrDefault("BREADTH_SYMBOLS", "AAPL,MSFT,NVDA,AMZN,GOOGL,META,AVGO,TSLA,BRK.B,JPM,LLY,V,UNH,XOM,MA,COST,HD,PG,JNJ,WMT")),
+
+		NewsFetchConcurrency:   getEnvIntOrDefault("NEWS_FETCH_CONCURRENCY", 4),
+		NewsFeedTimeoutSeconds: getEnvIntOrDefault("NEWS_FEED_TIMEOUT_SECONDS", 10),
+		NewsGoogleIntervalMs:   getEnvIntOrDefault("NEWS_GOOGLE_INTERVAL_MS", 1000),
+		NewsBlockKeywords:      splitList(getEnvOrDefault("NEWS_BLOCK_KEYWORDS", "stocks to buy,stock to buy,top 10 stocks,top 5 stocks,make you a millionaire,you won't believe,sponsored")),
+		NewsAllowKeywords:      splitList(os.Getenv("NEWS_ALLOW_KEYWORDS")),
+
+		ScheduledAnalysisWatchlist: os.Getenv("SCHEDULED_ANALYSIS_WATCHLIST"),
+		ScheduledAnalysisInterval:  getEnvIntOrDefault("SCHEDULED_ANALYSIS_INTERVAL_MINUTES", 30),
+		ScheduledAnalysisMinScore:  getEnvIntOrDefault("SCHEDULED_ANALYSIS_MIN_SCORE", 7),
+
+		OptionSnapshotSymbols:         splitList(os.Getenv("OPTION_SNAPSHOT_SYMBOLS")),
+		OptionSnapshotIntervalMinutes: getEnvIntOrDefault("OPTION_SNAPSHOT_INTERVAL_MINUTES", 15),
+
+		BarRecordSymbols:      splitList(os.Getenv("BAR_RECORD_SYMBOLS")),
+		BarRecordFlushSeconds: getEnvIntOrDefault("BAR_RECORD_FLUSH_SECONDS", 30),
+		BarRecordBufferSize:   getEnvIntOrDefault("BAR_RECORD_BUFFER_SIZE", 500),
+
+		OptionsLiquidityMode:    getEnvOrDefault("OPTIONS_LIQUIDITY_MODE", "exclude"),
+		OptionsMinVolume:        getEnvIntOrDefault("OPTIONS_MIN_VOLUME", 0),
+		OptionsMinOpenInterest:  getEnvIntOrDefault("OPTIONS_MIN_OPEN_INTEREST", 100),
+		OptionsMaxSpreadPercent: getEnvFloatOrDefault("OPTIONS_MAX_SPREAD_PERCENT", 25),
+
+		DailyBriefWatchlist: getEnvOrDefault("DAILY_BRIEF_WATCHLIST", os.Getenv("SCHEDULED_ANALYSIS_WATCHLIST")),
+		DailyBriefTopSetups: getEnvIntOrDefault("DAILY_BRIEF_TOP_SETUPS", 5),
+
+		TradeReasoningEnabled: getEnvOrDefault("TRADE_REASONING_ENABLED", "false") == "true",
+
+		ActivityLogArchiveDays: getEnvIntOrDefault("ACTIVITY_LOG_ARCHIVE_DAYS", 7),
+		ActivityLogDeleteDays:  getEnvIntOrDefault("ACTIVITY_LOG_DELETE_DAYS", 0),
+
+		ReportWebhookURL: os.Getenv("REPORT_WEBHOOK_URL"),
+
+		GeminiPromptPricePer1K:     getEnvFloatOrDefault("GEMINI_PROMPT_PRICE_PER_1K", 0.0001),
+		GeminiCompletionPricePer1K: getEnvFloatOrDefault("GEMINI_COMPLETION_PRICE_PER_1K", 0.0004),
+
+		MoneyDecimals: getEnvIntOrDefault("MONEY_DECIMALS", 2),
+		PriceDecimals: getEnvIntOrDefault("PRICE_DECIMALS", 4),
+
+		AssetCacheTTLMinutes: getEnvIntOrDefault("ASSET_CACHE_TTL_MINUTES", 60),
+
+		OptionsChainCacheTTLSeconds: getEnvIntOrDefault("OPTIONS_CHAIN_CACHE_TTL_SECONDS", 30),
+		OptionsChainMinIntervalMs:   getEnvIntOrDefault("OPTIONS_CHAIN_MIN_INTERVAL_MS", 500),
+
+		EntryOrderTimeoutMinutes: getEnvIntOrDefault("ENTRY_ORDER_TIMEOUT_MINUTES", 1440),
+
+		DayTradeFlattenMinutes: getEnvIntOrDefault("DAY_TRADE_FLATTEN_MINUTES", 0),
+
+		MinRiskReward: getEnvFloatOrDefault("MIN_RISK_REWARD", 0),
+
+		MaxOpenPositions: getEnvIntOrDefault("MAX_OPEN_POSITIONS", 0),
+
+		BuyingPowerMultiplier: getEnvFloatOrDefault("BUYING_POWER_MULTIPLIER", 0),
+
+		MonitorConcurrency:           getEnvIntOrDefault("MONITOR_CONCURRENCY", 4),
+		ClosedMonitorIntervalSeconds: getEnvIntOrDefault("MONITOR_CLOSED_INTERVAL_SECONDS", 300),
+		DryRun:                       getEnvOrDefault("DRY_RUN", "false") == "true",
+
+		StopPlacementAttempts:     getEnvIntOrDefault("STOP_PLACEMENT_ATTEMPTS", 3),
+		StopPlacementRetryDelayMs: getEnvIntOrDefault("STOP_PLACEMENT_RETRY_DELAY_MS", 1000),
+
+		AssetClassOverrides: parsePairs(os.Getenv("ASSET_CLASS_OVERRIDES")),
+
+		StopFloorMode:        getEnvOrDefault("STOP_FLOOR_MODE", "off"),
+		StopFloorPercent:     getEnvFloatOrDefault("STOP_FLOOR_PERCENT", 0),
+		StopFloorATRMultiple: getEnvFloatOrDefault("STOP_FLOOR_ATR_MULTIPLE", 0),
+
+		SpreadGuardMode:  getEnvOrDefault("SPREAD_GUARD_MODE", "off"),
+		MaxSpreadPercent: getEnvFloatOrDefault("MAX_SPREAD_PERCENT", 0),
+
+		PriceReference: getEnvOrDefault("ENTRY_PRICE_REFERENCE", "mid"),
+
+		PDTGuardMode:       getEnvOrDefault("PDT_GUARD_MODE", "reject"),
+		PDTMaxDayTrades:    getEnvIntOrDefault("PDT_MAX_DAY_TRADES", 3),
+		PDTEquityThreshold: getEnvFloatOrDefault("PDT_EQUITY_THRESHOLD", 25000),
+
+		FeePerShare:    getEnvFloatOrDefault("FEE_PER_SHARE", 0),
+		FeePerTrade:    getEnvFloatOrDefault("FEE_PER_TRADE", 0),
+		FeePerContract: getEnvFloatOrDefault("FEE_PER_CONTRACT", 0),
+		FeePercent:     getEnvFloatOrDefault("FEE_PERCENT", 0),
+
+		NewsSourceWeights: parseWeights(os.Getenv("NEWS_SOURCE_WEIGHTS")),
 	}
 
 	return nil
@@ -50,3 +304,58 @@ func getEnvOrDefault(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvFloatOrDefault(key string, defaultValue float64) float64 {
+	if value, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// splitList parses a comma-separated env value, dropping empty entries
+func splitList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parsePairs parses comma-separated "name:value" pairs, skipping entries without a value
+func parsePairs(value string) map[string]string {
+	pairs := make(map[string]string)
+	for _, item := range splitList(value) {
+		idx := strings.LastIndex(item, ":")
+		if idx <= 0 || strings.TrimSpace(item[idx+1:]) == "" {
+			continue
+		}
+		pairs[strings.TrimSpace(item[:idx])] = strings.TrimSpace(item[idx+1:])
+	}
+	return pairs
+}
+
+// parseWeights parses comma-separated "name:weight" pairs, skipping invalid entries
+func parseWeights(value string) map[string]float64 {
+	weights := make(map[string]float64)
+	for _, item := range splitList(value) {
+		idx := strings.LastIndex(item, ":")
+		if idx <= 0 {
+			continue
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(item[idx+1:]), 64)
+		if err != nil || weight < 0 {
+			continue
+		}
+		weights[strings.TrimSpace(item[:idx])] = weight
+	}
+	return weights
+}
@@ -3,19 +3,22 @@ package controllers
 import (
 	"net/http"
 	"prophet-trader/services"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ActivityController handles activity logging endpoints
 type ActivityController struct {
-	activityLogger *services.ActivityLogger
+	activityLogger  *services.ActivityLogger
+	positionManager *services.PositionManager
 }
 
 // NewActivityController creates a new activity controller
-func NewActivityController(activityLogger *services.ActivityLogger) *ActivityController {
+func NewActivityController(activityLogger *services.ActivityLogger, positionManager *services.PositionManager) *ActivityController {
 	return &ActivityController{
-		activityLogger: activityLogger,
+		activityLogger:  activityLogger,
+		positionManager: positionManager,
 	}
 }
 
@@ -57,10 +60,11 @@ func (ac *ActivityController) HandleListActivityLogs(c *gin.Context) {
 	})
 }
 
-// HandleStartSession starts a new trading session
+// HandleStartSession starts the trading session, resuming today's log unless force_new is set
 func (ac *ActivityController) HandleStartSession(c *gin.Context) {
 	var req struct {
 		StartingCapital float64 `json:"starting_capital" binding:"required"`
+		ForceNew        bool    `json:"force_new"` // discard today's existing log instead of resuming it
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -68,19 +72,25 @@ func (ac *ActivityController) HandleStartSession(c *gin.Context) {
 		return
 	}
 
-	if err := ac.activityLogger.StartSession(c.Request.Context(), req.StartingCapital); err != nil {
+	resumed, err := ac.activityLogger.StartSession(c.Request.Context(), req.StartingCapital, req.ForceNew)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Session started"})
+	if resumed {
+		c.JSON(http.StatusOK, gin.H{"message": "Session resumed", "resumed": true})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Session started", "resumed": false})
 }
 
 // HandleEndSession ends the current trading session
 func (ac *ActivityController) HandleEndSession(c *gin.Context) {
 	var req struct {
-		EndingCapital   float64 `json:"ending_capital" binding:"required"`
-		ActivePositions int     `json:"active_positions"`
+		EndingCapital    float64 `json:"ending_capital" binding:"required"`
+		ActivePositions  int     `json:"active_positions"`
+		FlattenDayTrades bool    `json:"flatten_day_trades"` // close DAY_TRADE positions before ending
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -88,12 +98,95 @@ func (ac *ActivityController) HandleEndSession(c *gin.Context) {
 		return
 	}
 
+	response := gin.H{"message": "Session ended"}
+
+	// Flatten first so the closes are recorded in the session being ended
+	if req.FlattenDayTrades {
+		closed, failures := ac.positionManager.FlattenDayTrades(c.Request.Context(), "Flattened at session end")
+		response["flattened"] = closed
+		if len(failures) > 0 {
+			failed := make(map[string]string, len(failures))
+			for positionID, err := range failures {
+				failed[positionID] = err.Error()
+			}
+			response["flatten_failures"] = failed
+		}
+	}
+
 	if err := ac.activityLogger.EndSession(c.Request.Context(), req.EndingCapital, req.ActivePositions); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Session ended"})
+	// Deliver the day's recap when a report sender is configured
+	if ac.activityLogger.HasReportSender() {
+		response["report_sent"] = true
+		if err := ac.sendReport(c, time.Now().Format("2006-01-02"), "text"); err != nil {
+			response["report_sent"] = false
+			response["report_error"] = err.Error()
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// HandleGetSessionReport returns the end-of-day recap for a session
+// GET /api/v1/activity/report?date=2025-11-17&format=text|html|json
+func (ac *ActivityController) HandleGetSessionReport(c *gin.Context) {
+	date := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
+	format := c.DefaultQuery("format", "text")
+
+	report, err := ac.activityLogger.GenerateSessionReport(date, ac.openPositions(date))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	body, contentType, err := report.Render(format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, []byte(body))
+}
+
+// HandleSendSessionReport delivers a session report through the configured sender
+// POST /api/v1/activity/report/send?date=2025-11-17&format=text|html
+func (ac *ActivityController) HandleSendSessionReport(c *gin.Context) {
+	date := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
+
+	if err := ac.sendReport(c, date, c.DefaultQuery("format", "text")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Report sent", "date": date})
+}
+
+// sendReport generates and delivers the report for date
+func (ac *ActivityController) sendReport(c *gin.Context, date, format string) error {
+	report, err := ac.activityLogger.GenerateSessionReport(date, ac.openPositions(date))
+	if err != nil {
+		return err
+	}
+	return ac.activityLogger.SendSessionReport(c.Request.Context(), report, format)
+}
+
+// openPositions lists currently open managed positions for today's report.
+// Past reports omit them since today's holdings say nothing about that day.
+func (ac *ActivityController) openPositions(date string) []*services.ManagedPosition {
+	if ac.positionManager == nil || date != time.Now().Format("2006-01-02") {
+		return nil
+	}
+
+	open := ac.positionManager.ListManagedPositions("ACTIVE")
+	return append(open, ac.positionManager.ListManagedPositions("PARTIAL")...)
 }
 
 // HandleLogActivity logs a general activity
@@ -118,3 +211,41 @@ func (ac *ActivityController) HandleLogActivity(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Activity logged"})
 }
+
+// HandleLogDecision logs a trading decision; the market snapshot is captured server-side
+// POST /api/v1/activity/decision
+func (ac *ActivityController) HandleLogDecision(c *gin.Context) {
+	var req struct {
+		Action     string                 `json:"action" binding:"required"` // BUY, SELL, HOLD, PASS
+		Symbol     string                 `json:"symbol"`
+		Reasoning  string                 `json:"reasoning"`
+		Conviction int                    `json:"conviction"`
+		MarketData map[string]interface{} `json:"market_data"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	if err := ac.activityLogger.LogDecision(c.Request.Context(), req.Action, req.Symbol, req.Reasoning, req.Conviction, req.MarketData); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Decision logged"})
+}
+
+// HandleReplaySession returns a day's decisions and events as one timeline
+// GET /api/v1/activity/replay?date=2025-11-17&symbol=AAPL
+func (ac *ActivityController) HandleReplaySession(c *gin.Context) {
+	date := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
+
+	replay, err := ac.activityLogger.ReplaySession(date, c.Query("symbol"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, replay)
+}
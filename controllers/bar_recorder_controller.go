@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"net/http"
+	"prophet-trader/apperrors"
+	"prophet-trader/services"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BarRecorderController serves minute bars recorded from the live stream
+type BarRecorderController struct {
+	recorder *services.BarRecorder
+}
+
+// NewBarRecorderController creates a new bar recorder controller
+func NewBarRecorderController(recorder *services.BarRecorder) *BarRecorderController {
+	return &BarRecorderController{
+		recorder: recorder,
+	}
+}
+
+// HandleGetRecordedBars replays the recorded minute bars of a symbol. start
+// and end are RFC3339 or YYYY-MM-DD (end date inclusive) and default to the
+// last 24 hours.
+// GET /api/v1/market/bars/:symbol/recorded?start=2025-01-15&end=2025-01-15
+func (bc *BarRecorderController) HandleGetRecordedBars(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	end := time.Now()
+	start := end.Add(-24 * time.Hour)
+	if s := c.Query("start"); s != "" {
+		t, err := parseBarTime(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start", "details": "use YYYY-MM-DD or RFC3339"})
+			return
+		}
+		start = t
+	}
+	if s := c.Query("end"); s != "" {
+		t, err := parseBarTime(s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end", "details": "use YYYY-MM-DD or RFC3339"})
+			return
+		}
+		if _, err := time.Parse("2006-01-02", s); err == nil {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		end = t
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end must not be before start"})
+		return
+	}
+
+	bars, err := bc.recorder.Replay(symbol, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get recorded bars", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":    symbol,
+		"timeframe": "1Min",
+		"start":     start,
+		"end":       end,
+		"count":     len(bars),
+		"bars":      bars,
+	})
+}
+
+// HandleGetSessionVWAP reconstructs a regular session's VWAP from recorded
+// minute bars. date is YYYY-MM-DD and defaults to today.
+// GET /api/v1/market/bars/:symbol/recorded/session-vwap?date=2025-01-15
+func (bc *BarRecorderController) HandleGetSessionVWAP(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	day := time.Now()
+	if s := c.Query("date"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date", "details": "use YYYY-MM-DD"})
+			return
+		}
+		day = t
+	}
+
+	result, err := bc.recorder.SessionVWAP(symbol, day)
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, http.StatusInternalServerError), gin.H{"error": "Failed to reconstruct session VWAP", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":       symbol,
+		"date":         day.Format("2006-01-02"),
+		"session_vwap": result,
+	})
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"prophet-trader/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BriefController handles the daily brief endpoint
+type BriefController struct {
+	briefService *services.DailyBriefService
+}
+
+// NewBriefController creates a new brief controller
+func NewBriefController(briefService *services.DailyBriefService) *BriefController {
+	return &BriefController{
+		briefService: briefService,
+	}
+}
+
+// DailyBriefRequest optionally overrides the configured watchlist and setup count
+type DailyBriefRequest struct {
+	Watchlist string `json:"watchlist"`
+	TopSetups int    `json:"top_setups"`
+}
+
+// HandleGenerateDailyBrief generates the daily brief and records it in the activity log
+// POST /api/v1/intelligence/daily-brief
+func (bc *BriefController) HandleGenerateDailyBrief(c *gin.Context) {
+	var req DailyBriefRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request",
+				"details": err.Error(),
+			})
+			return
+		}
+	}
+
+	// News cleaning plus scoring a full watchlist takes a while
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	brief, err := bc.briefService.Generate(ctx, req.Watchlist, req.TopSetups)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to generate daily brief",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, brief)
+}
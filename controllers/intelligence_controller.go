@@ -2,9 +2,13 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"prophet-trader/apperrors"
 	"prophet-trader/interfaces"
 	"prophet-trader/services"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,16 +20,20 @@ type IntelligenceController struct {
 	geminiService        *services.GeminiService
 	analysisService      *services.TechnicalAnalysisService
 	stockAnalysisService *services.StockAnalysisService
+	breadthService       *services.MarketBreadthService
+	watchlistService     *services.WatchlistService
 	dataService          interfaces.DataService
 }
 
 // NewIntelligenceController creates a new intelligence controller
-func NewIntelligenceController(newsService *services.NewsService, geminiService *services.GeminiService, analysisService *services.TechnicalAnalysisService, stockAnalysisService *services.StockAnalysisService, dataService interfaces.DataService) *IntelligenceController {
+func NewIntelligenceController(newsService *services.NewsService, geminiService *services.GeminiService, analysisService *services.TechnicalAnalysisService, stockAnalysisService *services.StockAnalysisService, breadthService *services.MarketBreadthService, watchlistService *services.WatchlistService, dataService interfaces.DataService) *IntelligenceController {
 	return &IntelligenceController{
 		newsService:          newsService,
 		geminiService:        geminiService,
 		analysisService:      analysisService,
 		stockAnalysisService: stockAnalysisService,
+		breadthService:       breadthService,
+		watchlistService:     watchlistService,
 		dataService:          dataService,
 	}
 }
@@ -37,6 +45,7 @@ type AggregateNewsRequest struct {
 	GoogleTopics         []string `json:"google_topics"`           // BUSINESS, TECHNOLOGY, etc.
 	Symbols              []string `json:"symbols"`                 // Stock symbols to search for
 	MaxArticlesPerSource int      `json:"max_articles_per_source"` // Default 10
+	GroupBySymbol        bool     `json:"group_by_symbol"`         // Add a per-symbol summary for each requested symbol
 }
 
 // HandleGetCleanedNews aggregates news from multiple sources and returns a cleaned summary
@@ -60,41 +69,51 @@ func (ic *IntelligenceController) HandleGetCleanedNews(c *gin.Context) {
 		req.MaxArticlesPerSource = 25
 	}
 
-	// Aggregate news from all requested sources
-	allNews := make([]services.NewsItem, 0)
+	// Collect the requested feeds, keeping track of which symbol each
+	// symbol search feed was built for
+	feeds := make([]services.NewsFeed, 0)
+	feedSymbols := make([]string, 0)
+	addFeed := func(feed services.NewsFeed, symbol string) {
+		feeds = append(feeds, feed)
+		feedSymbols = append(feedSymbols, symbol)
+	}
 
-	// Fetch from Google News
 	if req.IncludeGoogle {
-		// Fetch by topics
 		for _, topic := range req.GoogleTopics {
-			if news, err := ic.newsService.GetGoogleNewsByTopic(topic); err == nil {
-				limit := min(len(news), req.MaxArticlesPerSource)
-				allNews = append(allNews, news[:limit]...)
-			}
+			addFeed(services.GoogleTopicFeed(topic), "")
 		}
-
-		// Fetch by symbols
 		for _, symbol := range req.Symbols {
-			if news, err := ic.newsService.GetGoogleNewsSearch(symbol); err == nil {
-				limit := min(len(news), req.MaxArticlesPerSource)
-				allNews = append(allNews, news[:limit]...)
-			}
+			addFeed(services.GoogleSearchFeed(symbol), strings.ToUpper(symbol))
 		}
 
 		// If no specific topics or symbols, get general business news
 		if len(req.GoogleTopics) == 0 && len(req.Symbols) == 0 {
-			if news, err := ic.newsService.GetGoogleNewsByTopic("BUSINESS"); err == nil {
-				limit := min(len(news), req.MaxArticlesPerSource)
-				allNews = append(allNews, news[:limit]...)
-			}
+			addFeed(services.GoogleTopicFeed("BUSINESS"), "")
 		}
 	}
 
-	// Fetch from MarketWatch
 	if req.IncludeMarketWatch {
-		if news, err := ic.newsService.GetAllMarketWatchNews(); err == nil {
-			limit := min(len(news), req.MaxArticlesPerSource*4) // Get from all 4 feeds
-			allNews = append(allNews, news[:limit]...)
+		for _, feed := range services.MarketWatchFeeds() {
+			addFeed(feed, "")
+		}
+	}
+
+	// Fetch all feeds in parallel; feeds that fail or time out are reported
+	// and the rest are still used
+	allNews := make([]services.NewsItem, 0)
+	symbolNews := make(map[string][]services.NewsItem)
+	failedFeeds := make(map[string]string)
+
+	for i, result := range ic.newsService.FetchFeeds(c.Request.Context(), feeds) {
+		if result.Err != nil {
+			failedFeeds[result.Feed.Name] = result.Err.Error()
+			continue
+		}
+
+		limit := min(len(result.Items), req.MaxArticlesPerSource)
+		allNews = append(allNews, result.Items[:limit]...)
+		if symbol := feedSymbols[i]; symbol != "" {
+			symbolNews[symbol] = append(symbolNews[symbol], result.Items[:limit]...)
 		}
 	}
 
@@ -102,6 +121,7 @@ func (ic *IntelligenceController) HandleGetCleanedNews(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"message":      "No news found",
 			"cleaned_news": nil,
+			"failed_feeds": failedFeeds,
 		})
 		return
 	}
@@ -116,31 +136,79 @@ func (ic *IntelligenceController) HandleGetCleanedNews(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"cleaned_news":      cleanedNews,
 		"raw_article_count": len(allNews),
-	})
+	}
+	if len(failedFeeds) > 0 {
+		response["failed_feeds"] = failedFeeds
+	}
+
+	// Summarize each symbol's own articles so a UI can show a card per ticker
+	if req.GroupBySymbol {
+		symbolSummaries := make(map[string]*services.SymbolNewsSummary)
+		for symbol, news := range symbolNews {
+			if len(news) == 0 {
+				continue
+			}
+			summary, err := ic.geminiService.SummarizeSymbolNews(symbol, news)
+			if err != nil {
+				symbolSummaries[symbol] = &services.SymbolNewsSummary{
+					Symbol:       symbol,
+					ArticleCount: len(news),
+					Error:        err.Error(),
+				}
+				continue
+			}
+			symbolSummaries[symbol] = summary
+		}
+		response["symbol_summaries"] = symbolSummaries
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
-// HandleGetQuickMarketIntelligence provides a quick market overview
-// GET /api/v1/intelligence/quick-market
-func (ic *IntelligenceController) HandleGetQuickMarketIntelligence(c *gin.Context) {
-	// Get latest from MarketWatch (fastest, most relevant)
-	allNews := make([]services.NewsItem, 0)
+// quickMarketDefaultItems is how many items each MarketWatch feed contributes by default
+const quickMarketDefaultItems = 5
+
+// quickMarketMaxItems caps the items taken from a single feed
+const quickMarketMaxItems = 50
 
-	// Get top stories
-	if news, err := ic.newsService.GetMarketWatchTopStories(); err == nil {
-		allNews = append(allNews, news[:min(5, len(news))]...)
+// HandleGetQuickMarketIntelligence provides a quick market overview.
+// Each feed contributes up to 5 items by default (0 skips the feed). With
+// raw=true the deduplicated headlines are returned without Gemini cleaning.
+// GET /api/v1/intelligence/quick-market?top_stories=5&bulletins=5&market_pulse=5&raw=false
+func (ic *IntelligenceController) HandleGetQuickMarketIntelligence(c *gin.Context) {
+	feeds := []struct {
+		param string
+		fetch func() ([]services.NewsItem, error)
+	}{
+		{"top_stories", ic.newsService.GetMarketWatchTopStories},
+		{"bulletins", ic.newsService.GetMarketWatchBulletins},
+		{"market_pulse", ic.newsService.GetMarketWatchMarketPulse},
 	}
 
-	// Get bulletins
-	if news, err := ic.newsService.GetMarketWatchBulletins(); err == nil {
-		allNews = append(allNews, news[:min(5, len(news))]...)
+	limits := make([]int, len(feeds))
+	for i, feed := range feeds {
+		limit, err := strconv.Atoi(c.DefaultQuery(feed.param, strconv.Itoa(quickMarketDefaultItems)))
+		if err != nil || limit < 0 || limit > quickMarketMaxItems {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("%s must be between 0 and %d", feed.param, quickMarketMaxItems),
+			})
+			return
+		}
+		limits[i] = limit
 	}
 
-	// Get market pulse
-	if news, err := ic.newsService.GetMarketWatchMarketPulse(); err == nil {
-		allNews = append(allNews, news[:min(5, len(news))]...)
+	// Get latest from MarketWatch (fastest, most relevant)
+	allNews := make([]services.NewsItem, 0)
+	for i, feed := range feeds {
+		if limits[i] == 0 {
+			continue
+		}
+		if news, err := feed.fetch(); err == nil {
+			allNews = append(allNews, news[:min(limits[i], len(news))]...)
+		}
 	}
 
 	if len(allNews) == 0 {
@@ -150,6 +218,16 @@ func (ic *IntelligenceController) HandleGetQuickMarketIntelligence(c *gin.Contex
 		return
 	}
 
+	// Cheap headline feed: skip the Gemini call entirely
+	if c.Query("raw") == "true" {
+		headlines := services.DedupeNews(allNews)
+		c.JSON(http.StatusOK, gin.H{
+			"count": len(headlines),
+			"news":  headlines,
+		})
+		return
+	}
+
 	// Clean the news
 	cleanedNews, err := ic.geminiService.CleanNewsForTrading(allNews)
 	if err != nil {
@@ -163,8 +241,12 @@ func (ic *IntelligenceController) HandleGetQuickMarketIntelligence(c *gin.Contex
 	c.JSON(http.StatusOK, cleanedNews)
 }
 
-// HandleAnalyzeStock provides comprehensive analysis for a single stock
-// GET /api/v1/intelligence/analyze/:symbol
+// HandleAnalyzeStock provides comprehensive analysis for a single stock.
+// Results are cached briefly per symbol; pass refresh=true to bypass the cache.
+// depth=quick|standard|deep picks a preset trading latency for completeness
+// (see services.AnalysisOptionsFor); include_news=false then skips the news
+// search for a fast technical-only analysis.
+// GET /api/v1/intelligence/analyze/:symbol?compact=true&depth=quick&include_news=false
 func (ic *IntelligenceController) HandleAnalyzeStock(c *gin.Context) {
 	symbol := c.Param("symbol")
 	if symbol == "" {
@@ -178,7 +260,23 @@ func (ic *IntelligenceController) HandleAnalyzeStock(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	analysis, err := ic.stockAnalysisService.AnalyzeStock(ctx, symbol)
+	opts, err := ic.stockAnalysisService.AnalysisOptionsFor(c.Query("depth"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if includeNews := c.Query("include_news"); includeNews != "" {
+		opts.IncludeNews = includeNews == "true"
+	}
+
+	var analysis *services.StockAnalysis
+	if c.Query("refresh") == "true" {
+		analysis, err = ic.stockAnalysisService.RefreshAnalysisWith(ctx, symbol, opts)
+	} else {
+		analysis, err = ic.stockAnalysisService.AnalyzeStockWith(ctx, symbol, opts)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to analyze stock",
@@ -187,16 +285,93 @@ func (ic *IntelligenceController) HandleAnalyzeStock(c *gin.Context) {
 		return
 	}
 
+	if c.Query("compact") == "true" {
+		c.JSON(http.StatusOK, analysis.ToCompact())
+		return
+	}
+
 	c.JSON(http.StatusOK, analysis)
 }
 
+// HandleAnalyzeAsOf recomputes a stock's analysis as of the close of a past
+// date from stored daily bars only, with no bar after the date, for research
+// into how scores relate to the price action that followed. depth=deep adds
+// the weekly and 52-week pictures; news is never searched.
+// GET /api/v1/intelligence/analyze/:symbol/as-of?date=2025-01-15&depth=deep&compact=true
+func (ic *IntelligenceController) HandleAnalyzeAsOf(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	date, err := time.Parse("2006-01-02", c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "date required",
+			"details": "use YYYY-MM-DD",
+		})
+		return
+	}
+
+	opts, err := ic.stockAnalysisService.AnalysisOptionsFor(c.Query("depth"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	analysis, err := ic.stockAnalysisService.AnalyzeAsOf(c.Request.Context(), symbol, date, opts)
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   "Failed to recompute analysis",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if c.Query("compact") == "true" {
+		c.JSON(http.StatusOK, analysis.ToCompact())
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
+
+// HandleGetScore returns only the trade setup scores for a symbol, for cheap
+// screening of large universes before a full analysis
+// GET /api/v1/intelligence/score/:symbol
+func (ic *IntelligenceController) HandleGetScore(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if symbol == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "symbol required",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	score, err := ic.stockAnalysisService.ScoreStock(ctx, symbol)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to score stock",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, score)
+}
+
 // AnalyzeStocksRequest represents a request to analyze multiple stocks
 type AnalyzeStocksRequest struct {
-	Symbols []string `json:"symbols" binding:"required"`
+	Symbols     []string `json:"symbols"`
+	Watchlist   string   `json:"watchlist"`    // Name of a saved watchlist, alternative to symbols
+	Depth       string   `json:"depth"`        // "quick", "standard" (default) or "deep"
+	IncludeNews *bool    `json:"include_news"` // false skips the news search; defaults to the depth's setting
 }
 
 // HandleAnalyzeMultipleStocks provides comprehensive analysis for multiple stocks
-// POST /api/v1/intelligence/analyze-multiple
+// POST /api/v1/intelligence/analyze-multiple?compact=true
 func (ic *IntelligenceController) HandleAnalyzeMultipleStocks(c *gin.Context) {
 	var req AnalyzeStocksRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -207,9 +382,21 @@ func (ic *IntelligenceController) HandleAnalyzeMultipleStocks(c *gin.Context) {
 		return
 	}
 
+	if len(req.Symbols) == 0 && req.Watchlist != "" {
+		watchlist, err := ic.watchlistService.GetWatchlist(req.Watchlist)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Watchlist not found",
+				"details": err.Error(),
+			})
+			return
+		}
+		req.Symbols = watchlist.Symbols
+	}
+
 	if len(req.Symbols) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "At least one symbol required",
+			"error": "At least one symbol or a watchlist required",
 		})
 		return
 	}
@@ -218,7 +405,18 @@ func (ic *IntelligenceController) HandleAnalyzeMultipleStocks(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	analyses, err := ic.stockAnalysisService.AnalyzeStocks(ctx, req.Symbols)
+	opts, err := ic.stockAnalysisService.AnalysisOptionsFor(req.Depth)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if req.IncludeNews != nil {
+		opts.IncludeNews = *req.IncludeNews
+	}
+
+	analyses, failures, err := ic.stockAnalysisService.AnalyzeStocksWith(ctx, req.Symbols, opts)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to analyze stocks",
@@ -227,9 +425,323 @@ func (ic *IntelligenceController) HandleAnalyzeMultipleStocks(c *gin.Context) {
 		return
 	}
 
+	failureDetails := make(map[string]string, len(failures))
+	for symbol, failure := range failures {
+		failureDetails[symbol] = failure.Error()
+	}
+
+	if c.Query("compact") == "true" {
+		compactAnalyses := make(map[string]services.StockAnalysisCompact, len(analyses))
+		for symbol, analysis := range analyses {
+			compactAnalyses[symbol] = analysis.ToCompact()
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"analyses":  compactAnalyses,
+			"count":     len(compactAnalyses),
+			"requested": len(req.Symbols),
+			"failures":  failureDetails,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"analyses":  analyses,
+		"count":     len(analyses),
+		"requested": len(req.Symbols),
+		"failures":  failureDetails,
+	})
+}
+
+// ScreenRequest is a symbol list (or watchlist) and the criteria to screen it with
+type ScreenRequest struct {
+	Symbols   []string                `json:"symbols"`
+	Watchlist string                  `json:"watchlist"` // Name of a saved watchlist, alternative to symbols
+	Criteria  services.ScreenCriteria `json:"criteria" binding:"required"`
+}
+
+// HandleScreen analyzes a symbol list and returns only the symbols meeting
+// the criteria, sorted by composite score
+// POST /api/v1/intelligence/screen
+func (ic *IntelligenceController) HandleScreen(c *gin.Context) {
+	var req ScreenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if len(req.Symbols) == 0 && req.Watchlist != "" {
+		watchlist, err := ic.watchlistService.GetWatchlist(req.Watchlist)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Watchlist not found",
+				"details": err.Error(),
+			})
+			return
+		}
+		req.Symbols = watchlist.Symbols
+	}
+
+	if len(req.Symbols) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "At least one symbol or a watchlist required",
+		})
+		return
+	}
+
+	if err := req.Criteria.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid criteria",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+	defer cancel()
+
+	result, err := ic.stockAnalysisService.Screen(ctx, req.Symbols, req.Criteria)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to screen stocks",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches":  result.Matches,
+		"count":    len(result.Matches),
+		"scanned":  result.Scanned,
+		"failures": result.Failures,
+	})
+}
+
+// HandleGetMarketBreadth computes quantitative breadth across a basket of symbols
+// GET /api/v1/intelligence/breadth?symbols=AAPL,MSFT,NVDA
+func (ic *IntelligenceController) HandleGetMarketBreadth(c *gin.Context) {
+	var symbols []string
+	if symbolsParam := c.Query("symbols"); symbolsParam != "" {
+		for _, symbol := range strings.Split(symbolsParam, ",") {
+			if symbol = strings.TrimSpace(symbol); symbol != "" {
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+
+	// Add timeout to prevent indefinite hangs
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	breadth, err := ic.breadthService.GetBreadth(ctx, symbols)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to compute market breadth",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, breadth)
+}
+
+// ApplySplitRequest is a known split to apply to the stored daily bars
+type ApplySplitRequest struct {
+	Ratio         float64 `json:"ratio" binding:"required,gt=0"`     // new shares per old share: 4 for 4-for-1, 0.1 for 1-for-10
+	EffectiveDate string  `json:"effective_date" binding:"required"` // first day trading split-adjusted, YYYY-MM-DD
+}
+
+// HandleApplySplit rescales the stored daily bars of a symbol for a split
+// before detection notices it
+// POST /api/v1/intelligence/splits/:symbol
+func (ic *IntelligenceController) HandleApplySplit(c *gin.Context) {
+	symbol := c.Param("symbol")
+
+	var req ApplySplitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Midnight UTC falls between the previous session's bar and the effective day's
+	effective, err := time.Parse("2006-01-02", req.EffectiveDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid effective_date",
+			"details": "use YYYY-MM-DD",
+		})
+		return
+	}
+
+	adjusted, err := ic.stockAnalysisService.ApplySplit(symbol, effective, req.Ratio)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to apply split",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":         symbol,
+		"ratio":          req.Ratio,
+		"effective_date": req.EffectiveDate,
+		"bars_adjusted":  adjusted,
+	})
+}
+
+// HandleBacktest replays the daily technical signal for a symbol over stored
+// bars, filling entries and exits at the next bar's open with the requested
+// slippage and fees, and checking stops and targets against each bar's range
+// POST /api/v1/intelligence/backtest
+func (ic *IntelligenceController) HandleBacktest(c *gin.Context) {
+	var req services.BacktestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := ic.stockAnalysisService.Backtest(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   "Failed to run backtest",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// HandleGetAnchoredVWAP returns VWAP anchored to an event date and the current price's deviation from it
+// GET /api/v1/intelligence/anchored-vwap/:symbol?anchor=2025-01-15&timeframe=1Day
+func (ic *IntelligenceController) HandleGetAnchoredVWAP(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	anchorParam := c.Query("anchor")
+	if anchorParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "anchor required (YYYY-MM-DD or RFC3339)",
+		})
+		return
+	}
+
+	anchor, err := time.Parse(time.RFC3339, anchorParam)
+	if err != nil {
+		anchor, err = time.Parse("2006-01-02", anchorParam)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid anchor",
+			"details": "use YYYY-MM-DD or RFC3339",
+		})
+		return
+	}
+
+	timeframe, err := services.NormalizeTimeframe(c.DefaultQuery("timeframe", "1Day"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timeframe",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := ic.analysisService.GetAnchoredVWAP(ctx, symbol, anchor, timeframe)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to calculate anchored VWAP",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":        symbol,
+		"timeframe":     timeframe,
+		"anchored_vwap": result,
+	})
+}
+
+// IndicatorsRequest selects the indicators to compute for a symbol
+type IndicatorsRequest struct {
+	Timeframe  string                   `json:"timeframe"`
+	Indicators []services.IndicatorSpec `json:"indicators" binding:"required,min=1"`
+}
+
+// HandleGetIndicators computes only the requested indicators for a symbol
+// POST /api/v1/intelligence/indicators/:symbol
+// {"timeframe": "1Day", "indicators": [{"name": "rsi"}, {"name": "bollinger", "period": 20, "params": {"std_dev": 2}}]}
+func (ic *IntelligenceController) HandleGetIndicators(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+
+	var req IndicatorsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     "Invalid request",
+			"details":   err.Error(),
+			"available": services.AvailableIndicators(),
+		})
+		return
+	}
+
+	if req.Timeframe == "" {
+		req.Timeframe = "1Day"
+	}
+	timeframe, err := services.NormalizeTimeframe(req.Timeframe)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid timeframe",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	// Reject bad specs before fetching any bars
+	if _, err := services.ComputeIndicators(nil, req.Indicators); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     "Invalid indicators",
+			"details":   err.Error(),
+			"available": services.AvailableIndicators(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	results, err := ic.analysisService.GetIndicators(ctx, symbol, timeframe, req.Indicators)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to compute indicators",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":     symbol,
+		"timeframe":  timeframe,
+		"indicators": results,
+	})
+}
+
+// HandleGetMetrics reports service usage metrics such as Gemini token spend
+// GET /metrics
+func (ic *IntelligenceController) HandleGetMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
-		"analyses": analyses,
-		"count":    len(analyses),
+		"gemini": ic.geminiService.GetUsageStats(),
 	})
 }
 
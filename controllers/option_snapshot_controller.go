@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"prophet-trader/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OptionSnapshotController serves stored option IV/greeks history
+type OptionSnapshotController struct {
+	recorder *services.OptionSnapshotRecorder
+}
+
+// NewOptionSnapshotController creates a new option snapshot controller
+func NewOptionSnapshotController(recorder *services.OptionSnapshotRecorder) *OptionSnapshotController {
+	return &OptionSnapshotController{
+		recorder: recorder,
+	}
+}
+
+// HandleGetContractSnapshots returns the snapshot series for one contract
+// GET /api/v1/options/snapshots/:symbol?start=2025-01-01&end=2025-01-31
+func (oc *OptionSnapshotController) HandleGetContractSnapshots(c *gin.Context) {
+	start, end, err := snapshotRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date range", "details": err.Error()})
+		return
+	}
+
+	symbol := c.Param("symbol")
+	snapshots, err := oc.recorder.History(symbol, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get option snapshots", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"symbol":    symbol,
+		"start":     start,
+		"end":       end,
+		"count":     len(snapshots),
+		"snapshots": snapshots,
+	})
+}
+
+// HandleGetUnderlyingSnapshots returns the snapshot series for every contract on an underlying
+// GET /api/v1/options/snapshots?underlying=AAPL&start=2025-01-01&end=2025-01-31
+func (oc *OptionSnapshotController) HandleGetUnderlyingSnapshots(c *gin.Context) {
+	underlying := c.Query("underlying")
+	if underlying == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "underlying is required"})
+		return
+	}
+
+	start, end, err := snapshotRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date range", "details": err.Error()})
+		return
+	}
+
+	snapshots, err := oc.recorder.UnderlyingHistory(underlying, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get option snapshots", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"underlying": underlying,
+		"start":      start,
+		"end":        end,
+		"count":      len(snapshots),
+		"snapshots":  snapshots,
+	})
+}
+
+// HandleCaptureSnapshots captures all tracked contracts immediately
+// POST /api/v1/options/snapshots/capture
+func (oc *OptionSnapshotController) HandleCaptureSnapshots(c *gin.Context) {
+	saved := oc.recorder.Capture(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{
+		"saved":   saved,
+		"tracked": oc.recorder.TrackedContracts(c.Request.Context()),
+	})
+}
+
+// snapshotRange parses start/end (YYYY-MM-DD) query params, defaulting to the
+// last 30 days. The end date is inclusive.
+func snapshotRange(c *gin.Context) (time.Time, time.Time, error) {
+	end := time.Now()
+	start := end.AddDate(0, 0, -30)
+
+	if s := c.Query("start"); s != "" {
+		t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+		if err != nil {
+			return start, end, fmt.Errorf("start must be YYYY-MM-DD")
+		}
+		start = t
+	}
+	if s := c.Query("end"); s != "" {
+		t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+		if err != nil {
+			return start, end, fmt.Errorf("end must be YYYY-MM-DD")
+		}
+		end = t.AddDate(0, 0, 1)
+	}
+
+	if !end.After(start) {
+		return start, end, fmt.Errorf("end must not be before start")
+	}
+	return start, end, nil
+}
@@ -2,9 +2,15 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"math"
+	"prophet-trader/apperrors"
 	"prophet-trader/interfaces"
+	"prophet-trader/services"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,10 +19,14 @@ import (
 
 // OrderController handles trading operations
 type OrderController struct {
-	tradingService interfaces.TradingService
-	dataService    interfaces.DataService
-	storageService interfaces.StorageService
-	logger         *logrus.Logger
+	tradingService   interfaces.TradingService
+	dataService      interfaces.DataService
+	storageService   interfaces.StorageService
+	logger           *logrus.Logger
+	defaultTimeframe string
+	assets           *services.AssetCache // shortability for sells that open a short (nil = unchecked)
+	liquidity        services.OptionLiquidityGate
+	chains           *services.OptionsChainCache
 }
 
 // NewOrderController creates a new order controller
@@ -31,13 +41,44 @@ func NewOrderController(
 	})
 
 	return &OrderController{
-		tradingService: trading,
-		dataService:    data,
-		storageService: storage,
-		logger:         logger,
+		tradingService:   trading,
+		dataService:      data,
+		storageService:   storage,
+		logger:           logger,
+		defaultTimeframe: "1Day",
+		chains:           services.NewOptionsChainCache(trading, -1, -1),
 	}
 }
 
+// SetDefaultTimeframe sets the timeframe used when a bars request doesn't specify one
+func (oc *OrderController) SetDefaultTimeframe(timeframe string) error {
+	canonical, err := services.NormalizeTimeframe(timeframe)
+	if err != nil {
+		return err
+	}
+	oc.defaultTimeframe = canonical
+	return nil
+}
+
+// SetAssetCache enables the shortability check on sells that open a short
+func (oc *OrderController) SetAssetCache(assets *services.AssetCache) {
+	oc.assets = assets
+}
+
+// SetOptionsLiquidityGate sets the liquidity gate applied to options chain results
+func (oc *OrderController) SetOptionsLiquidityGate(gate services.OptionLiquidityGate) error {
+	if err := gate.Validate(); err != nil {
+		return err
+	}
+	oc.liquidity = gate
+	return nil
+}
+
+// SetOptionsChainCache replaces the cache options chain requests are served from
+func (oc *OrderController) SetOptionsChainCache(cache *services.OptionsChainCache) {
+	oc.chains = cache
+}
+
 // BuyRequest represents a buy order request
 type BuyRequest struct {
 	Symbol      string   `json:"symbol" binding:"required"`
@@ -46,6 +87,7 @@ type BuyRequest struct {
 	TimeInForce string   `json:"time_in_force"` // "day", "gtc", "ioc", "fok"
 	LimitPrice  *float64 `json:"limit_price,omitempty"`
 	StopPrice   *float64 `json:"stop_price,omitempty"`
+	Intent      string   `json:"intent,omitempty"` // "open" or "close"; inferred from positions when empty
 }
 
 // SellRequest represents a sell order request
@@ -56,6 +98,7 @@ type SellRequest struct {
 	TimeInForce string   `json:"time_in_force"` // "day", "gtc", "ioc", "fok"
 	LimitPrice  *float64 `json:"limit_price,omitempty"`
 	StopPrice   *float64 `json:"stop_price,omitempty"`
+	Intent      string   `json:"intent,omitempty"` // "open" (sell short) or "close" (sell a long); inferred when empty
 }
 
 // Buy executes a buy order
@@ -68,10 +111,16 @@ func (oc *OrderController) Buy(ctx context.Context, req BuyRequest) (*interfaces
 		req.TimeInForce = "day"
 	}
 
+	intent, err := oc.resolveIntent(ctx, req.Symbol, "buy", req.Qty, req.Intent)
+	if err != nil {
+		return nil, err
+	}
+
 	oc.logger.WithFields(logrus.Fields{
 		"symbol": req.Symbol,
 		"qty":    req.Qty,
 		"type":   req.Type,
+		"intent": intent,
 	}).Info("Processing buy order")
 
 	order := &interfaces.Order{
@@ -84,6 +133,7 @@ func (oc *OrderController) Buy(ctx context.Context, req BuyRequest) (*interfaces
 		StopPrice:   req.StopPrice,
 		Status:      "pending",
 		SubmittedAt: time.Now(),
+		Intent:      intent,
 	}
 
 	// Place the order
@@ -114,10 +164,21 @@ func (oc *OrderController) Sell(ctx context.Context, req SellRequest) (*interfac
 		req.TimeInForce = "day"
 	}
 
+	intent, err := oc.resolveIntent(ctx, req.Symbol, "sell", req.Qty, req.Intent)
+	if err != nil {
+		return nil, err
+	}
+	if intent == services.OrderIntentOpen {
+		if err := oc.checkShortable(ctx, req.Symbol); err != nil {
+			return nil, err
+		}
+	}
+
 	oc.logger.WithFields(logrus.Fields{
 		"symbol": req.Symbol,
 		"qty":    req.Qty,
 		"type":   req.Type,
+		"intent": intent,
 	}).Info("Processing sell order")
 
 	order := &interfaces.Order{
@@ -130,6 +191,7 @@ func (oc *OrderController) Sell(ctx context.Context, req SellRequest) (*interfac
 		StopPrice:   req.StopPrice,
 		Status:      "pending",
 		SubmittedAt: time.Now(),
+		Intent:      intent,
 	}
 
 	// Place the order
@@ -150,6 +212,39 @@ func (oc *OrderController) Sell(ctx context.Context, req SellRequest) (*interfac
 	return result, nil
 }
 
+// resolveIntent works out whether the order opens or closes a position. If
+// positions can't be fetched a requested intent is used as given, otherwise
+// the intent is left unknown.
+func (oc *OrderController) resolveIntent(ctx context.Context, symbol, side string, qty float64, requested string) (string, error) {
+	positions, err := oc.tradingService.GetPositions(ctx)
+	if err != nil {
+		oc.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to get positions, order intent not verified")
+		if requested != "" && requested != services.OrderIntentOpen && requested != services.OrderIntentClose {
+			return "", apperrors.Validation("invalid intent %q: use open or close", requested)
+		}
+		return requested, nil
+	}
+
+	return services.ResolveOrderIntent(positions, symbol, side, qty, requested)
+}
+
+// checkShortable rejects a short sale the broker won't lend shares for
+func (oc *OrderController) checkShortable(ctx context.Context, symbol string) error {
+	if oc.assets == nil {
+		return nil
+	}
+
+	info, err := oc.assets.Get(ctx, symbol)
+	if err != nil {
+		oc.logger.WithError(err).WithField("symbol", symbol).Warn("Shortability check unavailable, allowing short sale")
+		return nil
+	}
+	if !info.Tradable || !info.Shortable || !info.EasyToBorrow {
+		return apperrors.Validation("%s cannot be sold short: the broker reports it as not shortable or not easy to borrow", symbol)
+	}
+	return nil
+}
+
 // QuickBuy executes a simple market buy order
 func (oc *OrderController) QuickBuy(symbol string, qty float64) (*interfaces.OrderResult, error) {
 	return oc.Buy(context.Background(), BuyRequest{
@@ -213,7 +308,7 @@ func (oc *OrderController) HandleBuy(c *gin.Context) {
 
 	result, err := oc.Buy(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -230,7 +325,7 @@ func (oc *OrderController) HandleSell(c *gin.Context) {
 
 	result, err := oc.Sell(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -246,18 +341,76 @@ func (oc *OrderController) HandleCancelOrder(c *gin.Context) {
 	}
 
 	if err := oc.CancelOrder(orderID); err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(200, gin.H{"message": "Order canceled successfully"})
 }
 
+// HandleGetOpenOrders lists all open orders at the broker
+// GET /api/v1/orders/open
+func (oc *OrderController) HandleGetOpenOrders(c *gin.Context) {
+	orders, err := oc.tradingService.ListOrders(c.Request.Context(), "open")
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"orders": orders,
+		"count":  len(orders),
+	})
+}
+
+// CancelOrderResult is the outcome of cancelling one order in a bulk cancel
+type CancelOrderResult struct {
+	OrderID  string `json:"order_id"`
+	Symbol   string `json:"symbol"`
+	Canceled bool   `json:"canceled"`
+	Error    string `json:"error,omitempty"`
+}
+
+// HandleCancelAllOrders cancels every open order at the broker, continuing
+// past individual failures
+// POST /api/v1/orders/cancel-all
+func (oc *OrderController) HandleCancelAllOrders(c *gin.Context) {
+	orders, err := oc.tradingService.ListOrders(c.Request.Context(), "open")
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
+		return
+	}
+
+	results := make([]CancelOrderResult, 0, len(orders))
+	canceled := 0
+	for _, order := range orders {
+		result := CancelOrderResult{OrderID: order.ID, Symbol: order.Symbol}
+		if err := oc.CancelOrder(order.ID); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Canceled = true
+			canceled++
+		}
+		results = append(results, result)
+	}
+
+	oc.logger.WithFields(logrus.Fields{
+		"canceled": canceled,
+		"failed":   len(orders) - canceled,
+	}).Warn("Canceled all open orders")
+
+	c.JSON(200, gin.H{
+		"results":  results,
+		"canceled": canceled,
+		"failed":   len(orders) - canceled,
+	})
+}
+
 // HandleGetPositions handles HTTP get positions requests
 func (oc *OrderController) HandleGetPositions(c *gin.Context) {
 	positions, err := oc.GetPositions()
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -268,25 +421,131 @@ func (oc *OrderController) HandleGetPositions(c *gin.Context) {
 func (oc *OrderController) HandleGetAccount(c *gin.Context) {
 	account, err := oc.GetAccount()
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(200, account)
 }
 
+// HandleSaveAccountSnapshot fetches the account and persists a snapshot on demand
+// POST /api/v1/account/snapshot
+func (oc *OrderController) HandleSaveAccountSnapshot(c *gin.Context) {
+	account, err := oc.tradingService.GetAccount(c.Request.Context())
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
+		return
+	}
+
+	snapshot, err := oc.storageService.SaveAccountSnapshot(account)
+	if err != nil {
+		oc.logger.WithError(err).Error("Failed to save account snapshot")
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
+		return
+	}
+
+	oc.logger.WithField("snapshot_time", snapshot.SnapshotTime).Info("Account snapshot saved")
+	c.JSON(200, gin.H{
+		"snapshot":      snapshot,
+		"snapshot_time": snapshot.SnapshotTime,
+	})
+}
+
+// Order list page sizes
+const (
+	defaultOrdersPageSize = 50
+	maxOrdersPageSize     = 500
+)
+
 // HandleGetOrders handles HTTP get orders requests
+// GET /api/v1/orders?status=&symbol=&position_id=&source=broker&limit=50&offset=0
+// Orders come from the broker, whose statuses are current. Pass source=local
+// to page the stored order log instead; position_id always reads it, since
+// only stored orders are linked to managed positions. Stored statuses are
+// written at submit time and updated on cancels and managed-order fills, so
+// they can lag the broker.
 func (oc *OrderController) HandleGetOrders(c *gin.Context) {
-	status := c.Query("status")
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultOrdersPageSize)))
+	if err != nil || limit <= 0 {
+		c.JSON(400, gin.H{"error": "limit must be a positive integer"})
+		return
+	}
+	if limit > maxOrdersPageSize {
+		limit = maxOrdersPageSize
+	}
 
-	ctx := context.Background()
-	orders, err := oc.tradingService.ListOrders(ctx, status)
-	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(400, gin.H{"error": "offset must be a non-negative integer"})
+		return
+	}
+
+	source := c.DefaultQuery("source", "broker")
+	if source != "broker" && source != "local" {
+		c.JSON(400, gin.H{"error": "source must be 'broker' or 'local'"})
 		return
 	}
 
-	c.JSON(200, orders)
+	filter := interfaces.OrderFilter{
+		Status:     c.Query("status"),
+		Symbol:     strings.ToUpper(c.Query("symbol")),
+		PositionID: c.Query("position_id"),
+		Limit:      limit,
+		Offset:     offset,
+	}
+
+	var orders []*interfaces.Order
+	var total int64
+
+	if source == "broker" && filter.PositionID == "" {
+		ctx := context.Background()
+		all, err := oc.tradingService.ListOrders(ctx, filter.Status)
+		if err != nil {
+			c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
+			return
+		}
+		orders, total = pageOrders(all, filter)
+	} else {
+		orders, total, err = oc.storageService.GetOrdersFiltered(filter)
+		if err != nil {
+			c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"orders":   orders,
+		"total":    total,
+		"limit":    limit,
+		"offset":   offset,
+		"has_more": int64(offset+len(orders)) < total,
+	})
+}
+
+// pageOrders applies a filter's symbol and paging to an in-memory order list
+func pageOrders(all []*interfaces.Order, filter interfaces.OrderFilter) ([]*interfaces.Order, int64) {
+	matched := make([]*interfaces.Order, 0, len(all))
+	for _, order := range all {
+		if filter.Symbol != "" && order.Symbol != filter.Symbol {
+			continue
+		}
+		if filter.PositionID != "" && order.PositionID != filter.PositionID {
+			continue
+		}
+		matched = append(matched, order)
+	}
+
+	total := int64(len(matched))
+	if filter.Offset >= len(matched) {
+		return []*interfaces.Order{}, total
+	}
+
+	end := filter.Offset + filter.Limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	return matched[filter.Offset:end], total
 }
 
 // HandleGetQuote handles HTTP get quote requests
@@ -301,7 +560,7 @@ func (oc *OrderController) HandleGetQuote(c *gin.Context) {
 	ctx := context.Background()
 	quote, err := oc.dataService.GetLatestQuote(ctx, symbol)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -320,15 +579,17 @@ func (oc *OrderController) HandleGetBar(c *gin.Context) {
 	ctx := context.Background()
 	bar, err := oc.dataService.GetLatestBar(ctx, symbol)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(200, bar)
 }
 
-// HandleGetBars handles HTTP get historical bars requests
-// GET /api/v1/market/bars/:symbol?start=2025-01-01&end=2025-01-10&timeframe=1D
+// HandleGetBars handles HTTP get historical bars requests. start and end take
+// a date or an RFC3339 time. stream=true streams the bars as newline-delimited
+// JSON instead of one aggregated response (see streamBars).
+// GET /api/v1/market/bars/:symbol?start=2025-01-01&end=2025-01-10&timeframe=1Day&stream=true
 func (oc *OrderController) HandleGetBars(c *gin.Context) {
 	symbol := c.Param("symbol")
 	if symbol == "" {
@@ -339,28 +600,40 @@ func (oc *OrderController) HandleGetBars(c *gin.Context) {
 	// Parse query parameters
 	startStr := c.Query("start")
 	endStr := c.Query("end")
-	timeframe := c.DefaultQuery("timeframe", "1D")
+	timeframe, err := services.NormalizeTimeframe(c.DefaultQuery("timeframe", oc.defaultTimeframe))
+	if err != nil {
+		c.JSON(400, gin.H{
+			"error":   err.Error(),
+			"allowed": services.AllowedTimeframes(),
+		})
+		return
+	}
 
 	// Default to last 30 days if not specified
 	end := time.Now()
 	start := end.AddDate(0, 0, -30)
 
 	if startStr != "" {
-		if t, err := time.Parse("2006-01-02", startStr); err == nil {
+		if t, err := parseBarTime(startStr); err == nil {
 			start = t
 		}
 	}
 
 	if endStr != "" {
-		if t, err := time.Parse("2006-01-02", endStr); err == nil {
+		if t, err := parseBarTime(endStr); err == nil {
 			end = t
 		}
 	}
 
+	if c.Query("stream") == "true" {
+		oc.streamBars(c, symbol, start, end, timeframe)
+		return
+	}
+
 	ctx := context.Background()
 	bars, err := oc.dataService.GetHistoricalBars(ctx, symbol, start, end, timeframe)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -374,6 +647,57 @@ func (oc *OrderController) HandleGetBars(c *gin.Context) {
 	})
 }
 
+// parseBarTime parses a bars range bound given as a date or an RFC3339 time
+func parseBarTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// barStreamEnd is the last line of a streamed bars response. ResumeFrom is the
+// timestamp of the last bar sent; a client cut off (or seeing an error) can
+// request the rest with start set to it, skipping the first bar it gets back.
+type barStreamEnd struct {
+	Done       bool       `json:"done"`
+	Count      int        `json:"count"`
+	ResumeFrom *time.Time `json:"resume_from,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// streamBars writes bars as newline-delimited JSON, one bar per line, fetched
+// and flushed window by window so large ranges never sit in memory. The final
+// line is a barStreamEnd. Errors after the first bar can't change the status
+// code and are reported there instead.
+func (oc *OrderController) streamBars(c *gin.Context, symbol string, start, end time.Time, timeframe string) {
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(200)
+
+	encoder := json.NewEncoder(c.Writer)
+	summary := barStreamEnd{}
+	err := services.StreamBars(c.Request.Context(), oc.dataService, symbol, start, end, timeframe, func(bars []*interfaces.Bar) error {
+		for _, bar := range bars {
+			if err := encoder.Encode(bar); err != nil {
+				return err
+			}
+		}
+		summary.Count += len(bars)
+		resumeFrom := bars[len(bars)-1].Timestamp
+		summary.ResumeFrom = &resumeFrom
+		c.Writer.Flush()
+		return nil
+	})
+
+	if err != nil {
+		oc.logger.WithError(err).WithField("symbol", symbol).Warn("Bar stream ended early")
+		summary.Error = err.Error()
+	} else {
+		summary.Done = true
+	}
+	encoder.Encode(summary)
+	c.Writer.Flush()
+}
+
 // OptionsOrderRequest represents an options order request
 type OptionsOrderRequest struct {
 	Symbol        string   `json:"symbol" binding:"required"`
@@ -426,7 +750,7 @@ func (oc *OrderController) PlaceOptionsOrder(c *gin.Context) {
 	result, err := oc.tradingService.PlaceOptionsOrder(ctx, order)
 	if err != nil {
 		oc.logger.WithError(err).Error("Failed to place options order")
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -442,7 +766,7 @@ func (oc *OrderController) GetOptionsPosition(c *gin.Context) {
 
 	position, err := oc.tradingService.GetOptionsPosition(ctx, symbol)
 	if err != nil {
-		c.JSON(404, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 404), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -456,7 +780,7 @@ func (oc *OrderController) ListOptionsPositions(c *gin.Context) {
 
 	positions, err := oc.tradingService.ListOptionsPositions(ctx)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -464,6 +788,11 @@ func (oc *OrderController) ListOptionsPositions(c *gin.Context) {
 }
 
 // GetOptionsChain handles GET /api/options/chain/:symbol?expiration=2025-11-22&delta_min=0.4&delta_max=0.6&min_bid=0.1
+// Instead of an explicit expiration, expiration_mode picks one of the listed expirations:
+// nearest, next_weekly (default), next_monthly or nearest_to_dte=N
+// liquidity=off|exclude|flag overrides how the configured liquidity gate treats illiquid contracts.
+// compact=true returns only the fields needed to pick a contract.
+// Chains are cached briefly per expiration and filtered from the cache; refresh=true refetches.
 func (oc *OrderController) GetOptionsChain(c *gin.Context) {
 	symbol := c.Param("symbol")
 	if symbol == "" {
@@ -471,31 +800,30 @@ func (oc *OrderController) GetOptionsChain(c *gin.Context) {
 		return
 	}
 
-	// Get expiration date from query parameter
-	expirationStr := c.Query("expiration")
-	var expiration time.Time
-	var err error
-
-	if expirationStr != "" {
-		expiration, err = time.Parse("2006-01-02", expirationStr)
-		if err != nil {
-			c.JSON(400, gin.H{"error": "invalid expiration date format, use YYYY-MM-DD"})
+	gate := oc.liquidity
+	if mode := c.Query("liquidity"); mode != "" {
+		gate.Mode = mode
+		if err := gate.Validate(); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
 			return
 		}
-	} else {
-		// Default to next Friday (typical weekly options expiration)
-		expiration = getNextFriday()
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	chain, err := oc.tradingService.GetOptionsChain(ctx, symbol, expiration)
+	expiration, mode, ok := oc.chainExpiration(ctx, c, symbol)
+	if !ok {
+		return
+	}
+
+	result, err := oc.chains.Get(ctx, symbol, expiration, c.Query("refresh") == "true")
 	if err != nil {
 		oc.logger.WithError(err).Error("Failed to get options chain")
-		c.JSON(500, gin.H{"error": err.Error()})
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
 		return
 	}
+	chain := result.Contracts
 
 	// Apply filters for token efficiency
 	filtered := make([]*interfaces.OptionContract, 0)
@@ -562,15 +890,175 @@ func (oc *OrderController) GetOptionsChain(c *gin.Context) {
 		filtered = append(filtered, contract)
 	}
 
+	// Drop or flag contracts too thin to exit at a fair price
+	filtered, illiquid := gate.Apply(filtered, true)
+
+	var contracts interface{} = filtered
+	if c.Query("compact") == "true" {
+		compactContracts := make([]interfaces.OptionContractCompact, len(filtered))
+		for i, contract := range filtered {
+			compactContracts[i] = contract.ToCompact()
+		}
+		contracts = compactContracts
+	}
+
+	c.JSON(200, gin.H{
+		"symbol":          symbol,
+		"expiration":      expiration.Format("2006-01-02"),
+		"expiration_mode": mode,
+		"dte":             daysToExpiration(expiration, time.Now()),
+		"total":           len(chain),
+		"filtered":        len(filtered),
+		"illiquid":        illiquid,
+		"liquidity_mode":  gate.Mode,
+		"cached":          result.Cached,
+		"stale":           result.Stale,
+		"fetched_at":      result.FetchedAt,
+		"contracts":       contracts,
+	})
+}
+
+// chainExpiration resolves the expiration an options chain request asks for,
+// from the expiration date or expiration_mode query parameter (default
+// next_weekly). It writes the error response and returns false on failure.
+func (oc *OrderController) chainExpiration(ctx context.Context, c *gin.Context, symbol string) (time.Time, string, bool) {
+	expirationStr := c.Query("expiration")
+	mode := c.Query("expiration_mode")
+
+	if expirationStr != "" {
+		expiration, err := time.Parse("2006-01-02", expirationStr)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "invalid expiration date format, use YYYY-MM-DD"})
+			return time.Time{}, "", false
+		}
+		return expiration, "explicit", true
+	}
+
+	if mode == "" {
+		mode = "next_weekly"
+	}
+	if _, _, err := parseExpirationMode(mode); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return time.Time{}, "", false
+	}
+
+	expirations, err := oc.tradingService.GetOptionExpirations(ctx, symbol)
+	if err != nil {
+		// Fall back to next Friday (typical weekly options expiration)
+		oc.logger.WithError(err).Warn("Failed to get option expirations, defaulting to next Friday")
+		return getNextFriday(), "next_friday", true
+	}
+
+	expiration, err := selectExpiration(expirations, mode, time.Now())
+	if err != nil {
+		c.JSON(404, gin.H{"error": err.Error()})
+		return time.Time{}, "", false
+	}
+	return expiration, mode, true
+}
+
+// GetOptionsChainTable returns an options chain as an interfaces.OptionChain:
+// calls and puts separated and sorted by strike, with the underlying's
+// current price, for rendering a standard option table. It takes the same
+// expiration, expiration_mode, liquidity and refresh parameters as
+// GetOptionsChain but applies no delta, bid or type filters.
+func (oc *OrderController) GetOptionsChainTable(c *gin.Context) {
+	symbol := strings.ToUpper(c.Param("symbol"))
+	if symbol == "" {
+		c.JSON(400, gin.H{"error": "symbol required"})
+		return
+	}
+
+	gate := oc.liquidity
+	if mode := c.Query("liquidity"); mode != "" {
+		gate.Mode = mode
+		if err := gate.Validate(); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	expiration, mode, ok := oc.chainExpiration(ctx, c, symbol)
+	if !ok {
+		return
+	}
+
+	result, err := oc.chains.Get(ctx, symbol, expiration, c.Query("refresh") == "true")
+	if err != nil {
+		oc.logger.WithError(err).Error("Failed to get options chain")
+		c.JSON(apperrors.HTTPStatus(err, 500), gin.H{"error": err.Error()})
+		return
+	}
+	contracts, illiquid := gate.Apply(result.Contracts, true)
+
+	// The table is still useful without the underlying price, so a failed
+	// quote only leaves it at zero
+	underlyingPrice := 0.0
+	if quote, err := oc.dataService.GetLatestQuote(ctx, symbol); err != nil {
+		oc.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to get underlying quote for options chain")
+	} else {
+		underlyingPrice = quoteMid(quote)
+	}
+
 	c.JSON(200, gin.H{
-		"symbol":     symbol,
-		"expiration": expiration.Format("2006-01-02"),
-		"total":      len(chain),
-		"filtered":   len(filtered),
-		"contracts":  filtered,
+		"symbol":          symbol,
+		"expiration":      expiration.Format("2006-01-02"),
+		"expiration_mode": mode,
+		"dte":             daysToExpiration(expiration, time.Now()),
+		"illiquid":        illiquid,
+		"liquidity_mode":  gate.Mode,
+		"cached":          result.Cached,
+		"stale":           result.Stale,
+		"fetched_at":      result.FetchedAt,
+		"chain":           buildOptionChain(symbol, underlyingPrice, contracts),
 	})
 }
 
+// buildOptionChain splits contracts into calls and puts, each sorted by strike
+func buildOptionChain(symbol string, underlyingPrice float64, contracts []*interfaces.OptionContract) *interfaces.OptionChain {
+	chain := &interfaces.OptionChain{
+		UnderlyingSymbol: symbol,
+		UnderlyingPrice:  underlyingPrice,
+		Timestamp:        time.Now(),
+		Calls:            make([]*interfaces.OptionContract, 0),
+		Puts:             make([]*interfaces.OptionContract, 0),
+	}
+
+	for _, contract := range contracts {
+		switch contract.ContractType {
+		case "call":
+			chain.Calls = append(chain.Calls, contract)
+		case "put":
+			chain.Puts = append(chain.Puts, contract)
+		}
+	}
+
+	byStrike := func(side []*interfaces.OptionContract) {
+		sort.SliceStable(side, func(i, j int) bool {
+			return side[i].StrikePrice < side[j].StrikePrice
+		})
+	}
+	byStrike(chain.Calls)
+	byStrike(chain.Puts)
+
+	return chain
+}
+
+// quoteMid returns the midpoint of a quote, or whichever side is quoted
+func quoteMid(quote *interfaces.Quote) float64 {
+	switch {
+	case quote.BidPrice > 0 && quote.AskPrice > 0:
+		return math.Round((quote.BidPrice+quote.AskPrice)/2*100) / 100
+	case quote.AskPrice > 0:
+		return quote.AskPrice
+	default:
+		return quote.BidPrice
+	}
+}
+
 // getNextFriday returns the date of the next Friday
 func getNextFriday() time.Time {
 	now := time.Now()
@@ -579,4 +1067,115 @@ func getNextFriday() time.Time {
 		daysUntilFriday = 7 // If today is Friday, get next Friday
 	}
 	return now.AddDate(0, 0, daysUntilFriday)
-}
\ No newline at end of file
+}
+
+// parseExpirationMode validates an expiration_mode value and extracts the target
+// days to expiration for nearest_to_dte=N
+func parseExpirationMode(mode string) (name string, targetDTE int, err error) {
+	switch mode {
+	case "nearest", "next_weekly", "next_monthly":
+		return mode, 0, nil
+	}
+
+	if value, ok := strings.CutPrefix(mode, "nearest_to_dte="); ok {
+		targetDTE, err = strconv.Atoi(value)
+		if err != nil || targetDTE < 0 {
+			return "", 0, fmt.Errorf("invalid nearest_to_dte value %q, must be a non-negative integer", value)
+		}
+		return "nearest_to_dte", targetDTE, nil
+	}
+
+	return "", 0, fmt.Errorf("invalid expiration_mode %q: use nearest, next_weekly, next_monthly or nearest_to_dte=N", mode)
+}
+
+// selectExpiration picks an expiration from the available (ascending) dates:
+//   - nearest: the first expiration today or later
+//   - next_weekly: the first weekly expiration after today (the last listed expiration of its week)
+//   - next_monthly: the first standard monthly expiration (third Friday, or the Thursday before
+//     when that Friday is a holiday)
+//   - nearest_to_dte=N: the expiration whose days to expiration is closest to N, earliest on ties
+func selectExpiration(expirations []time.Time, mode string, now time.Time) (time.Time, error) {
+	name, targetDTE, err := parseExpirationMode(mode)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	today := now.Format("2006-01-02")
+	listed := make(map[string]bool, len(expirations))
+	upcoming := make([]time.Time, 0, len(expirations))
+	for _, expiration := range expirations {
+		listed[expiration.Format("2006-01-02")] = true
+		if expiration.Format("2006-01-02") >= today {
+			upcoming = append(upcoming, expiration)
+		}
+	}
+	if len(upcoming) == 0 {
+		return time.Time{}, fmt.Errorf("no upcoming option expirations available")
+	}
+
+	switch name {
+	case "nearest":
+		return upcoming[0], nil
+
+	case "next_weekly":
+		for i, expiration := range upcoming {
+			if expiration.Format("2006-01-02") == today {
+				continue
+			}
+			year, week := expiration.ISOWeek()
+			if i+1 < len(upcoming) {
+				nextYear, nextWeek := upcoming[i+1].ISOWeek()
+				if nextYear == year && nextWeek == week {
+					continue
+				}
+			}
+			return expiration, nil
+		}
+
+	case "next_monthly":
+		for _, expiration := range upcoming {
+			thirdFriday := thirdFridayOf(expiration)
+			if sameDate(expiration, thirdFriday) {
+				return expiration, nil
+			}
+			if sameDate(expiration, thirdFriday.AddDate(0, 0, -1)) && !listed[thirdFriday.Format("2006-01-02")] {
+				return expiration, nil
+			}
+		}
+
+	case "nearest_to_dte":
+		best := upcoming[0]
+		bestDiff := math.MaxInt
+		for _, expiration := range upcoming {
+			diff := daysToExpiration(expiration, now) - targetDTE
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff < bestDiff {
+				best, bestDiff = expiration, diff
+			}
+		}
+		return best, nil
+	}
+
+	return time.Time{}, fmt.Errorf("no expiration available for mode %s", mode)
+}
+
+// thirdFridayOf returns the third Friday of the month containing t
+func thirdFridayOf(t time.Time) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	offset := (int(time.Friday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+14)
+}
+
+// sameDate reports whether two times fall on the same calendar date
+func sameDate(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
+
+// daysToExpiration returns whole calendar days from now until the expiration date
+func daysToExpiration(expiration, now time.Time) int {
+	today, _ := time.Parse("2006-01-02", now.Format("2006-01-02"))
+	expiry, _ := time.Parse("2006-01-02", expiration.Format("2006-01-02"))
+	return int(expiry.Sub(today).Hours() / 24)
+}
@@ -1,8 +1,14 @@
 package controllers
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"prophet-trader/apperrors"
+	"prophet-trader/database"
 	"prophet-trader/services"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -33,21 +39,75 @@ func (pmc *PositionManagementController) HandlePlaceManagedPosition(c *gin.Conte
 
 	position, err := pmc.positionManager.PlaceManagedPosition(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(apperrors.HTTPStatus(err, http.StatusInternalServerError), gin.H{
 			"error":   "Failed to place managed position",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message":  "Managed position created successfully",
 		"position": position,
+	}
+	if position.StopLossLimitOffset > 0 {
+		response["warning"] = services.StopLimitWarning
+	}
+	if position.TakeProfitType == services.TakeProfitTypeStop || position.TakeProfitType == services.TakeProfitTypeStopLimit {
+		response["take_profit_warning"] = services.TakeProfitTouchWarning
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BatchPlacementRequest opens several managed positions in one call
+type BatchPlacementRequest struct {
+	Mode      string                                  `json:"mode" binding:"omitempty,oneof=all_or_none best_effort"` // default all_or_none
+	Positions []*services.PlaceManagedPositionRequest `json:"positions" binding:"required,min=1,max=50,dive"`
+}
+
+// HandlePlaceManagedPositions places a basket of managed positions
+// POST /api/v1/positions/managed/batch
+func (pmc *PositionManagementController) HandlePlaceManagedPositions(c *gin.Context) {
+	var req BatchPlacementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	result, err := pmc.positionManager.PlaceManagedPositions(c.Request.Context(), req.Positions, req.Mode)
+	if err != nil {
+		if result == nil {
+			c.JSON(apperrors.HTTPStatus(err, http.StatusInternalServerError), gin.H{
+				"error":   "Failed to place batch",
+				"details": err.Error(),
+			})
+			return
+		}
+		// Rejected as a whole: report why each request failed
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":   "Batch rejected",
+			"details": err.Error(),
+			"batch":   result,
+		})
+		return
+	}
+
+	status := http.StatusOK
+	if result.Placed == 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, gin.H{
+		"message": fmt.Sprintf("Placed %d of %d positions", result.Placed, len(req.Positions)),
+		"batch":   result,
 	})
 }
 
 // HandleGetManagedPosition retrieves a specific managed position
-// GET /api/v1/positions/managed/:id
+// GET /api/v1/positions/managed/:id?compact=true
 func (pmc *PositionManagementController) HandleGetManagedPosition(c *gin.Context) {
 	positionID := c.Param("id")
 	if positionID == "" {
@@ -59,29 +119,78 @@ func (pmc *PositionManagementController) HandleGetManagedPosition(c *gin.Context
 
 	position, err := pmc.positionManager.GetManagedPosition(positionID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(apperrors.HTTPStatus(err, http.StatusNotFound), gin.H{
 			"error":   "Position not found",
 			"details": err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, position)
+	if c.Query("compact") == "true" {
+		c.JSON(http.StatusOK, position.ToCompact())
+		return
+	}
+
+	c.JSON(http.StatusOK, position.ToResponse())
 }
 
 // HandleListManagedPositions lists all managed positions
-// GET /api/v1/positions/managed?status=ACTIVE
+// GET /api/v1/positions/managed?status=ACTIVE&compact=true
 func (pmc *PositionManagementController) HandleListManagedPositions(c *gin.Context) {
 	status := c.Query("status")
 
 	positions := pmc.positionManager.ListManagedPositions(status)
 
+	if c.Query("compact") == "true" {
+		c.JSON(http.StatusOK, gin.H{
+			"count":     len(positions),
+			"positions": services.ToCompactPositions(positions),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"count":     len(positions),
-		"positions": positions,
+		"positions": services.ToPositionResponses(positions),
 	})
 }
 
+// HandleGetTradeStats returns closed-trade performance overall and per strategy
+// GET /api/v1/positions/trades/stats?strategy=SWING_TRADE&symbol=AAPL&since=2025-01-01&until=2025-02-01&include_simulated=false
+func (pmc *PositionManagementController) HandleGetTradeStats(c *gin.Context) {
+	filter := database.TradeFilter{
+		Strategy:         strings.ToUpper(c.Query("strategy")),
+		Symbol:           strings.ToUpper(c.Query("symbol")),
+		IncludeSimulated: c.Query("include_simulated") == "true",
+	}
+
+	for param, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("%s must be a date (YYYY-MM-DD)", param),
+			})
+			return
+		}
+		*target = t
+	}
+
+	report, err := pmc.positionManager.GetTradeStats(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to compute trade stats",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // HandleCloseManagedPosition manually closes a managed position
 // DELETE /api/v1/positions/managed/:id
 func (pmc *PositionManagementController) HandleCloseManagedPosition(c *gin.Context) {
@@ -94,7 +203,7 @@ func (pmc *PositionManagementController) HandleCloseManagedPosition(c *gin.Conte
 	}
 
 	if err := pmc.positionManager.CloseManagedPosition(c.Request.Context(), positionID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(apperrors.HTTPStatus(err, http.StatusInternalServerError), gin.H{
 			"error":   "Failed to close position",
 			"details": err.Error(),
 		})
@@ -105,3 +214,233 @@ func (pmc *PositionManagementController) HandleCloseManagedPosition(c *gin.Conte
 		"message": "Position closed successfully",
 	})
 }
+
+// HandleUpdateTrailingStop enables or disables trailing on a managed position
+// PUT /api/v1/positions/managed/:id/trailing
+func (pmc *PositionManagementController) HandleUpdateTrailingStop(c *gin.Context) {
+	positionID := c.Param("id")
+	if positionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "position ID required",
+		})
+		return
+	}
+
+	var req services.UpdateTrailingStopRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	position, err := pmc.positionManager.SetTrailingStop(c.Request.Context(), positionID, &req)
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   "Failed to update trailing stop",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Trailing stop updated successfully",
+		"position": position,
+	})
+}
+
+// HandleReduceManagedPosition exits part of a managed position
+// POST /api/v1/positions/managed/:id/reduce
+func (pmc *PositionManagementController) HandleReduceManagedPosition(c *gin.Context) {
+	positionID := c.Param("id")
+	if positionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "position ID required",
+		})
+		return
+	}
+
+	var req services.ReduceManagedPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	position, err := pmc.positionManager.ReduceManagedPosition(c.Request.Context(), positionID, &req)
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, http.StatusBadRequest), gin.H{
+			"error":   "Failed to reduce position",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Reduce order placed",
+		"position": position,
+	})
+}
+
+// HandleReverseManagedPosition closes a position and, once the close fills,
+// opens the opposite side with the risk parameters in the body. Symbol and
+// side may be omitted; they default to the position's symbol and the opposite side.
+// POST /api/v1/positions/managed/:id/reverse
+func (pmc *PositionManagementController) HandleReverseManagedPosition(c *gin.Context) {
+	positionID := c.Param("id")
+	if positionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "position ID required",
+		})
+		return
+	}
+
+	// Decoded without binding validation since symbol and side are optional here
+	var req services.PlaceManagedPositionRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	closed, opened, err := pmc.positionManager.CloseAndReverse(c.Request.Context(), positionID, &req)
+	if err != nil {
+		response := gin.H{
+			"error":   "Failed to reverse position",
+			"details": err.Error(),
+		}
+		if closed != nil {
+			response["closed_position"] = closed
+		}
+		c.JSON(apperrors.HTTPStatus(err, http.StatusBadRequest), response)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Position reversed successfully",
+		"closed_position": closed,
+		"position":        opened,
+	})
+}
+
+// HandleImportManagedPosition wraps an existing broker position in managed risk orders
+// POST /api/v1/positions/managed/import
+func (pmc *PositionManagementController) HandleImportManagedPosition(c *gin.Context) {
+	var req services.ImportManagedPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	position, err := pmc.positionManager.ImportExistingPosition(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, http.StatusBadRequest), gin.H{
+			"error":   "Failed to import position",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Position imported successfully",
+		"position": position,
+	})
+}
+
+// HandlePlaceManagedOptionsPosition opens a managed options position
+// POST /api/v1/positions/managed/options
+func (pmc *PositionManagementController) HandlePlaceManagedOptionsPosition(c *gin.Context) {
+	var req services.PlaceManagedOptionsPositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	position, err := pmc.positionManager.PlaceManagedOptionsPosition(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, http.StatusBadRequest), gin.H{
+			"error":   "Failed to place options position",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Managed options position created successfully",
+		"position": position,
+	})
+}
+
+// AppendNoteRequest is a trade journal entry to add to a managed position
+type AppendNoteRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+// HandleAppendPositionNote appends a timestamped note to a managed position's journal
+// POST /api/v1/positions/managed/:id/notes
+func (pmc *PositionManagementController) HandleAppendPositionNote(c *gin.Context) {
+	positionID := c.Param("id")
+	if positionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "position ID required",
+		})
+		return
+	}
+
+	var req AppendNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	position, err := pmc.positionManager.AppendPositionNote(positionID, req.Note)
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, http.StatusInternalServerError), gin.H{
+			"error":   "Failed to append note",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Note appended successfully",
+		"position": position,
+	})
+}
+
+// HandleGetManagedPositionHistory returns the full lifecycle of a managed position
+// GET /api/v1/positions/managed/:id/history
+func (pmc *PositionManagementController) HandleGetManagedPositionHistory(c *gin.Context) {
+	positionID := c.Param("id")
+	if positionID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "position ID required",
+		})
+		return
+	}
+
+	history, err := pmc.positionManager.GetManagedPositionHistory(c.Request.Context(), positionID)
+	if err != nil {
+		c.JSON(apperrors.HTTPStatus(err, http.StatusNotFound), gin.H{
+			"error":   "Failed to get position history",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
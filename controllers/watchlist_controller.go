@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"net/http"
+	"prophet-trader/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WatchlistController handles watchlist CRUD operations
+type WatchlistController struct {
+	watchlistService *services.WatchlistService
+}
+
+// NewWatchlistController creates a new watchlist controller
+func NewWatchlistController(watchlistService *services.WatchlistService) *WatchlistController {
+	return &WatchlistController{
+		watchlistService: watchlistService,
+	}
+}
+
+// WatchlistRequest represents a request to create or update a watchlist
+type WatchlistRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Symbols     []string `json:"symbols" binding:"required"`
+}
+
+// HandleCreateWatchlist creates a new watchlist
+// POST /api/v1/watchlists
+func (wc *WatchlistController) HandleCreateWatchlist(c *gin.Context) {
+	var req WatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if _, err := wc.watchlistService.GetWatchlist(req.Name); err == nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Watchlist already exists",
+		})
+		return
+	}
+
+	watchlist, err := wc.watchlistService.SaveWatchlist(req.Name, req.Description, req.Symbols)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to save watchlist",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// HandleListWatchlists lists all watchlists
+// GET /api/v1/watchlists
+func (wc *WatchlistController) HandleListWatchlists(c *gin.Context) {
+	watchlists, err := wc.watchlistService.ListWatchlists()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list watchlists",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"count":      len(watchlists),
+		"watchlists": watchlists,
+	})
+}
+
+// HandleGetWatchlist retrieves a watchlist by name
+// GET /api/v1/watchlists/:name
+func (wc *WatchlistController) HandleGetWatchlist(c *gin.Context) {
+	watchlist, err := wc.watchlistService.GetWatchlist(c.Param("name"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Watchlist not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// HandleUpdateWatchlist replaces the symbols and description of a watchlist
+// PUT /api/v1/watchlists/:name
+func (wc *WatchlistController) HandleUpdateWatchlist(c *gin.Context) {
+	name := c.Param("name")
+
+	var req WatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if _, err := wc.watchlistService.GetWatchlist(name); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Watchlist not found",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	watchlist, err := wc.watchlistService.SaveWatchlist(name, req.Description, req.Symbols)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Failed to save watchlist",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// HandleDeleteWatchlist deletes a watchlist
+// DELETE /api/v1/watchlists/:name
+func (wc *WatchlistController) HandleDeleteWatchlist(c *gin.Context) {
+	if err := wc.watchlistService.DeleteWatchlist(c.Param("name")); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Failed to delete watchlist",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Watchlist deleted successfully",
+	})
+}
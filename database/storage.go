@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"prophet-trader/interfaces"
 	"prophet-trader/models"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -14,10 +16,17 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-// LocalStorage implements the StorageService interface using SQLite
+// DefaultAccountID is the account of a single-account deployment and of
+// rows stored before storage was scoped by account
+const DefaultAccountID = "default"
+
+// LocalStorage implements the StorageService interface using SQLite. Orders,
+// positions, trades, snapshots, signals, managed positions and watchlists are
+// scoped to one account; market data (bars, option snapshots) is shared.
 type LocalStorage struct {
-	db     *gorm.DB
-	logger *logrus.Logger
+	db        *gorm.DB
+	logger    *logrus.Logger
+	accountID string
 }
 
 // NewLocalStorage creates a new local storage service
@@ -45,9 +54,14 @@ func NewLocalStorage(dbPath string) (*LocalStorage, error) {
 		&models.DBAccountSnapshot{},
 		&models.DBSignal{},
 		&models.DBManagedPosition{},
+		&models.DBWatchlist{},
+		&models.DBOptionSnapshot{},
 	); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
+	if err := migrateAccountScope(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
 
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.TextFormatter{
@@ -55,23 +69,242 @@ func NewLocalStorage(dbPath string) (*LocalStorage, error) {
 	})
 
 	return &LocalStorage{
-		db:     db,
-		logger: logger,
+		db:        db,
+		logger:    logger,
+		accountID: DefaultAccountID,
 	}, nil
 }
 
-// SaveBars saves multiple bars to the database
+// accountScopedModels are the models whose rows belong to an account
+var accountScopedModels = []interface{}{
+	&models.DBOrder{},
+	&models.DBPosition{},
+	&models.DBTrade{},
+	&models.DBAccountSnapshot{},
+	&models.DBSignal{},
+	&models.DBManagedPosition{},
+	&models.DBWatchlist{},
+}
+
+// migrateAccountScope assigns rows stored before account scoping to the
+// default account, and drops the old single-column unique indexes that
+// per-account indexes replace
+func migrateAccountScope(db *gorm.DB) error {
+	for _, index := range []struct {
+		model interface{}
+		name  string
+	}{
+		{&models.DBPosition{}, "idx_positions_symbol"},
+		{&models.DBWatchlist{}, "idx_watchlists_name"},
+	} {
+		if db.Migrator().HasIndex(index.model, index.name) {
+			if err := db.Migrator().DropIndex(index.model, index.name); err != nil {
+				return fmt.Errorf("failed to drop index %s: %w", index.name, err)
+			}
+		}
+	}
+
+	for _, model := range accountScopedModels {
+		if err := db.Unscoped().Model(model).
+			Where("account_id IS NULL OR account_id = ''").
+			Update("account_id", DefaultAccountID).Error; err != nil {
+			return fmt.Errorf("failed to assign existing rows to the default account: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetAccountID sets the account this storage reads and writes
+func (s *LocalStorage) SetAccountID(accountID string) error {
+	accountID = strings.TrimSpace(accountID)
+	if accountID == "" {
+		return fmt.Errorf("account ID must not be empty")
+	}
+	s.accountID = accountID
+	return nil
+}
+
+// AccountID returns the account this storage reads and writes
+func (s *LocalStorage) AccountID() string {
+	return s.accountID
+}
+
+// ForAccount returns storage scoped to another account on the same database,
+// for deployments that manage several accounts
+func (s *LocalStorage) ForAccount(accountID string) (*LocalStorage, error) {
+	scoped := *s
+	if err := scoped.SetAccountID(accountID); err != nil {
+		return nil, err
+	}
+	return &scoped, nil
+}
+
+// scoped starts a query limited to the storage's account
+func (s *LocalStorage) scoped() *gorm.DB {
+	return s.db.Where("account_id = ?", s.accountID)
+}
+
+// StreamBarTimeframe is the timeframe of live streamed bars stored by SaveBars
+const StreamBarTimeframe = "1Min"
+
+// SaveBars upserts streamed minute bars in one transaction. A bar already
+// stored for the same symbol and minute is overwritten, since the stream
+// re-sends a bar when late trades revise it; within the batch the last copy
+// of a bar wins.
 func (s *LocalStorage) SaveBars(bars []*interfaces.Bar) error {
 	if len(bars) == 0 {
 		return nil
 	}
 
-	s.logger.WithField("count", len(bars)).Info("Saving bars to database")
+	// Latest copy of each bar, grouped by symbol in arrival order
+	bySymbol := make(map[string][]*interfaces.Bar)
+	index := make(map[string]int)
+	for _, bar := range bars {
+		key := bar.Symbol + "|" + strconv.FormatInt(bar.Timestamp.UnixNano(), 10)
+		if i, ok := index[key]; ok {
+			bySymbol[bar.Symbol][i] = bar
+			continue
+		}
+		index[key] = len(bySymbol[bar.Symbol])
+		bySymbol[bar.Symbol] = append(bySymbol[bar.Symbol], bar)
+	}
+
+	var created, updated int
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for symbol, group := range bySymbol {
+			first, last := group[0].Timestamp, group[0].Timestamp
+			for _, bar := range group {
+				if bar.Timestamp.Before(first) {
+					first = bar.Timestamp
+				}
+				if bar.Timestamp.After(last) {
+					last = bar.Timestamp
+				}
+			}
+
+			var existing []models.DBBar
+			if err := tx.Select("id", "timestamp").
+				Where("symbol = ? AND timeframe = ? AND timestamp >= ? AND timestamp <= ?", symbol, StreamBarTimeframe, first, last).
+				Find(&existing).Error; err != nil {
+				return fmt.Errorf("failed to check stored bars: %w", err)
+			}
+			ids := make(map[int64]uint, len(existing))
+			for _, row := range existing {
+				ids[row.Timestamp.UnixNano()] = row.ID
+			}
+
+			inserts := make([]*models.DBBar, 0, len(group))
+			for _, bar := range group {
+				id, ok := ids[bar.Timestamp.UnixNano()]
+				if !ok {
+					inserts = append(inserts, &models.DBBar{
+						Symbol:    bar.Symbol,
+						Timestamp: bar.Timestamp,
+						Open:      bar.Open,
+						High:      bar.High,
+						Low:       bar.Low,
+						Close:     bar.Close,
+						Volume:    bar.Volume,
+						VWAP:      bar.VWAP,
+						Timeframe: StreamBarTimeframe,
+					})
+					continue
+				}
+
+				if err := tx.Model(&models.DBBar{}).Where("id = ?", id).Updates(map[string]interface{}{
+					"open":   bar.Open,
+					"high":   bar.High,
+					"low":    bar.Low,
+					"close":  bar.Close,
+					"volume": bar.Volume,
+					"vwap":   bar.VWAP,
+				}).Error; err != nil {
+					return fmt.Errorf("failed to update bar: %w", err)
+				}
+				updated++
+			}
+
+			if len(inserts) > 0 {
+				if err := tx.Create(&inserts).Error; err != nil {
+					return fmt.Errorf("failed to save bars: %w", err)
+				}
+				created += len(inserts)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"created": created,
+		"updated": updated,
+	}).Debug("Streamed bars saved")
+	return nil
+}
+
+// GetStreamBars retrieves stored streamed minute bars for a symbol within a
+// time range
+func (s *LocalStorage) GetStreamBars(symbol string, start, end time.Time) ([]*interfaces.Bar, error) {
+	var dbBars []*models.DBBar
+
+	result := s.db.Where("symbol = ? AND timeframe = ? AND timestamp >= ? AND timestamp <= ?", symbol, StreamBarTimeframe, start, end).
+		Order("timestamp ASC").
+		Find(&dbBars)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get streamed bars: %w", result.Error)
+	}
+
+	bars := make([]*interfaces.Bar, len(dbBars))
+	for i, dbBar := range dbBars {
+		bars[i] = &interfaces.Bar{
+			Symbol:    dbBar.Symbol,
+			Timestamp: dbBar.Timestamp,
+			Open:      dbBar.Open,
+			High:      dbBar.High,
+			Low:       dbBar.Low,
+			Close:     dbBar.Close,
+			Volume:    dbBar.Volume,
+			VWAP:      dbBar.VWAP,
+		}
+	}
+
+	return bars, nil
+}
+
+// Daily bars are cached for long-window analysis and kept for just over a year
+const (
+	dailyBarTimeframe     = "1Day"
+	dailyBarRetentionDays = 400
+)
+
+// SaveDailyBars stores completed daily bars, skipping days already stored
+func (s *LocalStorage) SaveDailyBars(bars []*interfaces.Bar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+
+	symbol := bars[0].Symbol
+	var existing []time.Time
+	if err := s.db.Model(&models.DBBar{}).
+		Where("symbol = ? AND timeframe = ? AND timestamp >= ? AND timestamp <= ?",
+			symbol, dailyBarTimeframe, bars[0].Timestamp, bars[len(bars)-1].Timestamp).
+		Pluck("timestamp", &existing).Error; err != nil {
+		return fmt.Errorf("failed to check stored bars: %w", err)
+	}
+
+	stored := make(map[int64]bool, len(existing))
+	for _, ts := range existing {
+		stored[ts.Unix()] = true
+	}
 
-	// Convert interface bars to DB bars
-	dbBars := make([]*models.DBBar, len(bars))
-	for i, bar := range bars {
-		dbBars[i] = &models.DBBar{
+	dbBars := make([]*models.DBBar, 0, len(bars))
+	for _, bar := range bars {
+		if stored[bar.Timestamp.Unix()] {
+			continue
+		}
+		dbBars = append(dbBars, &models.DBBar{
 			Symbol:    bar.Symbol,
 			Timestamp: bar.Timestamp,
 			Open:      bar.Open,
@@ -80,17 +313,82 @@ func (s *LocalStorage) SaveBars(bars []*interfaces.Bar) error {
 			Close:     bar.Close,
 			Volume:    bar.Volume,
 			VWAP:      bar.VWAP,
+			Timeframe: dailyBarTimeframe,
+		})
+	}
+
+	if len(dbBars) == 0 {
+		return nil
+	}
+
+	if err := s.db.Create(&dbBars).Error; err != nil {
+		return fmt.Errorf("failed to save daily bars: %w", err)
+	}
+
+	return nil
+}
+
+// GetDailyBars retrieves stored daily bars for a symbol within a time range
+func (s *LocalStorage) GetDailyBars(symbol string, start, end time.Time) ([]*interfaces.Bar, error) {
+	var dbBars []*models.DBBar
+
+	result := s.db.Where("symbol = ? AND timeframe = ? AND timestamp >= ? AND timestamp <= ?", symbol, dailyBarTimeframe, start, end).
+		Order("timestamp ASC").
+		Find(&dbBars)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get daily bars: %w", result.Error)
+	}
+
+	bars := make([]*interfaces.Bar, len(dbBars))
+	for i, dbBar := range dbBars {
+		bars[i] = &interfaces.Bar{
+			Symbol:    dbBar.Symbol,
+			Timestamp: dbBar.Timestamp,
+			Open:      dbBar.Open,
+			High:      dbBar.High,
+			Low:       dbBar.Low,
+			Close:     dbBar.Close,
+			Volume:    dbBar.Volume,
+			VWAP:      dbBar.VWAP,
 		}
 	}
 
-	// Batch insert with upsert on conflict
-	result := s.db.Create(&dbBars)
+	return bars, nil
+}
+
+// AdjustDailyBarsForSplit rescales a symbol's stored daily bars before a
+// split to split-adjusted terms: prices are divided by ratio and volume
+// multiplied by it. ratio is new shares per old share (4 for a 4-for-1,
+// 0.1 for a 1-for-10 reverse split). It returns the number of bars adjusted.
+func (s *LocalStorage) AdjustDailyBarsForSplit(symbol string, before time.Time, ratio float64) (int64, error) {
+	if ratio <= 0 {
+		return 0, fmt.Errorf("split ratio must be positive")
+	}
+
+	result := s.db.Model(&models.DBBar{}).
+		Where("symbol = ? AND timeframe = ? AND timestamp < ?", symbol, dailyBarTimeframe, before).
+		Updates(map[string]interface{}{
+			"open":   gorm.Expr("open / ?", ratio),
+			"high":   gorm.Expr("high / ?", ratio),
+			"low":    gorm.Expr("low / ?", ratio),
+			"close":  gorm.Expr("close / ?", ratio),
+			"vwap":   gorm.Expr("vwap / ?", ratio),
+			"volume": gorm.Expr("CAST(ROUND(volume * ?) AS INTEGER)", ratio),
+		})
 	if result.Error != nil {
-		return fmt.Errorf("failed to save bars: %w", result.Error)
+		return 0, fmt.Errorf("failed to adjust daily bars: %w", result.Error)
 	}
 
-	s.logger.WithField("saved", result.RowsAffected).Info("Bars saved successfully")
-	return nil
+	return result.RowsAffected, nil
+}
+
+// DeleteDailyBars removes a symbol's stored daily bars so they are refetched
+func (s *LocalStorage) DeleteDailyBars(symbol string) (int64, error) {
+	result := s.db.Unscoped().Where("symbol = ? AND timeframe = ?", symbol, dailyBarTimeframe).Delete(&models.DBBar{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete daily bars: %w", result.Error)
+	}
+	return result.RowsAffected, nil
 }
 
 // GetBars retrieves bars for a symbol within a time range
@@ -126,6 +424,7 @@ func (s *LocalStorage) GetBars(symbol string, start, end time.Time) ([]*interfac
 // SaveOrder saves an order to the database
 func (s *LocalStorage) SaveOrder(order *interfaces.Order) error {
 	dbOrder := &models.DBOrder{
+		AccountID:      s.accountID,
 		OrderID:        order.ID,
 		Symbol:         order.Symbol,
 		Qty:            order.Qty,
@@ -140,6 +439,25 @@ func (s *LocalStorage) SaveOrder(order *interfaces.Order) error {
 		SubmittedAt:    order.SubmittedAt,
 		FilledAt:       order.FilledAt,
 		CanceledAt:     order.CanceledAt,
+		PositionID:     order.PositionID,
+		PositionRole:   order.PositionRole,
+		Intent:         order.Intent,
+	}
+
+	// Reuse the existing row so status updates don't collide with the order_id unique index
+	var existing models.DBOrder
+	if err := s.scoped().Where("order_id = ?", order.ID).First(&existing).Error; err == nil {
+		dbOrder.ID = existing.ID
+		dbOrder.CreatedAt = existing.CreatedAt
+		dbOrder.StrategyName = existing.StrategyName
+		dbOrder.Metadata = existing.Metadata
+		if dbOrder.PositionID == "" {
+			dbOrder.PositionID = existing.PositionID
+			dbOrder.PositionRole = existing.PositionRole
+		}
+		if dbOrder.Intent == "" {
+			dbOrder.Intent = existing.Intent
+		}
 	}
 
 	result := s.db.Save(dbOrder)
@@ -154,34 +472,19 @@ func (s *LocalStorage) SaveOrder(order *interfaces.Order) error {
 func (s *LocalStorage) GetOrder(orderID string) (*interfaces.Order, error) {
 	var dbOrder models.DBOrder
 
-	result := s.db.Where("order_id = ?", orderID).First(&dbOrder)
+	result := s.scoped().Where("order_id = ?", orderID).First(&dbOrder)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get order: %w", result.Error)
 	}
 
-	return &interfaces.Order{
-		ID:             dbOrder.OrderID,
-		Symbol:         dbOrder.Symbol,
-		Qty:            dbOrder.Qty,
-		Side:           dbOrder.Side,
-		Type:           dbOrder.Type,
-		TimeInForce:    dbOrder.TimeInForce,
-		LimitPrice:     dbOrder.LimitPrice,
-		StopPrice:      dbOrder.StopPrice,
-		Status:         dbOrder.Status,
-		FilledQty:      dbOrder.FilledQty,
-		FilledAvgPrice: dbOrder.FilledAvgPrice,
-		SubmittedAt:    dbOrder.SubmittedAt,
-		FilledAt:       dbOrder.FilledAt,
-		CanceledAt:     dbOrder.CanceledAt,
-	}, nil
+	return dbOrderToOrder(&dbOrder), nil
 }
 
 // GetOrders retrieves orders by status
 func (s *LocalStorage) GetOrders(status string) ([]*interfaces.Order, error) {
 	var dbOrders []*models.DBOrder
 
-	query := s.db.Model(&models.DBOrder{})
+	query := s.scoped().Model(&models.DBOrder{})
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
@@ -193,43 +496,111 @@ func (s *LocalStorage) GetOrders(status string) ([]*interfaces.Order, error) {
 
 	orders := make([]*interfaces.Order, len(dbOrders))
 	for i, dbOrder := range dbOrders {
-		orders[i] = &interfaces.Order{
-			ID:             dbOrder.OrderID,
-			Symbol:         dbOrder.Symbol,
-			Qty:            dbOrder.Qty,
-			Side:           dbOrder.Side,
-			Type:           dbOrder.Type,
-			TimeInForce:    dbOrder.TimeInForce,
-			LimitPrice:     dbOrder.LimitPrice,
-			StopPrice:      dbOrder.StopPrice,
-			Status:         dbOrder.Status,
-			FilledQty:      dbOrder.FilledQty,
-			FilledAvgPrice: dbOrder.FilledAvgPrice,
-			SubmittedAt:    dbOrder.SubmittedAt,
-			FilledAt:       dbOrder.FilledAt,
-			CanceledAt:     dbOrder.CanceledAt,
-		}
+		orders[i] = dbOrderToOrder(dbOrder)
+	}
+
+	return orders, nil
+}
+
+// GetOrdersFiltered retrieves a page of orders, newest first, along with the
+// total number of orders matching the filter
+func (s *LocalStorage) GetOrdersFiltered(filter interfaces.OrderFilter) ([]*interfaces.Order, int64, error) {
+	query := s.scoped().Model(&models.DBOrder{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Symbol != "" {
+		query = query.Where("symbol = ?", filter.Symbol)
+	}
+	if filter.PositionID != "" {
+		query = query.Where("position_id = ?", filter.PositionID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	var dbOrders []*models.DBOrder
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	result := query.Order("submitted_at DESC").Find(&dbOrders)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("failed to get orders: %w", result.Error)
+	}
+
+	orders := make([]*interfaces.Order, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		orders[i] = dbOrderToOrder(dbOrder)
+	}
+
+	return orders, total, nil
+}
+
+// GetOrdersForPosition retrieves all orders linked to a managed position, oldest first
+func (s *LocalStorage) GetOrdersForPosition(positionID string) ([]*interfaces.Order, error) {
+	var dbOrders []*models.DBOrder
+
+	result := s.scoped().Where("position_id = ?", positionID).Order("submitted_at ASC").Find(&dbOrders)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get orders for position: %w", result.Error)
+	}
+
+	orders := make([]*interfaces.Order, len(dbOrders))
+	for i, dbOrder := range dbOrders {
+		orders[i] = dbOrderToOrder(dbOrder)
 	}
 
 	return orders, nil
 }
 
+// dbOrderToOrder converts a stored order to the interface type
+func dbOrderToOrder(dbOrder *models.DBOrder) *interfaces.Order {
+	return &interfaces.Order{
+		ID:             dbOrder.OrderID,
+		Symbol:         dbOrder.Symbol,
+		Qty:            dbOrder.Qty,
+		Side:           dbOrder.Side,
+		Type:           dbOrder.Type,
+		TimeInForce:    dbOrder.TimeInForce,
+		LimitPrice:     dbOrder.LimitPrice,
+		StopPrice:      dbOrder.StopPrice,
+		Status:         dbOrder.Status,
+		FilledQty:      dbOrder.FilledQty,
+		FilledAvgPrice: dbOrder.FilledAvgPrice,
+		SubmittedAt:    dbOrder.SubmittedAt,
+		FilledAt:       dbOrder.FilledAt,
+		CanceledAt:     dbOrder.CanceledAt,
+		PositionID:     dbOrder.PositionID,
+		PositionRole:   dbOrder.PositionRole,
+		Intent:         dbOrder.Intent,
+	}
+}
+
 // CleanupOldData removes data older than the specified time
 func (s *LocalStorage) CleanupOldData(before time.Time) error {
 	s.logger.WithField("before", before).Info("Cleaning up old data")
 
-	// Delete old bars
-	if err := s.db.Where("timestamp < ?", before).Delete(&models.DBBar{}).Error; err != nil {
+	// Delete old bars. Daily bars are kept longer for 52-week analysis.
+	if err := s.db.Where("timestamp < ? AND (timeframe IS NULL OR timeframe != ?)", before, dailyBarTimeframe).Delete(&models.DBBar{}).Error; err != nil {
 		return fmt.Errorf("failed to delete old bars: %w", err)
 	}
+	if err := s.db.Where("timestamp < ? AND timeframe = ?", time.Now().AddDate(0, 0, -dailyBarRetentionDays), dailyBarTimeframe).Delete(&models.DBBar{}).Error; err != nil {
+		return fmt.Errorf("failed to delete old daily bars: %w", err)
+	}
 
 	// Delete old account snapshots
-	if err := s.db.Where("snapshot_time < ?", before).Delete(&models.DBAccountSnapshot{}).Error; err != nil {
+	if err := s.scoped().Where("snapshot_time < ?", before).Delete(&models.DBAccountSnapshot{}).Error; err != nil {
 		return fmt.Errorf("failed to delete old snapshots: %w", err)
 	}
 
 	// Delete old signals
-	if err := s.db.Where("created_at < ?", before).Delete(&models.DBSignal{}).Error; err != nil {
+	if err := s.scoped().Where("created_at < ?", before).Delete(&models.DBSignal{}).Error; err != nil {
 		return fmt.Errorf("failed to delete old signals: %w", err)
 	}
 
@@ -242,6 +613,7 @@ func (s *LocalStorage) CleanupOldData(before time.Time) error {
 // SavePosition saves a position snapshot
 func (s *LocalStorage) SavePosition(position *interfaces.Position) error {
 	dbPosition := &models.DBPosition{
+		AccountID:      s.accountID,
 		Symbol:         position.Symbol,
 		Qty:            position.Qty,
 		AvgEntryPrice:  position.AvgEntryPrice,
@@ -262,9 +634,10 @@ func (s *LocalStorage) SavePosition(position *interfaces.Position) error {
 	return nil
 }
 
-// SaveAccountSnapshot saves an account snapshot
-func (s *LocalStorage) SaveAccountSnapshot(account *interfaces.Account) error {
+// SaveAccountSnapshot saves an account snapshot and returns the stored row
+func (s *LocalStorage) SaveAccountSnapshot(account *interfaces.Account) (*interfaces.AccountSnapshot, error) {
 	dbSnapshot := &models.DBAccountSnapshot{
+		AccountID:        s.accountID,
 		Cash:             account.Cash,
 		PortfolioValue:   account.PortfolioValue,
 		BuyingPower:      account.BuyingPower,
@@ -275,15 +648,111 @@ func (s *LocalStorage) SaveAccountSnapshot(account *interfaces.Account) error {
 
 	result := s.db.Save(dbSnapshot)
 	if result.Error != nil {
-		return fmt.Errorf("failed to save account snapshot: %w", result.Error)
+		return nil, fmt.Errorf("failed to save account snapshot: %w", result.Error)
+	}
+
+	return &interfaces.AccountSnapshot{
+		ID:               dbSnapshot.ID,
+		Cash:             dbSnapshot.Cash,
+		PortfolioValue:   dbSnapshot.PortfolioValue,
+		BuyingPower:      dbSnapshot.BuyingPower,
+		DayTradeCount:    dbSnapshot.DayTradeCount,
+		PatternDayTrader: dbSnapshot.PatternDayTrader,
+		SnapshotTime:     dbSnapshot.SnapshotTime,
+	}, nil
+}
+
+// SaveOptionSnapshot saves one option contract snapshot
+func (s *LocalStorage) SaveOptionSnapshot(snapshot *models.DBOptionSnapshot) error {
+	result := s.db.Create(snapshot)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save option snapshot: %w", result.Error)
 	}
 
 	return nil
 }
 
+// GetOptionSnapshots retrieves a contract's snapshots within a time range, oldest first
+func (s *LocalStorage) GetOptionSnapshots(symbol string, start, end time.Time) ([]*models.DBOptionSnapshot, error) {
+	var snapshots []*models.DBOptionSnapshot
+
+	result := s.db.Where("symbol = ? AND snapshot_time >= ? AND snapshot_time <= ?", symbol, start, end).
+		Order("snapshot_time ASC").
+		Find(&snapshots)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get option snapshots: %w", result.Error)
+	}
+
+	return snapshots, nil
+}
+
+// GetOptionSnapshotsForUnderlying retrieves snapshots of every contract on an
+// underlying within a time range, oldest first
+func (s *LocalStorage) GetOptionSnapshotsForUnderlying(underlying string, start, end time.Time) ([]*models.DBOptionSnapshot, error) {
+	var snapshots []*models.DBOptionSnapshot
+
+	result := s.db.Where("underlying = ? AND snapshot_time >= ? AND snapshot_time <= ?", underlying, start, end).
+		Order("snapshot_time ASC, symbol ASC").
+		Find(&snapshots)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get option snapshots: %w", result.Error)
+	}
+
+	return snapshots, nil
+}
+
+// SaveTrade saves a completed trade
+func (s *LocalStorage) SaveTrade(trade *models.DBTrade) error {
+	trade.AccountID = s.accountID
+	result := s.db.Create(trade)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save trade: %w", result.Error)
+	}
+
+	return nil
+}
+
+// TradeFilter selects closed trades. Empty fields match everything.
+type TradeFilter struct {
+	Strategy         string
+	Symbol           string
+	Since            time.Time // exit time on or after (zero = all)
+	Until            time.Time // exit time before (zero = now)
+	IncludeSimulated bool      // dry-run trades are excluded unless set
+}
+
+// GetTrades retrieves closed trades matching filter, oldest exit first
+func (s *LocalStorage) GetTrades(filter TradeFilter) ([]*models.DBTrade, error) {
+	query := s.scoped().Model(&models.DBTrade{})
+	if filter.Strategy != "" {
+		query = query.Where("strategy_name = ?", filter.Strategy)
+	}
+	if filter.Symbol != "" {
+		query = query.Where("symbol = ?", filter.Symbol)
+	}
+	if !filter.Since.IsZero() {
+		query = query.Where("exit_time >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("exit_time < ?", filter.Until)
+	}
+	if !filter.IncludeSimulated {
+		// Trades saved before the column existed are NULL
+		query = query.Where("simulated = ? OR simulated IS NULL", false)
+	}
+
+	var trades []*models.DBTrade
+	if err := query.Order("exit_time ASC").Find(&trades).Error; err != nil {
+		return nil, fmt.Errorf("failed to get trades: %w", err)
+	}
+
+	return trades, nil
+}
+
 // SaveSignal saves a trading signal
 func (s *LocalStorage) SaveSignal(symbol, signalType, strategyName, reason string, strength float64) error {
 	dbSignal := &models.DBSignal{
+		AccountID:    s.accountID,
 		Symbol:       symbol,
 		SignalType:   signalType,
 		Strength:     strength,
@@ -302,6 +771,17 @@ func (s *LocalStorage) SaveSignal(symbol, signalType, strategyName, reason strin
 
 // SaveManagedPosition saves a managed position to the database
 func (s *LocalStorage) SaveManagedPosition(position *models.DBManagedPosition) error {
+	position.AccountID = s.accountID
+
+	// Reuse the existing row so updates don't collide with the position_id unique index
+	if position.ID == 0 {
+		var existing models.DBManagedPosition
+		if err := s.scoped().Where("position_id = ?", position.PositionID).First(&existing).Error; err == nil {
+			position.ID = existing.ID
+			position.CreatedAt = existing.CreatedAt
+		}
+	}
+
 	result := s.db.Save(position)
 	if result.Error != nil {
 		return fmt.Errorf("failed to save managed position: %w", result.Error)
@@ -313,7 +793,7 @@ func (s *LocalStorage) SaveManagedPosition(position *models.DBManagedPosition) e
 func (s *LocalStorage) GetManagedPosition(positionID string) (*models.DBManagedPosition, error) {
 	var dbPosition models.DBManagedPosition
 
-	result := s.db.Where("position_id = ?", positionID).First(&dbPosition)
+	result := s.scoped().Where("position_id = ?", positionID).First(&dbPosition)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get managed position: %w", result.Error)
 	}
@@ -325,7 +805,7 @@ func (s *LocalStorage) GetManagedPosition(positionID string) (*models.DBManagedP
 func (s *LocalStorage) GetAllManagedPositions(status string) ([]*models.DBManagedPosition, error) {
 	var dbPositions []*models.DBManagedPosition
 
-	query := s.db.Model(&models.DBManagedPosition{})
+	query := s.scoped().Model(&models.DBManagedPosition{})
 	if status != "" {
 		query = query.Where("status = ?", status)
 	}
@@ -340,13 +820,67 @@ func (s *LocalStorage) GetAllManagedPositions(status string) ([]*models.DBManage
 
 // DeleteManagedPosition deletes a managed position by ID
 func (s *LocalStorage) DeleteManagedPosition(positionID string) error {
-	result := s.db.Where("position_id = ?", positionID).Delete(&models.DBManagedPosition{})
+	result := s.scoped().Where("position_id = ?", positionID).Delete(&models.DBManagedPosition{})
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete managed position: %w", result.Error)
 	}
 	return nil
 }
 
+// SaveWatchlist creates or updates a watchlist by name
+func (s *LocalStorage) SaveWatchlist(watchlist *models.DBWatchlist) error {
+	watchlist.AccountID = s.accountID
+	if watchlist.ID == 0 {
+		var existing models.DBWatchlist
+		if err := s.scoped().Where("name = ?", watchlist.Name).First(&existing).Error; err == nil {
+			watchlist.ID = existing.ID
+			watchlist.CreatedAt = existing.CreatedAt
+		}
+	}
+
+	result := s.db.Save(watchlist)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save watchlist: %w", result.Error)
+	}
+	return nil
+}
+
+// GetWatchlist retrieves a watchlist by name
+func (s *LocalStorage) GetWatchlist(name string) (*models.DBWatchlist, error) {
+	var dbWatchlist models.DBWatchlist
+
+	result := s.scoped().Where("name = ?", name).First(&dbWatchlist)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get watchlist: %w", result.Error)
+	}
+
+	return &dbWatchlist, nil
+}
+
+// GetAllWatchlists retrieves all watchlists ordered by name
+func (s *LocalStorage) GetAllWatchlists() ([]*models.DBWatchlist, error) {
+	var dbWatchlists []*models.DBWatchlist
+
+	result := s.scoped().Order("name ASC").Find(&dbWatchlists)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get watchlists: %w", result.Error)
+	}
+
+	return dbWatchlists, nil
+}
+
+// DeleteWatchlist permanently deletes a watchlist so its name can be reused
+func (s *LocalStorage) DeleteWatchlist(name string) error {
+	result := s.scoped().Unscoped().Where("name = ?", name).Delete(&models.DBWatchlist{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete watchlist: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("watchlist not found: %s", name)
+	}
+	return nil
+}
+
 // Close closes the database connection
 func (s *LocalStorage) Close() error {
 	sqlDB, err := s.db.DB()
@@ -1,6 +1,7 @@
 package interfaces
 
 import (
+	"context"
 	"time"
 )
 
@@ -22,6 +23,39 @@ type OptionContract struct {
 	Theta            float64
 	Vega             float64
 	DTE              int // Days to expiration
+	QuoteTime        time.Time // Time of the latest quote (zero if unknown)
+	LiquidityIssues  []string  // Liquidity thresholds the contract fails, when flagged rather than excluded
+}
+
+// OptionContractCompact is the essential subset of an OptionContract for
+// picking a contract: strike, side, quote, delta and liquidity
+type OptionContractCompact struct {
+	Symbol       string  `json:"symbol"`
+	ContractType string  `json:"type"`
+	StrikePrice  float64 `json:"strike"`
+	DTE          int     `json:"dte"`
+	Bid          float64 `json:"bid"`
+	Ask          float64 `json:"ask"`
+	Delta        float64 `json:"delta"`
+	IV           float64 `json:"iv"`
+	OpenInterest int64   `json:"open_interest"`
+	Illiquid     bool    `json:"illiquid,omitempty"`
+}
+
+// ToCompact converts an OptionContract to a compact version
+func (o *OptionContract) ToCompact() OptionContractCompact {
+	return OptionContractCompact{
+		Symbol:       o.Symbol,
+		ContractType: o.ContractType,
+		StrikePrice:  o.StrikePrice,
+		DTE:          o.DTE,
+		Bid:          o.Bid,
+		Ask:          o.Ask,
+		Delta:        o.Delta,
+		IV:           o.ImpliedVolatility,
+		OpenInterest: o.OpenInterest,
+		Illiquid:     len(o.LiquidityIssues) > 0,
+	}
 }
 
 // OptionPosition represents an open options position
@@ -50,3 +84,8 @@ type OptionDataService interface {
 	GetOptionChains(symbol string) (map[time.Time]*OptionChain, error)
 	GetOptionSnapshot(optionSymbol string) (*OptionContract, error)
 }
+
+// OptionSnapshotProvider supplies live premium and greeks for one option contract
+type OptionSnapshotProvider interface {
+	GetOptionSnapshot(ctx context.Context, optionSymbol string) (*OptionContract, error)
+}
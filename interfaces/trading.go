@@ -17,11 +17,19 @@ type TradingService interface {
 	// Options trading methods
 	PlaceOptionsOrder(ctx context.Context, order *OptionsOrder) (*OrderResult, error)
 	GetOptionsChain(ctx context.Context, underlying string, expiration time.Time) ([]*OptionContract, error)
+	GetOptionExpirations(ctx context.Context, underlying string) ([]time.Time, error)
 	GetOptionsQuote(ctx context.Context, symbol string) (*OptionsQuote, error)
 	GetOptionsPosition(ctx context.Context, symbol string) (*OptionsPosition, error)
 	ListOptionsPositions(ctx context.Context) ([]*OptionsPosition, error)
 }
 
+// AssetInfoProvider is an optional TradingService capability providing symbol
+// trading metadata. Brokers that don't implement it are assumed to allow
+// shorting and fractional shares at standard US equity ticks.
+type AssetInfoProvider interface {
+	GetAssetInfo(ctx context.Context, symbol string) (*AssetInfo, error)
+}
+
 // DataService defines the interface for market data operations
 type DataService interface {
 	GetHistoricalBars(ctx context.Context, symbol string, start, end time.Time, timeframe string) ([]*Bar, error)
@@ -38,6 +46,9 @@ type StorageService interface {
 	SaveOrder(order *Order) error
 	GetOrder(orderID string) (*Order, error)
 	GetOrders(status string) ([]*Order, error)
+	GetOrdersForPosition(positionID string) ([]*Order, error)
+	GetOrdersFiltered(filter OrderFilter) ([]*Order, int64, error)
+	SaveAccountSnapshot(account *Account) (*AccountSnapshot, error)
 	CleanupOldData(before time.Time) error
 }
 
@@ -68,6 +79,33 @@ type Order struct {
 	SubmittedAt   time.Time
 	FilledAt      *time.Time
 	CanceledAt    *time.Time
+	PositionID    string // Managed position this order belongs to, if any
+	PositionRole  string // "entry", "stop_loss", "take_profit", "partial_exit", "exit"
+	Intent        string // "open" or "close", when known
+}
+
+// OrderFilter selects a page of stored orders. Empty fields match everything.
+type OrderFilter struct {
+	Status     string
+	Symbol     string
+	PositionID string
+	Limit      int
+	Offset     int
+}
+
+// AssetInfo describes how a symbol can be traded
+type AssetInfo struct {
+	Symbol             string
+	Class              string // "us_equity", "crypto", ...
+	Exchange           string
+	Tradable           bool
+	Shortable          bool
+	EasyToBorrow       bool
+	Fractionable       bool
+	Marginable         bool
+	PriceIncrement     float64 // tick size at or above $1
+	SubDollarIncrement float64 // tick size below $1
+	LotSize            float64 // smallest whole-share order increment
 }
 
 type OrderRequest struct {
@@ -107,6 +145,16 @@ type Account struct {
 	PatternDayTrader bool
 }
 
+type AccountSnapshot struct {
+	ID               uint
+	Cash             float64
+	PortfolioValue   float64
+	BuyingPower      float64
+	DayTradeCount    int
+	PatternDayTrader bool
+	SnapshotTime     time.Time
+}
+
 type Bar struct {
 	Symbol    string
 	Timestamp time.Time
@@ -79,10 +79,36 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
       },
       {
         name: 'get_orders',
-        description: 'Get all orders (open, filled, cancelled)',
+        description: 'Get orders (open, filled, cancelled), newest first. Returns { orders, total, limit, offset, has_more }; page with limit/offset while has_more is true. Statuses come from the broker by default; source=local reads the stored order log, whose statuses can lag the broker.',
         inputSchema: {
           type: 'object',
-          properties: {},
+          properties: {
+            status: {
+              type: 'string',
+              description: 'Order status to filter by (broker: open, closed or all)',
+            },
+            symbol: {
+              type: 'string',
+              description: 'Only orders for this symbol',
+            },
+            position_id: {
+              type: 'string',
+              description: 'Only orders placed for this managed position (always read from the stored order log)',
+            },
+            source: {
+              type: 'string',
+              description: 'broker (default) or local',
+              enum: ['broker', 'local'],
+            },
+            limit: {
+              type: 'number',
+              description: 'Page size (default 50, max 500)',
+            },
+            offset: {
+              type: 'number',
+              description: 'Orders to skip (default 0)',
+            },
+          },
         },
       },
       {
@@ -108,6 +134,11 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
               type: 'number',
               description: 'Limit price (required for limit orders)',
             },
+            intent: {
+              type: 'string',
+              description: 'Optional: open (buy to open) or close (buy to cover a short). Inferred from current positions when omitted.',
+              enum: ['open', 'close'],
+            },
           },
           required: ['symbol', 'quantity', 'order_type'],
         },
@@ -135,6 +166,11 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
               type: 'number',
               description: 'Limit price (required for limit orders)',
             },
+            intent: {
+              type: 'string',
+              description: 'Optional: open (sell short) or close (sell a long position). Inferred from current positions when omitted.',
+              enum: ['open', 'close'],
+            },
           },
           required: ['symbol', 'quantity', 'order_type'],
         },
@@ -188,6 +224,15 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
               type: 'number',
               description: 'Absolute take profit price',
             },
+            take_profit_order_type: {
+              type: 'string',
+              enum: ['limit', 'stop', 'stop_limit'],
+              description: 'How the target exits (default limit). A resting limit fills at the target or better but may not fill if price gaps through it; stop exits at market once price touches the target but may fill worse; stop_limit bounds that fill but may not fill in a fast move.',
+            },
+            take_profit_limit_offset: {
+              type: 'number',
+              description: '% beyond the target for the stop_limit exit limit (required for stop_limit), e.g. 0.5',
+            },
             trailing_stop: {
               type: 'boolean',
               description: 'Enable trailing stop loss',
@@ -196,6 +241,18 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
               type: 'number',
               description: 'Trailing stop percentage',
             },
+            sar_stop: {
+              type: 'boolean',
+              description: 'Trail the stop at the daily Parabolic SAR (only tightens; cannot be combined with trailing_stop)',
+            },
+            sar_step: {
+              type: 'number',
+              description: 'Parabolic SAR acceleration step (default 0.02)',
+            },
+            sar_max: {
+              type: 'number',
+              description: 'Parabolic SAR maximum acceleration (default 0.2)',
+            },
             partial_exit: {
               type: 'object',
               description: 'Partial profit taking configuration',
@@ -206,14 +263,42 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
                 },
                 percent: {
                   type: 'number',
-                  description: 'Percentage of position to exit (e.g., 50 for 50%)',
+                  description: 'Percentage of position to exit (e.g., 50 for 50%); greater than 0 and at most 100',
                 },
                 target_percent: {
                   type: 'number',
                   description: 'Profit % to trigger partial exit (e.g., 20 for +20%)',
                 },
+                target_price: {
+                  type: 'number',
+                  description: 'Absolute partial exit price, e.g. a resistance level (alternative to target_percent; must be between entry and take profit)',
+                },
+                breakeven_stop: {
+                  type: 'boolean',
+                  description: 'Move the stop to the entry price once the partial exit fills',
+                },
+                trailing_reset: {
+                  type: 'string',
+                  description: 'With trailing_stop on, what the runner trails from after the partial fill: peak (default) keeps trailing from the best price since entry and never loosens the stop; current restarts the trail from the price at the fill (may loosen the stop); breakeven resets the stop to entry and trails from new highs',
+                  enum: ['peak', 'current', 'breakeven'],
+                },
               },
             },
+            entry_time_in_force: {
+              type: 'string',
+              description: 'Time in force for the entry order. Defaults to gtc (day for DAY_TRADE)',
+              enum: ['day', 'gtc', 'opg', 'cls', 'ioc', 'fok'],
+            },
+            stop_time_in_force: {
+              type: 'string',
+              description: 'Time in force for the stop loss order. Defaults to gtc (day for DAY_TRADE, so stops do not carry overnight)',
+              enum: ['day', 'gtc'],
+            },
+            target_time_in_force: {
+              type: 'string',
+              description: 'Time in force for take profit and partial exit orders. Defaults to gtc (day for DAY_TRADE)',
+              enum: ['day', 'gtc'],
+            },
             notes: {
               type: 'string',
               description: 'Notes about this position',
@@ -225,10 +310,105 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
                 type: 'string',
               },
             },
+            dry_run: {
+              type: 'boolean',
+              description: 'Simulate the position: no broker orders are sent; entry, stop and target fill against live quotes and the position and its trade are tagged simulated',
+            },
+            price_reference: {
+              type: 'string',
+              description: 'Current price used for quantity, stop and target when there is no entry_price (default mid). ask overstates a long entry, bid a short one',
+              enum: ['bid', 'ask', 'mid', 'last'],
+            },
           },
           required: ['symbol', 'side', 'allocation_dollars'],
         },
       },
+      {
+        name: 'place_managed_positions_batch',
+        description: 'Open a basket of managed positions in one call. All requests are validated and checked against buying power and the open-position limit before anything is placed. mode all_or_none (default) places nothing if any request fails; best_effort places the valid ones that fit, in order.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            mode: {
+              type: 'string',
+              enum: ['all_or_none', 'best_effort'],
+              description: 'Whether one failure rejects the whole batch (default all_or_none)',
+            },
+            positions: {
+              type: 'array',
+              description: 'Up to 50 position requests, each with the same fields as place_managed_position',
+              items: { type: 'object' },
+            },
+          },
+          required: ['positions'],
+        },
+      },
+      {
+        name: 'place_managed_options_position',
+        description: 'Buy options contracts with a managed stop (on the option premium or the underlying price) and a profit target on the premium. Exits are checked during market hours and closed with a market order.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            symbol: {
+              type: 'string',
+              description: 'Option symbol in OCC format (e.g., TSLA251219C00400000)',
+            },
+            strategy: {
+              type: 'string',
+              enum: ['SWING_TRADE', 'LONG_TERM', 'DAY_TRADE'],
+              description: 'Trading strategy type',
+            },
+            allocation_dollars: {
+              type: 'number',
+              description: 'Dollar amount to allocate',
+            },
+            sizing_mode: {
+              type: 'string',
+              enum: ['premium', 'delta'],
+              description: 'premium (default): spend the allocation on premium. delta: size so delta-adjusted underlying exposure matches the allocation',
+            },
+            entry_strategy: {
+              type: 'string',
+              enum: ['market', 'limit'],
+              description: 'Entry order type (default market)',
+            },
+            entry_price: {
+              type: 'number',
+              description: 'Limit premium per share (required for limit orders)',
+            },
+            stop_basis: {
+              type: 'string',
+              enum: ['premium', 'underlying'],
+              description: 'What the stop watches (default premium)',
+            },
+            stop_loss_price: {
+              type: 'number',
+              description: 'Premium stop price (premium basis)',
+            },
+            stop_loss_percent: {
+              type: 'number',
+              description: 'Premium stop as % below entry (premium basis)',
+            },
+            underlying_stop_price: {
+              type: 'number',
+              description: 'Underlying price that stops the position: below it for calls, above it for puts (underlying basis)',
+            },
+            take_profit_price: {
+              type: 'number',
+              description: 'Premium profit target',
+            },
+            take_profit_percent: {
+              type: 'number',
+              description: 'Premium profit target as % above entry',
+            },
+            notes: {
+              type: 'string',
+              description: 'Trade notes',
+            },
+          },
+          required: ['symbol', 'allocation_dollars'],
+        },
+      },
       {
         name: 'get_managed_positions',
         description: 'List managed positions with optional status filter. By default, returns only ACTIVE positions for token efficiency. Use status="" or status="ALL" to get all positions.',
@@ -240,6 +420,39 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
               description: 'Filter by status. Leave empty or use "ALL" for all positions. Use PENDING, ACTIVE, PARTIAL, CLOSED, or STOPPED_OUT for specific statuses. Defaults to ACTIVE only.',
               enum: ['PENDING', 'ACTIVE', 'PARTIAL', 'CLOSED', 'STOPPED_OUT', 'ALL', ''],
             },
+            compact: {
+              type: 'boolean',
+              description: 'Return only id, symbol, side, status, quantities, price levels and P&L per position (default false)',
+            },
+          },
+        },
+      },
+      {
+        name: 'get_trade_stats',
+        description: 'Closed-trade performance (win rate, net P&L after fees, profit factor, expectancy, hold time, MAE/MFE) overall and broken down by strategy and by close reason (TAKE_PROFIT, STOP_LOSS, TRAILING_STOP, TIME_EXIT, MANUAL, REVERSED, EXPIRED), e.g. to compare SWING_TRADE and DAY_TRADE or stop-outs and targets.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            strategy: {
+              type: 'string',
+              description: 'Only trades of this strategy (SWING_TRADE, LONG_TERM, DAY_TRADE)',
+            },
+            symbol: {
+              type: 'string',
+              description: 'Only trades of this symbol',
+            },
+            since: {
+              type: 'string',
+              description: 'Trades closed on or after this date (YYYY-MM-DD)',
+            },
+            until: {
+              type: 'string',
+              description: 'Trades closed before this date (YYYY-MM-DD)',
+            },
+            include_simulated: {
+              type: 'boolean',
+              description: 'Include dry-run trades (default false)',
+            },
           },
         },
       },
@@ -271,6 +484,49 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
           required: ['position_id'],
         },
       },
+      {
+        name: 'reverse_managed_position',
+        description: 'Close a managed stock position at market and, once the close fills, open the opposite side with fresh risk parameters. The new entry is never placed before the close is confirmed. Requires market hours.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            position_id: {
+              type: 'string',
+              description: 'Position ID to reverse',
+            },
+            allocation_dollars: {
+              type: 'number',
+              description: 'Dollar amount for the new position',
+            },
+            strategy: {
+              type: 'string',
+              enum: ['SWING_TRADE', 'LONG_TERM', 'DAY_TRADE'],
+              description: 'Trading strategy type for the new position',
+            },
+            stop_loss_price: {
+              type: 'number',
+              description: 'Stop loss price for the new position',
+            },
+            stop_loss_percent: {
+              type: 'number',
+              description: 'Stop loss as % from entry',
+            },
+            take_profit_price: {
+              type: 'number',
+              description: 'Take profit price for the new position',
+            },
+            take_profit_percent: {
+              type: 'number',
+              description: 'Take profit as % from entry',
+            },
+            notes: {
+              type: 'string',
+              description: 'Why the thesis flipped',
+            },
+          },
+          required: ['position_id', 'allocation_dollars'],
+        },
+      },
       {
         name: 'cancel_order',
         description: 'Cancel an open order by ID',
@@ -340,6 +596,106 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
           required: ['symbol'],
         },
       },
+      {
+        name: 'get_recorded_bars',
+        description: 'Replay 1-minute bars recorded from the live stream (only symbols in BAR_RECORD_SYMBOLS are recorded). With session_vwap, returns the regular-session VWAP reconstructed from the recorded bars for one date instead.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            symbol: {
+              type: 'string',
+              description: 'Stock symbol',
+            },
+            start: {
+              type: 'string',
+              description: 'Start, YYYY-MM-DD or RFC3339 (default: 24 hours ago)',
+            },
+            end: {
+              type: 'string',
+              description: 'End, YYYY-MM-DD (inclusive) or RFC3339 (default: now)',
+            },
+            session_vwap: {
+              type: 'boolean',
+              description: 'Return the session VWAP for date instead of the bars',
+            },
+            date: {
+              type: 'string',
+              description: 'Session date for session_vwap, YYYY-MM-DD (default: today)',
+            },
+          },
+          required: ['symbol'],
+        },
+      },
+      {
+        name: 'analyze_stock_as_of',
+        description: 'Recompute what the stock analysis would have scored at the close of a past date, from stored daily bars only (no live data, no bars after the date, no news). Returns the scores and trade setup for correlating historical scores with later price action.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            symbol: {
+              type: 'string',
+              description: 'Stock symbol',
+            },
+            date: {
+              type: 'string',
+              description: 'Past date, YYYY-MM-DD',
+            },
+            depth: {
+              type: 'string',
+              enum: ['quick', 'standard', 'deep'],
+              description: 'deep adds the weekly and 52-week pictures (default: standard)',
+            },
+            compact: {
+              type: 'boolean',
+              description: 'Return only price, trend, levels and scores',
+            },
+          },
+          required: ['symbol', 'date'],
+        },
+      },
+      {
+        name: 'backtest_stock',
+        description: 'Replay the daily technical signal for a stock over cached daily bars (long only). BUY signals buy and SELL signals sell at the next day\'s open with slippage and fees; stops and targets are checked against each day\'s low and high. Returns each trade with its exit reason (SIGNAL, STOP, TARGET, END) and the net P&L and win rate.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            symbol: {
+              type: 'string',
+              description: 'Stock symbol',
+            },
+            start: {
+              type: 'string',
+              description: 'First simulated day, YYYY-MM-DD',
+            },
+            end: {
+              type: 'string',
+              description: 'Last simulated day, YYYY-MM-DD',
+            },
+            quantity: {
+              type: 'number',
+              description: 'Shares per trade',
+            },
+            stop_percent: {
+              type: 'number',
+              description: 'Stop loss % below the entry fill (default: none)',
+            },
+            target_percent: {
+              type: 'number',
+              description: 'Take profit % above the entry fill (default: none)',
+            },
+            slippage_mode: {
+              type: 'string',
+              enum: ['fixed', 'percent'],
+              description: 'fixed: $ per share; percent: % of price',
+            },
+            slippage: {
+              type: 'number',
+              description: 'Slippage amount in the chosen mode (default: 0)',
+            },
+          },
+          required: ['symbol', 'start', 'end', 'quantity'],
+        },
+      },
       {
         name: 'get_news',
         description: 'Get latest news from Google News RSS feed',
@@ -486,10 +842,27 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
       },
       {
         name: 'get_quick_market_intelligence',
-        description: 'Get AI-powered quick market intelligence (Gemini-cleaned news from MarketWatch - 15 articles max, very fast)',
+        description: 'Get AI-powered quick market intelligence (Gemini-cleaned news from MarketWatch - 5 articles per feed by default, very fast). Set raw to get the deduplicated headlines without spending Gemini tokens.',
         inputSchema: {
           type: 'object',
-          properties: {},
+          properties: {
+            top_stories: {
+              type: 'number',
+              description: 'Items from MarketWatch top stories (0-50, default 5, 0 skips the feed)',
+            },
+            bulletins: {
+              type: 'number',
+              description: 'Items from MarketWatch bulletins (0-50, default 5)',
+            },
+            market_pulse: {
+              type: 'number',
+              description: 'Items from MarketWatch market pulse (0-50, default 5)',
+            },
+            raw: {
+              type: 'boolean',
+              description: 'Return raw deduplicated headlines instead of the Gemini summary (default false)',
+            },
+          },
         },
       },
       {
@@ -503,10 +876,131 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
               items: { type: 'string' },
               description: 'Array of stock symbols to analyze (e.g., ["CLRB", "PLUG", "BE", "NVDA"])',
             },
+            compact: {
+              type: 'boolean',
+              description: 'Return only price, trend, RSI, levels and scores per stock (default false)',
+            },
+            depth: {
+              type: 'string',
+              enum: ['quick', 'standard', 'deep'],
+              description: 'Analysis preset (default standard). quick: 30 days of bars from the local cache when current, no news, no weekly or 52-week context. standard: the server defaults. deep: news, weekly confluence and 52-week range from a year of bars (slowest).',
+            },
+            include_news: {
+              type: 'boolean',
+              description: 'Search news for catalysts (defaults to the depth preset). false gives a faster technical-only analysis with a neutral catalyst score.',
+            },
           },
           required: ['symbols'],
         },
       },
+      {
+        name: 'apply_stock_split',
+        description: 'Rescale the cached daily price history of a stock for a split, so indicators stay correct. Splits are also detected automatically; use this for a known split the cache has not caught yet.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            symbol: {
+              type: 'string',
+              description: 'Stock symbol',
+            },
+            ratio: {
+              type: 'number',
+              description: 'New shares per old share: 4 for a 4-for-1 split, 0.1 for a 1-for-10 reverse split',
+            },
+            effective_date: {
+              type: 'string',
+              description: 'First day trading split-adjusted (YYYY-MM-DD)',
+            },
+          },
+          required: ['symbol', 'ratio', 'effective_date'],
+        },
+      },
+      {
+        name: 'score_stock',
+        description: 'Get only the technical, volume, catalyst and composite scores plus factual notes for a stock. Much cheaper than analyze_stocks; use it to screen many symbols before a full analysis.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            symbol: {
+              type: 'string',
+              description: 'Stock symbol to score (e.g., "NVDA")',
+            },
+          },
+          required: ['symbol'],
+        },
+      },
+      {
+        name: 'screen_stocks',
+        description: 'Screen a symbol list or watchlist and return only the stocks meeting all criteria, sorted by composite score. Criteria map a field to comparators, e.g. {"rsi": {"lt": 30}, "volume_ratio": {"gt": 2}, "trend": {"eq": "BULLISH"}, "composite_score": {"gt": 7}}. Numeric fields: price, day_change, rsi, volume_ratio, volatility, volatility_annualized, technical_score, catalyst_score, volume_score, composite_score, risk_reward (lt, lte, gt, gte, eq). Text fields: trend, price_strength, confluence, data_quality (eq).',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            symbols: {
+              type: 'array',
+              items: { type: 'string' },
+              description: 'Symbols to screen',
+            },
+            watchlist: {
+              type: 'string',
+              description: 'Saved watchlist to screen instead of symbols',
+            },
+            criteria: {
+              type: 'object',
+              description: 'Field to comparator map, e.g. {"rsi": {"lt": 30}}',
+            },
+          },
+          required: ['criteria'],
+        },
+      },
+      {
+        name: 'get_indicators',
+        description: 'Compute only the technical indicators you ask for on a symbol, instead of the full analysis bundle. Available: sma, ema, rsi, macd, atr, bollinger (params: std_dev), momentum, volume, parabolic_sar (params: step, max). Results are keyed name_period (e.g. rsi_14) or by "as".',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            symbol: {
+              type: 'string',
+              description: 'Stock symbol',
+            },
+            timeframe: {
+              type: 'string',
+              description: 'Bar timeframe (default 1Day)',
+            },
+            indicators: {
+              type: 'array',
+              description: 'Indicators to compute, e.g. [{"name": "rsi"}, {"name": "sma", "period": 50}, {"name": "bollinger", "params": {"std_dev": 2}}]',
+              items: {
+                type: 'object',
+                properties: {
+                  name: { type: 'string' },
+                  period: { type: 'number' },
+                  params: { type: 'object' },
+                  as: { type: 'string' },
+                },
+                required: ['name'],
+              },
+            },
+          },
+          required: ['symbol', 'indicators'],
+        },
+      },
+      {
+        name: 'get_daily_brief',
+        description: 'Generate the session-start daily brief: cleaned market news, the top-scoring setups from a watchlist, and open-position risk. The brief is also saved to the activity log.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            watchlist: {
+              type: 'string',
+              description: 'Watchlist to score (defaults to DAILY_BRIEF_WATCHLIST)',
+            },
+            top_setups: {
+              type: 'number',
+              description: 'Number of top setups to include (default: 5)',
+            },
+          },
+        },
+      },
       {
         name: 'get_cleaned_news',
         description: 'Get AI-powered cleaned and aggregated news from multiple sources (Google News + MarketWatch)',
@@ -540,7 +1034,7 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
       },
       {
         name: 'log_decision',
-        description: 'Log a trading decision with reasoning to decisive_actions/ folder',
+        description: 'Log a trading decision with reasoning to decisive_actions/ folder and the session activity log, which captures the quote and analysis for the symbol at decision time',
         inputSchema: {
           type: 'object',
           properties: {
@@ -548,58 +1042,117 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
               type: 'string',
               description: 'The action taken (BUY, SELL, HOLD, PASS)',
             },
+            conviction: {
+              type: 'number',
+              description: 'Conviction 1-10 (optional)',
+            },
             symbol: {
               type: 'string',
               description: 'Stock symbol (optional)',
             },
             reasoning: {
               type: 'string',
-              description: 'Detailed reasoning for the decision',
+              description: 'Detailed reasoning for the decision',
+            },
+            market_data: {
+              type: 'object',
+              description: 'Relevant market data that influenced the decision',
+            },
+          },
+          required: ['action', 'reasoning'],
+        },
+      },
+      {
+        name: 'log_activity',
+        description: 'Log AI trading activity to the daily activity log (positions, intelligence, decisions)',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            type: {
+              type: 'string',
+              description: 'Activity type: ANALYSIS, INTELLIGENCE, DECISION, POSITION_CHECK',
+            },
+            action: {
+              type: 'string',
+              description: 'Action description (e.g., "Analyzed 10 stocks", "Gathered market intelligence")',
+            },
+            symbol: {
+              type: 'string',
+              description: 'Stock symbol if applicable',
+            },
+            reasoning: {
+              type: 'string',
+              description: 'Reasoning or notes for this activity',
+            },
+            details: {
+              type: 'object',
+              description: 'Additional details as key-value pairs',
+            },
+          },
+          required: ['type', 'action'],
+        },
+      },
+      {
+        name: 'get_activity_log',
+        description: 'Get the current day\'s activity log showing all AI trading activities',
+        inputSchema: {
+          type: 'object',
+          properties: {},
+        },
+      },
+      {
+        name: 'get_session_report',
+        description: 'Get the end-of-day recap for a session: trades, realized P&L, win rate, biggest winner/loser, open positions and notable intelligence.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            date: {
+              type: 'string',
+              description: 'Session date YYYY-MM-DD (default today)',
+            },
+          },
+        },
+      },
+      {
+        name: 'replay_session',
+        description: 'Replay a session: its decisions, position opens/closes, activities and intelligence as one timeline, oldest first. Each entry carries the latest market snapshot (quote, trend, RSI, scores) captured for its symbol, to see what the analysis said at each point.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            date: {
+              type: 'string',
+              description: 'Session date YYYY-MM-DD (default today)',
             },
-            market_data: {
-              type: 'object',
-              description: 'Relevant market data that influenced the decision',
+            symbol: {
+              type: 'string',
+              description: 'Only show entries about this symbol (optional)',
             },
           },
-          required: ['action', 'reasoning'],
         },
       },
       {
-        name: 'log_activity',
-        description: 'Log AI trading activity to the daily activity log (positions, intelligence, decisions)',
+        name: 'get_option_history',
+        description: 'Get stored IV, greeks and quote snapshots over time for one option contract or for every contract on an underlying. Open options positions are captured automatically.',
         inputSchema: {
           type: 'object',
           properties: {
-            type: {
+            symbol: {
               type: 'string',
-              description: 'Activity type: ANALYSIS, INTELLIGENCE, DECISION, POSITION_CHECK',
+              description: 'Option contract in OCC format (e.g., AAPL251219C00150000)',
             },
-            action: {
+            underlying: {
               type: 'string',
-              description: 'Action description (e.g., "Analyzed 10 stocks", "Gathered market intelligence")',
+              description: 'Underlying stock symbol, used when no contract symbol is given',
             },
-            symbol: {
+            start: {
               type: 'string',
-              description: 'Stock symbol if applicable',
+              description: 'Start date YYYY-MM-DD (default 30 days ago)',
             },
-            reasoning: {
+            end: {
               type: 'string',
-              description: 'Reasoning or notes for this activity',
-            },
-            details: {
-              type: 'object',
-              description: 'Additional details as key-value pairs',
+              description: 'End date YYYY-MM-DD, inclusive (default today)',
             },
           },
-          required: ['type', 'action'],
-        },
-      },
-      {
-        name: 'get_activity_log',
-        description: 'Get the current day\'s activity log showing all AI trading activities',
-        inputSchema: {
-          type: 'object',
-          properties: {},
         },
       },
       {
@@ -677,7 +1230,11 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
             },
             expiration: {
               type: 'string',
-              description: 'Expiration date in YYYY-MM-DD format (optional, defaults to next Friday)',
+              description: 'Expiration date in YYYY-MM-DD format (optional, overrides expiration_mode)',
+            },
+            expiration_mode: {
+              type: 'string',
+              description: 'Pick from listed expirations when no date is given: "nearest", "next_weekly" (default), "next_monthly" or "nearest_to_dte=N" (e.g. nearest_to_dte=30)',
             },
             delta_min: {
               type: 'number',
@@ -696,6 +1253,50 @@ server.setRequestHandler(ListToolsRequestSchema, async () => {
               description: 'Filter by option type: "call" or "put"',
               enum: ['call', 'put'],
             },
+            liquidity: {
+              type: 'string',
+              description: 'How to treat contracts failing the volume/open interest/spread gate: exclude, flag (keep with LiquidityIssues) or off. Defaults to the server setting.',
+              enum: ['exclude', 'flag', 'off'],
+            },
+            compact: {
+              type: 'boolean',
+              description: 'Return only symbol, type, strike, DTE, bid/ask, delta, IV and open interest per contract (default false)',
+            },
+            refresh: {
+              type: 'boolean',
+              description: 'Refetch the chain instead of filtering the briefly cached copy (default false)',
+            },
+          },
+          required: ['symbol'],
+        },
+      },
+      {
+        name: 'get_options_chain_table',
+        description: 'Get the full options chain for one expiration as a standard option table: calls and puts separated and sorted by strike, with the underlying price. Unfiltered, so prefer get_options_chain with filters when picking a contract.',
+        inputSchema: {
+          type: 'object',
+          properties: {
+            symbol: {
+              type: 'string',
+              description: 'Underlying stock symbol (e.g., SPY, TSLA, AAPL)',
+            },
+            expiration: {
+              type: 'string',
+              description: 'Expiration date in YYYY-MM-DD format (optional, overrides expiration_mode)',
+            },
+            expiration_mode: {
+              type: 'string',
+              description: 'Pick from listed expirations when no date is given: "nearest", "next_weekly" (default), "next_monthly" or "nearest_to_dte=N"',
+            },
+            liquidity: {
+              type: 'string',
+              description: 'How to treat contracts failing the liquidity gate: exclude, flag or off. Defaults to the server setting.',
+              enum: ['exclude', 'flag', 'off'],
+            },
+            refresh: {
+              type: 'boolean',
+              description: 'Refetch the chain instead of using the briefly cached copy (default false)',
+            },
           },
           required: ['symbol'],
         },
@@ -855,7 +1456,12 @@ server.setRequestHandler(CallToolRequestSchema, async (request) => {
       }
 
       case 'get_orders': {
-        const data = await callTradingBot('/orders');
+        const params = new URLSearchParams();
+        for (const key of ['status', 'symbol', 'position_id', 'source', 'limit', 'offset']) {
+          if (args[key] !== undefined && args[key] !== '') params.append(key, args[key]);
+        }
+        const query = params.toString() ? `?${params.toString()}` : '';
+        const data = await callTradingBot(`/orders${query}`);
         return {
           content: [
             {
@@ -872,7 +1478,8 @@ server.setRequestHandler(CallToolRequestSchema, async (request) => {
           symbol: args.symbol,
           qty: args.quantity,
           order_type: args.order_type,
-          ...(args.limit_price && { limit_price: args.limit_price })
+          ...(args.limit_price && { limit_price: args.limit_price }),
+          ...(args.intent && { intent: args.intent })
         };
         const data = await callTradingBot('/orders/buy', 'POST', requestData);
         return {
@@ -891,7 +1498,8 @@ server.setRequestHandler(CallToolRequestSchema, async (request) => {
           symbol: args.symbol,
           qty: args.quantity,
           order_type: args.order_type,
-          ...(args.limit_price && { limit_price: args.limit_price })
+          ...(args.limit_price && { limit_price: args.limit_price }),
+          ...(args.intent && { intent: args.intent })
         };
         const data = await callTradingBot('/orders/sell', 'POST', requestData);
         return {
@@ -916,6 +1524,48 @@ server.setRequestHandler(CallToolRequestSchema, async (request) => {
         };
       }
 
+      case 'place_managed_positions_batch': {
+        const data = await callTradingBot('/positions/managed/batch', 'POST', args);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'place_managed_options_position': {
+        const data = await callTradingBot('/positions/managed/options', 'POST', args);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'get_trade_stats': {
+        const params = new URLSearchParams();
+        for (const key of ['strategy', 'symbol', 'since', 'until']) {
+          if (args[key]) params.append(key, args[key]);
+        }
+        if (args.include_simulated) params.append('include_simulated', 'true');
+        const query = params.toString() ? `?${params.toString()}` : '';
+        const data = await callTradingBot(`/positions/trades/stats${query}`);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
       case 'get_managed_positions': {
         // Default to ACTIVE positions only for token efficiency
         // Use status="ALL" or status="" to get all positions
@@ -928,6 +1578,9 @@ server.setRequestHandler(CallToolRequestSchema, async (request) => {
           // Default: only ACTIVE positions
           endpoint = '/positions/managed?status=ACTIVE';
         }
+        if (args.compact) {
+          endpoint += endpoint.includes('?') ? '&compact=true' : '?compact=true';
+        }
 
         const data = await callTradingBot(endpoint);
 
@@ -984,6 +1637,19 @@ server.setRequestHandler(CallToolRequestSchema, async (request) => {
         };
       }
 
+      case 'reverse_managed_position': {
+        const { position_id, ...reversal } = args;
+        const data = await callTradingBot(`/positions/managed/${position_id}/reverse`, 'POST', reversal);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
       case 'cancel_order': {
         const data = await callTradingBot(`/orders/${args.order_id}`, 'DELETE');
         return {
@@ -1039,6 +1705,68 @@ server.setRequestHandler(CallToolRequestSchema, async (request) => {
         };
       }
 
+      case 'get_recorded_bars': {
+        const symbol = encodeURIComponent(args.symbol);
+        const params = new URLSearchParams();
+        let endpoint;
+        if (args.session_vwap) {
+          endpoint = `/market/bars/${symbol}/recorded/session-vwap`;
+          if (args.date) params.append('date', args.date);
+        } else {
+          endpoint = `/market/bars/${symbol}/recorded`;
+          if (args.start) params.append('start', args.start);
+          if (args.end) params.append('end', args.end);
+        }
+        if (params.toString()) endpoint += `?${params.toString()}`;
+
+        const data = await callTradingBot(endpoint);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'analyze_stock_as_of': {
+        const params = new URLSearchParams({ date: args.date });
+        if (args.depth) params.append('depth', args.depth);
+        if (args.compact) params.append('compact', 'true');
+        const data = await callTradingBot(`/intelligence/analyze/${encodeURIComponent(args.symbol)}/as-of?${params.toString()}`);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'backtest_stock': {
+        const data = await callTradingBot('/intelligence/backtest', 'POST', {
+          symbol: args.symbol,
+          start: args.start,
+          end: args.end,
+          quantity: args.quantity,
+          stop_percent: args.stop_percent,
+          target_percent: args.target_percent,
+          fill: {
+            slippage: { mode: args.slippage_mode, amount: args.slippage },
+          },
+        });
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
       case 'get_news': {
         const limit = args.limit || 20;
         const data = await callTradingBot(`/news?limit=${limit}`);
@@ -1279,7 +2007,13 @@ ${allNews.map((article, i) =>
       }
 
       case 'get_quick_market_intelligence': {
-        const data = await callTradingBot('/intelligence/quick-market');
+        const params = new URLSearchParams();
+        for (const key of ['top_stories', 'bulletins', 'market_pulse']) {
+          if (args[key] !== undefined) params.append(key, String(args[key]));
+        }
+        if (args.raw) params.append('raw', 'true');
+        const query = params.toString() ? `?${params.toString()}` : '';
+        const data = await callTradingBot(`/intelligence/quick-market${query}`);
         return {
           content: [
             {
@@ -1291,7 +2025,73 @@ ${allNews.map((article, i) =>
       }
 
       case 'analyze_stocks': {
-        const data = await callTradingBot('/intelligence/analyze-multiple', 'POST', args);
+        const { compact, ...body } = args;
+        const endpoint = compact ? '/intelligence/analyze-multiple?compact=true' : '/intelligence/analyze-multiple';
+        const data = await callTradingBot(endpoint, 'POST', body);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'apply_stock_split': {
+        const data = await callTradingBot(`/intelligence/splits/${encodeURIComponent(args.symbol)}`, 'POST', {
+          ratio: args.ratio,
+          effective_date: args.effective_date,
+        });
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'score_stock': {
+        const data = await callTradingBot(`/intelligence/score/${encodeURIComponent(args.symbol)}`);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'screen_stocks': {
+        const data = await callTradingBot('/intelligence/screen', 'POST', args);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'get_indicators': {
+        const { symbol, ...body } = args;
+        const data = await callTradingBot(`/intelligence/indicators/${encodeURIComponent(symbol)}`, 'POST', body);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'get_daily_brief': {
+        const data = await callTradingBot('/intelligence/daily-brief', 'POST', args);
         return {
           content: [
             {
@@ -1351,6 +2151,53 @@ ${allNews.map((article, i) =>
         };
       }
 
+      case 'get_session_report': {
+        const query = args.date ? `?date=${encodeURIComponent(args.date)}` : '';
+        const data = await callTradingBot(`/activity/report${query}`);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: typeof data === 'string' ? data : JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'replay_session': {
+        const params = new URLSearchParams();
+        if (args.date) params.append('date', args.date);
+        if (args.symbol) params.append('symbol', args.symbol);
+        const query = params.toString() ? `?${params.toString()}` : '';
+        const data = await callTradingBot(`/activity/replay${query}`);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'get_option_history': {
+        const params = new URLSearchParams();
+        if (!args.symbol && args.underlying) params.append('underlying', args.underlying);
+        if (args.start) params.append('start', args.start);
+        if (args.end) params.append('end', args.end);
+        const query = params.toString() ? `?${params.toString()}` : '';
+        const path = args.symbol ? `/options/snapshots/${encodeURIComponent(args.symbol)}` : '/options/snapshots';
+        const data = await callTradingBot(`${path}${query}`);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
       case 'log_decision': {
         const timestamp = new Date().toISOString().replace(/[:.]/g, '-');
         const filename = `${timestamp}_${args.action}${args.symbol ? '_' + args.symbol : ''}.json`;
@@ -1366,11 +2213,25 @@ ${allNews.map((article, i) =>
 
         await fs.writeFile(filepath, JSON.stringify(decision, null, 2));
 
+        // Also record it in the session log so it can be replayed with its market snapshot
+        let sessionNote = '';
+        try {
+          await callTradingBot('/activity/decision', 'POST', {
+            action: args.action,
+            symbol: args.symbol || '',
+            reasoning: args.reasoning,
+            conviction: args.conviction || 0,
+            market_data: args.market_data || {},
+          });
+        } catch (error) {
+          sessionNote = ` (not added to session log: ${error.message})`;
+        }
+
         return {
           content: [
             {
               type: 'text',
-              text: `Decision logged to ${filename}`,
+              text: `Decision logged to ${filename}${sessionNote}`,
             },
           ],
         };
@@ -1426,10 +2287,36 @@ ${allNews.map((article, i) =>
         const params = new URLSearchParams();
 
         if (args.expiration) params.append('expiration', args.expiration);
+        if (args.expiration_mode) params.append('expiration_mode', args.expiration_mode);
         if (args.delta_min !== undefined) params.append('delta_min', args.delta_min);
         if (args.delta_max !== undefined) params.append('delta_max', args.delta_max);
         if (args.min_bid !== undefined) params.append('min_bid', args.min_bid);
         if (args.type) params.append('type', args.type);
+        if (args.liquidity) params.append('liquidity', args.liquidity);
+        if (args.compact) params.append('compact', 'true');
+        if (args.refresh) params.append('refresh', 'true');
+
+        if (params.toString()) endpoint += `?${params.toString()}`;
+
+        const data = await callTradingBot(endpoint);
+        return {
+          content: [
+            {
+              type: 'text',
+              text: JSON.stringify(data, null, 2),
+            },
+          ],
+        };
+      }
+
+      case 'get_options_chain_table': {
+        let endpoint = `/options/chain/${args.symbol}/table`;
+        const params = new URLSearchParams();
+
+        if (args.expiration) params.append('expiration', args.expiration);
+        if (args.expiration_mode) params.append('expiration_mode', args.expiration_mode);
+        if (args.liquidity) params.append('liquidity', args.liquidity);
+        if (args.refresh) params.append('refresh', 'true');
 
         if (params.toString()) endpoint += `?${params.toString()}`;
 
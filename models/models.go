@@ -9,6 +9,7 @@ import (
 // DBOrder represents an order in the database
 type DBOrder struct {
 	gorm.Model
+	AccountID      string `gorm:"index"`
 	OrderID        string `gorm:"uniqueIndex"`
 	Symbol         string `gorm:"index"`
 	Qty            float64
@@ -26,6 +27,10 @@ type DBOrder struct {
 	// Metadata for strategy tracking
 	StrategyName string
 	Metadata     string // JSON string for flexible data
+	// Managed position link
+	PositionID   string `gorm:"index"`
+	PositionRole string
+	Intent       string // "open" or "close"
 }
 
 // DBBar represents historical price data in the database
@@ -45,7 +50,8 @@ type DBBar struct {
 // DBPosition represents a position snapshot in the database
 type DBPosition struct {
 	gorm.Model
-	Symbol         string `gorm:"uniqueIndex"`
+	AccountID      string `gorm:"uniqueIndex:idx_account_position_symbol"`
+	Symbol         string `gorm:"uniqueIndex:idx_account_position_symbol"`
 	Qty            float64
 	AvgEntryPrice  float64
 	MarketValue    float64
@@ -60,23 +66,31 @@ type DBPosition struct {
 // DBTrade represents executed trades for analysis
 type DBTrade struct {
 	gorm.Model
+	AccountID    string `gorm:"index"`
 	Symbol       string `gorm:"index"`
 	EntryPrice   float64
 	ExitPrice    float64
 	Qty          float64
 	Side         string
-	PnL          float64
-	PnLPercent   float64
+	PnL          float64 // net of estimated fees
+	PnLPercent   float64 // net of estimated fees
+	GrossPnL     float64
+	Fees         float64
+	MAEPercent   float64 // worst unrealized P&L percent while open
+	MFEPercent   float64 // best unrealized P&L percent while open
+	Simulated    bool    `gorm:"index"` // dry-run trade filled by the paper broker
 	EntryTime    time.Time
 	ExitTime     time.Time
 	Duration     int64 // seconds
 	StrategyName string
+	CloseReason  string `gorm:"index"` // TAKE_PROFIT, STOP_LOSS, TRAILING_STOP, ...
 	Metadata     string
 }
 
 // DBAccountSnapshot represents account state at a point in time
 type DBAccountSnapshot struct {
 	gorm.Model
+	AccountID        string `gorm:"index"`
 	Cash             float64
 	PortfolioValue   float64
 	BuyingPower      float64
@@ -85,9 +99,32 @@ type DBAccountSnapshot struct {
 	SnapshotTime     time.Time `gorm:"index"`
 }
 
+// DBOptionSnapshot records an option contract's quote, IV and greeks at a point in time
+type DBOptionSnapshot struct {
+	gorm.Model
+	Symbol            string `gorm:"index"` // OCC contract symbol
+	Underlying        string `gorm:"index"`
+	ContractType      string // "call" or "put"
+	StrikePrice       float64
+	ExpirationDate    time.Time
+	Bid               float64
+	Ask               float64
+	Premium           float64 // bid/ask midpoint
+	ImpliedVolatility float64
+	Delta             float64
+	Gamma             float64
+	Theta             float64
+	Vega              float64
+	OpenInterest      int64
+	Volume            int64
+	QuoteTime         time.Time
+	SnapshotTime      time.Time `gorm:"index"`
+}
+
 // DBSignal represents trading signals for audit/analysis
 type DBSignal struct {
 	gorm.Model
+	AccountID    string `gorm:"index"`
 	Symbol       string `gorm:"index"`
 	SignalType   string // "BUY", "SELL", "HOLD"
 	Strength     float64
@@ -102,6 +139,7 @@ type DBSignal struct {
 // DBManagedPosition represents a managed position with automated risk management
 type DBManagedPosition struct {
 	gorm.Model
+	AccountID         string `gorm:"index"`
 	PositionID        string `gorm:"uniqueIndex"`
 	Symbol            string `gorm:"index"`
 	Side              string
@@ -118,20 +156,43 @@ type DBManagedPosition struct {
 	StopLossPrice     float64
 	StopLossPercent   float64
 	StopLossOrderID   string
+	StopLossLimitOffset float64 // % beyond stop for stop_limit orders, 0 = plain stop
 	TrailingStop      bool
 	TrailingPercent   float64
+	TrailingReference float64 // high-water mark (low for shorts) the trailing stop follows
+	SARStop           bool
+	SARStep           float64
+	SARMax            float64
 
 	// Profit targets
 	TakeProfitPrice   float64
 	TakeProfitPercent float64
 	TakeProfitOrderID string
+	TakeProfitType        string  // limit, stop or stop_limit; "" = limit
+	TakeProfitLimitOffset float64 // % beyond the target for stop_limit
 
 	// Partial exit
 	PartialExitEnabled      bool
 	PartialExitPercent      float64
 	PartialExitTargetPercent float64
 	PartialExitTargetPrice   float64
+	PartialExitBreakevenStop bool
+	PartialExitTrailingReset string // "peak", "current" or "breakeven"
 	PartialExitOrders       string // JSON array of order IDs
+	ReduceOrderID           string
+	ReduceQty               float64
+
+	// Time in force per order type
+	EntryTimeInForce  string
+	StopTimeInForce   string
+	TargetTimeInForce string
+
+	// Options positions (empty AssetClass = equity)
+	AssetClass          string
+	Underlying          string
+	StopBasis           string
+	UnderlyingStopPrice float64
+	EntryDelta          float64
 
 	// Status
 	Status           string `gorm:"index"` // PENDING, ACTIVE, PARTIAL, CLOSED, STOPPED_OUT
@@ -140,10 +201,30 @@ type DBManagedPosition struct {
 	UnrealizedPLPC   float64
 	RemainingQty     float64
 
+	// Worst and best unrealized P&L percent seen while open
+	MaxAdverseExcursion   float64
+	MaxFavorableExcursion float64
+
 	// Metadata
 	Notes     string
+	Journal   string // JSON array of timestamped notes
 	Tags      string // JSON array
+	Simulated bool   // dry run: orders go to the paper broker
 	ClosedAt  *time.Time
+
+	// Generated trade reasoning
+	EntryReasoning string
+	ExitReasoning  string
+	CloseReason    string `gorm:"index"` // reason code, empty while open
+}
+
+// DBWatchlist represents a named list of symbols
+type DBWatchlist struct {
+	gorm.Model
+	AccountID   string `gorm:"uniqueIndex:idx_account_watchlist_name"`
+	Name        string `gorm:"uniqueIndex:idx_account_watchlist_name"`
+	Description string
+	Symbols     string // JSON array
 }
 
 // TableName overrides for cleaner table names
@@ -167,10 +248,18 @@ func (DBAccountSnapshot) TableName() string {
 	return "account_snapshots"
 }
 
+func (DBOptionSnapshot) TableName() string {
+	return "option_snapshots"
+}
+
 func (DBSignal) TableName() string {
 	return "signals"
 }
 
 func (DBManagedPosition) TableName() string {
 	return "managed_positions"
-}
\ No newline at end of file
+}
+
+func (DBWatchlist) TableName() string {
+	return "watchlists"
+}
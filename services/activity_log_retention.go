@@ -0,0 +1,184 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ActivityLogRetention controls how long activity log files are kept
+type ActivityLogRetention struct {
+	ArchiveAfterDays int // gzip logs older than this many days (0 = never archive)
+	DeleteAfterDays  int // delete logs and archives older than this many days (0 = keep forever)
+}
+
+// SetRetention configures the retention policy applied by ApplyRetention
+func (al *ActivityLogger) SetRetention(retention ActivityLogRetention) {
+	al.retention = retention
+}
+
+// ApplyRetention archives and deletes old activity logs according to the
+// retention policy. The current session's log is never touched.
+func (al *ActivityLogger) ApplyRetention() (archived, deleted int, err error) {
+	files, err := os.ReadDir(al.logDir)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read log directory: %w", err)
+	}
+
+	today := time.Now()
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		date, compressed, ok := parseActivityLogName(file.Name())
+		if !ok {
+			continue
+		}
+		if al.currentLog != nil && date == al.currentLog.Date {
+			continue
+		}
+
+		logDate, parseErr := time.ParseInLocation("2006-01-02", date, today.Location())
+		if parseErr != nil {
+			continue
+		}
+		ageDays := int(today.Sub(logDate).Hours() / 24)
+		path := filepath.Join(al.logDir, file.Name())
+
+		if al.retention.DeleteAfterDays > 0 && ageDays > al.retention.DeleteAfterDays {
+			if err := os.Remove(path); err != nil {
+				al.logger.WithError(err).WithField("file", file.Name()).Warn("Failed to delete old activity log")
+				continue
+			}
+			deleted++
+			continue
+		}
+
+		if !compressed && al.retention.ArchiveAfterDays > 0 && ageDays > al.retention.ArchiveAfterDays {
+			if err := gzipFile(path); err != nil {
+				al.logger.WithError(err).WithField("file", file.Name()).Warn("Failed to archive activity log")
+				continue
+			}
+			archived++
+		}
+	}
+
+	if archived > 0 || deleted > 0 {
+		al.logger.WithFields(logrus.Fields{
+			"archived": archived,
+			"deleted":  deleted,
+		}).Info("Activity log retention applied")
+	}
+
+	return archived, deleted, nil
+}
+
+// StartRetention applies the retention policy now and then on every interval
+// until ctx is cancelled
+func (al *ActivityLogger) StartRetention(ctx context.Context, interval time.Duration) {
+	if _, _, err := al.ApplyRetention(); err != nil {
+		al.logger.WithError(err).Warn("Failed to apply activity log retention")
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, _, err := al.ApplyRetention(); err != nil {
+					al.logger.WithError(err).Warn("Failed to apply activity log retention")
+				}
+			}
+		}
+	}()
+}
+
+// parseActivityLogName extracts the date from activity_DATE.json or
+// activity_DATE.json.gz and reports whether the file is compressed
+func parseActivityLogName(name string) (date string, compressed bool, ok bool) {
+	if !strings.HasPrefix(name, "activity_") {
+		return "", false, false
+	}
+
+	switch {
+	case strings.HasSuffix(name, ".json.gz"):
+		date = strings.TrimSuffix(strings.TrimPrefix(name, "activity_"), ".json.gz")
+		compressed = true
+	case strings.HasSuffix(name, ".json"):
+		date = strings.TrimSuffix(strings.TrimPrefix(name, "activity_"), ".json")
+	default:
+		return "", false, false
+	}
+
+	return date, compressed, date != ""
+}
+
+// readActivityLogFile reads a log for a date, falling back to its gzipped archive
+func (al *ActivityLogger) readActivityLogFile(date string) ([]byte, error) {
+	filename := filepath.Join(al.logDir, fmt.Sprintf("activity_%s.json", date))
+
+	data, err := os.ReadFile(filename)
+	if err == nil || !os.IsNotExist(err) {
+		return data, err
+	}
+
+	f, gzErr := os.Open(filename + ".gz")
+	if gzErr != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader, gzErr := gzip.NewReader(f)
+	if gzErr != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", gzErr)
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+// gzipFile compresses path to path.gz and removes the original
+func gzipFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+
+	writer := gzip.NewWriter(dst)
+	if _, err := io.Copy(writer, src); err != nil {
+		writer.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	src.Close()
+	return os.Remove(path)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -16,6 +17,11 @@ type ActivityLogger struct {
 	logger     *logrus.Logger
 	logDir     string
 	currentLog *DailyActivityLog
+	mu         sync.Mutex // serializes writes to currentLog (monitor workers, scheduler, handlers)
+	retention  ActivityLogRetention
+	fees       FeeModel
+	reportSender ReportSender
+	snapshots  DecisionSnapshotSource // market state captured with each decision (nil = none)
 }
 
 // DailyActivityLog represents a day's worth of trading activity
@@ -42,6 +48,7 @@ type SessionSummary struct {
 	TotalPnLPercent   float64 `json:"total_pnl_percent"`
 	LargestWin        float64 `json:"largest_win"`
 	LargestLoss       float64 `json:"largest_loss"`
+	TotalFees         float64 `json:"total_fees"`
 	StartingCapital   float64 `json:"starting_capital"`
 	EndingCapital     float64 `json:"ending_capital"`
 	CapitalDeployed   float64 `json:"capital_deployed"`
@@ -66,14 +73,17 @@ type PositionActivity struct {
 	Timestamp        time.Time `json:"timestamp"`
 	Symbol           string    `json:"symbol"`
 	Side             string    `json:"side"`
+	Strategy         string    `json:"strategy,omitempty"` // SWING_TRADE, LONG_TERM, DAY_TRADE
 	Quantity         float64   `json:"quantity"`
 	EntryPrice       float64   `json:"entry_price"`
 	ExitPrice        float64   `json:"exit_price,omitempty"`
 	AllocationDollar float64   `json:"allocation_dollars"`
 	StopLoss         float64   `json:"stop_loss"`
 	TakeProfit       float64   `json:"take_profit"`
-	PnL              float64   `json:"pnl,omitempty"`
-	PnLPercent       float64   `json:"pnl_percent,omitempty"`
+	PnL              float64   `json:"pnl,omitempty"`          // net of estimated fees
+	PnLPercent       float64   `json:"pnl_percent,omitempty"`  // net of estimated fees
+	GrossPnL         float64   `json:"gross_pnl,omitempty"`
+	Fees             float64   `json:"fees,omitempty"`
 	HoldDays         int       `json:"hold_days,omitempty"`
 	Reasoning        string    `json:"reasoning"`
 	Tags             []string  `json:"tags,omitempty"`
@@ -96,7 +106,8 @@ type DecisionLog struct {
 	Symbol      string                 `json:"symbol"`
 	Reasoning   string                 `json:"reasoning"`
 	Conviction  int                    `json:"conviction"`
-	MarketData  map[string]interface{} `json:"market_data,omitempty"`
+	MarketData  map[string]interface{} `json:"market_data,omitempty"` // context supplied by the caller
+	Snapshot    *DecisionSnapshot      `json:"snapshot,omitempty"`    // market state captured at decision time
 }
 
 // NewActivityLogger creates a new activity logger
@@ -115,10 +126,29 @@ func NewActivityLogger(logDir string) *ActivityLogger {
 	}
 }
 
-// StartSession initializes a new trading session for the day
-func (al *ActivityLogger) StartSession(ctx context.Context, startingCapital float64) error {
+// SetFeeModel sets the fee model subtracted from realized P&L of closed positions
+func (al *ActivityLogger) SetFeeModel(fees FeeModel) {
+	al.fees = fees
+}
+
+// StartSession starts the day's trading session. If today's log already
+// exists (e.g. after a restart) it is resumed with its activities and summary
+// intact and resumed is true; forceNew discards it and starts over.
+func (al *ActivityLogger) StartSession(ctx context.Context, startingCapital float64, forceNew bool) (resumed bool, err error) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
 	date := time.Now().Format("2006-01-02")
 
+	if !forceNew {
+		resumed, err := al.resumeSession(date)
+		if err != nil || resumed {
+			return resumed, err
+		}
+	} else {
+		al.logger.WithField("date", date).Warn("Starting a new session, discarding any existing log for today")
+	}
+
 	al.currentLog = &DailyActivityLog{
 		Date:         date,
 		SessionStart: time.Now(),
@@ -137,11 +167,61 @@ func (al *ActivityLogger) StartSession(ctx context.Context, startingCapital floa
 		"starting_capital": startingCapital,
 	}).Info("Trading session started")
 
-	return al.saveLog()
+	return false, al.saveLog()
+}
+
+// resumeSession makes today's existing log current. A log that exists but
+// can't be read is an error, so it is never overwritten by a fresh session.
+func (al *ActivityLogger) resumeSession(date string) (bool, error) {
+	if al.currentLog != nil && al.currentLog.Date == date {
+		return true, nil
+	}
+
+	data, err := al.readActivityLogFile(date)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read existing log for %s: %w", date, err)
+	}
+
+	var log DailyActivityLog
+	if err := json.Unmarshal(data, &log); err != nil {
+		return false, fmt.Errorf("existing log for %s is unreadable (start with force_new to replace it): %w", date, err)
+	}
+
+	// Reopen a session that was ended earlier today
+	log.SessionEnd = time.Time{}
+	if log.Activities == nil {
+		log.Activities = make([]Activity, 0)
+	}
+	if log.PositionsOpened == nil {
+		log.PositionsOpened = make([]PositionActivity, 0)
+	}
+	if log.PositionsClosed == nil {
+		log.PositionsClosed = make([]PositionActivity, 0)
+	}
+	if log.MarketIntelligence == nil {
+		log.MarketIntelligence = make([]IntelligenceNote, 0)
+	}
+	if log.Decisions == nil {
+		log.Decisions = make([]DecisionLog, 0)
+	}
+	al.currentLog = &log
+
+	al.logger.WithFields(logrus.Fields{
+		"date":       date,
+		"activities": len(log.Activities),
+	}).Info("Resumed existing trading session")
+
+	return true, al.saveLog()
 }
 
 // EndSession closes the current trading session
 func (al *ActivityLogger) EndSession(ctx context.Context, endingCapital float64, activePositions int) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
 	if al.currentLog == nil {
 		return fmt.Errorf("no active session")
 	}
@@ -167,6 +247,9 @@ func (al *ActivityLogger) EndSession(ctx context.Context, endingCapital float64,
 
 // LogActivity logs a general activity
 func (al *ActivityLogger) LogActivity(activityType, action, symbol, reasoning string, details map[string]interface{}) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
 	if al.currentLog == nil {
 		return fmt.Errorf("no active session - call StartSession first")
 	}
@@ -192,7 +275,10 @@ func (al *ActivityLogger) LogActivity(activityType, action, symbol, reasoning st
 }
 
 // LogPositionOpened logs when a new position is opened
-func (al *ActivityLogger) LogPositionOpened(symbol, side string, quantity, entryPrice, allocation, stopLoss, takeProfit float64, conviction int, reasoning string, tags []string) error {
+func (al *ActivityLogger) LogPositionOpened(symbol, side, strategy string, quantity, entryPrice, allocation, stopLoss, takeProfit float64, conviction int, reasoning string, tags []string) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
 	if al.currentLog == nil {
 		return fmt.Errorf("no active session")
 	}
@@ -201,6 +287,7 @@ func (al *ActivityLogger) LogPositionOpened(symbol, side string, quantity, entry
 		Timestamp:        time.Now(),
 		Symbol:           symbol,
 		Side:             side,
+		Strategy:         strategy,
 		Quantity:         quantity,
 		EntryPrice:       entryPrice,
 		AllocationDollar: allocation,
@@ -226,36 +313,44 @@ func (al *ActivityLogger) LogPositionOpened(symbol, side string, quantity, entry
 }
 
 // LogPositionClosed logs when a position is closed
-func (al *ActivityLogger) LogPositionClosed(symbol, side string, quantity, entryPrice, exitPrice, allocation float64, holdDays int, reasoning string, tags []string) error {
+func (al *ActivityLogger) LogPositionClosed(symbol, side, strategy string, quantity, entryPrice, exitPrice, allocation float64, holdDays int, reasoning string, tags []string) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
 	if al.currentLog == nil {
 		return fmt.Errorf("no active session")
 	}
 
-	pnl := 0.0
-	pnlPercent := 0.0
-
+	multiplier := contractMultiplier(symbol)
+	grossPnL := 0.0
 	if side == "buy" {
-		pnl = (exitPrice - entryPrice) * quantity
-		if entryPrice > 0 {
-			pnlPercent = ((exitPrice - entryPrice) / entryPrice) * 100
-		}
+		grossPnL = (exitPrice - entryPrice) * quantity * multiplier
 	} else {
-		pnl = (entryPrice - exitPrice) * quantity
-		if entryPrice > 0 {
-			pnlPercent = ((entryPrice - exitPrice) / entryPrice) * 100
-		}
+		grossPnL = (entryPrice - exitPrice) * quantity * multiplier
+	}
+
+	// Net P&L after estimated round-trip fees
+	grossPnL = RoundMoney(grossPnL)
+	fees := RoundMoney(al.fees.RoundTripFees(symbol, quantity, entryPrice, exitPrice))
+	pnl := RoundMoney(grossPnL - fees)
+	pnlPercent := 0.0
+	if entryPrice > 0 && quantity > 0 {
+		pnlPercent = RoundPercent(pnl / (entryPrice * quantity * multiplier) * 100)
 	}
 
 	position := PositionActivity{
 		Timestamp:        time.Now(),
 		Symbol:           symbol,
 		Side:             side,
+		Strategy:         strategy,
 		Quantity:         quantity,
-		EntryPrice:       entryPrice,
-		ExitPrice:        exitPrice,
+		EntryPrice:       RoundPrice(entryPrice),
+		ExitPrice:        RoundPrice(exitPrice),
 		AllocationDollar: allocation,
 		PnL:              pnl,
 		PnLPercent:       pnlPercent,
+		GrossPnL:         grossPnL,
+		Fees:             fees,
 		HoldDays:         holdDays,
 		Reasoning:        reasoning,
 		Tags:             tags,
@@ -263,6 +358,7 @@ func (al *ActivityLogger) LogPositionClosed(symbol, side string, quantity, entry
 
 	al.currentLog.PositionsClosed = append(al.currentLog.PositionsClosed, position)
 	al.currentLog.Summary.PositionsClosed++
+	al.currentLog.Summary.TotalFees = RoundMoney(al.currentLog.Summary.TotalFees + fees)
 
 	// Update win/loss stats
 	if pnl > 0 {
@@ -280,6 +376,8 @@ func (al *ActivityLogger) LogPositionClosed(symbol, side string, quantity, entry
 	al.logger.WithFields(logrus.Fields{
 		"symbol":      symbol,
 		"pnl":         pnl,
+		"gross_pnl":   grossPnL,
+		"fees":        fees,
 		"pnl_percent": pnlPercent,
 		"hold_days":   holdDays,
 	}).Info("Position closed logged")
@@ -289,6 +387,9 @@ func (al *ActivityLogger) LogPositionClosed(symbol, side string, quantity, entry
 
 // LogIntelligence logs market intelligence gathering
 func (al *ActivityLogger) LogIntelligence(source, topic, summary string, symbols []string) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
 	if al.currentLog == nil {
 		return fmt.Errorf("no active session")
 	}
@@ -313,12 +414,9 @@ func (al *ActivityLogger) LogIntelligence(source, topic, summary string, symbols
 	return al.saveLog()
 }
 
-// LogDecision logs a trading decision
-func (al *ActivityLogger) LogDecision(action, symbol, reasoning string, conviction int, marketData map[string]interface{}) error {
-	if al.currentLog == nil {
-		return fmt.Errorf("no active session")
-	}
-
+// LogDecision logs a trading decision along with a snapshot of the symbol's
+// quote and analysis when a snapshot source is set
+func (al *ActivityLogger) LogDecision(ctx context.Context, action, symbol, reasoning string, conviction int, marketData map[string]interface{}) error {
 	decision := DecisionLog{
 		Timestamp:  time.Now(),
 		Action:     action,
@@ -328,6 +426,18 @@ func (al *ActivityLogger) LogDecision(action, symbol, reasoning string, convicti
 		MarketData: marketData,
 	}
 
+	// Capture before locking: the snapshot may call out to the data provider
+	if al.snapshots != nil && symbol != "" {
+		decision.Snapshot = al.snapshots.DecisionSnapshot(ctx, symbol)
+	}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if al.currentLog == nil {
+		return fmt.Errorf("no active session")
+	}
+
 	al.currentLog.Decisions = append(al.currentLog.Decisions, decision)
 
 	return al.saveLog()
@@ -335,6 +445,9 @@ func (al *ActivityLogger) LogDecision(action, symbol, reasoning string, convicti
 
 // LogStocksAnalyzed updates the count of stocks analyzed
 func (al *ActivityLogger) LogStocksAnalyzed(count int) error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
 	if al.currentLog == nil {
 		return fmt.Errorf("no active session")
 	}
@@ -354,9 +467,7 @@ func (al *ActivityLogger) GetCurrentLog() (*DailyActivityLog, error) {
 
 // GetLogForDate retrieves the log for a specific date
 func (al *ActivityLogger) GetLogForDate(date string) (*DailyActivityLog, error) {
-	filename := filepath.Join(al.logDir, fmt.Sprintf("activity_%s.json", date))
-
-	data, err := os.ReadFile(filename)
+	data, err := al.readActivityLogFile(date)
 	if err != nil {
 		return nil, fmt.Errorf("log not found for date %s: %w", date, err)
 	}
@@ -377,14 +488,15 @@ func (al *ActivityLogger) ListAvailableLogs() ([]string, error) {
 	}
 
 	dates := make([]string, 0)
+	seen := make(map[string]bool)
 	for _, file := range files {
-		if !file.IsDir() && filepath.Ext(file.Name()) == ".json" {
-			// Extract date from filename (activity_2025-11-17.json)
-			name := file.Name()
-			if len(name) > 19 && name[:9] == "activity_" {
-				date := name[9 : len(name)-5]
-				dates = append(dates, date)
-			}
+		if file.IsDir() {
+			continue
+		}
+		// Extract date from filename (activity_2025-11-17.json or archived .json.gz)
+		if date, _, ok := parseActivityLogName(file.Name()); ok && !seen[date] {
+			seen[date] = true
+			dates = append(dates, date)
 		}
 	}
 
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timeline entry kinds in a session replay
+const (
+	ReplayDecision       = "DECISION"
+	ReplayActivity       = "ACTIVITY"
+	ReplayPositionOpened = "POSITION_OPENED"
+	ReplayPositionClosed = "POSITION_CLOSED"
+	ReplayIntelligence   = "INTELLIGENCE"
+)
+
+// DecisionSnapshot is the market state captured when a decision is logged, so
+// a replay can show what the quote and analysis said at that moment
+type DecisionSnapshot struct {
+	CapturedAt     time.Time  `json:"captured_at"`
+	Bid            float64    `json:"bid,omitempty"`
+	Ask            float64    `json:"ask,omitempty"`
+	Price          float64    `json:"price,omitempty"`
+	DayChange      float64    `json:"day_change_percent,omitempty"`
+	Trend          string     `json:"trend,omitempty"`
+	RSI            float64    `json:"rsi_14,omitempty"`
+	VolumeRatio    float64    `json:"volume_ratio,omitempty"`
+	Support        float64    `json:"support_level,omitempty"`
+	Resistance     float64    `json:"resistance_level,omitempty"`
+	TechnicalScore int        `json:"technical_score,omitempty"`
+	CatalystScore  int        `json:"catalyst_score,omitempty"`
+	VolumeScore    int        `json:"volume_score,omitempty"`
+	CompositeScore float64    `json:"composite_score,omitempty"`
+	Confluence     string     `json:"confluence,omitempty"`
+	DataQuality    string     `json:"data_quality,omitempty"`
+	AnalysisTime   *time.Time `json:"analysis_time,omitempty"` // when the analysis used was computed
+	Errors         []string   `json:"errors,omitempty"`        // parts that could not be captured
+}
+
+// DecisionSnapshotSource captures the market state for a symbol
+type DecisionSnapshotSource interface {
+	DecisionSnapshot(ctx context.Context, symbol string) *DecisionSnapshot
+}
+
+// SetSnapshotSource sets where LogDecision captures market snapshots from.
+// Nil logs decisions with only the caller's market data.
+func (al *ActivityLogger) SetSnapshotSource(source DecisionSnapshotSource) {
+	al.snapshots = source
+}
+
+// DecisionSnapshot captures the latest quote and the (cached) analysis of a
+// symbol. Failures are recorded on the snapshot rather than returned so a
+// decision is never lost for want of market data.
+func (sas *StockAnalysisService) DecisionSnapshot(ctx context.Context, symbol string) *DecisionSnapshot {
+	snapshot := &DecisionSnapshot{CapturedAt: time.Now()}
+
+	if quote, err := sas.dataService.GetLatestQuote(ctx, symbol); err != nil {
+		snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("quote: %v", err))
+	} else {
+		snapshot.Bid = quote.BidPrice
+		snapshot.Ask = quote.AskPrice
+	}
+
+	analysis, err := sas.AnalyzeStock(ctx, symbol)
+	if err != nil {
+		snapshot.Errors = append(snapshot.Errors, fmt.Sprintf("analysis: %v", err))
+		return snapshot
+	}
+
+	analysisTime := analysis.Timestamp
+	snapshot.Price = analysis.CurrentPrice
+	snapshot.DayChange = analysis.Technical.DayChange
+	snapshot.Trend = analysis.Technical.Trend
+	snapshot.RSI = analysis.Technical.RSI
+	snapshot.VolumeRatio = analysis.Technical.VolumeRatio
+	snapshot.Support = analysis.Technical.Support
+	snapshot.Resistance = analysis.Technical.Resistance
+	snapshot.TechnicalScore = analysis.TradeSetup.TechnicalScore
+	snapshot.CatalystScore = analysis.TradeSetup.CatalystScore
+	snapshot.VolumeScore = analysis.TradeSetup.VolumeScore
+	snapshot.CompositeScore = analysis.TradeSetup.CompositeScore
+	snapshot.Confluence = analysis.TradeSetup.Confluence
+	snapshot.DataQuality = analysis.DataQuality
+	snapshot.AnalysisTime = &analysisTime
+	return snapshot
+}
+
+// SessionReplay is a day's activity log reconstructed as one ordered timeline
+type SessionReplay struct {
+	Date         string        `json:"date"`
+	SessionStart time.Time     `json:"session_start"`
+	SessionEnd   time.Time     `json:"session_end,omitempty"`
+	Symbol       string        `json:"symbol,omitempty"` // filter applied, if any
+	Timeline     []ReplayEntry `json:"timeline"`
+	Decisions    int           `json:"decisions"`
+	Snapshots    int           `json:"snapshots"` // decisions that carry a market snapshot
+}
+
+// ReplayEntry is one point in a session replay. LastSnapshot is the most
+// recent decision snapshot for the symbol at or before this point, so every
+// entry shows the market picture the session was acting on.
+type ReplayEntry struct {
+	Timestamp    time.Time              `json:"timestamp"`
+	Kind         string                 `json:"kind"`
+	Action       string                 `json:"action,omitempty"`
+	Symbol       string                 `json:"symbol,omitempty"`
+	Symbols      []string               `json:"symbols,omitempty"` // intelligence notes
+	Reasoning    string                 `json:"reasoning,omitempty"`
+	Conviction   int                    `json:"conviction,omitempty"`
+	MarketData   map[string]interface{} `json:"market_data,omitempty"`
+	Details      map[string]interface{} `json:"details,omitempty"`
+	Position     *PositionActivity      `json:"position,omitempty"`
+	LastSnapshot *DecisionSnapshot      `json:"last_snapshot,omitempty"`
+}
+
+// ReplaySession rebuilds the decision timeline for date (YYYY-MM-DD), oldest
+// first. A non-empty symbol keeps only entries about that symbol.
+func (al *ActivityLogger) ReplaySession(date, symbol string) (*SessionReplay, error) {
+	var log *DailyActivityLog
+	if al.currentLog != nil && al.currentLog.Date == date {
+		log = al.currentLog
+	} else {
+		var err error
+		if log, err = al.GetLogForDate(date); err != nil {
+			return nil, err
+		}
+	}
+
+	symbol = strings.ToUpper(symbol)
+	replay := &SessionReplay{
+		Date:         log.Date,
+		SessionStart: log.SessionStart,
+		SessionEnd:   log.SessionEnd,
+		Symbol:       symbol,
+		Timeline:     []ReplayEntry{},
+	}
+
+	for _, d := range log.Decisions {
+		replay.Timeline = append(replay.Timeline, ReplayEntry{
+			Timestamp:    d.Timestamp,
+			Kind:         ReplayDecision,
+			Action:       d.Action,
+			Symbol:       d.Symbol,
+			Reasoning:    d.Reasoning,
+			Conviction:   d.Conviction,
+			MarketData:   d.MarketData,
+			LastSnapshot: d.Snapshot,
+		})
+	}
+	for _, a := range log.Activities {
+		replay.Timeline = append(replay.Timeline, ReplayEntry{
+			Timestamp: a.Timestamp,
+			Kind:      ReplayActivity,
+			Action:    a.Type + ":" + a.Action,
+			Symbol:    a.Symbol,
+			Reasoning: a.Reasoning,
+			Details:   a.Details,
+		})
+	}
+	for i := range log.PositionsOpened {
+		p := log.PositionsOpened[i]
+		replay.Timeline = append(replay.Timeline, ReplayEntry{
+			Timestamp:  p.Timestamp,
+			Kind:       ReplayPositionOpened,
+			Action:     p.Side,
+			Symbol:     p.Symbol,
+			Reasoning:  p.Reasoning,
+			Conviction: p.Conviction,
+			Position:   &p,
+		})
+	}
+	for i := range log.PositionsClosed {
+		p := log.PositionsClosed[i]
+		replay.Timeline = append(replay.Timeline, ReplayEntry{
+			Timestamp: p.Timestamp,
+			Kind:      ReplayPositionClosed,
+			Action:    p.Side,
+			Symbol:    p.Symbol,
+			Reasoning: p.Reasoning,
+			Position:  &p,
+		})
+	}
+	for _, n := range log.MarketIntelligence {
+		replay.Timeline = append(replay.Timeline, ReplayEntry{
+			Timestamp: n.Timestamp,
+			Kind:      ReplayIntelligence,
+			Action:    n.Source,
+			Symbols:   n.Symbols,
+			Reasoning: n.Topic + ": " + n.Summary,
+		})
+	}
+
+	sort.SliceStable(replay.Timeline, func(i, j int) bool {
+		return replay.Timeline[i].Timestamp.Before(replay.Timeline[j].Timestamp)
+	})
+
+	// Carry each symbol's latest snapshot forward, then apply the filter
+	latest := make(map[string]*DecisionSnapshot)
+	filtered := replay.Timeline[:0]
+	for _, entry := range replay.Timeline {
+		key := strings.ToUpper(entry.Symbol)
+		captured := entry.Kind == ReplayDecision && entry.LastSnapshot != nil
+		if captured {
+			latest[key] = entry.LastSnapshot
+		} else if key != "" {
+			entry.LastSnapshot = latest[key]
+		}
+
+		if symbol != "" && key != symbol && !containsSymbol(entry.Symbols, symbol) {
+			continue
+		}
+		if entry.Kind == ReplayDecision {
+			replay.Decisions++
+			if captured {
+				replay.Snapshots++
+			}
+		}
+		filtered = append(filtered, entry)
+	}
+	replay.Timeline = filtered
+
+	return replay, nil
+}
+
+// containsSymbol reports whether symbols includes symbol, ignoring case
+func containsSymbol(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if strings.EqualFold(s, symbol) {
+			return true
+		}
+	}
+	return false
+}
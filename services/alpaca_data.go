@@ -2,7 +2,7 @@ package services
 
 import (
 	"context"
-	"fmt"
+	"prophet-trader/apperrors"
 	"prophet-trader/interfaces"
 	"time"
 
@@ -44,7 +44,11 @@ func (s *AlpacaDataService) GetHistoricalBars(ctx context.Context, symbol string
 	}).Info("Fetching historical bars")
 
 	// Convert timeframe string to Alpaca TimeFrame
-	tf := s.parseTimeframe(timeframe)
+	canonical, err := NormalizeTimeframe(timeframe)
+	if err != nil {
+		return nil, err
+	}
+	tf := s.parseTimeframe(canonical)
 
 	req := marketdata.GetBarsRequest{
 		TimeFrame:  tf,
@@ -57,7 +61,7 @@ func (s *AlpacaDataService) GetHistoricalBars(ctx context.Context, symbol string
 	barsResp, err := s.client.GetBars(symbol, req)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to fetch historical bars")
-		return nil, fmt.Errorf("failed to get historical bars: %w", err)
+		return nil, brokerError(err, "failed to get historical bars")
 	}
 
 	bars := make([]*interfaces.Bar, 0)
@@ -84,7 +88,7 @@ func (s *AlpacaDataService) GetLatestBar(ctx context.Context, symbol string) (*i
 
 	barsResp, err := s.client.GetLatestBars([]string{symbol}, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest bar: %w", err)
+		return nil, brokerError(err, "failed to get latest bar")
 	}
 
 	if bar, ok := barsResp[symbol]; ok {
@@ -100,7 +104,7 @@ func (s *AlpacaDataService) GetLatestBar(ctx context.Context, symbol string) (*i
 		}, nil
 	}
 
-	return nil, fmt.Errorf("no bar data found for symbol: %s", symbol)
+	return nil, apperrors.NotFound("no bar data found for symbol: %s", symbol)
 }
 
 // GetLatestQuote retrieves the most recent quote for a symbol
@@ -109,7 +113,7 @@ func (s *AlpacaDataService) GetLatestQuote(ctx context.Context, symbol string) (
 
 	quotesResp, err := s.client.GetLatestQuotes([]string{symbol}, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest quote: %w", err)
+		return nil, brokerError(err, "failed to get latest quote")
 	}
 
 	if quote, ok := quotesResp[symbol]; ok {
@@ -123,7 +127,7 @@ func (s *AlpacaDataService) GetLatestQuote(ctx context.Context, symbol string) (
 		}, nil
 	}
 
-	return nil, fmt.Errorf("no quote data found for symbol: %s", symbol)
+	return nil, apperrors.NotFound("no quote data found for symbol: %s", symbol)
 }
 
 // GetLatestTrade retrieves the most recent trade for a symbol
@@ -132,7 +136,7 @@ func (s *AlpacaDataService) GetLatestTrade(ctx context.Context, symbol string) (
 
 	tradesResp, err := s.client.GetLatestTrades([]string{symbol}, req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get latest trade: %w", err)
+		return nil, brokerError(err, "failed to get latest trade")
 	}
 
 	if trade, ok := tradesResp[symbol]; ok {
@@ -144,7 +148,7 @@ func (s *AlpacaDataService) GetLatestTrade(ctx context.Context, symbol string) (
 		}, nil
 	}
 
-	return nil, fmt.Errorf("no trade data found for symbol: %s", symbol)
+	return nil, apperrors.NotFound("no trade data found for symbol: %s", symbol)
 }
 
 // StreamBars starts streaming bar data for specified symbols
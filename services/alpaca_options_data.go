@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"prophet-trader/apperrors"
 	"prophet-trader/interfaces"
 	"time"
 
@@ -19,6 +20,7 @@ type AlpacaOptionsDataService struct {
 	baseURL   string
 	logger    *logrus.Logger
 	client    *http.Client
+	liquidity OptionLiquidityGate
 }
 
 // NewAlpacaOptionsDataService creates a new Alpaca options data service
@@ -38,6 +40,40 @@ func NewAlpacaOptionsDataService(apiKey, secretKey string) *AlpacaOptionsDataSer
 	}
 }
 
+// SetLiquidityGate filters or flags illiquid contracts in chain lookups.
+// Contract listings carry no quote, so only open interest is checked there.
+func (s *AlpacaOptionsDataService) SetLiquidityGate(gate OptionLiquidityGate) error {
+	if err := gate.Validate(); err != nil {
+		return err
+	}
+	s.liquidity = gate
+	return nil
+}
+
+// applyLiquidityGate removes or flags illiquid contracts in place
+func (s *AlpacaOptionsDataService) applyLiquidityGate(contracts map[string]*interfaces.OptionContract) {
+	illiquid := 0
+	for symbol, contract := range contracts {
+		issues := s.liquidity.Issues(contract, false)
+		if len(issues) == 0 {
+			continue
+		}
+		illiquid++
+		if s.liquidity.Mode == LiquidityGateExclude {
+			delete(contracts, symbol)
+		} else {
+			contract.LiquidityIssues = issues
+		}
+	}
+
+	if illiquid > 0 {
+		s.logger.WithFields(logrus.Fields{
+			"illiquid": illiquid,
+			"mode":     s.liquidity.Mode,
+		}).Debug("Applied option liquidity gate")
+	}
+}
+
 // AlpacaOptionsSnapshot represents Alpaca's options snapshot response
 type AlpacaOptionsSnapshot struct {
 	Snapshots map[string]AlpacaOptionContract `json:"snapshots"`
@@ -47,6 +83,7 @@ type AlpacaOptionsSnapshot struct {
 type AlpacaOptionContract struct {
 	LatestQuote AlpacaQuote `json:"latestQuote"`
 	LatestTrade AlpacaTrade `json:"latestTrade"`
+	DailyBar    AlpacaDailyBar `json:"dailyBar"`
 	Greeks      AlpacaGreeks `json:"greeks"`
 	ImpliedVolatility float64 `json:"impliedVolatility"`
 }
@@ -67,6 +104,11 @@ type AlpacaTrade struct {
 	Size      int       `json:"s"`
 }
 
+// AlpacaDailyBar represents the current day's bar
+type AlpacaDailyBar struct {
+	Volume int64 `json:"v"`
+}
+
 // AlpacaGreeks represents Greeks data
 type AlpacaGreeks struct {
 	Delta float64 `json:"delta"`
@@ -108,18 +150,18 @@ func (s *AlpacaOptionsDataService) GetOptionSnapshot(ctx context.Context, option
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch snapshot: %w", err)
+		return nil, brokerError(err, "failed to fetch snapshot")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, brokerStatusError(resp.StatusCode, "API error %d: %s", resp.StatusCode, string(body))
 	}
 
 	var snapshot AlpacaOptionsSnapshot
 	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
-		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+		return nil, brokerError(err, "failed to decode snapshot")
 	}
 
 	// Convert to our format
@@ -136,12 +178,14 @@ func (s *AlpacaOptionsDataService) GetOptionSnapshot(ctx context.Context, option
 			Theta:             alpacaContract.Greeks.Theta,
 			Vega:              alpacaContract.Greeks.Vega,
 			ImpliedVolatility: alpacaContract.ImpliedVolatility,
+			Volume:            alpacaContract.DailyBar.Volume,
+			QuoteTime:         alpacaContract.LatestQuote.Timestamp,
 		}
 
 		return contract, nil
 	}
 
-	return nil, fmt.Errorf("no snapshot data for %s", optionSymbol)
+	return nil, apperrors.NotFound("no snapshot data for %s", optionSymbol)
 }
 
 // GetOptionChain retrieves available options for an underlying symbol
@@ -168,18 +212,18 @@ func (s *AlpacaOptionsDataService) GetOptionChain(ctx context.Context, underlyin
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch option chain: %w", err)
+		return nil, brokerError(err, "failed to fetch option chain")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, brokerStatusError(resp.StatusCode, "API error %d: %s", resp.StatusCode, string(body))
 	}
 
 	var chainResp AlpacaOptionChainResponse
 	if err := json.NewDecoder(resp.Body).Decode(&chainResp); err != nil {
-		return nil, fmt.Errorf("failed to decode chain: %w", err)
+		return nil, brokerError(err, "failed to decode chain")
 	}
 
 	// Convert to our format
@@ -200,6 +244,7 @@ func (s *AlpacaOptionsDataService) GetOptionChain(ctx context.Context, underlyin
 
 		contracts[alpacaContract.Symbol] = contract
 	}
+	s.applyLiquidityGate(contracts)
 
 	s.logger.WithField("count", len(contracts)).Debug("Fetched option chain")
 	return contracts, nil
@@ -237,18 +282,18 @@ func (s *AlpacaOptionsDataService) FindOptionsNearDTE(ctx context.Context, under
 
 	resp, err := s.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch options: %w", err)
+		return nil, brokerError(err, "failed to fetch options")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+		return nil, brokerStatusError(resp.StatusCode, "API error %d: %s", resp.StatusCode, string(body))
 	}
 
 	var chainResp AlpacaOptionChainResponse
 	if err := json.NewDecoder(resp.Body).Decode(&chainResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, brokerError(err, "failed to decode response")
 	}
 
 	contracts := make(map[string]*interfaces.OptionContract)
@@ -268,6 +313,7 @@ func (s *AlpacaOptionsDataService) FindOptionsNearDTE(ctx context.Context, under
 
 		contracts[alpacaContract.Symbol] = contract
 	}
+	s.applyLiquidityGate(contracts)
 
 	s.logger.WithField("count", len(contracts)).Info("Found option contracts")
 	return contracts, nil
@@ -3,10 +3,15 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"prophet-trader/apperrors"
 	"prophet-trader/interfaces"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/alpacahq/alpaca-trade-api-go/v3/alpaca"
@@ -21,6 +26,7 @@ type AlpacaTradingService struct {
 	dataClient *marketdata.Client
 	apiKey     string
 	apiSecret  string
+	baseURL    string
 	logger     *logrus.Logger
 }
 
@@ -48,6 +54,7 @@ func NewAlpacaTradingService(apiKey, secretKey, baseURL string, isPaper bool) (*
 		dataClient: dataClient,
 		apiKey:     apiKey,
 		apiSecret:  secretKey,
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
 		logger:     logger,
 	}, nil
 }
@@ -83,7 +90,7 @@ func (s *AlpacaTradingService) PlaceOrder(ctx context.Context, order *interfaces
 	alpacaOrder, err := s.client.PlaceOrder(req)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to place order")
-		return nil, fmt.Errorf("failed to place order: %w", err)
+		return nil, brokerError(err, "failed to place order")
 	}
 
 	return &interfaces.OrderResult{
@@ -100,7 +107,7 @@ func (s *AlpacaTradingService) CancelOrder(ctx context.Context, orderID string)
 	err := s.client.CancelOrder(orderID)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to cancel order")
-		return fmt.Errorf("failed to cancel order: %w", err)
+		return brokerError(err, "failed to cancel order")
 	}
 
 	return nil
@@ -110,7 +117,7 @@ func (s *AlpacaTradingService) CancelOrder(ctx context.Context, orderID string)
 func (s *AlpacaTradingService) GetOrder(ctx context.Context, orderID string) (*interfaces.Order, error) {
 	alpacaOrder, err := s.client.GetOrder(orderID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get order: %w", err)
+		return nil, brokerError(err, "failed to get order")
 	}
 
 	return s.convertAlpacaOrder(alpacaOrder), nil
@@ -128,7 +135,7 @@ func (s *AlpacaTradingService) ListOrders(ctx context.Context, status string) ([
 
 	alpacaOrders, err := s.client.GetOrders(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list orders: %w", err)
+		return nil, brokerError(err, "failed to list orders")
 	}
 
 	orders := make([]*interfaces.Order, len(alpacaOrders))
@@ -143,7 +150,7 @@ func (s *AlpacaTradingService) ListOrders(ctx context.Context, status string) ([
 func (s *AlpacaTradingService) GetPositions(ctx context.Context) ([]*interfaces.Position, error) {
 	alpacaPositions, err := s.client.GetPositions()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get positions: %w", err)
+		return nil, brokerError(err, "failed to get positions")
 	}
 
 	positions := make([]*interfaces.Position, len(alpacaPositions))
@@ -164,11 +171,42 @@ func (s *AlpacaTradingService) GetPositions(ctx context.Context) ([]*interfaces.
 	return positions, nil
 }
 
+// GetAssetInfo retrieves trading metadata for a symbol
+func (s *AlpacaTradingService) GetAssetInfo(ctx context.Context, symbol string) (*interfaces.AssetInfo, error) {
+	asset, err := s.client.GetAsset(symbol)
+	if err != nil {
+		return nil, brokerError(err, "failed to get asset")
+	}
+
+	info := &interfaces.AssetInfo{
+		Symbol:             asset.Symbol,
+		Class:              string(asset.Class),
+		Exchange:           asset.Exchange,
+		Tradable:           asset.Tradable && asset.Status == alpaca.AssetActive,
+		Shortable:          asset.Shortable,
+		EasyToBorrow:       asset.EasyToBorrow,
+		Fractionable:       asset.Fractionable,
+		Marginable:         asset.Marginable,
+		PriceIncrement:     0.01,
+		SubDollarIncrement: 0.0001,
+		LotSize:            1,
+	}
+
+	// Crypto ticks and lots are left to the crypto asset-class defaults
+	if asset.Class == alpaca.Crypto {
+		info.PriceIncrement = 0
+		info.SubDollarIncrement = 0
+		info.LotSize = 0
+	}
+
+	return info, nil
+}
+
 // GetAccount retrieves account information
 func (s *AlpacaTradingService) GetAccount(ctx context.Context) (*interfaces.Account, error) {
 	alpacaAccount, err := s.client.GetAccount()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get account: %w", err)
+		return nil, brokerError(err, "failed to get account")
 	}
 
 	return &interfaces.Account{
@@ -251,7 +289,7 @@ func (s *AlpacaTradingService) PlaceOptionsOrder(ctx context.Context, order *int
 	alpacaOrder, err := s.client.PlaceOrder(req)
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to place options order")
-		return nil, fmt.Errorf("failed to place options order: %w", err)
+		return nil, brokerError(err, "failed to place options order")
 	}
 
 	return &interfaces.OrderResult{
@@ -276,6 +314,9 @@ type alpacaOptionsSnapshot struct {
 			Size  int       `json:"s"`
 			T     time.Time `json:"t"`
 		} `json:"latestTrade"`
+		DailyBar struct {
+			Volume int64 `json:"v"`
+		} `json:"dailyBar"`
 		Greeks struct {
 			Delta float64 `json:"delta"`
 			Gamma float64 `json:"gamma"`
@@ -317,32 +358,35 @@ func (s *AlpacaTradingService) GetOptionsChain(ctx context.Context, underlying s
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch options chain: %w", err)
+		return nil, brokerError(err, "failed to fetch options chain")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("options chain API error (HTTP %d): %s", resp.StatusCode, string(body))
+		return nil, brokerStatusError(resp.StatusCode, "options chain API error (HTTP %d): %s", resp.StatusCode, string(body))
 	}
 
 	// Parse response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, brokerError(err, "failed to read response")
 	}
 
 	var snapshot alpacaOptionsSnapshot
 	if err := json.Unmarshal(body, &snapshot); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+		return nil, brokerError(err, "failed to parse response")
+	}
+
+	// Snapshots carry no open interest; it comes from the contracts listing
+	openInterest, err := s.getOpenInterest(ctx, underlying, expiration)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to get open interest for options chain")
 	}
 
 	// Convert to our OptionContract format
 	contracts := make([]*interfaces.OptionContract, 0, len(snapshot.Snapshots))
 	for symbol, data := range snapshot.Snapshots {
-		// Parse the OCC symbol to extract strike, expiration, and type
-		// OCC format: TSLA251219C00400000
-		// This is a simplified parser - you may want to use a proper OCC parser library
 		contract := &interfaces.OptionContract{
 			Symbol:           symbol,
 			UnderlyingSymbol: underlying,
@@ -354,8 +398,14 @@ func (s *AlpacaTradingService) GetOptionsChain(ctx context.Context, underlying s
 			Gamma:            data.Greeks.Gamma,
 			Theta:            data.Greeks.Theta,
 			Vega:             data.Greeks.Vega,
+			Volume:           data.DailyBar.Volume,
+			OpenInterest:     openInterest[symbol],
 			ExpirationDate:   expiration,
-			// TODO: Parse strike price and option type from OCC symbol
+		}
+		// Strike and type come from the OCC symbol, e.g. TSLA251219C00400000
+		if _, contractType, strike, _, err := parseOCCSymbol(symbol); err == nil {
+			contract.ContractType = contractType
+			contract.StrikePrice = strike
 		}
 		contracts = append(contracts, contract)
 	}
@@ -364,6 +414,140 @@ func (s *AlpacaTradingService) GetOptionsChain(ctx context.Context, underlying s
 	return contracts, nil
 }
 
+// alpacaOptionContracts is the response from the option contracts endpoint
+type alpacaOptionContracts struct {
+	OptionContracts []struct {
+		Symbol         string `json:"symbol"`
+		ExpirationDate string `json:"expiration_date"`
+		OpenInterest   string `json:"open_interest"`
+	} `json:"option_contracts"`
+	NextPageToken *string `json:"next_page_token"`
+}
+
+// getOpenInterest returns open interest by contract symbol for one expiration
+func (s *AlpacaTradingService) getOpenInterest(ctx context.Context, underlying string, expiration time.Time) (map[string]int64, error) {
+	openInterest := make(map[string]int64)
+	pageToken := ""
+
+	for {
+		url := fmt.Sprintf("%s/v2/options/contracts?underlying_symbols=%s&expiration_date=%s&limit=10000",
+			s.baseURL, underlying, expiration.Format("2006-01-02"))
+		if pageToken != "" {
+			url += "&page_token=" + pageToken
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return openInterest, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("APCA-API-KEY-ID", s.apiKey)
+		req.Header.Set("APCA-API-SECRET-KEY", s.apiSecret)
+		req.Header.Set("Accept", "application/json")
+
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			return openInterest, brokerError(err, "failed to fetch option contracts")
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return openInterest, brokerError(err, "failed to read response")
+		}
+		if resp.StatusCode != http.StatusOK {
+			return openInterest, brokerStatusError(resp.StatusCode, "option contracts API error (HTTP %d): %s", resp.StatusCode, string(body))
+		}
+
+		var page alpacaOptionContracts
+		if err := json.Unmarshal(body, &page); err != nil {
+			return openInterest, brokerError(err, "failed to parse response")
+		}
+
+		for _, contract := range page.OptionContracts {
+			// Open interest is a string, and empty when the contract hasn't traded
+			if oi, err := strconv.ParseInt(contract.OpenInterest, 10, 64); err == nil {
+				openInterest[contract.Symbol] = oi
+			}
+		}
+
+		if page.NextPageToken == nil || *page.NextPageToken == "" {
+			break
+		}
+		pageToken = *page.NextPageToken
+	}
+
+	return openInterest, nil
+}
+
+// GetOptionExpirations returns the distinct expiration dates of active contracts
+// for an underlying, from today onward, in ascending order
+func (s *AlpacaTradingService) GetOptionExpirations(ctx context.Context, underlying string) ([]time.Time, error) {
+	seen := make(map[string]bool)
+	expirations := make([]time.Time, 0)
+	pageToken := ""
+
+	for {
+		url := fmt.Sprintf("%s/v2/options/contracts?underlying_symbols=%s&status=active&expiration_date_gte=%s&limit=10000",
+			s.baseURL, underlying, time.Now().Format("2006-01-02"))
+		if pageToken != "" {
+			url += "&page_token=" + pageToken
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("APCA-API-KEY-ID", s.apiKey)
+		req.Header.Set("APCA-API-SECRET-KEY", s.apiSecret)
+		req.Header.Set("Accept", "application/json")
+
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, brokerError(err, "failed to fetch option contracts")
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, brokerError(err, "failed to read response")
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, brokerStatusError(resp.StatusCode, "option contracts API error (HTTP %d): %s", resp.StatusCode, string(body))
+		}
+
+		var page alpacaOptionContracts
+		if err := json.Unmarshal(body, &page); err != nil {
+			return nil, brokerError(err, "failed to parse response")
+		}
+
+		for _, contract := range page.OptionContracts {
+			if seen[contract.ExpirationDate] {
+				continue
+			}
+			seen[contract.ExpirationDate] = true
+			if expiration, err := time.Parse("2006-01-02", contract.ExpirationDate); err == nil {
+				expirations = append(expirations, expiration)
+			}
+		}
+
+		if page.NextPageToken == nil || *page.NextPageToken == "" {
+			break
+		}
+		pageToken = *page.NextPageToken
+	}
+
+	sort.Slice(expirations, func(i, j int) bool {
+		return expirations[i].Before(expirations[j])
+	})
+
+	s.logger.WithFields(logrus.Fields{
+		"underlying":  underlying,
+		"expirations": len(expirations),
+	}).Info("Fetched option expirations")
+
+	return expirations, nil
+}
+
 // GetOptionsQuote retrieves a quote for a specific options contract
 func (s *AlpacaTradingService) GetOptionsQuote(ctx context.Context, symbol string) (*interfaces.OptionsQuote, error) {
 	// Note: This would use Alpaca's options quotes API
@@ -377,7 +561,7 @@ func (s *AlpacaTradingService) GetOptionsQuote(ctx context.Context, symbol strin
 func (s *AlpacaTradingService) GetOptionsPosition(ctx context.Context, symbol string) (*interfaces.OptionsPosition, error) {
 	positions, err := s.client.GetPositions()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get positions: %w", err)
+		return nil, brokerError(err, "failed to get positions")
 	}
 
 	for _, pos := range positions {
@@ -396,14 +580,14 @@ func (s *AlpacaTradingService) GetOptionsPosition(ctx context.Context, symbol st
 		}
 	}
 
-	return nil, fmt.Errorf("options position not found: %s", symbol)
+	return nil, apperrors.NotFound("options position not found: %s", symbol)
 }
 
 // ListOptionsPositions retrieves all options positions
 func (s *AlpacaTradingService) ListOptionsPositions(ctx context.Context) ([]*interfaces.OptionsPosition, error) {
 	positions, err := s.client.GetPositions()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get positions: %w", err)
+		return nil, brokerError(err, "failed to get positions")
 	}
 
 	optionsPositions := []*interfaces.OptionsPosition{}
@@ -424,4 +608,25 @@ func (s *AlpacaTradingService) ListOptionsPositions(ctx context.Context) ([]*int
 	}
 
 	return optionsPositions, nil
-}
\ No newline at end of file
+}
+// brokerError classifies an Alpaca API failure: HTTP 429 is a rate limit,
+// 404 a missing resource and anything else a broker error
+func brokerError(err error, action string) error {
+	var apiErr *alpaca.APIError
+	if errors.As(err, &apiErr) {
+		return brokerStatusError(apiErr.StatusCode, "%s: %w", action, err)
+	}
+	return apperrors.Broker("%s: %w", action, err)
+}
+
+// brokerStatusError classifies a non-OK response by its HTTP status
+func brokerStatusError(statusCode int, format string, args ...any) error {
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return apperrors.RateLimited(format, args...)
+	case http.StatusNotFound:
+		return apperrors.NotFound(format, args...)
+	default:
+		return apperrors.Broker(format, args...)
+	}
+}
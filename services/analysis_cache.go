@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultAnalysisCacheTTL is how long a full stock analysis is reused
+const DefaultAnalysisCacheTTL = 45 * time.Second
+
+// cachedAnalysis is a completed analysis and when it was produced
+type cachedAnalysis struct {
+	analysis *StockAnalysis
+	cachedAt time.Time
+}
+
+// SetAnalysisCacheTTL sets how long AnalyzeStock results are reused per
+// symbol. Zero disables the cache.
+func (sas *StockAnalysisService) SetAnalysisCacheTTL(ttl time.Duration) error {
+	if ttl < 0 {
+		return fmt.Errorf("analysis cache TTL must not be negative")
+	}
+
+	sas.analysisMu.Lock()
+	defer sas.analysisMu.Unlock()
+	sas.analysisTTL = ttl
+	if ttl == 0 {
+		sas.analyses = make(map[string]cachedAnalysis)
+	}
+	return nil
+}
+
+// SetAnalysisBarFreshness makes a cached analysis expire as soon as a newer
+// latest bar exists for the symbol, so results are reused until a new candle
+// forms. The TTL still applies: whichever of the two triggers first wins.
+// Each cache hit then costs one latest-bar lookup; if that lookup fails the
+// entry is judged by the TTL alone.
+func (sas *StockAnalysisService) SetAnalysisBarFreshness(enabled bool) {
+	sas.analysisMu.Lock()
+	defer sas.analysisMu.Unlock()
+	sas.barFreshness = enabled
+}
+
+// AnalyzeStock provides comprehensive analysis for a single stock, reusing
+// the previous result for the symbol when it is within the cache TTL and,
+// with bar freshness enabled, no newer bar has arrived
+func (sas *StockAnalysisService) AnalyzeStock(ctx context.Context, symbol string) (*StockAnalysis, error) {
+	return sas.AnalyzeStockWith(ctx, symbol, sas.DefaultAnalysisOptions())
+}
+
+// AnalyzeStockWith is AnalyzeStock with explicit analysis options. Results
+// are cached separately per options.
+func (sas *StockAnalysisService) AnalyzeStockWith(ctx context.Context, symbol string, opts AnalysisOptions) (*StockAnalysis, error) {
+	key := opts.cacheKey(symbol)
+
+	sas.analysisMu.Lock()
+	cached, ok := sas.analyses[key]
+	ttl := sas.analysisTTL
+	barFreshness := sas.barFreshness
+	sas.analysisMu.Unlock()
+
+	if ok && time.Since(cached.cachedAt) < ttl {
+		if !barFreshness || !sas.hasNewerBar(ctx, symbol, cached.analysis) {
+			result := *cached.analysis
+			return &result, nil
+		}
+	}
+
+	return sas.RefreshAnalysisWith(ctx, symbol, opts)
+}
+
+// hasNewerBar reports whether the symbol's latest bar is newer than the one
+// analysis was computed from. An analysis without a bar time is always stale.
+func (sas *StockAnalysisService) hasNewerBar(ctx context.Context, symbol string, analysis *StockAnalysis) bool {
+	if analysis.LatestBarTime == nil {
+		return true
+	}
+
+	bar, err := sas.dataService.GetLatestBar(ctx, symbol)
+	if err != nil {
+		return false
+	}
+	return bar.Timestamp.After(*analysis.LatestBarTime)
+}
+
+// RefreshAnalysis runs the full analysis for a symbol, bypassing and then
+// updating the cache
+func (sas *StockAnalysisService) RefreshAnalysis(ctx context.Context, symbol string) (*StockAnalysis, error) {
+	return sas.RefreshAnalysisWith(ctx, symbol, sas.DefaultAnalysisOptions())
+}
+
+// RefreshAnalysisWith is RefreshAnalysis with explicit analysis options
+func (sas *StockAnalysisService) RefreshAnalysisWith(ctx context.Context, symbol string, opts AnalysisOptions) (*StockAnalysis, error) {
+	analysis, err := sas.analyzeStock(ctx, symbol, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sas.storeAnalysis(opts.cacheKey(symbol), analysis)
+
+	result := *analysis
+	return &result, nil
+}
+
+// storeAnalysis caches an analysis under key, first dropping expired entries
+// so symbols that are never requested again don't stay in memory
+func (sas *StockAnalysisService) storeAnalysis(key string, analysis *StockAnalysis) {
+	sas.analysisMu.Lock()
+	defer sas.analysisMu.Unlock()
+
+	if sas.analysisTTL <= 0 {
+		return
+	}
+	now := time.Now()
+	for cachedKey, cached := range sas.analyses {
+		if now.Sub(cached.cachedAt) >= sas.analysisTTL {
+			delete(sas.analyses, cachedKey)
+		}
+	}
+	sas.analyses[key] = cachedAnalysis{analysis: analysis, cachedAt: now}
+}
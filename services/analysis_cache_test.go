@@ -0,0 +1,38 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoreAnalysisDropsExpiredEntries(t *testing.T) {
+	sas := NewStockAnalysisService(nil, nil, nil)
+	if err := sas.SetAnalysisCacheTTL(time.Minute); err != nil {
+		t.Fatalf("SetAnalysisCacheTTL: %v", err)
+	}
+
+	sas.analyses["OLD"] = cachedAnalysis{analysis: &StockAnalysis{Symbol: "OLD"}, cachedAt: time.Now().Add(-2 * time.Minute)}
+	sas.analyses["RECENT"] = cachedAnalysis{analysis: &StockAnalysis{Symbol: "RECENT"}, cachedAt: time.Now().Add(-30 * time.Second)}
+	sas.storeAnalysis("NEW", &StockAnalysis{Symbol: "NEW"})
+
+	if _, ok := sas.analyses["OLD"]; ok {
+		t.Error("expired entry kept")
+	}
+	for _, key := range []string{"RECENT", "NEW"} {
+		if _, ok := sas.analyses[key]; !ok {
+			t.Errorf("%s missing from the cache", key)
+		}
+	}
+}
+
+func TestStoreAnalysisSkippedWithCacheDisabled(t *testing.T) {
+	sas := NewStockAnalysisService(nil, nil, nil)
+	if err := sas.SetAnalysisCacheTTL(0); err != nil {
+		t.Fatalf("SetAnalysisCacheTTL: %v", err)
+	}
+
+	sas.storeAnalysis("NEW", &StockAnalysis{Symbol: "NEW"})
+	if len(sas.analyses) != 0 {
+		t.Errorf("cache holds %d entries, want none", len(sas.analyses))
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"prophet-trader/interfaces"
+	"strings"
+	"time"
+)
+
+// AnalysisOptions selects the optional, slower parts of a stock analysis
+type AnalysisOptions struct {
+	// IncludeNews searches recent news for catalysts. Without it the catalyst
+	// score is neutral and the analysis is marked NewsSkipped.
+	IncludeNews bool `json:"include_news"`
+
+	// MultiTimeframe adds weekly confluence from ~6 months of daily bars
+	MultiTimeframe bool `json:"multi_timeframe"`
+
+	// YearRange adds 52-week high/low context from a year of daily bars
+	YearRange bool `json:"year_range"`
+
+	// StoredBars uses the daily bar cache as is when it already covers the
+	// range up to the last session, without refetching the latest days
+	// (which also skips split detection for the request)
+	StoredBars bool `json:"stored_bars"`
+}
+
+// Analysis depth presets
+const (
+	AnalysisDepthQuick    = "quick"
+	AnalysisDepthStandard = "standard"
+	AnalysisDepthDeep     = "deep"
+)
+
+// AnalysisOptionsFor returns the options of a depth preset, trading latency
+// and API calls for completeness:
+//   - quick: 30 days of daily bars, served from the bar cache when it is
+//     current; no news search, no weekly confluence, no 52-week range
+//   - standard: the server's configured defaults (ANALYSIS_INCLUDE_NEWS,
+//     ANALYSIS_MULTI_TIMEFRAME, ANALYSIS_YEAR_RANGE), as AnalyzeStock uses
+//   - deep: news search, weekly confluence and the 52-week range, from a
+//     year of daily bars
+//
+// An empty depth is standard.
+func (sas *StockAnalysisService) AnalysisOptionsFor(depth string) (AnalysisOptions, error) {
+	switch depth {
+	case AnalysisDepthQuick:
+		return AnalysisOptions{StoredBars: true}, nil
+	case "", AnalysisDepthStandard:
+		return sas.DefaultAnalysisOptions(), nil
+	case AnalysisDepthDeep:
+		return AnalysisOptions{IncludeNews: true, MultiTimeframe: true, YearRange: true}, nil
+	}
+	return AnalysisOptions{}, fmt.Errorf("invalid analysis depth %q: use quick, standard or deep", depth)
+}
+
+// SetIncludeNews sets whether analyses search news by default. Callers can
+// still override it per request with AnalysisOptions.
+func (sas *StockAnalysisService) SetIncludeNews(enabled bool) {
+	sas.analysisMu.Lock()
+	defer sas.analysisMu.Unlock()
+	sas.includeNews = enabled
+}
+
+// DefaultAnalysisOptions returns the options AnalyzeStock uses
+func (sas *StockAnalysisService) DefaultAnalysisOptions() AnalysisOptions {
+	sas.analysisMu.Lock()
+	defer sas.analysisMu.Unlock()
+	return AnalysisOptions{
+		IncludeNews:    sas.includeNews,
+		MultiTimeframe: sas.multiTimeframe,
+		YearRange:      sas.yearRange,
+	}
+}
+
+// cacheKey keys cached analyses by symbol and options, so a technical-only
+// result is never served for a request that wants news, nor a quick one for
+// a deep request
+func (o AnalysisOptions) cacheKey(symbol string) string {
+	key := strings.ToUpper(symbol)
+	if !o.IncludeNews {
+		key += "|no-news"
+	}
+	if o.MultiTimeframe {
+		key += "|weekly"
+	}
+	if o.YearRange {
+		key += "|52w"
+	}
+	if o.StoredBars {
+		key += "|stored"
+	}
+	return key
+}
+
+// applyNewsSkipped scores the catalyst component as neutral for an analysis
+// that didn't search news, rather than as "no news found"
+func (sas *StockAnalysisService) applyNewsSkipped(setup *TradeSetup) {
+	setup.CatalystScore = sas.neutralScore
+	setup.CompositeScore = sas.weights.Score(setup.TechnicalScore, setup.CatalystScore, setup.VolumeScore)
+	setup.Notes += " | News skipped: catalyst score is neutral"
+}
+
+// storedBarsMaxAge is how old the newest cached daily bar may be for the
+// cache alone to serve a StoredBars request; it spans a long weekend
+const storedBarsMaxAge = 4 * 24 * time.Hour
+
+// analysisBars returns the daily bars an analysis runs on, from the bar cache
+// alone when the options allow it and the cache is current
+func (sas *StockAnalysisService) analysisBars(ctx context.Context, symbol string, start, end time.Time, opts AnalysisOptions) ([]*interfaces.Bar, error) {
+	if opts.StoredBars {
+		if bars, ok := sas.storedDailyBars(symbol, start, end); ok {
+			return bars, nil
+		}
+	}
+	return sas.fetchDailyBars(ctx, symbol, start, end)
+}
+
+// storedDailyBars returns the cached daily bars for a range when they cover
+// it up to the last few days, without any broker request
+func (sas *StockAnalysisService) storedDailyBars(symbol string, start, end time.Time) ([]*interfaces.Bar, bool) {
+	if sas.barStorage == nil {
+		return nil, false
+	}
+
+	stored, err := sas.barStorage.GetDailyBars(symbol, start, end)
+	if err != nil || len(stored) == 0 {
+		return nil, false
+	}
+	if stored[0].Timestamp.Sub(start) >= 7*24*time.Hour || end.Sub(stored[len(stored)-1].Timestamp) > storedBarsMaxAge {
+		return nil, false
+	}
+	return stored, true
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AnalysisSchedulerConfig configures the scheduled watchlist analysis job
+type AnalysisSchedulerConfig struct {
+	Watchlist string        // Name of the saved watchlist to analyze
+	Interval  time.Duration // Time between runs
+	MinScore  int           // Composite score at or above which a setup is recorded
+}
+
+// AnalysisScheduler periodically analyzes a watchlist during market hours and
+// records notable setups in the activity log
+type AnalysisScheduler struct {
+	stockAnalysisService *StockAnalysisService
+	watchlistService     *WatchlistService
+	activityLogger       *ActivityLogger
+	config               AnalysisSchedulerConfig
+	logger               *logrus.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewAnalysisScheduler creates a new analysis scheduler
+func NewAnalysisScheduler(stockAnalysisService *StockAnalysisService, watchlistService *WatchlistService, activityLogger *ActivityLogger, config AnalysisSchedulerConfig) *AnalysisScheduler {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	return &AnalysisScheduler{
+		stockAnalysisService: stockAnalysisService,
+		watchlistService:     watchlistService,
+		activityLogger:       activityLogger,
+		config:               config,
+		logger:               logger,
+	}
+}
+
+// Start runs the scheduler in the background until Stop is called or ctx is cancelled
+func (as *AnalysisScheduler) Start(ctx context.Context) error {
+	if as.config.Watchlist == "" {
+		return fmt.Errorf("no watchlist configured for scheduled analysis")
+	}
+	if as.config.Interval <= 0 {
+		return fmt.Errorf("scheduled analysis interval must be positive")
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	if as.cancel != nil {
+		return fmt.Errorf("scheduled analysis already running")
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	as.cancel = cancel
+	as.done = make(chan struct{})
+
+	go as.run(runCtx, as.done)
+
+	as.logger.WithFields(logrus.Fields{
+		"watchlist": as.config.Watchlist,
+		"interval":  as.config.Interval,
+		"min_score": as.config.MinScore,
+	}).Info("Scheduled analysis started")
+
+	return nil
+}
+
+// Stop stops the scheduler and waits for an in-flight run to finish
+func (as *AnalysisScheduler) Stop() {
+	as.mu.Lock()
+	cancel, done := as.cancel, as.done
+	as.cancel, as.done = nil, nil
+	as.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+func (as *AnalysisScheduler) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(as.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			as.logger.Info("Scheduled analysis stopped")
+			return
+		case now := <-ticker.C:
+			if !IsMarketOpen(now) {
+				continue
+			}
+			as.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce analyzes the configured watchlist and logs notable setups
+func (as *AnalysisScheduler) RunOnce(ctx context.Context) {
+	watchlist, err := as.watchlistService.GetWatchlist(as.config.Watchlist)
+	if err != nil {
+		as.logger.WithError(err).Warn("Scheduled analysis skipped")
+		return
+	}
+
+	analyses, failures, err := as.stockAnalysisService.AnalyzeStocks(ctx, watchlist.Symbols)
+	if err != nil {
+		as.logger.WithError(err).Error("Scheduled analysis failed")
+		return
+	}
+	for symbol, failure := range failures {
+		as.logger.WithError(failure).WithField("symbol", symbol).Warn("Scheduled analysis skipped symbol")
+	}
+
+	// Record notable setups in score order
+	notable := make([]*StockAnalysis, 0)
+	for _, analysis := range analyses {
+		if analysis.TradeSetup.CompositeScore >= float64(as.config.MinScore) {
+			notable = append(notable, analysis)
+		}
+	}
+	sort.Slice(notable, func(i, j int) bool {
+		return notable[i].TradeSetup.CompositeScore > notable[j].TradeSetup.CompositeScore
+	})
+
+	if err := as.activityLogger.LogStocksAnalyzed(len(analyses)); err != nil {
+		as.logger.WithError(err).Warn("Failed to record analyzed stock count")
+	}
+
+	notableSymbols := make([]string, 0, len(notable))
+	for _, analysis := range notable {
+		notableSymbols = append(notableSymbols, analysis.Symbol)
+
+		summary := fmt.Sprintf("Composite %.1f/10 (tech %d, volume %d, catalyst %d) at $%.2f | %s",
+			analysis.TradeSetup.CompositeScore,
+			analysis.TradeSetup.TechnicalScore,
+			analysis.TradeSetup.VolumeScore,
+			analysis.TradeSetup.CatalystScore,
+			analysis.CurrentPrice,
+			analysis.TradeSetup.Notes,
+		)
+		if err := as.activityLogger.LogIntelligence("ANALYSIS", "Notable setup: "+analysis.Symbol, summary, []string{analysis.Symbol}); err != nil {
+			as.logger.WithError(err).Warn("Failed to log notable setup")
+		}
+	}
+
+	summary := fmt.Sprintf("Scheduled analysis of watchlist %q: %d/%d analyzed, %d at or above score %d",
+		watchlist.Name, len(analyses), len(watchlist.Symbols), len(notable), as.config.MinScore)
+	if err := as.activityLogger.LogActivity("ANALYSIS", "SCHEDULED_ANALYSIS", "", summary, map[string]interface{}{
+		"watchlist":       watchlist.Name,
+		"analyzed":        len(analyses),
+		"notable_symbols": notableSymbols,
+		"min_score":       as.config.MinScore,
+	}); err != nil {
+		as.logger.WithError(err).Warn("Failed to log scheduled analysis")
+	}
+
+	as.logger.WithFields(logrus.Fields{
+		"watchlist": watchlist.Name,
+		"analyzed":  len(analyses),
+		"notable":   len(notable),
+	}).Info("Scheduled analysis complete")
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"prophet-trader/interfaces"
+	"sync"
+	"time"
+)
+
+// DefaultAssetCacheTTL is how long asset metadata is reused before it is refetched
+const DefaultAssetCacheTTL = time.Hour
+
+// US equity tick sizes (Reg NMS sub-penny rule); other classes are in asset_class.go
+const (
+	equityPriceIncrement     = 0.01
+	equitySubDollarIncrement = 0.0001
+)
+
+// ErrAssetInfoUnavailable is returned when the broker can't provide asset metadata
+var ErrAssetInfoUnavailable = errors.New("asset info not available from broker")
+
+// AssetCache caches per-symbol trading metadata (tick size, lot size,
+// shortability, fractionability) fetched from the broker
+type AssetCache struct {
+	provider interfaces.AssetInfoProvider
+	ttl      time.Duration
+
+	entries map[string]cachedAsset
+	mu      sync.RWMutex
+}
+
+type cachedAsset struct {
+	info      *interfaces.AssetInfo
+	fetchedAt time.Time
+}
+
+// NewAssetCache creates an asset cache backed by the trading service. Brokers
+// that don't implement AssetInfoProvider always return ErrAssetInfoUnavailable.
+func NewAssetCache(tradingService interfaces.TradingService, ttl time.Duration) *AssetCache {
+	provider, _ := tradingService.(interfaces.AssetInfoProvider)
+	if ttl <= 0 {
+		ttl = DefaultAssetCacheTTL
+	}
+
+	return &AssetCache{
+		provider: provider,
+		ttl:      ttl,
+		entries:  make(map[string]cachedAsset),
+	}
+}
+
+// Get returns asset metadata for a symbol, fetching it when missing or expired
+func (ac *AssetCache) Get(ctx context.Context, symbol string) (*interfaces.AssetInfo, error) {
+	if ac == nil || ac.provider == nil {
+		return nil, ErrAssetInfoUnavailable
+	}
+
+	ac.mu.RLock()
+	entry, ok := ac.entries[symbol]
+	ac.mu.RUnlock()
+
+	if ok && time.Since(entry.fetchedAt) < ac.ttl {
+		return entry.info, nil
+	}
+
+	info, err := ac.provider.GetAssetInfo(ctx, symbol)
+	if err != nil {
+		// Serve stale metadata rather than nothing
+		if ok {
+			return entry.info, nil
+		}
+		return nil, err
+	}
+
+	ac.mu.Lock()
+	ac.entries[symbol] = cachedAsset{info: info, fetchedAt: time.Now()}
+	ac.mu.Unlock()
+
+	return info, nil
+}
+
+// tickSize returns the minimum price increment for a price, defaulting to the
+// asset class's ticks when the broker doesn't report them
+func tickSize(info *interfaces.AssetInfo, price float64, assetClass string) float64 {
+	if info != nil {
+		if price < 1 && info.SubDollarIncrement > 0 {
+			return info.SubDollarIncrement
+		}
+		if price >= 1 && info.PriceIncrement > 0 {
+			return info.PriceIncrement
+		}
+	}
+
+	rules := rulesFor(assetClass)
+	if price < 1 {
+		return rules.subDollarIncrement
+	}
+	return rules.priceIncrement
+}
+
+// roundToTick rounds a price to the nearest valid tick
+func roundToTick(price, tick float64) float64 {
+	if tick <= 0 {
+		return price
+	}
+
+	// Second rounding clears float noise like 142.38000000000002
+	decimals := math.Max(0, math.Ceil(-math.Log10(tick)))
+	scale := math.Pow(10, decimals)
+	return math.Round(math.Round(price/tick)*tick*scale) / scale
+}
+
+// SetAssetCache replaces the asset metadata cache (e.g. to share one across services)
+func (pm *PositionManager) SetAssetCache(cache *AssetCache) {
+	pm.assets = cache
+}
+
+// roundPrice rounds a price to the symbol's tick size
+func (pm *PositionManager) roundPrice(ctx context.Context, symbol string, price float64) float64 {
+	info, _ := pm.assets.Get(ctx, symbol)
+	return roundToTick(price, tickSize(info, price, pm.assetClass(ctx, symbol)))
+}
+
+// fractionable reports whether fractional quantities may be traded for a symbol.
+// Unknown symbols are assumed fractionable; order types still apply their own limits.
+func (pm *PositionManager) fractionable(ctx context.Context, symbol string) bool {
+	info, err := pm.assets.Get(ctx, symbol)
+	if err != nil {
+		return true
+	}
+	return info.Fractionable
+}
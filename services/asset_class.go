@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"prophet-trader/interfaces"
+	"sort"
+	"strings"
+)
+
+// Asset classes a managed position can hold (AssetClassOption is in options_position.go)
+const (
+	AssetClassEquity = "equity"
+	AssetClassCrypto = "crypto"
+)
+
+// brokerCryptoClass is the broker's asset class for crypto pairs
+const brokerCryptoClass = "crypto"
+
+// assetClassRules are the quantity, price and time-in-force conventions of an asset class
+type assetClassRules struct {
+	qtyIncrement       float64 // step used to size a position from its allocation
+	priceIncrement     float64 // default tick at or above $1
+	subDollarIncrement float64 // default tick below $1
+	defaultTimeInForce string  // "" = gtc, or day for DAY_TRADE
+	entryTimeInForces  map[string]bool
+	riskTimeInForces   map[string]bool
+}
+
+// Crypto trades around the clock in fractional quantities and only accepts
+// gtc and ioc orders, so a DAY_TRADE crypto position still defaults to gtc.
+var assetClassRulesByClass = map[string]assetClassRules{
+	AssetClassEquity: {
+		qtyIncrement:       1,
+		priceIncrement:     equityPriceIncrement,
+		subDollarIncrement: equitySubDollarIncrement,
+		entryTimeInForces:  entryTimeInForces,
+		riskTimeInForces:   riskTimeInForces,
+	},
+	AssetClassCrypto: {
+		qtyIncrement:       fractionalQtyIncrement,
+		priceIncrement:     0.01,
+		subDollarIncrement: 0.000000001,
+		defaultTimeInForce: "gtc",
+		entryTimeInForces:  map[string]bool{"gtc": true, "ioc": true},
+		riskTimeInForces:   map[string]bool{"gtc": true},
+	},
+	AssetClassOption: {
+		qtyIncrement:       1,
+		priceIncrement:     0.01,
+		subDollarIncrement: 0.01,
+		defaultTimeInForce: "day",
+		entryTimeInForces:  map[string]bool{"day": true},
+		riskTimeInForces:   map[string]bool{"day": true},
+	},
+}
+
+// rulesFor returns the rules for an asset class; unknown and empty classes are equities
+func rulesFor(class string) assetClassRules {
+	if rules, ok := assetClassRulesByClass[class]; ok {
+		return rules
+	}
+	return assetClassRulesByClass[AssetClassEquity]
+}
+
+// DetectAssetClass classifies a symbol: OCC symbols are options, pairs like
+// BTC/USD or assets the broker reports as crypto are crypto, anything else
+// is an equity. info may be nil.
+func DetectAssetClass(symbol string, info *interfaces.AssetInfo) string {
+	switch {
+	case isOptionSymbol(symbol):
+		return AssetClassOption
+	case strings.Contains(symbol, "/"):
+		return AssetClassCrypto
+	case info != nil && info.Class == brokerCryptoClass:
+		return AssetClassCrypto
+	default:
+		return AssetClassEquity
+	}
+}
+
+// SetAssetClassOverrides fixes the asset class of specific symbols, for
+// brokers or symbols where detection gets it wrong (e.g. "BTCUSD": "crypto")
+func (pm *PositionManager) SetAssetClassOverrides(overrides map[string]string) error {
+	normalized := make(map[string]string, len(overrides))
+	for symbol, class := range overrides {
+		class = strings.ToLower(strings.TrimSpace(class))
+		if _, ok := assetClassRulesByClass[class]; !ok {
+			return fmt.Errorf("invalid asset class %q for %s: use equity, crypto or option", class, symbol)
+		}
+		normalized[strings.ToUpper(strings.TrimSpace(symbol))] = class
+	}
+
+	pm.assetClasses = normalized
+	return nil
+}
+
+// assetClass returns the configured or detected asset class of a symbol
+func (pm *PositionManager) assetClass(ctx context.Context, symbol string) string {
+	if class, ok := pm.assetClasses[strings.ToUpper(symbol)]; ok {
+		return class
+	}
+	if isOptionSymbol(symbol) || strings.Contains(symbol, "/") {
+		return DetectAssetClass(symbol, nil)
+	}
+	info, _ := pm.assets.Get(ctx, symbol)
+	return DetectAssetClass(symbol, info)
+}
+
+// normalizeQty rounds an order quantity down to what the broker accepts for
+// the symbol. Crypto takes fractional quantities on every order type.
+func (pm *PositionManager) normalizeQty(ctx context.Context, symbol string, qty float64, orderType, timeInForce string) float64 {
+	if pm.assetClass(ctx, symbol) == AssetClassCrypto {
+		return floorToIncrement(qty, fractionalQtyIncrement)
+	}
+	return normalizeOrderQty(qty, orderType, timeInForce, pm.fractionable(ctx, symbol))
+}
+
+// floorToIncrement rounds qty down to a multiple of increment. The small
+// epsilon keeps values like 2.9999999999 from float math from losing a step.
+func floorToIncrement(qty, increment float64) float64 {
+	return math.Floor(qty/increment+1e-6) * increment
+}
+
+// timeInForceList formats an allowed time-in-force set for error messages
+func timeInForceList(allowed map[string]bool) string {
+	values := make([]string, 0, len(allowed))
+	for tif := range allowed {
+		values = append(values, tif)
+	}
+	sort.Strings(values)
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"prophet-trader/apperrors"
+	"prophet-trader/interfaces"
+)
+
+// backtestWarmupDays is how much daily history before the start date is read
+// so indicators are computed from the first simulated day
+const backtestWarmupDays = 120
+
+// Backtest exit reasons
+const (
+	ExitSignal = "SIGNAL" // SELL signal, filled at the next bar's open
+	ExitStop   = "STOP"
+	ExitTarget = "TARGET"
+	ExitEnd    = "END" // still open when the period ended, filled at the last close
+)
+
+// BacktestRequest replays the technical signal over stored daily bars
+type BacktestRequest struct {
+	Symbol        string    `json:"symbol" binding:"required"`
+	Start         string    `json:"start" binding:"required"` // YYYY-MM-DD
+	End           string    `json:"end" binding:"required"`   // YYYY-MM-DD, inclusive
+	Quantity      float64   `json:"quantity" binding:"required,gt=0"`
+	StopPercent   float64   `json:"stop_percent"`   // stop below the entry fill; 0 disables
+	TargetPercent float64   `json:"target_percent"` // target above the entry fill; 0 disables
+	Fill          FillModel `json:"fill"`           // fees default to the configured fee model
+}
+
+// BacktestTrade is one simulated round trip
+type BacktestTrade struct {
+	EntryTime  time.Time `json:"entry_time"`
+	EntryPrice float64   `json:"entry_price"`
+	ExitTime   time.Time `json:"exit_time"`
+	ExitPrice  float64   `json:"exit_price"`
+	ExitReason string    `json:"exit_reason"`
+	Quantity   float64   `json:"quantity"`
+	Fees       float64   `json:"fees"`
+	NetPnL     float64   `json:"net_pnl"`
+}
+
+// BacktestResult summarizes a backtest
+type BacktestResult struct {
+	Symbol    string          `json:"symbol"`
+	Bars      int             `json:"bars"` // simulated days
+	Trades    []BacktestTrade `json:"trades"`
+	Wins      int             `json:"wins"`
+	WinRate   float64         `json:"win_rate"` // % of trades with positive net P&L
+	TotalFees float64         `json:"total_fees"`
+	NetPnL    float64         `json:"net_pnl"`
+}
+
+// SetFeeModel sets the fees charged by backtests that don't specify their own
+func (sas *StockAnalysisService) SetFeeModel(fees FeeModel) {
+	sas.fees = fees
+}
+
+// Backtest replays the daily technical signal for a symbol over stored bars.
+// It is long only: a BUY signal while flat buys at the next bar's open and a
+// SELL signal while long sells at the next bar's open, both through the fill
+// model, so no trade uses a price it could not have seen. Stops and targets
+// are checked against each bar's low and high.
+func (sas *StockAnalysisService) Backtest(ctx context.Context, req *BacktestRequest) (*BacktestResult, error) {
+	if sas.barStorage == nil {
+		return nil, apperrors.Validation("daily bar cache is not enabled")
+	}
+	if err := req.Fill.Validate(); err != nil {
+		return nil, apperrors.Validation("%v", err)
+	}
+	if req.StopPercent < 0 || req.StopPercent >= 100 || req.TargetPercent < 0 {
+		return nil, apperrors.Validation("stop_percent must be between 0 and 100 and target_percent non-negative")
+	}
+	start, err := time.ParseInLocation("2006-01-02", req.Start, marketLocation)
+	if err != nil {
+		return nil, apperrors.Validation("invalid start date %q: use YYYY-MM-DD", req.Start)
+	}
+	end, err := time.ParseInLocation("2006-01-02", req.End, marketLocation)
+	if err != nil {
+		return nil, apperrors.Validation("invalid end date %q: use YYYY-MM-DD", req.End)
+	}
+	if !start.Before(end) {
+		return nil, apperrors.Validation("start %s must be before end %s", req.Start, req.End)
+	}
+
+	symbol := strings.ToUpper(req.Symbol)
+	end = end.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	bars, err := sas.barStorage.GetDailyBars(symbol, start.AddDate(0, 0, -backtestWarmupDays), end)
+	if err != nil {
+		return nil, err
+	}
+	first := barsThrough(bars, start.Add(-time.Nanosecond))
+	if first == len(bars) {
+		return nil, apperrors.NotFound("no stored daily bars for %s between %s and %s", symbol, req.Start, req.End)
+	}
+
+	cfg := *req
+	cfg.Symbol = symbol
+	if cfg.Fill.Fees.IsZero() {
+		cfg.Fill.Fees = sas.fees
+	}
+
+	tas := NewTechnicalAnalysisService(sas.dataService)
+	return replayBacktest(bars, first, &cfg, func(current int) (string, error) {
+		result, err := tas.AnalyzeAt(ctx, symbol, bars, current)
+		if err != nil {
+			return "", err
+		}
+		return result.Signal, nil
+	})
+}
+
+// replayBacktest simulates trading bars[first:]. signal(current) returns the
+// signal at the close of bar current-1, seeing only bars[:current].
+func replayBacktest(bars []*interfaces.Bar, first int, req *BacktestRequest, signal func(current int) (string, error)) (*BacktestResult, error) {
+	result := &BacktestResult{Symbol: req.Symbol, Bars: len(bars) - first, Trades: []BacktestTrade{}}
+	model := req.Fill
+
+	var open *BacktestTrade
+	var stopPrice, targetPrice float64
+	pending := "" // market order decided at the previous close
+
+	exit := func(bar *interfaces.Bar, fill Fill, reason string) {
+		open.ExitTime = bar.Timestamp
+		open.ExitPrice = fill.Price
+		open.ExitReason = reason
+		open.Fees += fill.Fees
+		open.NetPnL = (open.ExitPrice-open.EntryPrice)*open.Quantity - open.Fees
+		result.Trades = append(result.Trades, *open)
+		open = nil
+	}
+
+	for i := first; i < len(bars); i++ {
+		bar := bars[i]
+
+		switch pending {
+		case "buy":
+			fill := model.MarketFill(req.Symbol, "buy", req.Quantity, bar)
+			open = &BacktestTrade{EntryTime: bar.Timestamp, EntryPrice: fill.Price, Quantity: req.Quantity, Fees: fill.Fees}
+			stopPrice, targetPrice = 0, 0
+			if req.StopPercent > 0 {
+				stopPrice = fill.Price * (1 - req.StopPercent/100)
+			}
+			if req.TargetPercent > 0 {
+				targetPrice = fill.Price * (1 + req.TargetPercent/100)
+			}
+		case "sell":
+			exit(bar, model.MarketFill(req.Symbol, "sell", open.Quantity, bar), ExitSignal)
+		}
+		pending = ""
+
+		// With both in the bar's range the order of the touches is unknown;
+		// assume the stop came first
+		if open != nil && stopPrice > 0 {
+			if fill, ok := model.StopFill(req.Symbol, "buy", open.Quantity, stopPrice, bar); ok {
+				exit(bar, fill, ExitStop)
+			}
+		}
+		if open != nil && targetPrice > 0 {
+			if fill, ok := model.LimitFill(req.Symbol, "buy", open.Quantity, targetPrice, bar); ok {
+				exit(bar, fill, ExitTarget)
+			}
+		}
+
+		// A signal on the last bar has no next open to fill at
+		if i == len(bars)-1 {
+			break
+		}
+		sig, err := signal(i + 1)
+		if err != nil {
+			return nil, err
+		}
+		if open == nil && sig == "BUY" {
+			pending = "buy"
+		} else if open != nil && sig == "SELL" {
+			pending = "sell"
+		}
+	}
+
+	if open != nil {
+		last := bars[len(bars)-1]
+		exit(last, model.CloseFill(req.Symbol, "sell", open.Quantity, last), ExitEnd)
+	}
+
+	for _, trade := range result.Trades {
+		if trade.NetPnL > 0 {
+			result.Wins++
+		}
+		result.TotalFees += trade.Fees
+		result.NetPnL += trade.NetPnL
+	}
+	if len(result.Trades) > 0 {
+		result.WinRate = math.Round(float64(result.Wins)/float64(len(result.Trades))*1000) / 10
+	}
+	return result, nil
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prophet-trader/interfaces"
+)
+
+// backtestBars builds daily bars from open/high/low/close rows
+func backtestBars(ohlc ...[4]float64) []*interfaces.Bar {
+	bars := testBars(make([][3]float64, len(ohlc))...)
+	for i, v := range ohlc {
+		bars[i].Open, bars[i].High, bars[i].Low, bars[i].Close = v[0], v[1], v[2], v[3]
+	}
+	return bars
+}
+
+// scripted returns a signal function answering signals[current], HOLD otherwise
+func scripted(signals map[int]string) func(int) (string, error) {
+	return func(current int) (string, error) {
+		if sig, ok := signals[current]; ok {
+			return sig, nil
+		}
+		return "HOLD", nil
+	}
+}
+
+func TestReplayBacktest(t *testing.T) {
+	whipsaw := backtestBars(
+		[4]float64{100, 101, 99, 100},
+		[4]float64{102, 103, 101, 102},
+		[4]float64{104, 113, 90, 104},
+		[4]float64{106, 107, 105, 106},
+		[4]float64{107, 108, 106, 107},
+	)
+	calm := backtestBars(
+		[4]float64{100, 101, 99, 100},
+		[4]float64{102, 103, 101, 102},
+		[4]float64{104, 105, 103, 104},
+		[4]float64{106, 107, 105, 106},
+		[4]float64{107, 108, 106, 107},
+	)
+	rally := backtestBars(
+		[4]float64{100, 101, 99, 100},
+		[4]float64{102, 103, 101, 102},
+		[4]float64{104, 113, 103, 104},
+		[4]float64{106, 107, 105, 106},
+		[4]float64{107, 108, 106, 107},
+	)
+	model := FillModel{Slippage: SlippageModel{Mode: SlippageFixed, Amount: 0.1}, Fees: FeeModel{PerTrade: 1}}
+
+	tests := []struct {
+		name      string
+		bars      []*interfaces.Bar
+		signals   map[int]string
+		stop      float64
+		target    float64
+		wantEntry float64
+		wantExit  float64
+		wantEnd   int // bar index of the exit
+		reason    string
+	}{
+		{"signal exit at the next open", calm, map[int]string{1: "BUY", 3: "SELL"}, 0, 0, 102.1, 105.9, 3, ExitSignal},
+		{"stop on the bar low, ahead of the target", whipsaw, map[int]string{1: "BUY"}, 5, 10, 102.1, 102.1*0.95 - 0.1, 2, ExitStop},
+		{"target on the bar high", rally, map[int]string{1: "BUY"}, 5, 10, 102.1, 102.1 * 1.1, 2, ExitTarget},
+		{"open at the end closes at the last close", calm, map[int]string{1: "BUY"}, 0, 0, 102.1, 106.9, 4, ExitEnd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &BacktestRequest{Symbol: "TEST", Quantity: 10, StopPercent: tt.stop, TargetPercent: tt.target, Fill: model}
+			result, err := replayBacktest(tt.bars, 0, req, scripted(tt.signals))
+			if err != nil {
+				t.Fatalf("replayBacktest: %v", err)
+			}
+			if len(result.Trades) != 1 {
+				t.Fatalf("trades = %+v, want 1", result.Trades)
+			}
+
+			trade := result.Trades[0]
+			if !trade.EntryTime.Equal(tt.bars[1].Timestamp) || !approxEqual(trade.EntryPrice, tt.wantEntry) {
+				t.Errorf("entry %s at %v, want the next bar's open plus slippage %v", trade.EntryTime, trade.EntryPrice, tt.wantEntry)
+			}
+			if !trade.ExitTime.Equal(tt.bars[tt.wantEnd].Timestamp) || !approxEqual(trade.ExitPrice, tt.wantExit) || trade.ExitReason != tt.reason {
+				t.Errorf("exit %s at %v (%s), want bar %d at %v (%s)", trade.ExitTime, trade.ExitPrice, trade.ExitReason, tt.wantEnd, tt.wantExit, tt.reason)
+			}
+			if !approxEqual(trade.Fees, 2) {
+				t.Errorf("fees = %v, want 2", trade.Fees)
+			}
+			if want := (tt.wantExit-tt.wantEntry)*10 - 2; !approxEqual(trade.NetPnL, want) || !approxEqual(result.NetPnL, want) {
+				t.Errorf("net P&L = %v (total %v), want %v", trade.NetPnL, result.NetPnL, want)
+			}
+		})
+	}
+}
+
+func TestReplayBacktestIgnoresSignalOnLastBar(t *testing.T) {
+	bars := wavyBars(5)
+	var asked []int
+	signal := func(current int) (string, error) {
+		asked = append(asked, current)
+		if current == len(bars) {
+			return "BUY", nil
+		}
+		return "HOLD", nil
+	}
+
+	result, err := replayBacktest(bars, 0, &BacktestRequest{Symbol: "TEST", Quantity: 1}, signal)
+	if err != nil {
+		t.Fatalf("replayBacktest: %v", err)
+	}
+	if len(result.Trades) != 0 {
+		t.Errorf("trades = %+v, want none", result.Trades)
+	}
+	if last := asked[len(asked)-1]; last != len(bars)-1 {
+		t.Errorf("last signal asked for %d bars, want %d: the final bar has no next open", last, len(bars)-1)
+	}
+}
+
+func TestBacktestStoredBars(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, marketLocation)
+	bars := marketDays(start, 200)
+	sas := storedBarsAnalysis(t, bars)
+	sas.SetFeeModel(FeeModel{PerTrade: 1})
+
+	req := &BacktestRequest{
+		Symbol:   "test",
+		Start:    bars[150].Timestamp.Format("2006-01-02"),
+		End:      bars[199].Timestamp.Format("2006-01-02"),
+		Quantity: 10,
+	}
+	result, err := sas.Backtest(ctx, req)
+	if err != nil {
+		t.Fatalf("Backtest: %v", err)
+	}
+	if result.Symbol != "TEST" || result.Bars != 50 {
+		t.Errorf("symbol %s bars %d, want TEST 50", result.Symbol, result.Bars)
+	}
+	if len(result.Trades) == 0 {
+		t.Fatal("no trades: the wavy fixture should produce a BUY signal")
+	}
+	for _, trade := range result.Trades {
+		if trade.EntryTime.Before(bars[151].Timestamp) {
+			t.Errorf("trade entered %s, before the first simulated signal could fill", trade.EntryTime)
+		}
+		if !approxEqual(trade.Fees, 2) {
+			t.Errorf("trade fees = %v, want the configured 1 per order", trade.Fees)
+		}
+	}
+}
+
+func TestBacktestValidation(t *testing.T) {
+	sas := storedBarsAnalysis(t, marketDays(time.Date(2024, 1, 1, 0, 0, 0, 0, marketLocation), 10))
+
+	tests := []struct {
+		name string
+		req  BacktestRequest
+	}{
+		{"bad date", BacktestRequest{Start: "2024/01/01", End: "2024-01-10"}},
+		{"start after end", BacktestRequest{Start: "2024-01-10", End: "2024-01-01"}},
+		{"stop of 100%", BacktestRequest{Start: "2024-01-01", End: "2024-01-10", StopPercent: 100}},
+		{"bad slippage mode", BacktestRequest{Start: "2024-01-01", End: "2024-01-10", Fill: FillModel{Slippage: SlippageModel{Mode: "spread"}}}},
+		{"no bars in range", BacktestRequest{Start: "2025-01-01", End: "2025-02-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Symbol, tt.req.Quantity = "TEST", 1
+			if _, err := sas.Backtest(context.Background(), &tt.req); err == nil {
+				t.Error("accepted")
+			}
+		})
+	}
+}
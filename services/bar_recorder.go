@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"prophet-trader/apperrors"
+	"prophet-trader/database"
+	"prophet-trader/interfaces"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Defaults for recording streamed bars
+const (
+	DefaultBarFlushInterval = 30 * time.Second
+	DefaultBarBufferSize    = 500
+)
+
+// BarRecorder persists live streamed minute bars so intraday history is
+// kept for later analysis. Bars are buffered and written in batches, every
+// flush interval or as soon as the buffer is full, and whatever is buffered
+// is flushed when recording stops.
+type BarRecorder struct {
+	data          interfaces.DataService
+	storage       *database.LocalStorage
+	symbols       []string
+	flushInterval time.Duration
+	bufferSize    int
+	logger        *logrus.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewBarRecorder creates a recorder of streamed bars for symbols. Non-positive
+// flushInterval and bufferSize use the defaults.
+func NewBarRecorder(data interfaces.DataService, storage *database.LocalStorage, symbols []string, flushInterval time.Duration, bufferSize int) *BarRecorder {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	if flushInterval <= 0 {
+		flushInterval = DefaultBarFlushInterval
+	}
+	if bufferSize <= 0 {
+		bufferSize = DefaultBarBufferSize
+	}
+
+	tracked := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		tracked = append(tracked, strings.ToUpper(strings.TrimSpace(symbol)))
+	}
+
+	return &BarRecorder{
+		data:          data,
+		storage:       storage,
+		symbols:       tracked,
+		flushInterval: flushInterval,
+		bufferSize:    bufferSize,
+		logger:        logger,
+	}
+}
+
+// Start subscribes to the bar stream and records it in the background until
+// Stop is called or ctx is cancelled
+func (r *BarRecorder) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return fmt.Errorf("bar recording already running")
+	}
+	if len(r.symbols) == 0 {
+		return fmt.Errorf("no symbols to record")
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	bars, err := r.data.StreamBars(runCtx, r.symbols)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to subscribe to bars: %w", err)
+	}
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(runCtx, bars, r.done)
+
+	r.logger.WithFields(logrus.Fields{
+		"symbols":        r.symbols,
+		"flush_interval": r.flushInterval,
+		"buffer_size":    r.bufferSize,
+	}).Info("Bar recording started")
+
+	return nil
+}
+
+// Stop stops recording and waits for the final flush
+func (r *BarRecorder) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+func (r *BarRecorder) run(ctx context.Context, bars <-chan *interfaces.Bar, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	buffer := make([]*interfaces.Bar, 0, r.bufferSize)
+	for {
+		select {
+		case <-ctx.Done():
+			r.flush(buffer)
+			r.logger.Info("Bar recording stopped")
+			return
+		case bar, ok := <-bars:
+			if !ok {
+				r.flush(buffer)
+				r.logger.Warn("Bar stream closed, recording stopped")
+				return
+			}
+			buffer = append(buffer, bar)
+			if len(buffer) >= r.bufferSize {
+				buffer = r.flush(buffer)
+			}
+		case <-ticker.C:
+			buffer = r.flush(buffer)
+		}
+	}
+}
+
+// flush writes the buffered bars and returns the emptied buffer. Bars that
+// fail to save are kept for the next flush, up to the buffer size, so a
+// brief storage error loses nothing.
+func (r *BarRecorder) flush(buffer []*interfaces.Bar) []*interfaces.Bar {
+	if len(buffer) == 0 {
+		return buffer
+	}
+
+	if err := r.storage.SaveBars(buffer); err != nil {
+		r.logger.WithError(err).WithField("bars", len(buffer)).Error("Failed to save streamed bars")
+		if len(buffer) < r.bufferSize {
+			return buffer
+		}
+		r.logger.WithField("dropped", len(buffer)).Warn("Bar buffer full, dropping unsaved bars")
+	}
+	return buffer[:0]
+}
+
+// Replay returns the recorded minute bars of symbol within a time range
+func (r *BarRecorder) Replay(symbol string, start, end time.Time) ([]*interfaces.Bar, error) {
+	return r.storage.GetStreamBars(strings.ToUpper(symbol), start, end)
+}
+
+// SessionVWAP reconstructs the VWAP of the regular session on the calendar
+// date of day (its year, month and day, taken as an exchange date) from
+// recorded minute bars, as of the last recorded bar
+func (r *BarRecorder) SessionVWAP(symbol string, day time.Time) (*AnchoredVWAPResult, error) {
+	sessionOpen := time.Date(day.Year(), day.Month(), day.Day(), 0, sessionOpenMinutes, 0, 0, marketLocation)
+	sessionClose := time.Date(day.Year(), day.Month(), day.Day(), 0, sessionCloseMinutes, 0, 0, marketLocation)
+
+	bars, err := r.Replay(symbol, sessionOpen, sessionClose.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 {
+		return nil, apperrors.NotFound("no recorded bars for %s on %s", strings.ToUpper(symbol), day.Format("2006-01-02"))
+	}
+	return AnchoredVWAP(bars, sessionOpen)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"prophet-trader/interfaces"
+	"time"
+)
+
+// resampleIntervals maps supported target timeframes to their nominal length
+var resampleIntervals = map[string]time.Duration{
+	"1Min":   time.Minute,
+	"5Min":   5 * time.Minute,
+	"15Min":  15 * time.Minute,
+	"30Min":  30 * time.Minute,
+	"1Hour":  time.Hour,
+	"4Hour":  4 * time.Hour,
+	"1Day":   24 * time.Hour,
+	"1Week":  7 * 24 * time.Hour,
+	"1Month": 30 * 24 * time.Hour,
+}
+
+// marketLocation is the exchange timezone used to align buckets to the session
+var marketLocation = loadMarketLocation()
+
+func loadMarketLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.FixedZone("EST", -5*60*60)
+	}
+	return loc
+}
+
+// ResampleBars aggregates bars into a coarser timeframe without refetching.
+// Open is the first bar's open, close the last bar's close, high/low the
+// extremes, volume the sum and VWAP the volume-weighted average of the inputs.
+//
+// Intraday buckets are aligned to the 9:30 ET session open (so 1Hour bars run
+// 9:30-10:30, 10:30-11:30, ...). Daily buckets use the ET calendar date, weekly
+// buckets the ISO week and monthly buckets the calendar month. The most recent
+// bucket may be partial; it is returned with whatever bars fall inside it.
+func ResampleBars(bars []*interfaces.Bar, targetTimeframe string) ([]*interfaces.Bar, error) {
+	targetTimeframe, err := NormalizeTimeframe(targetTimeframe)
+	if err != nil {
+		return nil, err
+	}
+	target := resampleIntervals[targetTimeframe]
+
+	if len(bars) == 0 {
+		return []*interfaces.Bar{}, nil
+	}
+
+	if source := inferBarInterval(bars); source > target {
+		return nil, fmt.Errorf("cannot resample %s bars to finer timeframe %s", source, targetTimeframe)
+	}
+
+	resampled := make([]*interfaces.Bar, 0)
+
+	var current *interfaces.Bar
+	var currentBucket time.Time
+	vwapNotional := 0.0
+
+	flush := func() {
+		if current != nil && current.Volume > 0 {
+			current.VWAP = vwapNotional / float64(current.Volume)
+		}
+	}
+
+	for _, bar := range bars {
+		bucket := bucketStart(bar.Timestamp, targetTimeframe, target)
+		if current == nil || !bucket.Equal(currentBucket) {
+			flush()
+			current = &interfaces.Bar{
+				Symbol:    bar.Symbol,
+				Timestamp: bucket,
+				Open:      bar.Open,
+				High:      bar.High,
+				Low:       bar.Low,
+			}
+			resampled = append(resampled, current)
+			currentBucket = bucket
+			vwapNotional = 0
+		}
+
+		current.High = math.Max(current.High, bar.High)
+		current.Low = math.Min(current.Low, bar.Low)
+		current.Close = bar.Close
+		current.Volume += bar.Volume
+		vwapNotional += bar.VWAP * float64(bar.Volume)
+	}
+	flush()
+
+	return resampled, nil
+}
+
+// bucketStart returns the start of the target bucket containing t
+func bucketStart(t time.Time, timeframe string, interval time.Duration) time.Time {
+	local := t.In(marketLocation)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, marketLocation)
+
+	switch timeframe {
+	case "1Day":
+		return day
+	case "1Week":
+		// ISO weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case "1Month":
+		return time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, marketLocation)
+	}
+
+	sessionOpen := day.Add(9*time.Hour + 30*time.Minute)
+	elapsed := local.Sub(sessionOpen)
+	buckets := int64(math.Floor(float64(elapsed) / float64(interval)))
+	return sessionOpen.Add(time.Duration(buckets) * interval)
+}
+
+// inferBarInterval estimates the source timeframe from the smallest gap between bars
+func inferBarInterval(bars []*interfaces.Bar) time.Duration {
+	var smallest time.Duration
+	for i := 1; i < len(bars); i++ {
+		gap := bars[i].Timestamp.Sub(bars[i-1].Timestamp)
+		if gap > 0 && (smallest == 0 || gap < smallest) {
+			smallest = gap
+		}
+	}
+	return smallest
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"prophet-trader/interfaces"
+)
+
+// seriesBars builds consecutive bars from start, one per step, with rising
+// prices so each bar's values are distinguishable
+func seriesBars(start time.Time, step time.Duration, n int) []*interfaces.Bar {
+	bars := make([]*interfaces.Bar, n)
+	for i := range bars {
+		price := 100 + float64(i)
+		bars[i] = &interfaces.Bar{
+			Symbol:    "TEST",
+			Timestamp: start.Add(time.Duration(i) * step),
+			Open:      price,
+			High:      price + 2,
+			Low:       price - 1,
+			Close:     price + 1,
+			Volume:    int64(100 * (i + 1)),
+			VWAP:      price + 0.5,
+		}
+	}
+	return bars
+}
+
+func TestResampleBarsDailyToWeekly(t *testing.T) {
+	// Mon 2024-01-08 through Fri 2024-01-19, stamped at midnight ET
+	monday := time.Date(2024, 1, 8, 0, 0, 0, 0, marketLocation)
+	var bars []*interfaces.Bar
+	for week := 0; week < 2; week++ {
+		bars = append(bars, seriesBars(monday.AddDate(0, 0, 7*week), 24*time.Hour, 5)...)
+	}
+	// Give the second week its own prices
+	for i, bar := range bars[5:] {
+		bar.Open, bar.High, bar.Low, bar.Close = 200+float64(i), 205+float64(i), 195+float64(i), 201+float64(i)
+	}
+
+	weekly, err := ResampleBars(bars, "1Week")
+	if err != nil {
+		t.Fatalf("ResampleBars: %v", err)
+	}
+	if len(weekly) != 2 {
+		t.Fatalf("got %d weekly bars, want 2", len(weekly))
+	}
+
+	first := weekly[0]
+	if !first.Timestamp.Equal(monday) {
+		t.Errorf("first week starts %s, want %s", first.Timestamp, monday)
+	}
+	if first.Open != 100 || first.Close != 105 || first.High != 106 || first.Low != 99 {
+		t.Errorf("first week OHLC = %v/%v/%v/%v, want 100/106/99/105", first.Open, first.High, first.Low, first.Close)
+	}
+	if first.Volume != 1500 {
+		t.Errorf("first week volume = %d, want 1500", first.Volume)
+	}
+	// (100.5*100 + 101.5*200 + 102.5*300 + 103.5*400 + 104.5*500) / 1500
+	if !approxEqual(first.VWAP, 154750.0/1500.0) {
+		t.Errorf("first week VWAP = %v, want %v", first.VWAP, 154750.0/1500.0)
+	}
+
+	second := weekly[1]
+	if !second.Timestamp.Equal(monday.AddDate(0, 0, 7)) {
+		t.Errorf("second week starts %s, want %s", second.Timestamp, monday.AddDate(0, 0, 7))
+	}
+	if second.Open != 200 || second.Close != 205 || second.High != 209 || second.Low != 195 {
+		t.Errorf("second week OHLC = %v/%v/%v/%v, want 200/209/195/205", second.Open, second.High, second.Low, second.Close)
+	}
+}
+
+func TestResampleBarsMinuteToFiveMinute(t *testing.T) {
+	open := time.Date(2024, 3, 12, 9, 30, 0, 0, marketLocation)
+	bars := seriesBars(open, time.Minute, 12)
+
+	resampled, err := ResampleBars(bars, "5Min")
+	if err != nil {
+		t.Fatalf("ResampleBars: %v", err)
+	}
+	if len(resampled) != 3 {
+		t.Fatalf("got %d bars, want 3", len(resampled))
+	}
+
+	for i, want := range []time.Time{open, open.Add(5 * time.Minute), open.Add(10 * time.Minute)} {
+		if !resampled[i].Timestamp.Equal(want) {
+			t.Errorf("bar %d starts %s, want %s", i, resampled[i].Timestamp, want)
+		}
+	}
+
+	first := resampled[0]
+	if first.Open != 100 || first.Close != 105 || first.High != 106 || first.Low != 99 || first.Volume != 1500 {
+		t.Errorf("first bar = %+v, want O100 H106 L99 C105 V1500", first)
+	}
+
+	// The last bucket is partial: 9:40 and 9:41 only
+	last := resampled[2]
+	if last.Open != 110 || last.Close != 112 || last.Volume != 1100+1200 {
+		t.Errorf("partial bar = %+v, want O110 C112 V2300", last)
+	}
+}
+
+func TestResampleBarsRejectsFinerTarget(t *testing.T) {
+	bars := seriesBars(time.Date(2024, 1, 8, 0, 0, 0, 0, marketLocation), 24*time.Hour, 3)
+	if _, err := ResampleBars(bars, "5Min"); err == nil {
+		t.Error("daily bars resampled to 5Min without error")
+	}
+}
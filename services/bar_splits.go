@@ -0,0 +1,189 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"prophet-trader/interfaces"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Split handling for the daily bar cache.
+//
+// Bars are fetched split- and dividend-adjusted, so after a split the broker
+// restates all history while the bars already stored keep their old prices.
+// Without correction the stored and fresh halves of a series differ by the
+// split ratio and every indicator spanning the split is wrong.
+//
+// Each cache refill refetches the last stored day alongside the missing ones
+// and compares the two closes for that day:
+//   - equal within barDriftTolerance: the stored history is still valid
+//   - off by a ratio within splitRatioTolerance of a common split ratio
+//     (see commonSplitRatios, or the reciprocal for reverse splits): stored
+//     bars up to that day are rescaled, prices divided by the ratio and
+//     volume multiplied by it
+//   - off by anything else (e.g. a dividend adjustment): the stored bars
+//     are deleted and the whole range is refetched adjusted
+//
+// When the last stored day can't be compared (it's missing from the fresh
+// bars), the overnight gap from its close to the next open is checked instead:
+// a gap of at least minSplitGap that matches a common ratio is treated as a
+// split. Known splits can also be applied directly with ApplySplit.
+
+// commonSplitRatios are the forward split ratios recognized from price data
+var commonSplitRatios = []float64{1.5, 2, 3, 4, 5, 6, 7, 8, 10, 15, 20, 25, 30, 40, 50, 100}
+
+// Tolerances for split detection
+const (
+	barDriftTolerance   = 0.005 // stored vs refetched close for the same day
+	splitRatioTolerance = 0.03  // observed ratio vs a common split ratio
+	minSplitGap         = 1.4   // overnight gap (or its reciprocal) treated as a possible split
+)
+
+// MatchSplitRatio returns the common split ratio (new shares per old share)
+// that priceRatio, an old price divided by the adjusted price, corresponds to
+func MatchSplitRatio(priceRatio float64) (float64, bool) {
+	if priceRatio <= 0 {
+		return 0, false
+	}
+
+	forward := priceRatio
+	if forward < 1 {
+		forward = 1 / forward
+	}
+	for _, ratio := range commonSplitRatios {
+		if math.Abs(forward/ratio-1) <= splitRatioTolerance {
+			if priceRatio < 1 {
+				return 1 / ratio, true
+			}
+			return ratio, true
+		}
+	}
+	return 0, false
+}
+
+// DetectSplitGap reports whether the gap from one day's close to the next
+// day's open is large enough and close enough to a common ratio to be a split
+func DetectSplitGap(prevClose, nextOpen float64) (float64, bool) {
+	if prevClose <= 0 || nextOpen <= 0 {
+		return 0, false
+	}
+	gap := prevClose / nextOpen
+	if gap < minSplitGap && gap > 1/minSplitGap {
+		return 0, false
+	}
+	return MatchSplitRatio(gap)
+}
+
+// adjustBarsForSplit returns copies of bars rescaled by a split ratio
+func adjustBarsForSplit(bars []*interfaces.Bar, ratio float64) []*interfaces.Bar {
+	adjusted := make([]*interfaces.Bar, len(bars))
+	for i, bar := range bars {
+		b := *bar
+		b.Open /= ratio
+		b.High /= ratio
+		b.Low /= ratio
+		b.Close /= ratio
+		b.VWAP /= ratio
+		b.Volume = int64(math.Round(float64(b.Volume) * ratio))
+		adjusted[i] = &b
+	}
+	return adjusted
+}
+
+// reconcileStoredBars checks stored bars against freshly fetched adjusted
+// bars that start at (or just after) the last stored day. It returns the
+// stored bars in adjusted terms, or ok=false when the stored bars were
+// dropped and the range must be refetched.
+func (sas *StockAnalysisService) reconcileStoredBars(symbol string, stored, fresh []*interfaces.Bar) ([]*interfaces.Bar, bool) {
+	if len(stored) == 0 || len(fresh) == 0 {
+		return stored, true
+	}
+
+	last := stored[len(stored)-1]
+	var ratio float64
+	var split bool
+	if fresh[0].Timestamp.Equal(last.Timestamp) {
+		if fresh[0].Close <= 0 || math.Abs(last.Close/fresh[0].Close-1) <= barDriftTolerance {
+			return stored, true
+		}
+		ratio, split = MatchSplitRatio(last.Close / fresh[0].Close)
+		if !split {
+			sas.invalidateStoredBars(symbol, last.Close/fresh[0].Close)
+			return nil, false
+		}
+	} else if ratio, split = DetectSplitGap(last.Close, fresh[0].Open); !split {
+		return stored, true
+	}
+
+	if err := sas.applySplit(symbol, last.Timestamp.Add(time.Second), ratio); err != nil {
+		sas.invalidateStoredBars(symbol, ratio)
+		return nil, false
+	}
+	return adjustBarsForSplit(stored, ratio), true
+}
+
+// invalidateStoredBars drops a symbol's stored daily bars that no longer
+// match adjusted data
+func (sas *StockAnalysisService) invalidateStoredBars(symbol string, priceRatio float64) {
+	deleted, err := sas.barStorage.DeleteDailyBars(symbol)
+	if err != nil {
+		sas.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to delete stale daily bars")
+		return
+	}
+	sas.logger.WithFields(logrus.Fields{
+		"symbol":      symbol,
+		"price_ratio": priceRatio,
+		"deleted":     deleted,
+	}).Warn("Stored daily bars no longer match adjusted data, refetching")
+}
+
+// ApplySplit rescales the stored daily bars of symbol before effective by a
+// split ratio (new shares per old share, e.g. 4 for a 4-for-1 and 0.1 for a
+// 1-for-10 reverse split), for splits known ahead of detection. Bars on or
+// after effective are assumed to be adjusted already.
+func (sas *StockAnalysisService) ApplySplit(symbol string, effective time.Time, ratio float64) (int64, error) {
+	if sas.barStorage == nil {
+		return 0, fmt.Errorf("daily bar cache is not enabled")
+	}
+	if ratio <= 0 || ratio == 1 {
+		return 0, fmt.Errorf("split ratio must be positive and not 1")
+	}
+
+	adjusted, err := sas.barStorage.AdjustDailyBarsForSplit(symbol, effective, ratio)
+	if err != nil {
+		return 0, err
+	}
+	sas.forgetAnalyses(strings.ToUpper(symbol))
+	return adjusted, nil
+}
+
+// applySplit rescales stored bars for a detected split and logs it
+func (sas *StockAnalysisService) applySplit(symbol string, before time.Time, ratio float64) error {
+	adjusted, err := sas.barStorage.AdjustDailyBarsForSplit(symbol, before, ratio)
+	if err != nil {
+		sas.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to adjust stored bars for split")
+		return err
+	}
+	sas.forgetAnalyses(strings.ToUpper(symbol))
+
+	sas.logger.WithFields(logrus.Fields{
+		"symbol":   symbol,
+		"ratio":    ratio,
+		"adjusted": adjusted,
+	}).Warn("Split detected, stored daily bars adjusted")
+	return nil
+}
+
+// forgetAnalyses drops cached analyses of symbol computed from pre-split bars
+func (sas *StockAnalysisService) forgetAnalyses(symbol string) {
+	sas.analysisMu.Lock()
+	defer sas.analysisMu.Unlock()
+	for key := range sas.analyses {
+		if key == symbol || strings.HasPrefix(key, symbol+"|") {
+			delete(sas.analyses, key)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"math"
+	"prophet-trader/interfaces"
+	"time"
+)
+
+// streamChunkBars bounds the bars a single window of a streamed fetch can
+// hold, so memory stays flat however long the requested range is
+const streamChunkBars = 10000
+
+// StreamBars fetches bars for a range in consecutive time windows of at most
+// streamChunkBars bars each and passes every window to emit, oldest first, as
+// soon as it arrives. Bars already emitted are never repeated, so a caller
+// that stops part way can resume from the last timestamp it received.
+// Fetching stops at the first error from the data service or emit.
+func StreamBars(ctx context.Context, data interfaces.DataService, symbol string, start, end time.Time, timeframe string, emit func([]*interfaces.Bar) error) error {
+	timeframe, err := NormalizeTimeframe(timeframe)
+	if err != nil {
+		return err
+	}
+	// Coarse timeframes fit any range in one window (and would overflow Duration)
+	window := end.Sub(start) + time.Second
+	if interval := resampleIntervals[timeframe]; interval < math.MaxInt64/streamChunkBars && interval*streamChunkBars < window {
+		window = interval * streamChunkBars
+	}
+
+	var last time.Time
+	for windowStart := start; !windowStart.After(end); windowStart = windowStart.Add(window) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		windowEnd := windowStart.Add(window - time.Second)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+
+		bars, err := data.GetHistoricalBars(ctx, symbol, windowStart, windowEnd, timeframe)
+		if err != nil {
+			return err
+		}
+
+		fresh := bars[:0]
+		for _, bar := range bars {
+			if bar.Timestamp.After(last) {
+				fresh = append(fresh, bar)
+			}
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+		last = fresh[len(fresh)-1].Timestamp
+
+		if err := emit(fresh); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"prophet-trader/apperrors"
+	"prophet-trader/interfaces"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetBuyingPowerMultiplier sets how the spendable amount for new positions is
+// derived from the account. Zero (the default) uses the broker's reported
+// buying power, which already includes margin on a margin account. A positive
+// value uses cash times the multiplier instead: 1 for a cash account or to
+// stay off margin, 2 for overnight Reg T margin, 4 for intraday margin.
+func (pm *PositionManager) SetBuyingPowerMultiplier(multiplier float64) error {
+	if multiplier < 0 || multiplier > 4 {
+		return fmt.Errorf("buying power multiplier must be between 0 and 4")
+	}
+	pm.buyingPowerMultiplier = multiplier
+	return nil
+}
+
+// pendingAllocation sums the allocations of live managed positions whose entry
+// hasn't filled yet, which the account balance may not reflect
+func (pm *PositionManager) pendingAllocation() float64 {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	total := 0.0
+	for _, position := range pm.positions {
+		if position.Status == "PENDING" && !position.Simulated {
+			total += position.AllocationDollars
+		}
+	}
+	return total
+}
+
+// availableBuyingPower is what new positions may still spend: the account's
+// buying power (or cash times the multiplier) less pending managed entries
+func (pm *PositionManager) availableBuyingPower(account *interfaces.Account) float64 {
+	base := account.BuyingPower
+	if pm.buyingPowerMultiplier > 0 {
+		base = account.Cash * pm.buyingPowerMultiplier
+	}
+	return base - pm.pendingAllocation()
+}
+
+// checkBuyingPower rejects an entry the account can't fund, before any order
+// or local state is created. Dry-run positions spend nothing and are not
+// checked; if the account can't be read the broker is left to decide.
+func (pm *PositionManager) checkBuyingPower(ctx context.Context, req *PlaceManagedPositionRequest) error {
+	if req.DryRun || pm.dryRun {
+		return nil
+	}
+
+	account, err := pm.tradingService.GetAccount(ctx)
+	if err != nil {
+		pm.logger.WithError(err).Warn("Failed to get account for buying power check, leaving it to the broker")
+		return nil
+	}
+
+	available := pm.availableBuyingPower(account)
+	if req.AllocationDollars <= available {
+		return nil
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"symbol":     req.Symbol,
+		"allocation": req.AllocationDollars,
+		"available":  available,
+	}).Warn("Entry rejected for insufficient buying power")
+
+	return apperrors.Validation("insufficient buying power: allocation $%.2f exceeds available $%.2f (after $%.2f in pending managed entries)",
+		req.AllocationDollars, available, pm.pendingAllocation())
+}
@@ -0,0 +1,25 @@
+package services
+
+// Reason codes recorded when a position closes, alongside its status and
+// free-text exit reasoning
+const (
+	CloseReasonTakeProfit     = "TAKE_PROFIT"
+	CloseReasonStopLoss       = "STOP_LOSS"
+	CloseReasonTrailingStop   = "TRAILING_STOP"
+	CloseReasonTimeExit       = "TIME_EXIT"
+	CloseReasonManual         = "MANUAL"
+	CloseReasonReversed       = "REVERSED"
+	CloseReasonExpired        = "EXPIRED"
+	CloseReasonDailyLossLimit = "DAILY_LOSS_LIMIT"
+)
+
+// unspecifiedCloseReason groups trades closed before reason codes were recorded
+const unspecifiedCloseReason = "UNSPECIFIED"
+
+// stopCloseReason tells a trailing stop (percent or SAR) apart from a fixed one
+func stopCloseReason(position *ManagedPosition) string {
+	if position.TrailingStop || position.SARStop {
+		return CloseReasonTrailingStop
+	}
+	return CloseReasonStopLoss
+}
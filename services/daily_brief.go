@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DailyBriefConfig configures the daily brief
+type DailyBriefConfig struct {
+	Watchlist string // Name of the saved watchlist to score
+	TopSetups int    // Number of highest-scoring setups to include
+}
+
+// DailyBrief combines market news, the best watchlist setups and open-position
+// risk into one session-start report
+type DailyBrief struct {
+	GeneratedAt   time.Time         `json:"generated_at"`
+	MarketNews    *CleanedNews      `json:"market_news,omitempty"`
+	NewsError     string            `json:"news_error,omitempty"`
+	Watchlist     string            `json:"watchlist,omitempty"`
+	TopSetups     []*StockScore     `json:"top_setups"`
+	ScoreFailures map[string]string `json:"score_failures,omitempty"`
+	Risk          *RiskSummary      `json:"risk"`
+	Summary       string            `json:"summary"`
+}
+
+// DailyBriefService generates daily briefs and records them in the activity log
+type DailyBriefService struct {
+	newsService          *NewsService
+	geminiService        *GeminiService
+	stockAnalysisService *StockAnalysisService
+	watchlistService     *WatchlistService
+	positionManager      *PositionManager
+	activityLogger       *ActivityLogger
+	config               DailyBriefConfig
+	logger               *logrus.Logger
+}
+
+// NewDailyBriefService creates a new daily brief service
+func NewDailyBriefService(newsService *NewsService, geminiService *GeminiService, stockAnalysisService *StockAnalysisService, watchlistService *WatchlistService, positionManager *PositionManager, activityLogger *ActivityLogger, config DailyBriefConfig) *DailyBriefService {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	if config.TopSetups <= 0 {
+		config.TopSetups = 5
+	}
+
+	return &DailyBriefService{
+		newsService:          newsService,
+		geminiService:        geminiService,
+		stockAnalysisService: stockAnalysisService,
+		watchlistService:     watchlistService,
+		positionManager:      positionManager,
+		activityLogger:       activityLogger,
+		config:               config,
+		logger:               logger,
+	}
+}
+
+// Generate builds a daily brief for watchlist (the configured watchlist when
+// empty) and logs it as an intelligence note. News and scoring failures are
+// reported in the brief rather than failing it.
+func (ds *DailyBriefService) Generate(ctx context.Context, watchlistName string, topSetups int) (*DailyBrief, error) {
+	if watchlistName == "" {
+		watchlistName = ds.config.Watchlist
+	}
+	if topSetups <= 0 {
+		topSetups = ds.config.TopSetups
+	}
+
+	var symbols []string
+	if watchlistName != "" {
+		watchlist, err := ds.watchlistService.GetWatchlist(watchlistName)
+		if err != nil {
+			return nil, err
+		}
+		symbols = watchlist.Symbols
+	}
+
+	brief := &DailyBrief{
+		GeneratedAt: time.Now(),
+		Watchlist:   watchlistName,
+		TopSetups:   make([]*StockScore, 0),
+	}
+
+	news, err := ds.marketNews()
+	if err != nil {
+		ds.logger.WithError(err).Warn("Daily brief generated without market news")
+		brief.NewsError = err.Error()
+	}
+	brief.MarketNews = news
+
+	brief.TopSetups, brief.ScoreFailures = ds.topSetups(ctx, symbols, topSetups)
+	brief.Risk = ds.positionManager.GetRiskSummary()
+	brief.Summary = brief.summarize()
+
+	setupSymbols := make([]string, 0, len(brief.TopSetups))
+	for _, setup := range brief.TopSetups {
+		setupSymbols = append(setupSymbols, setup.Symbol)
+	}
+	if err := ds.activityLogger.LogIntelligence("ANALYSIS", "Daily brief", brief.Summary, setupSymbols); err != nil {
+		ds.logger.WithError(err).Warn("Failed to record daily brief in activity log")
+	}
+
+	return brief, nil
+}
+
+// marketNews cleans the latest MarketWatch headlines, as quick market intelligence does
+func (ds *DailyBriefService) marketNews() (*CleanedNews, error) {
+	sources := []func() ([]NewsItem, error){
+		ds.newsService.GetMarketWatchTopStories,
+		ds.newsService.GetMarketWatchBulletins,
+		ds.newsService.GetMarketWatchMarketPulse,
+	}
+
+	allNews := make([]NewsItem, 0)
+	for _, source := range sources {
+		if news, err := source(); err == nil {
+			if len(news) > 5 {
+				news = news[:5]
+			}
+			allNews = append(allNews, news...)
+		}
+	}
+
+	if len(allNews) == 0 {
+		return nil, fmt.Errorf("no market news found")
+	}
+
+	return ds.geminiService.CleanNewsForTrading(allNews)
+}
+
+// topSetups scores symbols and returns the highest composite scores first
+func (ds *DailyBriefService) topSetups(ctx context.Context, symbols []string, limit int) ([]*StockScore, map[string]string) {
+	scores := make([]*StockScore, 0, len(symbols))
+	failures := make(map[string]string)
+
+	for _, symbol := range symbols {
+		if ctx.Err() != nil {
+			failures[symbol] = ctx.Err().Error()
+			continue
+		}
+		score, err := ds.stockAnalysisService.ScoreStock(ctx, symbol)
+		if err != nil {
+			failures[symbol] = err.Error()
+			continue
+		}
+		scores = append(scores, score)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].CompositeScore > scores[j].CompositeScore
+	})
+	if len(scores) > limit {
+		scores = scores[:limit]
+	}
+	if len(failures) == 0 {
+		failures = nil
+	}
+
+	return scores, failures
+}
+
+// summarize renders the brief as the one-paragraph text stored in the activity log
+func (b *DailyBrief) summarize() string {
+	parts := make([]string, 0, 3)
+
+	if b.MarketNews != nil {
+		parts = append(parts, fmt.Sprintf("Market sentiment: %s. %s", b.MarketNews.MarketSentiment, b.MarketNews.ExecutiveSummary))
+	}
+
+	if len(b.TopSetups) > 0 {
+		setups := make([]string, 0, len(b.TopSetups))
+		for _, setup := range b.TopSetups {
+			setups = append(setups, fmt.Sprintf("%s %.1f", setup.Symbol, setup.CompositeScore))
+		}
+		parts = append(parts, "Top setups: "+strings.Join(setups, ", ")+".")
+	}
+
+	if b.Risk != nil {
+		parts = append(parts, fmt.Sprintf("Open risk: %d positions, $%.2f market value, $%.2f to stops, $%.2f unrealized P&L.",
+			b.Risk.OpenPositions, b.Risk.TotalMarketValue, b.Risk.TotalRiskToStop, b.Risk.TotalUnrealizedPL))
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// IsDayTrade reports whether a position uses the DAY_TRADE strategy or is tagged DAY_TRADE
+func IsDayTrade(position *ManagedPosition) bool {
+	if position.Strategy == "DAY_TRADE" {
+		return true
+	}
+	for _, tag := range position.Tags {
+		if strings.EqualFold(tag, "DAY_TRADE") {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDayTradeFlatten closes day trades automatically the given number of
+// minutes before the regular session close (0 disables it)
+func (pm *PositionManager) SetDayTradeFlatten(minutesBeforeClose int) error {
+	if minutesBeforeClose < 0 || minutesBeforeClose > sessionCloseMinutes-sessionOpenMinutes {
+		return fmt.Errorf("day trade flatten minutes must be between 0 and %d", sessionCloseMinutes-sessionOpenMinutes)
+	}
+
+	pm.flattenBeforeClose = minutesBeforeClose
+	return nil
+}
+
+// CloseAllManagedPositions closes every open or pending managed position
+// matching filter (all when nil) for reason (a CloseReason code) and returns
+// the IDs closed and any failures
+func (pm *PositionManager) CloseAllManagedPositions(ctx context.Context, filter func(*ManagedPosition) bool, reason string) ([]string, map[string]error) {
+	pm.mu.RLock()
+	matched := make([]*ManagedPosition, 0)
+	for _, pos := range pm.positions {
+		if !isTerminalStatus(pos.Status) && (filter == nil || filter(pos)) {
+			matched = append(matched, pos)
+		}
+	}
+	pm.mu.RUnlock()
+
+	closed := make([]string, 0, len(matched))
+	failures := make(map[string]error)
+	for _, pos := range matched {
+		if _, err := pm.closeManagedPosition(ctx, pos.ID, reason); err != nil {
+			failures[pos.ID] = err
+			continue
+		}
+		closed = append(closed, pos.ID)
+	}
+
+	return closed, failures
+}
+
+// FlattenDayTrades closes all day-trade positions and logs each close in the
+// activity log with reason
+func (pm *PositionManager) FlattenDayTrades(ctx context.Context, reason string) ([]string, map[string]error) {
+	closed, failures := pm.CloseAllManagedPositions(ctx, IsDayTrade, CloseReasonTimeExit)
+
+	for _, positionID := range closed {
+		pm.mu.RLock()
+		position := pm.positions[positionID]
+		pm.mu.RUnlock()
+		if position != nil {
+			pm.logPositionEvent(position, "AUTO_FLATTEN", reason, map[string]interface{}{
+				"strategy": position.Strategy,
+			})
+		}
+	}
+	for positionID, err := range failures {
+		pm.logger.WithError(err).WithField("position_id", positionID).Error("Failed to flatten day trade")
+	}
+
+	if len(closed) > 0 || len(failures) > 0 {
+		pm.logger.WithFields(logrus.Fields{
+			"closed": len(closed),
+			"failed": len(failures),
+			"reason": reason,
+		}).Info("Day trades flattened")
+	}
+
+	return closed, failures
+}
+
+// flattenDayTradesNearClose flattens day trades once the session is within
+// the configured number of minutes of the close
+func (pm *PositionManager) flattenDayTradesNearClose(ctx context.Context) {
+	if pm.flattenBeforeClose <= 0 {
+		return
+	}
+
+	minutesLeft, open := MinutesUntilClose(time.Now())
+	if !open || minutesLeft > pm.flattenBeforeClose {
+		return
+	}
+
+	pm.FlattenDayTrades(ctx, fmt.Sprintf("Auto-flattened %d minutes before market close", minutesLeft))
+}
@@ -0,0 +1,30 @@
+package services
+
+import "prophet-trader/interfaces"
+
+// SetDryRun makes every new managed position a dry run, as if each request
+// set dry_run. Positions already open keep trading where they started.
+func (pm *PositionManager) SetDryRun(enabled bool) {
+	pm.dryRun = enabled
+}
+
+// broker returns where a position's orders go: the paper broker for
+// simulated positions, the live broker otherwise
+func (pm *PositionManager) broker(position *ManagedPosition) interfaces.TradingService {
+	if position.Simulated {
+		return pm.paper
+	}
+	return pm.tradingService
+}
+
+// markSimulated flags a new position as a dry run and tags it so its trades
+// and activity are recognizable as simulated
+func markSimulated(position *ManagedPosition) {
+	position.Simulated = true
+	for _, tag := range position.Tags {
+		if tag == simulatedTag {
+			return
+		}
+	}
+	position.Tags = append(append([]string{}, position.Tags...), simulatedTag)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"prophet-trader/database"
+	"prophet-trader/interfaces"
+)
+
+// fakeTrading is an in-memory broker: orders rest as "new" until the test
+// fills them, and cancels take effect immediately. Unimplemented methods
+// panic through the embedded nil interface.
+type fakeTrading struct {
+	interfaces.TradingService
+
+	mu         sync.Mutex
+	nextID     int
+	orders     map[string]*interfaces.Order
+	placed     []*interfaces.Order
+	failPlaces int // reject this many upcoming orders
+}
+
+func newFakeTrading() *fakeTrading {
+	return &fakeTrading{orders: make(map[string]*interfaces.Order)}
+}
+
+func (f *fakeTrading) PlaceOrder(ctx context.Context, order *interfaces.Order) (*interfaces.OrderResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failPlaces > 0 {
+		f.failPlaces--
+		return nil, fmt.Errorf("order rejected")
+	}
+
+	f.nextID++
+	placed := *order
+	placed.ID = fmt.Sprintf("order-%d", f.nextID)
+	placed.Status = "new"
+	f.orders[placed.ID] = &placed
+	f.placed = append(f.placed, &placed)
+	return &interfaces.OrderResult{OrderID: placed.ID, Status: placed.Status}, nil
+}
+
+func (f *fakeTrading) CancelOrder(ctx context.Context, orderID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	order, ok := f.orders[orderID]
+	if !ok {
+		return fmt.Errorf("order %s not found", orderID)
+	}
+	if order.Status != "new" && order.Status != "partially_filled" {
+		return fmt.Errorf("order %s is %s", orderID, order.Status)
+	}
+	order.Status = "canceled"
+	return nil
+}
+
+func (f *fakeTrading) GetOrder(ctx context.Context, orderID string) (*interfaces.Order, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	order, ok := f.orders[orderID]
+	if !ok {
+		return nil, fmt.Errorf("order %s not found", orderID)
+	}
+	copied := *order
+	return &copied, nil
+}
+
+// failNext makes the next n PlaceOrder calls fail
+func (f *fakeTrading) failNext(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failPlaces = n
+}
+
+// fill marks an order filled (or partially filled, when qty is below the
+// order quantity) at price
+func (f *fakeTrading) fill(orderID string, qty, price float64, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	order := f.orders[orderID]
+	order.FilledQty = qty
+	order.FilledAvgPrice = &price
+	order.Status = status
+	now := time.Now()
+	order.FilledAt = &now
+}
+
+// open returns the resting orders of a type
+func (f *fakeTrading) open(orderType string) []*interfaces.Order {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var open []*interfaces.Order
+	for _, order := range f.placed {
+		if order.Type == orderType && order.Status == "new" {
+			copied := *order
+			open = append(open, &copied)
+		}
+	}
+	return open
+}
+
+// newTestPositionManager returns a manager backed by broker and a scratch database
+func newTestPositionManager(t *testing.T, broker interfaces.TradingService) *PositionManager {
+	t.Helper()
+
+	storage, err := database.NewLocalStorage(filepath.Join(t.TempDir(), "prophet.db"))
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	pm := NewPositionManager(broker, nil, storage)
+	t.Cleanup(pm.Stop)
+	return pm
+}
+
+// addActivePosition registers a filled long position with a resting stop and target
+func addActivePosition(t *testing.T, pm *PositionManager, qty float64) *ManagedPosition {
+	t.Helper()
+
+	position := &ManagedPosition{
+		ID:                "POS-TEST",
+		Symbol:            "AAPL",
+		Side:              "buy",
+		Quantity:          qty,
+		RemainingQty:      qty,
+		EntryPrice:        100,
+		CurrentPrice:      100,
+		StopLossPrice:     95,
+		TakeProfitPrice:   110,
+		Status:            "ACTIVE",
+		StopTimeInForce:   "gtc",
+		TargetTimeInForce: "gtc",
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+	pm.positions[position.ID] = position
+
+	ctx := context.Background()
+	if err := pm.placeStopLossOrder(ctx, position); err != nil {
+		t.Fatalf("placeStopLossOrder: %v", err)
+	}
+	if err := pm.placeTakeProfitOrder(ctx, position); err != nil {
+		t.Fatalf("placeTakeProfitOrder: %v", err)
+	}
+	return position
+}
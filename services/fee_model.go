@@ -0,0 +1,66 @@
+package services
+
+import (
+	"math"
+	"regexp"
+)
+
+// optionContractMultiplier is the number of shares one options contract controls
+const optionContractMultiplier = 100
+
+// occSymbolPattern matches OCC option symbols, e.g. TSLA251219C00400000
+var occSymbolPattern = regexp.MustCompile(`^[A-Z.]{1,6}\d{6}[CP]\d{8}$`)
+
+// FeeModel estimates trading costs for a single order. All components are
+// summed; zero values disable a component.
+type FeeModel struct {
+	PerShare    float64 `json:"per_share"`    // $ per share (stocks)
+	PerTrade    float64 `json:"per_trade"`    // $ flat per order
+	PerContract float64 `json:"per_contract"` // $ per options contract
+	Percent     float64 `json:"percent"`      // % of notional (regulatory fees, spread)
+}
+
+// IsZero reports whether the model charges nothing
+func (f FeeModel) IsZero() bool {
+	return f.PerShare == 0 && f.PerTrade == 0 && f.PerContract == 0 && f.Percent == 0
+}
+
+// OrderFees estimates the fees for one order of qty at price. Options are
+// detected from the OCC symbol and charged per contract on the full notional.
+func (f FeeModel) OrderFees(symbol string, qty, price float64) float64 {
+	qty = math.Abs(qty)
+	if qty == 0 {
+		return 0
+	}
+
+	fees := f.PerTrade
+	notional := qty * price
+	if isOptionSymbol(symbol) {
+		fees += f.PerContract * qty
+		notional *= optionContractMultiplier
+	} else {
+		fees += f.PerShare * qty
+	}
+	fees += notional * f.Percent / 100
+
+	return fees
+}
+
+// RoundTripFees estimates the fees for opening and closing qty
+func (f FeeModel) RoundTripFees(symbol string, qty, entryPrice, exitPrice float64) float64 {
+	return f.OrderFees(symbol, qty, entryPrice) + f.OrderFees(symbol, qty, exitPrice)
+}
+
+// contractMultiplier is the dollar value of a one-point move per unit of
+// quantity: 100 for an options contract, 1 for a share
+func contractMultiplier(symbol string) float64 {
+	if isOptionSymbol(symbol) {
+		return optionContractMultiplier
+	}
+	return 1
+}
+
+// isOptionSymbol reports whether symbol is an OCC options symbol
+func isOptionSymbol(symbol string) bool {
+	return occSymbolPattern.MatchString(symbol)
+}
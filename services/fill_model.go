@@ -0,0 +1,113 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"prophet-trader/interfaces"
+)
+
+// Slippage modes
+const (
+	SlippageFixed   = "fixed"   // Amount is $ per share
+	SlippagePercent = "percent" // Amount is % of price
+)
+
+// SlippageModel moves simulated fill prices against the trader
+type SlippageModel struct {
+	Mode   string  `json:"mode"`
+	Amount float64 `json:"amount"`
+}
+
+// FillModel simulates order fills on historical bars. Orders decided on a
+// bar's close execute at the next bar's open, so a simulation never trades
+// on prices it could not have seen.
+type FillModel struct {
+	Slippage SlippageModel `json:"slippage"`
+	Fees     FeeModel      `json:"fees"`
+}
+
+// Fill is a simulated execution
+type Fill struct {
+	Price float64 `json:"price"`
+	Fees  float64 `json:"fees"`
+}
+
+// Validate checks the slippage configuration
+func (m FillModel) Validate() error {
+	switch m.Slippage.Mode {
+	case "", SlippageFixed, SlippagePercent:
+	default:
+		return fmt.Errorf("invalid slippage mode %q: use fixed or percent", m.Slippage.Mode)
+	}
+	if m.Slippage.Amount < 0 {
+		return fmt.Errorf("slippage amount must be non-negative")
+	}
+	return nil
+}
+
+// slip worsens price for an order on side ("buy" pays more, "sell" receives less)
+func (m FillModel) slip(price float64, side string) float64 {
+	offset := m.Slippage.Amount
+	if m.Slippage.Mode == SlippagePercent {
+		offset = price * m.Slippage.Amount / 100
+	}
+	if side == "sell" {
+		return price - offset
+	}
+	return price + offset
+}
+
+// MarketFill fills a market order at the open of the bar after the signal
+func (m FillModel) MarketFill(symbol, side string, qty float64, next *interfaces.Bar) Fill {
+	price := m.slip(next.Open, side)
+	return Fill{Price: price, Fees: m.Fees.OrderFees(symbol, qty, price)}
+}
+
+// StopFill reports whether a protective stop for a position on positionSide
+// triggers within bar, checked against the bar's low (longs) or high (shorts)
+// rather than its close. A bar that gaps through the stop fills at the open.
+func (m FillModel) StopFill(symbol, positionSide string, qty, stopPrice float64, bar *interfaces.Bar) (Fill, bool) {
+	exitSide := "sell"
+	var price float64
+	if positionSide == "sell" {
+		exitSide = "buy"
+		if bar.High < stopPrice {
+			return Fill{}, false
+		}
+		price = math.Max(bar.Open, stopPrice)
+	} else {
+		if bar.Low > stopPrice {
+			return Fill{}, false
+		}
+		price = math.Min(bar.Open, stopPrice)
+	}
+
+	price = m.slip(price, exitSide)
+	return Fill{Price: price, Fees: m.Fees.OrderFees(symbol, qty, price)}, true
+}
+
+// LimitFill reports whether a take-profit limit for a position on positionSide
+// fills within bar. Limits fill at their price, or better on a gap, without slippage.
+func (m FillModel) LimitFill(symbol, positionSide string, qty, limitPrice float64, bar *interfaces.Bar) (Fill, bool) {
+	var price float64
+	if positionSide == "sell" {
+		if bar.Low > limitPrice {
+			return Fill{}, false
+		}
+		price = math.Min(bar.Open, limitPrice)
+	} else {
+		if bar.High < limitPrice {
+			return Fill{}, false
+		}
+		price = math.Max(bar.Open, limitPrice)
+	}
+
+	return Fill{Price: price, Fees: m.Fees.OrderFees(symbol, qty, price)}, true
+}
+
+// CloseFill fills a market order at bar's close, for a position still open
+// when the simulated period ends
+func (m FillModel) CloseFill(symbol, side string, qty float64, bar *interfaces.Bar) Fill {
+	price := m.slip(bar.Close, side)
+	return Fill{Price: price, Fees: m.Fees.OrderFees(symbol, qty, price)}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"testing"
+
+	"prophet-trader/interfaces"
+)
+
+func TestFillModelMarketFillSlipsAgainstTrader(t *testing.T) {
+	next := &interfaces.Bar{Open: 50, High: 52, Low: 49, Close: 51}
+
+	tests := []struct {
+		name     string
+		slippage SlippageModel
+		side     string
+		want     float64
+	}{
+		{"no slippage", SlippageModel{}, "buy", 50},
+		{"fixed buy", SlippageModel{Mode: SlippageFixed, Amount: 0.05}, "buy", 50.05},
+		{"fixed sell", SlippageModel{Mode: SlippageFixed, Amount: 0.05}, "sell", 49.95},
+		{"percent buy", SlippageModel{Mode: SlippagePercent, Amount: 0.2}, "buy", 50.1},
+		{"percent sell", SlippageModel{Mode: SlippagePercent, Amount: 0.2}, "sell", 49.9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fill := FillModel{Slippage: tt.slippage}.MarketFill("TEST", tt.side, 10, next)
+			if !approxEqual(fill.Price, tt.want) {
+				t.Errorf("price = %v, want %v", fill.Price, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillModelStopFillUsesBarRange(t *testing.T) {
+	model := FillModel{Fees: FeeModel{PerShare: 0.01}}
+
+	tests := []struct {
+		name      string
+		side      string
+		stop      float64
+		bar       interfaces.Bar
+		wantFill  bool
+		wantPrice float64
+	}{
+		{"long, low touches stop though close is above", "buy", 95, interfaces.Bar{Open: 98, High: 99, Low: 94, Close: 98}, true, 95},
+		{"long, low stays above stop", "buy", 95, interfaces.Bar{Open: 98, High: 99, Low: 96, Close: 97}, false, 0},
+		{"long, gap down fills at the open", "buy", 95, interfaces.Bar{Open: 92, High: 93, Low: 90, Close: 91}, true, 92},
+		{"short, high touches stop", "sell", 105, interfaces.Bar{Open: 102, High: 106, Low: 101, Close: 102}, true, 105},
+		{"short, gap up fills at the open", "sell", 105, interfaces.Bar{Open: 108, High: 109, Low: 107, Close: 108}, true, 108},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fill, ok := model.StopFill("TEST", tt.side, 10, tt.stop, &tt.bar)
+			if ok != tt.wantFill {
+				t.Fatalf("filled = %v, want %v", ok, tt.wantFill)
+			}
+			if !ok {
+				return
+			}
+			if !approxEqual(fill.Price, tt.wantPrice) {
+				t.Errorf("price = %v, want %v", fill.Price, tt.wantPrice)
+			}
+			if !approxEqual(fill.Fees, 0.1) {
+				t.Errorf("fees = %v, want 0.1", fill.Fees)
+			}
+		})
+	}
+}
+
+func TestFillModelLimitFillHasNoSlippage(t *testing.T) {
+	model := FillModel{Slippage: SlippageModel{Mode: SlippageFixed, Amount: 0.5}}
+
+	if _, ok := model.LimitFill("TEST", "buy", 10, 110, &interfaces.Bar{Open: 105, High: 109, Low: 104}); ok {
+		t.Error("long target filled below the bar high")
+	}
+	if fill, ok := model.LimitFill("TEST", "buy", 10, 110, &interfaces.Bar{Open: 105, High: 111, Low: 104}); !ok || fill.Price != 110 {
+		t.Errorf("long target = %+v %v, want 110", fill, ok)
+	}
+	if fill, ok := model.LimitFill("TEST", "buy", 10, 110, &interfaces.Bar{Open: 112, High: 113, Low: 111}); !ok || fill.Price != 112 {
+		t.Errorf("long target on a gap up = %+v %v, want the 112 open", fill, ok)
+	}
+	if fill, ok := model.LimitFill("TEST", "sell", 10, 90, &interfaces.Bar{Open: 95, High: 96, Low: 89}); !ok || fill.Price != 90 {
+		t.Errorf("short target = %+v %v, want 90", fill, ok)
+	}
+}
+
+func TestFillModelValidate(t *testing.T) {
+	if err := (FillModel{Slippage: SlippageModel{Mode: "spread"}}).Validate(); err == nil {
+		t.Error("unknown slippage mode accepted")
+	}
+	if err := (FillModel{Slippage: SlippageModel{Mode: SlippagePercent, Amount: -1}}).Validate(); err == nil {
+		t.Error("negative slippage accepted")
+	}
+	if err := (FillModel{Slippage: SlippageModel{Mode: SlippageFixed, Amount: 0.02}}).Validate(); err != nil {
+		t.Errorf("valid model rejected: %v", err)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -16,6 +18,27 @@ type GeminiService struct {
 	apiKey     string
 	httpClient *http.Client
 	model      string
+
+	// Usage accounting
+	usage                GeminiUsageStats
+	usageMu              sync.Mutex
+	promptPricePer1K     float64
+	completionPricePer1K float64
+
+	// News source reputation (lowercase source -> weight, 1.0 = neutral)
+	sourceWeights map[string]float64
+}
+
+// GeminiUsageStats accumulates token usage and estimated cost across calls
+type GeminiUsageStats struct {
+	Calls               int       `json:"calls"`
+	FailedCalls         int       `json:"failed_calls"`
+	PromptTokens        int64     `json:"prompt_tokens"`
+	CandidateTokens     int64     `json:"candidate_tokens"`
+	TotalTokens         int64     `json:"total_tokens"`
+	LargestPromptTokens int       `json:"largest_prompt_tokens"`
+	EstimatedCostUSD    float64   `json:"estimated_cost_usd"`
+	Since               time.Time `json:"since"`
 }
 
 // GeminiRequest represents a request to Gemini API
@@ -42,6 +65,11 @@ type GeminiResponse struct {
 			} `json:"parts"`
 		} `json:"content"`
 	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
 }
 
 // CleanedNews represents a token-efficient news summary
@@ -55,6 +83,9 @@ type CleanedNews struct {
 	ActionableItems  []string          `json:"actionable_items"`
 	ExecutiveSummary string            `json:"executive_summary"`
 	FullAnalysis     string            `json:"full_analysis"`
+	Chunked          bool              `json:"chunked,omitempty"`
+	ChunkCount       int               `json:"chunk_count,omitempty"`
+	Note             string            `json:"note,omitempty"`
 }
 
 // NewGeminiService creates a new Gemini service
@@ -69,36 +100,114 @@ func NewGeminiService(apiKey string) *GeminiService {
 			Timeout: 60 * time.Second,
 		},
 		model: "gemini-2.0-flash-exp",
+		usage: GeminiUsageStats{
+			Since: time.Now(),
+		},
 	}
 }
 
-// CleanNewsForTrading takes raw news items and creates a token-efficient summary
-// optimized for trading decisions
-func (gs *GeminiService) CleanNewsForTrading(newsItems []NewsItem) (*CleanedNews, error) {
-	if len(newsItems) == 0 {
-		return nil, fmt.Errorf("no news items provided")
+// SetPricing sets the per-1K-token prices used to estimate cost
+func (gs *GeminiService) SetPricing(promptPricePer1K, completionPricePer1K float64) {
+	gs.usageMu.Lock()
+	defer gs.usageMu.Unlock()
+
+	gs.promptPricePer1K = promptPricePer1K
+	gs.completionPricePer1K = completionPricePer1K
+}
+
+// SetSourceWeights sets news source reputation weights used when cleaning news.
+// Sources not listed have a neutral weight of 1.0.
+func (gs *GeminiService) SetSourceWeights(weights map[string]float64) {
+	gs.sourceWeights = make(map[string]float64, len(weights))
+	for source, weight := range weights {
+		gs.sourceWeights[strings.ToLower(strings.TrimSpace(source))] = weight
 	}
+}
 
-	// Build the news text
-	var newsText strings.Builder
-	for i, item := range newsItems {
-		newsText.WriteString(fmt.Sprintf("[%d] %s\n", i+1, item.Title))
-		if item.Description != "" {
-			// Clean HTML tags from description
-			cleanDesc := strings.ReplaceAll(item.Description, "<", "")
-			cleanDesc = strings.ReplaceAll(cleanDesc, ">", "")
-			newsText.WriteString(fmt.Sprintf("   %s\n", cleanDesc[:min(200, len(cleanDesc))]))
-		}
-		newsText.WriteString(fmt.Sprintf("   Source: %s | Published: %s\n\n", item.Source, item.PubDate))
+// sourceWeight returns the configured weight for a news source
+func (gs *GeminiService) sourceWeight(source string) float64 {
+	if weight, ok := gs.sourceWeights[strings.ToLower(strings.TrimSpace(source))]; ok {
+		return weight
 	}
+	return 1.0
+}
 
-	// Create a trading-focused prompt
-	prompt := fmt.Sprintf(`You are a financial analyst AI. Analyze the following %d news articles and create a CONCISE trading intelligence report.
+// rankNewsBySource orders articles by source weight, highest first, keeping
+// the original order among equal weights
+func (gs *GeminiService) rankNewsBySource(items []NewsItem) []NewsItem {
+	ranked := make([]NewsItem, len(items))
+	copy(ranked, items)
+	if len(gs.sourceWeights) == 0 {
+		return ranked
+	}
 
-NEWS ARTICLES:
-%s
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return gs.sourceWeight(ranked[i].Source) > gs.sourceWeight(ranked[j].Source)
+	})
+	return ranked
+}
 
-Provide a JSON response with this EXACT structure:
+// sourceWeightContext describes the configured source weights for the prompt
+func (gs *GeminiService) sourceWeightContext() string {
+	if len(gs.sourceWeights) == 0 {
+		return ""
+	}
+
+	sources := make([]string, 0, len(gs.sourceWeights))
+	for source := range gs.sourceWeights {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	var context strings.Builder
+	context.WriteString("SOURCE RELIABILITY WEIGHTS (1.0 = neutral, higher = more trustworthy). Give more weight to higher-rated sources and discount low-rated ones:\n")
+	for _, source := range sources {
+		context.WriteString(fmt.Sprintf("- %s: %.1f\n", source, gs.sourceWeights[source]))
+	}
+	context.WriteString("\n")
+
+	return context.String()
+}
+
+// GetUsageStats returns accumulated token usage and estimated cost
+func (gs *GeminiService) GetUsageStats() GeminiUsageStats {
+	gs.usageMu.Lock()
+	defer gs.usageMu.Unlock()
+
+	return gs.usage
+}
+
+// recordUsage adds a call's token counts to the running totals
+func (gs *GeminiService) recordUsage(promptTokens, candidateTokens, totalTokens int, failed bool) {
+	gs.usageMu.Lock()
+	defer gs.usageMu.Unlock()
+
+	gs.usage.Calls++
+	if failed {
+		gs.usage.FailedCalls++
+	}
+
+	gs.usage.PromptTokens += int64(promptTokens)
+	gs.usage.CandidateTokens += int64(candidateTokens)
+	gs.usage.TotalTokens += int64(totalTokens)
+	if promptTokens > gs.usage.LargestPromptTokens {
+		gs.usage.LargestPromptTokens = promptTokens
+	}
+
+	gs.usage.EstimatedCostUSD += float64(promptTokens)/1000*gs.promptPricePer1K +
+		float64(candidateTokens)/1000*gs.completionPricePer1K
+}
+
+// Prompt size limits for news cleaning. Inputs over either limit are
+// summarized in chunks and the chunk summaries are merged (map-reduce).
+const (
+	maxNewsArticlesPerPrompt = 50
+	maxNewsPromptChars       = 24000
+	maxNewsChunks            = 8
+)
+
+// newsReportFormat is the JSON structure requested for news reports
+const newsReportFormat = `Provide a JSON response with this EXACT structure:
 {
   "market_sentiment": "BULLISH|BEARISH|NEUTRAL",
   "key_themes": ["theme1", "theme2", "theme3"],
@@ -107,15 +216,125 @@ Provide a JSON response with this EXACT structure:
   },
   "actionable_items": ["brief actionable insight 1", "brief actionable insight 2"],
   "executive_summary": "2-3 sentence summary of the market situation"
+}`
+
+// CleanNewsForTrading takes raw news items and creates a token-efficient summary
+// optimized for trading decisions
+func (gs *GeminiService) CleanNewsForTrading(newsItems []NewsItem) (*CleanedNews, error) {
+	if len(newsItems) == 0 {
+		return nil, fmt.Errorf("no news items provided")
+	}
+
+	// Best sources first so they survive any trimming
+	newsItems = gs.rankNewsBySource(newsItems)
+
+	newsText := formatNewsItems(newsItems)
+	if len(newsItems) > maxNewsArticlesPerPrompt || len(newsText) > maxNewsPromptChars {
+		return gs.cleanNewsChunked(newsItems, len(newsText))
+	}
+
+	cleanedNews, err := gs.summarizeNews(len(newsItems), newsText)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanedNews.SourceCount = countUniqueSources(newsItems)
+	cleanedNews.ArticleCount = len(newsItems)
+
+	return cleanedNews, nil
+}
+
+// cleanNewsChunked summarizes each chunk of articles separately, then merges
+// the chunk summaries into a single report
+func (gs *GeminiService) cleanNewsChunked(newsItems []NewsItem, totalChars int) (*CleanedNews, error) {
+	chunks := chunkNewsItems(newsItems)
+
+	dropped := 0
+	if len(chunks) > maxNewsChunks {
+		for _, chunk := range chunks[maxNewsChunks:] {
+			dropped += len(chunk)
+		}
+		chunks = chunks[:maxNewsChunks]
+	}
+
+	// Map: summarize each chunk
+	summaries := make([]*CleanedNews, 0, len(chunks))
+	for _, chunk := range chunks {
+		summary, err := gs.summarizeNews(len(chunk), formatNewsItems(chunk))
+		if err != nil {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if len(summaries) == 0 {
+		return nil, fmt.Errorf("failed to summarize any of %d news chunks", len(chunks))
+	}
+
+	// Reduce: merge chunk summaries into one report
+	var summaryText strings.Builder
+	for i, summary := range summaries {
+		summaryText.WriteString(fmt.Sprintf("[Batch %d] Sentiment: %s\n", i+1, summary.MarketSentiment))
+		if len(summary.KeyThemes) > 0 {
+			summaryText.WriteString(fmt.Sprintf("   Themes: %s\n", strings.Join(summary.KeyThemes, "; ")))
+		}
+		for symbol, mention := range summary.StockMentions {
+			summaryText.WriteString(fmt.Sprintf("   %s: %s\n", symbol, mention))
+		}
+		if len(summary.ActionableItems) > 0 {
+			summaryText.WriteString(fmt.Sprintf("   Actionable: %s\n", strings.Join(summary.ActionableItems, "; ")))
+		}
+		summaryText.WriteString(fmt.Sprintf("   Summary: %s\n\n", summary.ExecutiveSummary))
+	}
+
+	prompt := fmt.Sprintf(`You are a financial analyst AI. The following are %d partial trading intelligence reports, each covering a batch of news articles. Merge them into ONE CONCISE trading intelligence report, resolving conflicting sentiment by weight of evidence.
+
+PARTIAL REPORTS:
+%s
+
+%s
+
+Keep it BRIEF and DENSE. Maximum 200 tokens total.`, len(summaries), summaryText.String(), newsReportFormat)
+
+	response, err := gs.generateContent(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge news summaries: %w", err)
+	}
+
+	cleanedNews := parseCleanedNews(response)
+	cleanedNews.SourceCount = countUniqueSources(newsItems)
+	cleanedNews.ArticleCount = len(newsItems)
+	cleanedNews.Chunked = true
+	cleanedNews.ChunkCount = len(chunks)
+	cleanedNews.Note = fmt.Sprintf("Input of %d articles (%d chars) exceeded prompt limits; summarized in %d chunks and merged",
+		len(newsItems), totalChars, len(chunks))
+	if failed := len(chunks) - len(summaries); failed > 0 {
+		cleanedNews.Note += fmt.Sprintf("; %d chunks failed", failed)
+	}
+	if dropped > 0 {
+		cleanedNews.Note += fmt.Sprintf("; %d articles beyond the %d-chunk limit were dropped", dropped, maxNewsChunks)
+	}
+
+	return cleanedNews, nil
 }
 
+// summarizeNews asks Gemini for a trading report over pre-formatted articles
+func (gs *GeminiService) summarizeNews(articleCount int, newsText string) (*CleanedNews, error) {
+	// Create a trading-focused prompt
+	prompt := fmt.Sprintf(`You are a financial analyst AI. Analyze the following %d news articles and create a CONCISE trading intelligence report.
+
+%sNEWS ARTICLES:
+%s
+
+%s
+
 Focus on:
 - Stock symbols and their sentiment
 - Market-moving themes
 - Actionable trading insights
 - Overall market direction
 
-Keep it BRIEF and DENSE. Maximum 200 tokens total.`, len(newsItems), newsText.String())
+Keep it BRIEF and DENSE. Maximum 200 tokens total.`, articleCount, gs.sourceWeightContext(), newsText, newsReportFormat)
 
 	// Call Gemini
 	response, err := gs.generateContent(prompt)
@@ -123,11 +342,13 @@ Keep it BRIEF and DENSE. Maximum 200 tokens total.`, len(newsItems), newsText.St
 		return nil, fmt.Errorf("failed to generate content: %w", err)
 	}
 
-	// Parse the JSON response
+	return parseCleanedNews(response), nil
+}
+
+// parseCleanedNews extracts the JSON report from a Gemini response
+func parseCleanedNews(response string) *CleanedNews {
 	var cleanedNews CleanedNews
 	cleanedNews.GeneratedAt = time.Now()
-	cleanedNews.SourceCount = countUniqueSources(newsItems)
-	cleanedNews.ArticleCount = len(newsItems)
 	cleanedNews.FullAnalysis = response
 
 	// Try to extract JSON from the response
@@ -136,11 +357,11 @@ Keep it BRIEF and DENSE. Maximum 200 tokens total.`, len(newsItems), newsText.St
 	if jsonStart >= 0 && jsonEnd > jsonStart {
 		jsonStr := response[jsonStart : jsonEnd+1]
 		var parsed struct {
-			MarketSentiment string            `json:"market_sentiment"`
-			KeyThemes       []string          `json:"key_themes"`
-			StockMentions   map[string]string `json:"stock_mentions"`
-			ActionableItems []string          `json:"actionable_items"`
-			ExecutiveSummary string           `json:"executive_summary"`
+			MarketSentiment  string            `json:"market_sentiment"`
+			KeyThemes        []string          `json:"key_themes"`
+			StockMentions    map[string]string `json:"stock_mentions"`
+			ActionableItems  []string          `json:"actionable_items"`
+			ExecutiveSummary string            `json:"executive_summary"`
 		}
 		if err := json.Unmarshal([]byte(jsonStr), &parsed); err == nil {
 			cleanedNews.MarketSentiment = parsed.MarketSentiment
@@ -151,7 +372,161 @@ Keep it BRIEF and DENSE. Maximum 200 tokens total.`, len(newsItems), newsText.St
 		}
 	}
 
-	return &cleanedNews, nil
+	return &cleanedNews
+}
+
+// formatNewsItem renders one article for inclusion in a prompt
+func formatNewsItem(index int, item NewsItem) string {
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("[%d] %s\n", index, item.Title))
+	if item.Description != "" {
+		// Clean HTML tags and entities from description
+		cleanDesc := cleanHTMLText(item.Description)
+		text.WriteString(fmt.Sprintf("   %s\n", cleanDesc[:min(200, len(cleanDesc))]))
+	}
+	text.WriteString(fmt.Sprintf("   Source: %s | Published: %s\n\n", item.Source, item.PubDate))
+	return text.String()
+}
+
+// formatNewsItems renders a list of articles for inclusion in a prompt
+func formatNewsItems(items []NewsItem) string {
+	var text strings.Builder
+	for i, item := range items {
+		text.WriteString(formatNewsItem(i+1, item))
+	}
+	return text.String()
+}
+
+// chunkNewsItems splits articles into chunks that each fit within the
+// per-prompt article and character limits
+func chunkNewsItems(items []NewsItem) [][]NewsItem {
+	chunks := make([][]NewsItem, 0)
+	current := make([]NewsItem, 0, maxNewsArticlesPerPrompt)
+	currentChars := 0
+
+	for _, item := range items {
+		itemChars := len(formatNewsItem(len(current)+1, item))
+		if len(current) > 0 && (len(current) >= maxNewsArticlesPerPrompt || currentChars+itemChars > maxNewsPromptChars) {
+			chunks = append(chunks, current)
+			current = make([]NewsItem, 0, maxNewsArticlesPerPrompt)
+			currentChars = 0
+		}
+		current = append(current, item)
+		currentChars += itemChars
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
+
+// SymbolNewsSummary is a short sentiment summary of one symbol's news
+type SymbolNewsSummary struct {
+	Symbol       string   `json:"symbol"`
+	ArticleCount int      `json:"article_count"`
+	Sentiment    string   `json:"sentiment"`
+	Summary      string   `json:"summary"`
+	KeyPoints    []string `json:"key_points"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// SummarizeSymbolNews produces a mini-summary of the articles fetched for a
+// single symbol
+func (gs *GeminiService) SummarizeSymbolNews(symbol string, newsItems []NewsItem) (*SymbolNewsSummary, error) {
+	if len(newsItems) == 0 {
+		return nil, fmt.Errorf("no news items provided for %s", symbol)
+	}
+
+	items := newsItems[:min(len(newsItems), maxNewsArticlesPerPrompt)]
+
+	prompt := fmt.Sprintf(`You are a financial analyst AI. Analyze the following %d news articles about %s and summarize what they mean for the stock.
+
+NEWS ARTICLES:
+%s
+
+Provide a JSON response with this EXACT structure:
+{
+  "sentiment": "POSITIVE|NEGATIVE|NEUTRAL",
+  "summary": "1-2 sentence summary focused on %s",
+  "key_points": ["brief point 1", "brief point 2"]
+}
+
+Ignore articles that are not about %s. Keep it BRIEF. Maximum 100 tokens total.`,
+		len(items), symbol, formatNewsItems(items), symbol, symbol)
+
+	response, err := gs.generateContent(prompt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	summary := &SymbolNewsSummary{
+		Symbol:       symbol,
+		ArticleCount: len(newsItems),
+	}
+
+	jsonStart := strings.Index(response, "{")
+	jsonEnd := strings.LastIndex(response, "}")
+	if jsonStart < 0 || jsonEnd <= jsonStart {
+		summary.Summary = strings.TrimSpace(response)
+		return summary, nil
+	}
+
+	var parsed struct {
+		Sentiment string   `json:"sentiment"`
+		Summary   string   `json:"summary"`
+		KeyPoints []string `json:"key_points"`
+	}
+	if err := json.Unmarshal([]byte(response[jsonStart:jsonEnd+1]), &parsed); err != nil {
+		summary.Summary = strings.TrimSpace(response)
+		return summary, nil
+	}
+
+	summary.Sentiment = parsed.Sentiment
+	summary.Summary = parsed.Summary
+	summary.KeyPoints = parsed.KeyPoints
+
+	return summary, nil
+}
+
+// GenerateTradeReasoning synthesizes a short, factual reasoning string for a
+// trade entry or exit from the stock's analysis and recent headlines
+func (gs *GeminiService) GenerateTradeReasoning(action, side string, analysis *StockAnalysis) (string, error) {
+	if analysis == nil {
+		return "", fmt.Errorf("no analysis provided")
+	}
+
+	headlines := "none"
+	if len(analysis.TradeSetup.RecentNews) > 0 {
+		headlines = "- " + strings.Join(analysis.TradeSetup.RecentNews, "\n- ")
+	}
+
+	prompt := fmt.Sprintf(`You are a trading journal assistant. Write the reasoning for a %s of a %s position in %s.
+
+DATA:
+Price: $%.2f
+Technicals: %s
+Scores: technical %d/10, volume %d/10, catalyst %d/10, composite %.1f/10
+Recent headlines:
+%s
+
+Write 1-2 plain sentences citing the specific data above. No recommendations, no disclaimers, no markdown.`,
+		action, side, analysis.Symbol,
+		analysis.CurrentPrice,
+		analysis.TradeSetup.Notes,
+		analysis.TradeSetup.TechnicalScore,
+		analysis.TradeSetup.VolumeScore,
+		analysis.TradeSetup.CatalystScore,
+		analysis.TradeSetup.CompositeScore,
+		headlines)
+
+	response, err := gs.generateContent(prompt)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate content: %w", err)
+	}
+
+	return strings.TrimSpace(response), nil
 }
 
 // generateContent calls the Gemini API
@@ -183,11 +558,13 @@ func (gs *GeminiService) generateContent(prompt string) (string, error) {
 
 	resp, err := gs.httpClient.Do(req)
 	if err != nil {
+		gs.recordUsage(0, 0, 0, true)
 		return "", fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		gs.recordUsage(0, 0, 0, true)
 		body, _ := io.ReadAll(resp.Body)
 		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
 	}
@@ -199,9 +576,13 @@ func (gs *GeminiService) generateContent(prompt string) (string, error) {
 
 	var geminiResp GeminiResponse
 	if err := json.Unmarshal(body, &geminiResp); err != nil {
+		gs.recordUsage(0, 0, 0, true)
 		return "", fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
+	usage := geminiResp.UsageMetadata
+	gs.recordUsage(usage.PromptTokenCount, usage.CandidatesTokenCount, usage.TotalTokenCount, false)
+
 	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
 		return "", fmt.Errorf("no content in response")
 	}
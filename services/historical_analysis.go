@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"prophet-trader/apperrors"
+	"prophet-trader/interfaces"
+)
+
+// AnalyzeAsOf recomputes the analysis of symbol as of the close of a past
+// date, for correlating historical scores with the price action that
+// followed. Only stored daily bars are read: no quote, news or broker
+// request is made, so the catalyst score is neutral. Bars after the date are
+// never read: the indicator signal comes from AnalyzeAt, which caps the bars
+// at the date and checks their order, and the same capped bars feed the
+// scores. Stored bars are split-adjusted, so prices before a later split
+// appear in post-split terms.
+func (sas *StockAnalysisService) AnalyzeAsOf(ctx context.Context, symbol string, date time.Time, opts AnalysisOptions) (*StockAnalysis, error) {
+	if sas.barStorage == nil {
+		return nil, apperrors.Validation("daily bar cache is not enabled")
+	}
+	symbol = strings.ToUpper(symbol)
+
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, marketLocation)
+	now := time.Now().In(marketLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, marketLocation)
+	if !day.Before(today) {
+		return nil, apperrors.Validation("date %s is not in the past: only completed days can be recomputed", day.Format("2006-01-02"))
+	}
+
+	endTime := day.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	startTime := endTime.AddDate(0, 0, -30)
+	fetchStart := startTime
+	if opts.MultiTimeframe {
+		fetchStart = endTime.AddDate(0, 0, -weeklyLookbackDays)
+	}
+	if opts.YearRange {
+		fetchStart = endTime.AddDate(0, 0, -yearLookbackDays)
+	}
+
+	stored, err := sas.barStorage.GetDailyBars(symbol, fetchStart, endTime)
+	if err != nil {
+		return nil, err
+	}
+	current := barsThrough(stored, endTime)
+	if current == 0 {
+		return nil, apperrors.NotFound("no stored daily bars for %s up to %s", symbol, day.Format("2006-01-02"))
+	}
+	signal, err := NewTechnicalAnalysisService(sas.dataService).AnalyzeAt(ctx, symbol, stored, current)
+	if err != nil {
+		return nil, err
+	}
+	bars := stored[:current:current]
+
+	last := bars[len(bars)-1]
+	asOf := day.Add(time.Duration(sessionCloseMinutes) * time.Minute)
+	barTime := last.Timestamp
+	analysis := &StockAnalysis{
+		Symbol:        symbol,
+		CurrentPrice:  last.Close,
+		DataQuality:   DataQualityFull,
+		LatestBarTime: &barTime,
+		AsOf:          &asOf,
+		Signal:        signal,
+		Timestamp:     time.Now(),
+	}
+
+	if gap := endTime.Sub(last.Timestamp); gap > storedBarsMaxAge {
+		analysis.DataIssues = append(analysis.DataIssues, fmt.Sprintf("latest stored bar is from %s, %.0f days before the date", last.Timestamp.Format("2006-01-02"), gap.Hours()/24))
+	}
+	if bars[0].Timestamp.Sub(fetchStart) >= 7*24*time.Hour {
+		analysis.DataIssues = append(analysis.DataIssues, fmt.Sprintf("stored history starts %s: indicators needing a longer lookback may be missing", bars[0].Timestamp.Format("2006-01-02")))
+	}
+
+	sas.applyHistory(analysis, bars, endTime, opts)
+	analysis.MarketCap = sas.estimateMarketCap(analysis.Technical.Price, symbol)
+
+	// Headlines can't be searched as of a past date
+	analysis.NewsSkipped = true
+	analysis.NewsSummary = "news not available for historical dates"
+	sas.applyTradeSetup(analysis, []string{})
+
+	return analysis, nil
+}
+
+// barsThrough returns how many leading bars are at or before end
+func barsThrough(bars []*interfaces.Bar, end time.Time) int {
+	n := 0
+	for n < len(bars) && !bars[n].Timestamp.After(end) {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"prophet-trader/database"
+	"prophet-trader/interfaces"
+)
+
+// storedBarsAnalysis returns a service whose daily bar cache holds bars
+func storedBarsAnalysis(t *testing.T, bars []*interfaces.Bar) *StockAnalysisService {
+	t.Helper()
+
+	storage, err := database.NewLocalStorage(filepath.Join(t.TempDir(), "prophet.db"))
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	if err := storage.SaveDailyBars(bars); err != nil {
+		t.Fatalf("SaveDailyBars: %v", err)
+	}
+
+	sas := NewStockAnalysisService(nil, nil, nil)
+	sas.SetYearRange(false, storage)
+	return sas
+}
+
+// marketDays returns n daily bars stamped at midnight ET from start
+func marketDays(start time.Time, n int) []*interfaces.Bar {
+	bars := wavyBars(n)
+	for i, bar := range bars {
+		bar.Timestamp = start.AddDate(0, 0, i)
+	}
+	return bars
+}
+
+func TestAnalyzeAsOfIgnoresLaterBars(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, marketLocation)
+	bars := marketDays(start, 60)
+	const asOf = 40
+	date := bars[asOf].Timestamp
+
+	// Later bars swing wildly; the recomputed analysis must not see them
+	withLater := marketDays(start, 60)
+	for _, bar := range withLater[asOf+1:] {
+		bar.High, bar.Low, bar.Close = 1000, 1, 999
+	}
+
+	want, err := storedBarsAnalysis(t, bars[:asOf+1]).AnalyzeAsOf(ctx, "TEST", date, AnalysisOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzeAsOf without later bars: %v", err)
+	}
+	got, err := storedBarsAnalysis(t, withLater).AnalyzeAsOf(ctx, "TEST", date, AnalysisOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzeAsOf with later bars: %v", err)
+	}
+
+	if got.CurrentPrice != bars[asOf].Close {
+		t.Errorf("current price = %v, want close on %s %v", got.CurrentPrice, date.Format("2006-01-02"), bars[asOf].Close)
+	}
+	if !got.LatestBarTime.Equal(date) {
+		t.Errorf("latest bar = %s, want %s", got.LatestBarTime, date)
+	}
+	if got.Signal == nil {
+		t.Fatal("no indicator signal")
+	}
+	if !reflect.DeepEqual(got.Signal, want.Signal) {
+		t.Errorf("signal read later bars:\ngot  %+v\nwant %+v", got.Signal, want.Signal)
+	}
+	if !reflect.DeepEqual(got.Technical, want.Technical) {
+		t.Errorf("technical analysis read later bars:\ngot  %+v\nwant %+v", got.Technical, want.Technical)
+	}
+	if got.TradeSetup.CompositeScore != want.TradeSetup.CompositeScore {
+		t.Errorf("composite score = %v, want %v", got.TradeSetup.CompositeScore, want.TradeSetup.CompositeScore)
+	}
+}
+
+func TestAnalyzeAsOfRequiresPastDate(t *testing.T) {
+	sas := storedBarsAnalysis(t, marketDays(time.Date(2024, 3, 1, 0, 0, 0, 0, marketLocation), 5))
+	if _, err := sas.AnalyzeAsOf(context.Background(), "TEST", time.Now(), AnalysisOptions{}); err == nil {
+		t.Error("today accepted as a historical date")
+	}
+}
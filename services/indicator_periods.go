@@ -0,0 +1,85 @@
+package services
+
+import (
+	"fmt"
+	"prophet-trader/interfaces"
+)
+
+// IndicatorPeriods are the lookbacks, in bars, used by the stock analysis
+// indicators for one timeframe
+type IndicatorPeriods struct {
+	Trend     int     `json:"trend"`      // bars in the trend moving average
+	RSI       int     `json:"rsi"`        // RSI period
+	TrendBand float64 `json:"trend_band"` // % above/below the average that counts as BULLISH/BEARISH
+}
+
+// defaultIndicatorPeriods scale the lookbacks to each bar size. RSI stays at
+// Wilder's 14 bars. The trend average spans roughly an hour for 1-5 minute
+// bars, a session for 30-minute and hourly bars, two weeks for daily bars and
+// a quarter for weekly bars. The trend band widens with the bar size since
+// a 5% move is routine for weekly bars but extreme intraday.
+var defaultIndicatorPeriods = map[string]IndicatorPeriods{
+	"1Min":   {Trend: 30, RSI: 14, TrendBand: 0.5},
+	"5Min":   {Trend: 12, RSI: 14, TrendBand: 0.75},
+	"15Min":  {Trend: 8, RSI: 14, TrendBand: 1},
+	"30Min":  {Trend: 13, RSI: 14, TrendBand: 1.5},
+	"1Hour":  {Trend: 7, RSI: 14, TrendBand: 2},
+	"4Hour":  {Trend: 10, RSI: 14, TrendBand: 3},
+	"1Day":   {Trend: 10, RSI: 14, TrendBand: 5},
+	"1Week":  {Trend: 13, RSI: 14, TrendBand: 8},
+	"1Month": {Trend: 6, RSI: 14, TrendBand: 12},
+}
+
+// barsPerYear annualizes per-bar volatility: 252 sessions of 6.5 regular
+// trading hours for intraday bars
+var barsPerYear = map[string]float64{
+	"1Min":   252 * 390,
+	"5Min":   252 * 78,
+	"15Min":  252 * 26,
+	"30Min":  252 * 13,
+	"1Hour":  252 * 6.5,
+	"4Hour":  252 * 6.5 / 4,
+	"1Day":   252,
+	"1Week":  52,
+	"1Month": 12,
+}
+
+// SetIndicatorPeriods overrides the indicator lookbacks for a timeframe
+func (sas *StockAnalysisService) SetIndicatorPeriods(timeframe string, periods IndicatorPeriods) error {
+	tf, err := NormalizeTimeframe(timeframe)
+	if err != nil {
+		return err
+	}
+	if periods.Trend <= 0 || periods.RSI <= 0 || periods.TrendBand <= 0 {
+		return fmt.Errorf("indicator periods and trend band must be positive")
+	}
+
+	sas.periods[tf] = periods
+	return nil
+}
+
+// IndicatorPeriodsFor returns the indicator lookbacks used for a timeframe
+func (sas *StockAnalysisService) IndicatorPeriodsFor(timeframe string) (IndicatorPeriods, error) {
+	tf, err := NormalizeTimeframe(timeframe)
+	if err != nil {
+		return IndicatorPeriods{}, err
+	}
+	return sas.periodsFor(tf), nil
+}
+
+// periodsFor returns the lookbacks for a canonical timeframe
+func (sas *StockAnalysisService) periodsFor(tf string) IndicatorPeriods {
+	if periods, ok := sas.periods[tf]; ok {
+		return periods
+	}
+	return defaultIndicatorPeriods[tf]
+}
+
+// TechnicalIndicators computes the analysis indicators on bars of the given
+// timeframe, using that timeframe's lookbacks
+func (sas *StockAnalysisService) TechnicalIndicators(bars []*interfaces.Bar, timeframe string) (TechnicalAnalysis, error) {
+	if _, err := sas.IndicatorPeriodsFor(timeframe); err != nil {
+		return TechnicalAnalysis{}, err
+	}
+	return sas.calculateTechnicalIndicators(bars, timeframe), nil
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"prophet-trader/interfaces"
+)
+
+// IndicatorSpec requests one indicator by name. Period and Params fall back
+// to the indicator's defaults; As names the result (default "name_period",
+// or just "name" for indicators without a period).
+type IndicatorSpec struct {
+	Name   string             `json:"name"`
+	Period int                `json:"period,omitempty"`
+	Params map[string]float64 `json:"params,omitempty"`
+	As     string             `json:"as,omitempty"`
+}
+
+// IndicatorResults holds requested indicator values keyed by result name
+type IndicatorResults struct {
+	BarCount int                    `json:"bar_count"`
+	Values   map[string]interface{} `json:"values"`
+	Skipped  map[string]string      `json:"skipped,omitempty"` // result name -> reason
+}
+
+// indicatorDef describes one computable indicator
+type indicatorDef struct {
+	defaultPeriod int                // 0 = takes no period
+	params        map[string]float64 // accepted params and their defaults
+	minBars       func(period int) int
+	compute       func(bars []*interfaces.Bar, period int, params map[string]float64) interface{}
+}
+
+// indicatorDefs are the indicators available to ComputeIndicators
+var indicatorDefs = map[string]indicatorDef{
+	"sma": {
+		defaultPeriod: 20,
+		minBars:       func(period int) int { return period },
+		compute: func(bars []*interfaces.Bar, period int, _ map[string]float64) interface{} {
+			return CalculateSMA(bars, period)
+		},
+	},
+	"ema": {
+		defaultPeriod: 20,
+		minBars:       func(period int) int { return period },
+		compute: func(bars []*interfaces.Bar, period int, _ map[string]float64) interface{} {
+			return calculateEMA(bars, period)
+		},
+	},
+	"rsi": {
+		defaultPeriod: 14,
+		minBars:       func(period int) int { return period + 1 },
+		compute: func(bars []*interfaces.Bar, period int, _ map[string]float64) interface{} {
+			return CalculateRSI(bars, period)
+		},
+	},
+	"macd": {
+		minBars: func(int) int { return 26 },
+		compute: func(bars []*interfaces.Bar, _ int, _ map[string]float64) interface{} {
+			return CalculateMACD(bars)
+		},
+	},
+	"atr": {
+		defaultPeriod: 14,
+		minBars:       func(period int) int { return period + 1 },
+		compute: func(bars []*interfaces.Bar, period int, _ map[string]float64) interface{} {
+			return CalculateATR(bars, period)
+		},
+	},
+	"bollinger": {
+		defaultPeriod: 20,
+		params:        map[string]float64{"std_dev": 2},
+		minBars:       func(period int) int { return period },
+		compute: func(bars []*interfaces.Bar, period int, params map[string]float64) interface{} {
+			return CalculateBollinger(bars, period, params["std_dev"])
+		},
+	},
+	"momentum": {
+		minBars: func(int) int { return 6 },
+		compute: func(bars []*interfaces.Bar, _ int, _ map[string]float64) interface{} {
+			return calculateMomentum(bars)
+		},
+	},
+	"volume": {
+		minBars: func(int) int { return 20 },
+		compute: func(bars []*interfaces.Bar, _ int, _ map[string]float64) interface{} {
+			return analyzeVolume(bars)
+		},
+	},
+	"parabolic_sar": {
+		params:  map[string]float64{"step": DefaultSARStep, "max": DefaultSARMax},
+		minBars: func(int) int { return 5 },
+		compute: func(bars []*interfaces.Bar, _ int, params map[string]float64) interface{} {
+			return CalculateParabolicSAR(bars, params["step"], params["max"])
+		},
+	},
+}
+
+// AvailableIndicators lists the indicator names ComputeIndicators accepts
+func AvailableIndicators() []string {
+	names := make([]string, 0, len(indicatorDefs))
+	for name := range indicatorDefs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ComputeIndicators computes only the requested indicators from bars, whose
+// last bar is "now". Indicators without enough bars are reported in Skipped;
+// an unknown indicator or bad parameter fails the whole request.
+func ComputeIndicators(bars []*interfaces.Bar, specs []IndicatorSpec) (*IndicatorResults, error) {
+	return computeIndicators(bars, specs, nil)
+}
+
+// computeIndicators is ComputeIndicators with an optional hook to raise each
+// result's minimum bar count
+func computeIndicators(bars []*interfaces.Bar, specs []IndicatorSpec, minBars func(key string, required int) int) (*IndicatorResults, error) {
+	type resolved struct {
+		key    string
+		def    indicatorDef
+		period int
+		params map[string]float64
+	}
+
+	// Validate everything before computing anything
+	plan := make([]resolved, 0, len(specs))
+	seen := make(map[string]bool)
+	for _, spec := range specs {
+		name := strings.ToLower(strings.TrimSpace(spec.Name))
+		def, ok := indicatorDefs[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown indicator %q (available: %s)", spec.Name, strings.Join(AvailableIndicators(), ", "))
+		}
+
+		period := def.defaultPeriod
+		if spec.Period != 0 {
+			if def.defaultPeriod == 0 {
+				return nil, fmt.Errorf("%s does not take a period", name)
+			}
+			if spec.Period < 1 {
+				return nil, fmt.Errorf("%s period must be positive", name)
+			}
+			period = spec.Period
+		}
+
+		params := make(map[string]float64, len(def.params))
+		for param, value := range def.params {
+			params[param] = value
+		}
+		for param, value := range spec.Params {
+			if _, ok := def.params[param]; !ok {
+				return nil, fmt.Errorf("%s does not take param %q", name, param)
+			}
+			if value <= 0 {
+				return nil, fmt.Errorf("%s param %s must be positive", name, param)
+			}
+			params[param] = value
+		}
+
+		key := indicatorKey(spec)
+		if seen[key] {
+			return nil, fmt.Errorf("indicator %q requested more than once (use as to name duplicates)", key)
+		}
+		seen[key] = true
+
+		plan = append(plan, resolved{key: key, def: def, period: period, params: params})
+	}
+
+	results := &IndicatorResults{
+		BarCount: len(bars),
+		Values:   make(map[string]interface{}, len(plan)),
+		Skipped:  make(map[string]string),
+	}
+
+	for _, item := range plan {
+		required := item.def.minBars(item.period)
+		if minBars != nil {
+			required = minBars(item.key, required)
+		}
+		if len(bars) < required {
+			results.Skipped[item.key] = fmt.Sprintf("insufficient data: need %d bars, have %d", required, len(bars))
+			continue
+		}
+		results.Values[item.key] = item.def.compute(bars, item.period, item.params)
+	}
+
+	return results, nil
+}
+
+// indicatorKey is the result name for a spec: As, else "name_period", or
+// "name" for indicators without a period
+func indicatorKey(spec IndicatorSpec) string {
+	if spec.As != "" {
+		return spec.As
+	}
+
+	name := strings.ToLower(strings.TrimSpace(spec.Name))
+	period := spec.Period
+	if period == 0 {
+		period = indicatorDefs[name].defaultPeriod
+	}
+	if period > 0 {
+		return fmt.Sprintf("%s_%d", name, period)
+	}
+	return name
+}
+
+// indicatorLookbackDays is how much calendar history GetIndicators fetches per timeframe
+var indicatorLookbackDays = map[string]int{
+	"1Min":   5,
+	"5Min":   10,
+	"15Min":  20,
+	"30Min":  30,
+	"1Hour":  60,
+	"4Hour":  120,
+	"1Day":   365,
+	"1Week":  3 * 365,
+	"1Month": 10 * 365,
+}
+
+// GetIndicators fetches recent bars for symbol and computes the requested indicators
+func (tas *TechnicalAnalysisService) GetIndicators(ctx context.Context, symbol, timeframe string, specs []IndicatorSpec) (*IndicatorResults, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no indicators requested")
+	}
+
+	days, ok := indicatorLookbackDays[timeframe]
+	if !ok {
+		return nil, fmt.Errorf("unsupported timeframe %q", timeframe)
+	}
+
+	end := time.Now()
+	bars, err := tas.dataService.GetHistoricalBars(ctx, symbol, end.AddDate(0, 0, -days), end, timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bars: %w", err)
+	}
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no bars data available")
+	}
+
+	return ComputeIndicators(bars, specs)
+}
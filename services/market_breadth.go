@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"prophet-trader/interfaces"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MarketBreadthService computes quantitative breadth across a basket of symbols
+type MarketBreadthService struct {
+	dataService     interfaces.DataService
+	analysisService *TechnicalAnalysisService
+	defaultBasket   []string
+	cacheTTL        time.Duration
+	cache           map[string]*MarketBreadth // basket key -> result
+	mu              sync.Mutex
+	logger          *logrus.Logger
+}
+
+// MarketBreadth summarizes how broadly a basket is participating in a move
+type MarketBreadth struct {
+	Symbols         []string        `json:"symbols"`
+	Analyzed        int             `json:"analyzed"`
+	Failed          []string        `json:"failed,omitempty"`
+	AboveSMA50      int             `json:"above_sma_50"`
+	AboveSMA50Pct   float64         `json:"above_sma_50_percent"`
+	RSIDistribution RSIDistribution `json:"rsi_distribution"`
+	AvgVolumeRatio  float64         `json:"avg_volume_ratio"`
+	BreadthScore    float64         `json:"breadth_score"` // 0-100
+	Breadth         string          `json:"breadth"`       // "STRONG", "WEAK", "MIXED"
+	GeneratedAt     time.Time       `json:"generated_at"`
+	Cached          bool            `json:"cached"`
+}
+
+// RSIDistribution buckets basket members by RSI(14)
+type RSIDistribution struct {
+	Oversold   int `json:"oversold"`   // < 30
+	Weak       int `json:"weak"`       // 30-50
+	Strong     int `json:"strong"`     // 50-70
+	Overbought int `json:"overbought"` // > 70
+}
+
+// NewMarketBreadthService creates a new market breadth service
+func NewMarketBreadthService(dataService interfaces.DataService, analysisService *TechnicalAnalysisService, defaultBasket []string) *MarketBreadthService {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	return &MarketBreadthService{
+		dataService:     dataService,
+		analysisService: analysisService,
+		defaultBasket:   defaultBasket,
+		cacheTTL:        5 * time.Minute,
+		cache:           make(map[string]*MarketBreadth),
+		logger:          logger,
+	}
+}
+
+// GetBreadth computes breadth for the given basket (or the default basket if empty).
+// Results are cached briefly since each call fetches bars for every symbol.
+func (mbs *MarketBreadthService) GetBreadth(ctx context.Context, symbols []string) (*MarketBreadth, error) {
+	if len(symbols) == 0 {
+		symbols = mbs.defaultBasket
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols configured for breadth basket")
+	}
+
+	basket := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		basket[i] = strings.ToUpper(strings.TrimSpace(symbol))
+	}
+	sort.Strings(basket)
+	key := strings.Join(basket, ",")
+
+	mbs.mu.Lock()
+	if cached, ok := mbs.cache[key]; ok && time.Since(cached.GeneratedAt) < mbs.cacheTTL {
+		mbs.mu.Unlock()
+		result := *cached
+		result.Cached = true
+		return &result, nil
+	}
+	mbs.mu.Unlock()
+
+	breadth := &MarketBreadth{
+		Symbols:     basket,
+		GeneratedAt: time.Now(),
+	}
+
+	// ~100 calendar days gives enough daily bars for SMA50
+	end := time.Now()
+	start := end.AddDate(0, 0, -100)
+
+	totalVolumeRatio := 0.0
+	volumeSamples := 0
+	rsiSamples := 0
+
+	for _, symbol := range basket {
+		bars, err := mbs.dataService.GetHistoricalBars(ctx, symbol, start, end, "1Day")
+		if err != nil || len(bars) == 0 {
+			mbs.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to fetch bars for breadth")
+			breadth.Failed = append(breadth.Failed, symbol)
+			continue
+		}
+
+		result, err := mbs.analysisService.Analyze(ctx, symbol, bars)
+		if err != nil {
+			breadth.Failed = append(breadth.Failed, symbol)
+			continue
+		}
+		breadth.Analyzed++
+
+		if result.DataQuality.Has(IndicatorSMA50) && result.CurrentPrice > result.SMA50 {
+			breadth.AboveSMA50++
+		}
+
+		if result.DataQuality.Has(IndicatorRSI14) {
+			rsiSamples++
+			switch {
+			case result.RSI < 30:
+				breadth.RSIDistribution.Oversold++
+			case result.RSI < 50:
+				breadth.RSIDistribution.Weak++
+			case result.RSI <= 70:
+				breadth.RSIDistribution.Strong++
+			default:
+				breadth.RSIDistribution.Overbought++
+			}
+		}
+
+		if result.Volume != nil {
+			totalVolumeRatio += result.Volume.Ratio
+			volumeSamples++
+		}
+	}
+
+	if breadth.Analyzed == 0 {
+		return nil, fmt.Errorf("failed to analyze any symbols in breadth basket")
+	}
+
+	breadth.AboveSMA50Pct = float64(breadth.AboveSMA50) / float64(breadth.Analyzed) * 100
+	if volumeSamples > 0 {
+		breadth.AvgVolumeRatio = totalVolumeRatio / float64(volumeSamples)
+	}
+
+	// Score: 60% weight on participation above SMA50, 40% on RSI above 50
+	rsiAbove50Pct := 0.0
+	if rsiSamples > 0 {
+		rsiAbove50Pct = float64(breadth.RSIDistribution.Strong+breadth.RSIDistribution.Overbought) / float64(rsiSamples) * 100
+	}
+	breadth.BreadthScore = breadth.AboveSMA50Pct*0.6 + rsiAbove50Pct*0.4
+
+	switch {
+	case breadth.BreadthScore >= 60:
+		breadth.Breadth = "STRONG"
+	case breadth.BreadthScore <= 40:
+		breadth.Breadth = "WEAK"
+	default:
+		breadth.Breadth = "MIXED"
+	}
+
+	mbs.mu.Lock()
+	mbs.cache[key] = breadth
+	mbs.mu.Unlock()
+
+	mbs.logger.WithFields(logrus.Fields{
+		"analyzed":      breadth.Analyzed,
+		"breadth_score": breadth.BreadthScore,
+	}).Info("Market breadth computed")
+
+	result := *breadth
+	return &result, nil
+}
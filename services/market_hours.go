@@ -0,0 +1,33 @@
+package services
+
+import "time"
+
+// Regular US equity session in exchange time
+const (
+	sessionOpenMinutes  = 9*60 + 30
+	sessionCloseMinutes = 16 * 60
+)
+
+// IsMarketOpen reports whether t falls within the regular trading session
+// (Monday-Friday, 9:30-16:00 ET). Exchange holidays are not accounted for.
+func IsMarketOpen(t time.Time) bool {
+	local := t.In(marketLocation)
+
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+
+	minutes := local.Hour()*60 + local.Minute()
+	return minutes >= sessionOpenMinutes && minutes < sessionCloseMinutes
+}
+
+// MinutesUntilClose returns the whole minutes left in the regular session at
+// t, and false when the market is closed
+func MinutesUntilClose(t time.Time) (int, bool) {
+	if !IsMarketOpen(t) {
+		return 0, false
+	}
+
+	local := t.In(marketLocation)
+	return sessionCloseMinutes - (local.Hour()*60 + local.Minute()), true
+}
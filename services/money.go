@@ -0,0 +1,44 @@
+package services
+
+import "math"
+
+// Decimal places used when rounding monetary values and prices. Configured
+// once at startup via SetMoneyPrecision.
+var (
+	moneyDecimals = 2
+	priceDecimals = 4
+)
+
+// percentDecimals is the precision for percentages
+const percentDecimals = 2
+
+// SetMoneyPrecision sets the decimal places for dollar amounts and prices.
+// Negative values leave the current setting unchanged.
+func SetMoneyPrecision(money, price int) {
+	if money >= 0 {
+		moneyDecimals = money
+	}
+	if price >= 0 {
+		priceDecimals = price
+	}
+}
+
+// RoundMoney rounds a dollar amount (P&L, fees, allocation) to the money precision
+func RoundMoney(value float64) float64 {
+	return roundTo(value, moneyDecimals)
+}
+
+// RoundPrice rounds a per-share price to the price precision
+func RoundPrice(value float64) float64 {
+	return roundTo(value, priceDecimals)
+}
+
+// RoundPercent rounds a percentage
+func RoundPercent(value float64) float64 {
+	return roundTo(value, percentDecimals)
+}
+
+func roundTo(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultMonitorConcurrency is how many positions a monitoring cycle checks at once
+const DefaultMonitorConcurrency = 4
+
+// Monitoring cadence while the market is open, and by default while it's closed
+const (
+	monitorInterval              = 10 * time.Second
+	DefaultClosedMonitorInterval = 5 * time.Minute
+)
+
+// SetMonitorConcurrency sets how many positions a monitoring cycle checks in
+// parallel. Each check makes its own order and quote calls, so this bounds
+// the burst of broker requests per cycle. 1 checks positions one at a time.
+func (pm *PositionManager) SetMonitorConcurrency(workers int) error {
+	if workers < 1 {
+		return fmt.Errorf("monitor concurrency must be at least 1")
+	}
+	pm.monitorConcurrency = workers
+	return nil
+}
+
+// SetClosedMonitorInterval sets how often positions are checked outside the
+// regular session, when stocks and options can't trade and each cycle's order
+// and quote calls are mostly wasted. Zero checks at the full cadence around
+// the clock. Crypto positions always get the full cadence.
+func (pm *PositionManager) SetClosedMonitorInterval(interval time.Duration) error {
+	if interval < 0 {
+		return fmt.Errorf("closed market monitor interval cannot be negative")
+	}
+	if interval > 0 && interval < monitorInterval {
+		interval = monitorInterval
+	}
+	pm.closedMonitorInterval = interval
+	return nil
+}
+
+// monitorDue reports whether a monitoring cycle should run at now, given when
+// the last one started. Cycles run every tick during the session (so gtc
+// orders filled at the open are picked up right away) and every
+// closedMonitorInterval outside it.
+func (pm *PositionManager) monitorDue(now, lastCycle time.Time) bool {
+	if pm.closedMonitorInterval == 0 || IsMarketOpen(now) || pm.hasLiveCrypto() {
+		return true
+	}
+	return now.Sub(lastCycle) >= pm.closedMonitorInterval
+}
+
+// hasLiveCrypto reports whether any non-terminal position trades around the clock
+func (pm *PositionManager) hasLiveCrypto() bool {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	for _, position := range pm.positions {
+		if position.AssetClass == AssetClassCrypto && !isTerminalStatus(position.Status) {
+			return true
+		}
+	}
+	return false
+}
+
+// startMonitorCycle runs one monitoring cycle in the background, skipping it
+// when the previous cycle is still running so slow cycles never pile up
+func (pm *PositionManager) startMonitorCycle(ctx context.Context) {
+	if !pm.monitorRunning.CompareAndSwap(false, true) {
+		pm.logger.Warn("Previous monitoring cycle still running, skipping this tick")
+		return
+	}
+
+	go func() {
+		defer pm.monitorRunning.Store(false)
+		pm.checkPositions(ctx)
+		pm.flattenDayTradesNearClose(ctx)
+	}()
+}
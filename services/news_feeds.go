@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MarketWatch RSS feed URLs
+const (
+	marketWatchTopStoriesURL        = "https://feeds.content.dowjones.io/public/rss/mw_topstories"
+	marketWatchRealtimeHeadlinesURL = "https://feeds.content.dowjones.io/public/rss/mw_realtimeheadlines"
+	marketWatchBulletinsURL         = "https://feeds.content.dowjones.io/public/rss/mw_bulletins"
+	marketWatchMarketPulseURL       = "https://feeds.content.dowjones.io/public/rss/mw_marketpulse"
+)
+
+// NewsFetchConfig bounds parallel feed fetching
+type NewsFetchConfig struct {
+	Concurrency int           // feeds fetched at once
+	FeedTimeout time.Duration // per-feed deadline, within the caller's context
+}
+
+// DefaultNewsFetchConfig fetches 4 feeds at a time with a 10s deadline each
+var DefaultNewsFetchConfig = NewsFetchConfig{
+	Concurrency: 4,
+	FeedTimeout: 10 * time.Second,
+}
+
+// NewsFeed is a named RSS feed
+type NewsFeed struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// FeedResult is the outcome of fetching one feed
+type FeedResult struct {
+	Feed  NewsFeed
+	Items []NewsItem
+	Err   error
+}
+
+// GoogleTopicFeed returns the Google News feed for a topic
+func GoogleTopicFeed(topic string) NewsFeed {
+	return NewsFeed{Name: "google:topic:" + topic, URL: googleTopicURL(topic)}
+}
+
+// GoogleSearchFeed returns the Google News search feed for a query
+func GoogleSearchFeed(query string) NewsFeed {
+	return NewsFeed{Name: "google:search:" + query, URL: googleSearchURL(query)}
+}
+
+// MarketWatchFeeds returns all MarketWatch feeds
+func MarketWatchFeeds() []NewsFeed {
+	return []NewsFeed{
+		{Name: "marketwatch:top_stories", URL: marketWatchTopStoriesURL},
+		{Name: "marketwatch:realtime_headlines", URL: marketWatchRealtimeHeadlinesURL},
+		{Name: "marketwatch:bulletins", URL: marketWatchBulletinsURL},
+		{Name: "marketwatch:market_pulse", URL: marketWatchMarketPulseURL},
+	}
+}
+
+// SetFetchConfig configures parallel feed fetching
+func (ns *NewsService) SetFetchConfig(config NewsFetchConfig) error {
+	if config.Concurrency <= 0 {
+		return fmt.Errorf("news fetch concurrency must be positive")
+	}
+	if config.FeedTimeout <= 0 {
+		return fmt.Errorf("news feed timeout must be positive")
+	}
+
+	ns.fetch = config
+	return nil
+}
+
+// FetchFeeds fetches feeds with a bounded worker pool. Each feed gets its own
+// timeout derived from ctx, so one hung feed can't hold up the rest. Results
+// are returned in the order of feeds; failed feeds carry their error.
+func (ns *NewsService) FetchFeeds(ctx context.Context, feeds []NewsFeed) []FeedResult {
+	results := make([]FeedResult, len(feeds))
+	jobs := make(chan int)
+
+	workers := ns.fetch.Concurrency
+	if workers > len(feeds) {
+		workers = len(feeds)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				feedCtx, cancel := context.WithTimeout(ctx, ns.fetch.FeedTimeout)
+				items, err := ns.fetchRSSFeedContext(feedCtx, feeds[i].URL)
+				cancel()
+				results[i] = FeedResult{Feed: feeds[i], Items: items, Err: err}
+			}
+		}()
+	}
+
+	for i := range feeds {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultNewsBlockKeywords drops listicle and promotional headlines
+var DefaultNewsBlockKeywords = []string{
+	"stocks to buy",
+	"stock to buy",
+	"top 10 stocks",
+	"top 5 stocks",
+	"make you a millionaire",
+	"you won't believe",
+	"sponsored",
+}
+
+// newsKeywordFilter holds lowercased keyword lists matched case-insensitively
+// as substrings
+type newsKeywordFilter struct {
+	block []string // a title containing any of these is dropped
+	allow []string // when set, the title or description must contain one of these
+}
+
+// SetKeywordFilter sets the keyword lists applied to every fetched feed. A
+// headline whose title contains a block keyword is dropped; when allow is
+// non-empty, a headline must also mention an allow keyword in its title or
+// description, e.g. "earnings", "shares" or "fed", to keep only
+// finance-relevant stories. Matching ignores case. Empty lists disable
+// each check; a new service blocks DefaultNewsBlockKeywords.
+func (ns *NewsService) SetKeywordFilter(block, allow []string) error {
+	filter := newsKeywordFilter{
+		block: normalizeKeywords(block),
+		allow: normalizeKeywords(allow),
+	}
+
+	for _, keyword := range filter.block {
+		for _, allowed := range filter.allow {
+			if keyword == allowed {
+				return fmt.Errorf("news keyword %q is both blocked and allowed", keyword)
+			}
+		}
+	}
+
+	ns.keywords = filter
+	return nil
+}
+
+// filterKeywords drops the items the keyword lists reject
+func (ns *NewsService) filterKeywords(items []NewsItem) []NewsItem {
+	if len(ns.keywords.block) == 0 && len(ns.keywords.allow) == 0 {
+		return items
+	}
+
+	kept := make([]NewsItem, 0, len(items))
+	for _, item := range items {
+		if ns.keywords.accepts(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// accepts reports whether an item passes the block and allow lists
+func (f newsKeywordFilter) accepts(item NewsItem) bool {
+	title := strings.ToLower(item.Title)
+	for _, keyword := range f.block {
+		if strings.Contains(title, keyword) {
+			return false
+		}
+	}
+
+	if len(f.allow) == 0 {
+		return true
+	}
+	description := strings.ToLower(item.Description)
+	for _, keyword := range f.allow {
+		if strings.Contains(title, keyword) || strings.Contains(description, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeKeywords lowercases and trims keywords, dropping empty ones
+func normalizeKeywords(keywords []string) []string {
+	normalized := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			normalized = append(normalized, keyword)
+		}
+	}
+	return normalized
+}
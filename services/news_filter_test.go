@@ -0,0 +1,94 @@
+package services
+
+import "testing"
+
+func TestNewsKeywordFilter(t *testing.T) {
+	items := []NewsItem{
+		{Title: "Apple Earnings Beat Estimates as iPhone Sales Rise"},
+		{Title: "3 Stocks to Buy Before the Fed Meeting", Description: "Shares of these picks could soar"},
+		{Title: "This AI Stock Could Make You a Millionaire"},
+		{Title: "Markets wrap", Description: "The FED held rates steady"},
+	}
+	all := []string{"Apple Earnings Beat Estimates as iPhone Sales Rise", "3 Stocks to Buy Before the Fed Meeting", "This AI Stock Could Make You a Millionaire", "Markets wrap"}
+
+	tests := []struct {
+		name  string
+		block []string
+		allow []string
+		want  []string
+	}{
+		{"no lists", nil, nil, all},
+		{"block list", []string{"stocks to buy", "make you a millionaire"}, nil, []string{"Apple Earnings Beat Estimates as iPhone Sales Rise", "Markets wrap"}},
+		{"block matches title only", []string{"soar"}, nil, all},
+		{"allow list matches title or description", nil, []string{"earnings", "fed"}, []string{"Apple Earnings Beat Estimates as iPhone Sales Rise", "3 Stocks to Buy Before the Fed Meeting", "Markets wrap"}},
+		{"block wins over allow", []string{"stocks to buy"}, []string{"fed"}, []string{"Markets wrap"}},
+		{"case insensitive", []string{"  MILLIONAIRE "}, []string{"EARNINGS", "fEd"}, []string{"Apple Earnings Beat Estimates as iPhone Sales Rise", "3 Stocks to Buy Before the Fed Meeting", "Markets wrap"}},
+		{"blank keywords ignored", []string{"", "  "}, []string{""}, all},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := NewNewsService()
+			if err := ns.SetKeywordFilter(tt.block, tt.allow); err != nil {
+				t.Fatalf("SetKeywordFilter: %v", err)
+			}
+
+			kept := ns.filterKeywords(items)
+			if len(kept) != len(tt.want) {
+				t.Fatalf("kept %d items %v, want %v", len(kept), titles(kept), tt.want)
+			}
+			for i, item := range kept {
+				if item.Title != tt.want[i] {
+					t.Errorf("kept[%d] = %q, want %q", i, item.Title, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultNewsBlockKeywords(t *testing.T) {
+	junk := []string{
+		"10 Stocks to Buy Now Before They Soar",
+		"1 Growth Stock to Buy and Hold Forever",
+		"Top 10 Stocks for the Next Decade",
+		"Wall Street's Top 5 Stocks This Week",
+		"This Dividend Stock Could Make You a Millionaire",
+		"You Won't Believe What Nvidia Just Did",
+		"Sponsored: The Next Big EV Play",
+	}
+	kept := []string{
+		"Apple Earnings Beat Estimates as iPhone Sales Rise",
+		"Microsoft to Buy Back $60 Billion of Stock",
+		"Fed Holds Rates Steady, Signals Two Cuts",
+		"Nvidia Shares Slide After New Export Curbs",
+	}
+
+	var items []NewsItem
+	for _, title := range append(junk, kept...) {
+		items = append(items, NewsItem{Title: title})
+	}
+
+	got := titles(NewNewsService().filterKeywords(items))
+	if len(got) != len(kept) {
+		t.Fatalf("kept %v, want %v", got, kept)
+	}
+	for i := range kept {
+		if got[i] != kept[i] {
+			t.Errorf("kept[%d] = %q, want %q", i, got[i], kept[i])
+		}
+	}
+}
+
+func TestSetKeywordFilterRejectsOverlap(t *testing.T) {
+	if err := NewNewsService().SetKeywordFilter([]string{"Crypto"}, []string{" crypto"}); err == nil {
+		t.Error("keyword both blocked and allowed was accepted")
+	}
+}
+
+func titles(items []NewsItem) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.Title
+	}
+	return out
+}
@@ -1,12 +1,18 @@
 package services
 
 import (
+	"context"
 	"encoding/xml"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // NewsItem represents a single news article from the RSS feed
@@ -53,15 +59,23 @@ type RSSFeed struct {
 // NewsService handles fetching news from various sources
 type NewsService struct {
 	httpClient *http.Client
+	fetch      NewsFetchConfig
+	throttle   *hostThrottle
+	keywords   newsKeywordFilter
 }
 
 // NewNewsService creates a new news service
 func NewNewsService() *NewsService {
-	return &NewsService{
+	ns := &NewsService{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		fetch:    DefaultNewsFetchConfig,
+		throttle: newHostThrottle(),
+		keywords: newsKeywordFilter{block: normalizeKeywords(DefaultNewsBlockKeywords)},
 	}
+	ns.throttle.intervals[googleNewsHost] = DefaultGoogleNewsInterval
+	return ns
 }
 
 // GetGoogleNews fetches the latest news from Google News RSS feed
@@ -73,6 +87,11 @@ func (ns *NewsService) GetGoogleNews() ([]NewsItem, error) {
 // GetGoogleNewsByTopic fetches news for a specific topic
 // Topics: WORLD, NATION, BUSINESS, TECHNOLOGY, ENTERTAINMENT, SPORTS, SCIENCE, HEALTH
 func (ns *NewsService) GetGoogleNewsByTopic(topic string) ([]NewsItem, error) {
+	return ns.fetchRSSFeed(googleTopicURL(topic))
+}
+
+// googleTopicURL returns the RSS URL for a Google News topic, defaulting to BUSINESS
+func googleTopicURL(topic string) string {
 	url := fmt.Sprintf("https://news.google.com/rss/topics/CAAqJggKIiBDQkFTRWdvSUwyMHZNRGx6TVdZU0FtVnVHZ0pWVXlnQVAB?hl=en-US&gl=US&ceid=US:en")
 
 	// Topic-specific URLs
@@ -91,59 +110,51 @@ func (ns *NewsService) GetGoogleNewsByTopic(topic string) ([]NewsItem, error) {
 		url = topicURL
 	}
 
-	return ns.fetchRSSFeed(url)
+	return url
 }
 
 // GetGoogleNewsSearch fetches news for a specific search query
 func (ns *NewsService) GetGoogleNewsSearch(query string) ([]NewsItem, error) {
+	return ns.fetchRSSFeed(googleSearchURL(query))
+}
+
+// googleSearchURL returns the Google News RSS search URL for query
+func googleSearchURL(query string) string {
 	// Use url.QueryEscape to properly encode the query parameter
 	encodedQuery := url.QueryEscape(query)
-	urlString := fmt.Sprintf("https://news.google.com/rss/search?q=%s&hl=en-US&gl=US&ceid=US:en", encodedQuery)
-	return ns.fetchRSSFeed(urlString)
+	return fmt.Sprintf("https://news.google.com/rss/search?q=%s&hl=en-US&gl=US&ceid=US:en", encodedQuery)
 }
 
 // GetMarketWatchTopStories fetches top stories from MarketWatch
 func (ns *NewsService) GetMarketWatchTopStories() ([]NewsItem, error) {
-	url := "https://feeds.content.dowjones.io/public/rss/mw_topstories"
-	return ns.fetchRSSFeed(url)
+	return ns.fetchRSSFeed(marketWatchTopStoriesURL)
 }
 
 // GetMarketWatchRealtimeHeadlines fetches real-time headlines from MarketWatch
 func (ns *NewsService) GetMarketWatchRealtimeHeadlines() ([]NewsItem, error) {
-	url := "https://feeds.content.dowjones.io/public/rss/mw_realtimeheadlines"
-	return ns.fetchRSSFeed(url)
+	return ns.fetchRSSFeed(marketWatchRealtimeHeadlinesURL)
 }
 
 // GetMarketWatchBulletins fetches breaking news bulletins from MarketWatch
 func (ns *NewsService) GetMarketWatchBulletins() ([]NewsItem, error) {
-	url := "https://feeds.content.dowjones.io/public/rss/mw_bulletins"
-	return ns.fetchRSSFeed(url)
+	return ns.fetchRSSFeed(marketWatchBulletinsURL)
 }
 
 // GetMarketWatchMarketPulse fetches market pulse updates from MarketWatch
 func (ns *NewsService) GetMarketWatchMarketPulse() ([]NewsItem, error) {
-	url := "https://feeds.content.dowjones.io/public/rss/mw_marketpulse"
-	return ns.fetchRSSFeed(url)
+	return ns.fetchRSSFeed(marketWatchMarketPulseURL)
 }
 
-// GetAllMarketWatchNews aggregates all MarketWatch feeds
+// GetAllMarketWatchNews aggregates all MarketWatch feeds, fetched in parallel
 func (ns *NewsService) GetAllMarketWatchNews() ([]NewsItem, error) {
 	allNews := make([]NewsItem, 0)
 
-	feeds := []func() ([]NewsItem, error){
-		ns.GetMarketWatchTopStories,
-		ns.GetMarketWatchRealtimeHeadlines,
-		ns.GetMarketWatchBulletins,
-		ns.GetMarketWatchMarketPulse,
-	}
-
-	for _, fetchFunc := range feeds {
-		items, err := fetchFunc()
-		if err != nil {
-			// Log error but continue with other feeds
+	for _, result := range ns.FetchFeeds(context.Background(), MarketWatchFeeds()) {
+		if result.Err != nil {
+			// Skip failed feeds but continue with the others
 			continue
 		}
-		allNews = append(allNews, items...)
+		allNews = append(allNews, result.Items...)
 	}
 
 	return allNews, nil
@@ -151,21 +162,14 @@ func (ns *NewsService) GetAllMarketWatchNews() ([]NewsItem, error) {
 
 // fetchRSSFeed is a helper method to fetch and parse any RSS feed
 func (ns *NewsService) fetchRSSFeed(url string) ([]NewsItem, error) {
-	// Make HTTP request
-	resp, err := ns.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch RSS feed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
+	return ns.fetchRSSFeedContext(context.Background(), url)
+}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+// fetchRSSFeedContext fetches and parses an RSS feed, aborting when ctx is done
+func (ns *NewsService) fetchRSSFeedContext(ctx context.Context, url string) ([]NewsItem, error) {
+	body, err := ns.getWithRetry(ctx, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
 	// Parse XML
@@ -176,6 +180,10 @@ func (ns *NewsService) fetchRSSFeed(url string) ([]NewsItem, error) {
 
 	// Parse pub dates
 	for i := range feed.Channel.Items {
+		feed.Channel.Items[i].Title = cleanHTMLText(feed.Channel.Items[i].Title)
+		feed.Channel.Items[i].Description = cleanHTMLText(feed.Channel.Items[i].Description)
+		splitSourceFromTitle(&feed.Channel.Items[i])
+
 		if feed.Channel.Items[i].PubDate != "" {
 			// Try to parse RFC1123 format (common in RSS)
 			if t, err := time.Parse(time.RFC1123, feed.Channel.Items[i].PubDate); err == nil {
@@ -186,7 +194,189 @@ func (ns *NewsService) fetchRSSFeed(url string) ([]NewsItem, error) {
 		}
 	}
 
-	return feed.Channel.Items, nil
+	return ns.filterKeywords(feed.Channel.Items), nil
+}
+
+// RSS fetch retry policy: attempts in total and the delay before the first retry,
+// doubled on each further retry
+const (
+	rssMaxAttempts    = 3
+	rssRetryBaseDelay = 500 * time.Millisecond
+)
+
+// getWithRetry fetches url, retrying network errors and 429/5xx responses
+// with exponential backoff. It stops early when ctx is done.
+func (ns *NewsService) getWithRetry(ctx context.Context, url string) ([]byte, error) {
+	var lastErr error
+
+	for attempt := 0; attempt < rssMaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("failed to fetch RSS feed: %w (last error: %v)", ctx.Err(), lastErr)
+			case <-time.After(rssRetryBaseDelay << (attempt - 1)):
+			}
+		}
+
+		// Space out requests to hosts that rate limit bursts
+		if err := ns.throttle.wait(ctx, url); err != nil {
+			return nil, fmt.Errorf("failed to fetch RSS feed: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create RSS request: %w", err)
+		}
+
+		resp, err := ns.httpClient.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("failed to fetch RSS feed: %w", err)
+			}
+			lastErr = fmt.Errorf("failed to fetch RSS feed: %w", err)
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		return body, nil
+	}
+
+	return nil, lastErr
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// cleanHTMLText strips HTML tags, decodes entities (&amp;, &#39;, &nbsp;) and
+// collapses whitespace so feed text is safe to show and to pass to Gemini
+func cleanHTMLText(s string) string {
+	if s == "" {
+		return s
+	}
+
+	// Strip tags before decoding so escaped text like "&lt;b&gt;" survives as literal text
+	text := htmlTagPattern.ReplaceAllString(s, " ")
+	text = html.UnescapeString(text)
+
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// maxSourceAttributionLen bounds how long a trailing " - Source" suffix may be
+// before we treat it as part of the headline rather than a publisher name
+const maxSourceAttributionLen = 40
+
+// splitSourceFromTitle moves Google News' trailing " - SourceName" title suffix
+// into the Source field. A known source is stripped only when the title ends
+// with exactly that name. Otherwise only a spaced hyphen is treated as a
+// separator, and the suffix must look like a publisher name, so headlines
+// like "Apple rallies - shares up 5%" keep their text.
+func splitSourceFromTitle(item *NewsItem) {
+	title := strings.TrimSpace(item.Title)
+	source := strings.TrimSpace(item.Source)
+
+	// Source already known - strip it only if the title ends with exactly that name
+	if source != "" {
+		if strings.HasSuffix(title, " - "+source) {
+			item.Title = strings.TrimSpace(strings.TrimSuffix(title, " - "+source))
+		}
+		return
+	}
+
+	idx := strings.LastIndex(title, " - ")
+	if idx <= 0 {
+		return
+	}
+
+	headline := strings.TrimSpace(title[:idx])
+	suffix := strings.TrimSpace(title[idx+3:])
+	if headline == "" || !looksLikeSourceName(suffix) {
+		return
+	}
+
+	item.Title = headline
+	item.Source = suffix
+}
+
+// knownNewsOutlets are publishers whose names fail the title-case heuristic
+// (digits, lowercase or trailing periods) or are worth matching outright
+var knownNewsOutlets = map[string]bool{
+	"24/7 wall st.":             true,
+	"9to5mac":                   true,
+	"ap news":                   true,
+	"associated press":          true,
+	"barron's":                  true,
+	"benzinga":                  true,
+	"bloomberg":                 true,
+	"business insider":          true,
+	"cnbc":                      true,
+	"cnn business":              true,
+	"coindesk":                  true,
+	"financial times":           true,
+	"forbes":                    true,
+	"fox business":              true,
+	"investing.com":             true,
+	"investopedia":              true,
+	"investor's business daily": true,
+	"marketwatch":               true,
+	"morningstar":               true,
+	"nasdaq":                    true,
+	"reuters":                   true,
+	"seeking alpha":             true,
+	"the motley fool":           true,
+	"the wall street journal":   true,
+	"thestreet":                 true,
+	"wsj":                       true,
+	"yahoo finance":             true,
+	"zacks":                     true,
+}
+
+// sourceNameConnectors may appear lowercase inside a title-cased publisher name
+var sourceNameConnectors = map[string]bool{
+	"and": true, "for": true, "in": true, "of": true, "on": true, "the": true,
+}
+
+// looksLikeSourceName reports whether s is plausibly a publisher name: a
+// known outlet, or a short title-cased name without digits or percentages
+func looksLikeSourceName(s string) bool {
+	if s == "" || len(s) > maxSourceAttributionLen {
+		return false
+	}
+	if knownNewsOutlets[strings.ToLower(s)] {
+		return true
+	}
+
+	// Publisher names are short and don't read like sentences
+	words := strings.Fields(s)
+	if len(words) > 5 {
+		return false
+	}
+	if strings.ContainsAny(s, "?!:;\"%0123456789") || strings.HasSuffix(s, ".") {
+		return false
+	}
+
+	for i, word := range words {
+		first, _ := utf8.DecodeRuneInString(word)
+		if !unicode.IsLower(first) {
+			continue
+		}
+		if i == 0 || !sourceNameConnectors[word] {
+			return false
+		}
+	}
+
+	return true
 }
 
 // GetLatestNews returns the most recent N news items
@@ -222,6 +412,32 @@ func (ns *NewsService) FilterNewsByKeywords(items []NewsItem, keywords []string)
 	return filtered
 }
 
+// DedupeNews drops repeated stories, keeping the first occurrence. Items
+// match on link, or on title (ignoring case and spacing) when a feed
+// republishes a story under a different link.
+func DedupeNews(items []NewsItem) []NewsItem {
+	seenLinks := make(map[string]bool)
+	seenTitles := make(map[string]bool)
+
+	deduped := make([]NewsItem, 0, len(items))
+	for _, item := range items {
+		link := strings.TrimSpace(item.Link)
+		title := strings.Join(strings.Fields(strings.ToLower(item.Title)), " ")
+		if (link != "" && seenLinks[link]) || (title != "" && seenTitles[title]) {
+			continue
+		}
+		if link != "" {
+			seenLinks[link] = true
+		}
+		if title != "" {
+			seenTitles[title] = true
+		}
+		deduped = append(deduped, item)
+	}
+
+	return deduped
+}
+
 // Helper function for case-insensitive string matching
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr ||
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSplitSourceFromTitle(t *testing.T) {
+	tests := []struct {
+		name       string
+		title      string
+		source     string
+		wantTitle  string
+		wantSource string
+	}{
+		{"publisher suffix", "Apple beats estimates - Reuters", "", "Apple beats estimates", "Reuters"},
+		{"multi-word publisher", "Stocks slide - The Wall Street Journal", "", "Stocks slide", "The Wall Street Journal"},
+		{"known outlet with digits", "Dividend picks - 24/7 Wall St.", "", "Dividend picks", "24/7 Wall St."},
+		{"known source stripped exactly", "Stocks slide - Yahoo Finance", "Yahoo Finance", "Stocks slide", "Yahoo Finance"},
+		{"known source kept when suffix differs", "Apple rallies - shares up 5%", "Reuters", "Apple rallies - shares up 5%", "Reuters"},
+		{"percent suffix", "Apple rallies - shares up 5%", "", "Apple rallies - shares up 5%", ""},
+		{"lowercase phrase suffix", "Fed holds rates - what it means", "", "Fed holds rates - what it means", ""},
+		{"digits suffix", "Nvidia earnings - Q3 Beat", "", "Nvidia earnings - Q3 Beat", ""},
+		{"question suffix", "Is the rally over - Should You Sell?", "", "Is the rally over - Should You Sell?", ""},
+		{"hyphenated word", "Short-term traders pile in", "", "Short-term traders pile in", ""},
+		{"sentence suffix", "Markets - Investors Brace For A Very Long And Volatile Week", "", "Markets - Investors Brace For A Very Long And Volatile Week", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := NewsItem{Title: tt.title, Source: tt.source}
+			splitSourceFromTitle(&item)
+			if item.Title != tt.wantTitle || item.Source != tt.wantSource {
+				t.Errorf("got title %q source %q, want %q %q", item.Title, item.Source, tt.wantTitle, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestCleanHTMLText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"ampersand entity", "Johnson &amp; Johnson", "Johnson & Johnson"},
+		{"numeric apostrophe", "Investors&#39; outlook", "Investors' outlook"},
+		{"nested tags", `<div><p>Stocks <b><i>rally</i></b></p> today</div>`, "Stocks rally today"},
+		{"link with attributes", `<a href="https://example.com?a=1&amp;b=2" target="_blank">Read more</a>`, "Read more"},
+		{"escaped markup kept as text", "Use &lt;b&gt; for bold", "Use <b> for bold"},
+		{"whitespace collapsed", "  Fed\n\n holds\t rates  ", "Fed holds rates"},
+		{"tags between words", "Apple<br/>Microsoft<br>Nvidia", "Apple Microsoft Nvidia"},
+		{"non-breaking space", "S&amp;P&nbsp;500", "S&P 500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cleanHTMLText(tt.in); got != tt.want {
+				t.Errorf("cleanHTMLText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// flakyServer answers the first failures requests with status, then serves an
+// empty feed, recording when each request arrived
+type flakyServer struct {
+	mu       sync.Mutex
+	failures int
+	status   int
+	hits     []time.Time
+}
+
+func (f *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.hits = append(f.hits, time.Now())
+	if len(f.hits) <= f.failures {
+		w.WriteHeader(f.status)
+		return
+	}
+	w.Write([]byte("<rss></rss>"))
+}
+
+func TestGetWithRetryRecoversAfterFailure(t *testing.T) {
+	flaky := &flakyServer{failures: 1, status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	body, err := NewNewsService().getWithRetry(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("getWithRetry: %v", err)
+	}
+	if string(body) != "<rss></rss>" {
+		t.Errorf("body = %q", body)
+	}
+
+	if len(flaky.hits) != 2 {
+		t.Fatalf("server hit %d times, want 2", len(flaky.hits))
+	}
+	if gap := flaky.hits[1].Sub(flaky.hits[0]); gap < rssRetryBaseDelay {
+		t.Errorf("retry after %s, want at least the %s backoff", gap, rssRetryBaseDelay)
+	}
+}
+
+func TestGetWithRetryDoesNotRetryClientErrors(t *testing.T) {
+	flaky := &flakyServer{failures: rssMaxAttempts, status: http.StatusNotFound}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	if _, err := NewNewsService().getWithRetry(context.Background(), server.URL); err == nil {
+		t.Fatal("404 returned no error")
+	}
+	if len(flaky.hits) != 1 {
+		t.Errorf("server hit %d times, want 1", len(flaky.hits))
+	}
+}
+
+func TestGetWithRetryStopsWhenCanceled(t *testing.T) {
+	flaky := &flakyServer{failures: rssMaxAttempts, status: http.StatusTooManyRequests}
+	server := httptest.NewServer(flaky)
+	defer server.Close()
+
+	// The context ends during the first backoff
+	ctx, cancel := context.WithTimeout(context.Background(), rssRetryBaseDelay/5)
+	defer cancel()
+
+	if _, err := NewNewsService().getWithRetry(ctx, server.URL); err == nil {
+		t.Fatal("canceled fetch returned no error")
+	}
+	if len(flaky.hits) != 1 {
+		t.Errorf("server hit %d times, want 1", len(flaky.hits))
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"prophet-trader/apperrors"
+	"sync"
+	"time"
+)
+
+// googleNewsHost is throttled by default; it answers bursts with 429s and empty feeds
+const googleNewsHost = "news.google.com"
+
+// DefaultGoogleNewsInterval is the default minimum gap between Google News requests
+const DefaultGoogleNewsInterval = time.Second
+
+// throttleMaxWait is the longest a request queues for its slot. Beyond that
+// it fails fast with errThrottled instead of stalling the caller.
+const throttleMaxWait = 10 * time.Second
+
+// errThrottled is returned when a host's request queue is too long
+var errThrottled = apperrors.RateLimited("request throttled")
+
+// hostThrottle spaces requests to each host by a minimum interval
+type hostThrottle struct {
+	mu        sync.Mutex
+	intervals map[string]time.Duration
+	next      map[string]time.Time // earliest start of the next request per host
+}
+
+// newHostThrottle creates a throttle with no limits
+func newHostThrottle() *hostThrottle {
+	return &hostThrottle{
+		intervals: make(map[string]time.Duration),
+		next:      make(map[string]time.Time),
+	}
+}
+
+// wait blocks until a request to rawURL's host may start, reserving its slot
+func (t *hostThrottle) wait(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	host := parsed.Hostname()
+
+	t.mu.Lock()
+	interval := t.intervals[host]
+	if interval <= 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	slot := t.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	delay := slot.Sub(now)
+	if delay > throttleMaxWait {
+		t.mu.Unlock()
+		return fmt.Errorf("%w: %s queue exceeds %s", errThrottled, host, throttleMaxWait)
+	}
+	t.next[host] = slot.Add(interval)
+	t.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// SetHostThrottle sets the minimum interval between requests to host. Zero
+// removes the limit.
+func (ns *NewsService) SetHostThrottle(host string, minInterval time.Duration) error {
+	if minInterval < 0 {
+		return fmt.Errorf("throttle interval must not be negative")
+	}
+
+	ns.throttle.mu.Lock()
+	defer ns.throttle.mu.Unlock()
+	ns.throttle.intervals[host] = minInterval
+	return nil
+}
+
+// SetGoogleNewsThrottle sets the minimum interval between Google News requests
+func (ns *NewsService) SetGoogleNewsThrottle(minInterval time.Duration) error {
+	return ns.SetHostThrottle(googleNewsHost, minInterval)
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"prophet-trader/database"
+	"prophet-trader/interfaces"
+	"prophet-trader/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultOptionSnapshotInterval is how often tracked contracts are captured
+const DefaultOptionSnapshotInterval = 15 * time.Minute
+
+// OptionSnapshot is one stored observation of an option contract
+type OptionSnapshot struct {
+	Symbol            string    `json:"symbol"`
+	Underlying        string    `json:"underlying"`
+	ContractType      string    `json:"contract_type"`
+	StrikePrice       float64   `json:"strike_price"`
+	ExpirationDate    string    `json:"expiration_date"`
+	Bid               float64   `json:"bid"`
+	Ask               float64   `json:"ask"`
+	Premium           float64   `json:"premium"`
+	ImpliedVolatility float64   `json:"implied_volatility"`
+	Delta             float64   `json:"delta"`
+	Gamma             float64   `json:"gamma"`
+	Theta             float64   `json:"theta"`
+	Vega              float64   `json:"vega"`
+	OpenInterest      int64     `json:"open_interest"`
+	Volume            int64     `json:"volume"`
+	QuoteTime         time.Time `json:"quote_time"`
+	SnapshotTime      time.Time `json:"snapshot_time"`
+}
+
+// OptionSnapshotRecorder periodically stores quotes, IV and greeks for tracked
+// contracts: configured symbols plus every open options position, managed or not
+type OptionSnapshotRecorder struct {
+	data            interfaces.OptionSnapshotProvider
+	storage         *database.LocalStorage
+	tradingService  interfaces.TradingService
+	positionManager *PositionManager
+	symbols         []string
+	interval        time.Duration
+	logger          *logrus.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewOptionSnapshotRecorder creates a recorder capturing symbols (OCC contract
+// symbols) alongside open options positions every interval
+func NewOptionSnapshotRecorder(data interfaces.OptionSnapshotProvider, storage *database.LocalStorage, tradingService interfaces.TradingService, positionManager *PositionManager, symbols []string, interval time.Duration) *OptionSnapshotRecorder {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	if interval <= 0 {
+		interval = DefaultOptionSnapshotInterval
+	}
+
+	tracked := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		tracked = append(tracked, strings.ToUpper(strings.TrimSpace(symbol)))
+	}
+
+	return &OptionSnapshotRecorder{
+		data:            data,
+		storage:         storage,
+		tradingService:  tradingService,
+		positionManager: positionManager,
+		symbols:         tracked,
+		interval:        interval,
+		logger:          logger,
+	}
+}
+
+// Start captures snapshots in the background during market hours until Stop
+// is called or ctx is cancelled
+func (r *OptionSnapshotRecorder) Start(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		return fmt.Errorf("option snapshot capture already running")
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(runCtx, r.done)
+
+	r.logger.WithFields(logrus.Fields{
+		"interval": r.interval,
+		"symbols":  len(r.symbols),
+	}).Info("Option snapshot capture started")
+
+	return nil
+}
+
+// Stop stops the capture loop and waits for an in-flight capture to finish
+func (r *OptionSnapshotRecorder) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	<-done
+}
+
+func (r *OptionSnapshotRecorder) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Option snapshot capture stopped")
+			return
+		case now := <-ticker.C:
+			if !IsMarketOpen(now) {
+				continue
+			}
+			r.Capture(ctx)
+		}
+	}
+}
+
+// TrackedContracts lists the contracts captured on each run, sorted
+func (r *OptionSnapshotRecorder) TrackedContracts(ctx context.Context) []string {
+	seen := make(map[string]bool)
+	for _, symbol := range r.symbols {
+		seen[symbol] = true
+	}
+
+	if r.positionManager != nil {
+		for _, position := range r.positionManager.ListManagedPositions("") {
+			if position.AssetClass == AssetClassOption && !isTerminalStatus(position.Status) {
+				seen[position.Symbol] = true
+			}
+		}
+	}
+
+	if positions, err := r.tradingService.ListOptionsPositions(ctx); err != nil {
+		r.logger.WithError(err).Warn("Failed to list options positions for snapshot capture")
+	} else {
+		for _, position := range positions {
+			seen[position.Symbol] = true
+		}
+	}
+
+	contracts := make([]string, 0, len(seen))
+	for symbol := range seen {
+		contracts = append(contracts, symbol)
+	}
+	sort.Strings(contracts)
+	return contracts
+}
+
+// Capture snapshots every tracked contract now and returns how many were stored
+func (r *OptionSnapshotRecorder) Capture(ctx context.Context) int {
+	contracts := r.TrackedContracts(ctx)
+	saved := 0
+
+	for _, symbol := range contracts {
+		contract, err := r.data.GetOptionSnapshot(ctx, symbol)
+		if err != nil {
+			r.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to get option snapshot")
+			continue
+		}
+
+		if err := r.storage.SaveOptionSnapshot(newDBOptionSnapshot(symbol, contract, time.Now())); err != nil {
+			r.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to save option snapshot")
+			continue
+		}
+		saved++
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"tracked": len(contracts),
+		"saved":   saved,
+	}).Debug("Option snapshot capture complete")
+
+	return saved
+}
+
+// History returns a contract's stored snapshots between start and end, oldest first
+func (r *OptionSnapshotRecorder) History(symbol string, start, end time.Time) ([]*OptionSnapshot, error) {
+	rows, err := r.storage.GetOptionSnapshots(strings.ToUpper(symbol), start, end)
+	if err != nil {
+		return nil, err
+	}
+	return toOptionSnapshots(rows), nil
+}
+
+// UnderlyingHistory returns stored snapshots of all contracts on an underlying
+// between start and end, oldest first
+func (r *OptionSnapshotRecorder) UnderlyingHistory(underlying string, start, end time.Time) ([]*OptionSnapshot, error) {
+	rows, err := r.storage.GetOptionSnapshotsForUnderlying(strings.ToUpper(underlying), start, end)
+	if err != nil {
+		return nil, err
+	}
+	return toOptionSnapshots(rows), nil
+}
+
+// newDBOptionSnapshot builds a storage row, filling contract details the
+// snapshot lacks from the OCC symbol
+func newDBOptionSnapshot(symbol string, contract *interfaces.OptionContract, at time.Time) *models.DBOptionSnapshot {
+	row := &models.DBOptionSnapshot{
+		Symbol:            symbol,
+		Underlying:        contract.UnderlyingSymbol,
+		ContractType:      contract.ContractType,
+		StrikePrice:       contract.StrikePrice,
+		ExpirationDate:    contract.ExpirationDate,
+		Bid:               contract.Bid,
+		Ask:               contract.Ask,
+		Premium:           contract.Premium,
+		ImpliedVolatility: contract.ImpliedVolatility,
+		Delta:             contract.Delta,
+		Gamma:             contract.Gamma,
+		Theta:             contract.Theta,
+		Vega:              contract.Vega,
+		OpenInterest:      contract.OpenInterest,
+		Volume:            contract.Volume,
+		QuoteTime:         contract.QuoteTime,
+		SnapshotTime:      at,
+	}
+
+	if underlying, contractType, strike, expiration, err := parseOCCSymbol(symbol); err == nil {
+		if row.Underlying == "" {
+			row.Underlying = underlying
+		}
+		if row.ContractType == "" {
+			row.ContractType = contractType
+		}
+		if row.StrikePrice == 0 {
+			row.StrikePrice = strike
+		}
+		if row.ExpirationDate.IsZero() {
+			row.ExpirationDate = expiration
+		}
+	}
+
+	return row
+}
+
+// parseOCCSymbol splits an OCC symbol (root, YYMMDD, C/P, strike x 1000)
+func parseOCCSymbol(symbol string) (underlying, contractType string, strike float64, expiration time.Time, err error) {
+	if len(symbol) <= occSuffixLength {
+		return "", "", 0, time.Time{}, fmt.Errorf("%s is not an OCC option symbol", symbol)
+	}
+
+	suffix := symbol[len(symbol)-occSuffixLength:]
+	expiration, err = time.Parse("060102", suffix[:6])
+	if err != nil {
+		return "", "", 0, time.Time{}, fmt.Errorf("%s has an invalid expiration: %w", symbol, err)
+	}
+
+	switch suffix[6] {
+	case 'C':
+		contractType = "call"
+	case 'P':
+		contractType = "put"
+	default:
+		return "", "", 0, time.Time{}, fmt.Errorf("%s has an invalid contract type", symbol)
+	}
+
+	strikeThousandths, err := strconv.ParseInt(suffix[7:], 10, 64)
+	if err != nil {
+		return "", "", 0, time.Time{}, fmt.Errorf("%s has an invalid strike: %w", symbol, err)
+	}
+
+	return symbol[:len(symbol)-occSuffixLength], contractType, float64(strikeThousandths) / 1000, expiration, nil
+}
+
+func toOptionSnapshots(rows []*models.DBOptionSnapshot) []*OptionSnapshot {
+	snapshots := make([]*OptionSnapshot, 0, len(rows))
+	for _, row := range rows {
+		snapshot := &OptionSnapshot{
+			Symbol:            row.Symbol,
+			Underlying:        row.Underlying,
+			ContractType:      row.ContractType,
+			StrikePrice:       row.StrikePrice,
+			Bid:               row.Bid,
+			Ask:               row.Ask,
+			Premium:           row.Premium,
+			ImpliedVolatility: row.ImpliedVolatility,
+			Delta:             row.Delta,
+			Gamma:             row.Gamma,
+			Theta:             row.Theta,
+			Vega:              row.Vega,
+			OpenInterest:      row.OpenInterest,
+			Volume:            row.Volume,
+			QuoteTime:         row.QuoteTime,
+			SnapshotTime:      row.SnapshotTime,
+		}
+		if !row.ExpirationDate.IsZero() {
+			snapshot.ExpirationDate = row.ExpirationDate.Format("2006-01-02")
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
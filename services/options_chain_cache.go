@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"prophet-trader/apperrors"
+	"prophet-trader/interfaces"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for the options chain cache
+const (
+	DefaultOptionsChainCacheTTL  = 30 * time.Second
+	DefaultOptionsChainFetchRate = 500 * time.Millisecond
+)
+
+// optionsChainMaxWait is the longest a chain fetch queues for its slot before
+// failing with a rate limit error (or serving a stale chain, if there is one)
+const optionsChainMaxWait = 5 * time.Second
+
+// OptionsChainCache caches raw options chains per (underlying, expiration) and
+// spaces the broker requests that refill it, so clients polling a chain
+// filter cached contracts instead of refetching the whole chain every time
+type OptionsChainCache struct {
+	trading     interfaces.TradingService
+	ttl         time.Duration
+	minInterval time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]cachedChain
+	inflight  map[string]*chainFetch
+	nextFetch time.Time // earliest start of the next broker request
+}
+
+type cachedChain struct {
+	contracts []*interfaces.OptionContract
+	fetchedAt time.Time
+}
+
+// chainFetch is a broker request in progress that concurrent callers for the
+// same key wait on instead of issuing their own
+type chainFetch struct {
+	done  chan struct{}
+	chain cachedChain
+	err   error
+}
+
+// ChainResult is a chain served by the cache. Contracts are copies the caller
+// may modify.
+type ChainResult struct {
+	Contracts []*interfaces.OptionContract
+	FetchedAt time.Time
+	Cached    bool // served without a broker request
+	Stale     bool // older than the TTL, served because the refetch was rate limited
+}
+
+// NewOptionsChainCache creates a chain cache. A ttl of zero disables caching
+// and a minInterval of zero disables rate limiting; negative values use the
+// defaults.
+func NewOptionsChainCache(trading interfaces.TradingService, ttl, minInterval time.Duration) *OptionsChainCache {
+	if ttl < 0 {
+		ttl = DefaultOptionsChainCacheTTL
+	}
+	if minInterval < 0 {
+		minInterval = DefaultOptionsChainFetchRate
+	}
+
+	return &OptionsChainCache{
+		trading:     trading,
+		ttl:         ttl,
+		minInterval: minInterval,
+		entries:     make(map[string]cachedChain),
+		inflight:    make(map[string]*chainFetch),
+	}
+}
+
+// Get returns the chain for underlying at expiration, from the cache when it
+// is within the TTL unless refresh is set
+func (cc *OptionsChainCache) Get(ctx context.Context, underlying string, expiration time.Time, refresh bool) (*ChainResult, error) {
+	key := strings.ToUpper(underlying) + "|" + expiration.Format("2006-01-02")
+
+	cc.mu.Lock()
+	entry, ok := cc.entries[key]
+	if ok && !refresh && time.Since(entry.fetchedAt) < cc.ttl {
+		cc.mu.Unlock()
+		return chainResult(entry, true, false), nil
+	}
+
+	// Join a fetch already under way for the same chain
+	if fetch, running := cc.inflight[key]; running {
+		cc.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-fetch.done:
+		}
+		if fetch.err != nil {
+			return nil, fetch.err
+		}
+		return chainResult(fetch.chain, true, false), nil
+	}
+
+	delay, err := cc.reserveSlot()
+	if err != nil {
+		cc.mu.Unlock()
+		if ok {
+			return chainResult(entry, true, true), nil
+		}
+		return nil, err
+	}
+	fetch := &chainFetch{done: make(chan struct{})}
+	cc.inflight[key] = fetch
+	cc.mu.Unlock()
+
+	fetch.chain, fetch.err = cc.fetch(ctx, underlying, expiration, delay)
+
+	cc.mu.Lock()
+	delete(cc.inflight, key)
+	if fetch.err == nil && cc.ttl > 0 {
+		cc.entries[key] = fetch.chain
+	}
+	cc.mu.Unlock()
+	close(fetch.done)
+
+	if fetch.err != nil {
+		return nil, fetch.err
+	}
+	return chainResult(fetch.chain, false, false), nil
+}
+
+// reserveSlot books the next broker request slot and returns how long to wait
+// for it. The caller must hold cc.mu.
+func (cc *OptionsChainCache) reserveSlot() (time.Duration, error) {
+	if cc.minInterval <= 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	slot := cc.nextFetch
+	if slot.Before(now) {
+		slot = now
+	}
+	delay := slot.Sub(now)
+	if delay > optionsChainMaxWait {
+		return 0, apperrors.RateLimited("options chain requests exceed one per %s, retry shortly", cc.minInterval)
+	}
+	cc.nextFetch = slot.Add(cc.minInterval)
+	return delay, nil
+}
+
+// fetch waits out delay and requests the chain from the broker
+func (cc *OptionsChainCache) fetch(ctx context.Context, underlying string, expiration time.Time, delay time.Duration) (cachedChain, error) {
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return cachedChain{}, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	contracts, err := cc.trading.GetOptionsChain(ctx, underlying, expiration)
+	if err != nil {
+		return cachedChain{}, fmt.Errorf("failed to fetch options chain: %w", err)
+	}
+	return cachedChain{contracts: contracts, fetchedAt: time.Now()}, nil
+}
+
+// chainResult copies a cached chain so callers can't modify the cache
+func chainResult(chain cachedChain, cached, stale bool) *ChainResult {
+	contracts := make([]*interfaces.OptionContract, len(chain.contracts))
+	for i, contract := range chain.contracts {
+		c := *contract
+		contracts[i] = &c
+	}
+	return &ChainResult{
+		Contracts: contracts,
+		FetchedAt: chain.fetchedAt,
+		Cached:    cached,
+		Stale:     stale,
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+
+	"prophet-trader/interfaces"
+)
+
+// Liquidity gate modes
+const (
+	LiquidityGateOff     = "off"
+	LiquidityGateExclude = "exclude"
+	LiquidityGateFlag    = "flag"
+)
+
+// OptionLiquidityGate sets the minimum liquidity an option contract needs to
+// be considered tradable. Zero thresholds are not checked.
+type OptionLiquidityGate struct {
+	Mode             string  // "off", "exclude" (drop illiquid contracts) or "flag" (keep and annotate)
+	MinVolume        int64   // minimum contracts traded today
+	MinOpenInterest  int64   // minimum open interest
+	MaxSpreadPercent float64 // maximum bid-ask spread as a percent of the midpoint
+}
+
+// Validate checks the gate's mode and thresholds
+func (g OptionLiquidityGate) Validate() error {
+	switch g.Mode {
+	case LiquidityGateOff, LiquidityGateExclude, LiquidityGateFlag:
+	default:
+		return fmt.Errorf("invalid liquidity gate mode %q: use off, exclude or flag", g.Mode)
+	}
+	if g.MinVolume < 0 || g.MinOpenInterest < 0 || g.MaxSpreadPercent < 0 {
+		return fmt.Errorf("liquidity thresholds must be non-negative")
+	}
+	return nil
+}
+
+// Issues lists the thresholds a contract fails. quoted says whether the
+// contract carries a live quote and volume; contract listings without one
+// are only checked on open interest.
+func (g OptionLiquidityGate) Issues(contract *interfaces.OptionContract, quoted bool) []string {
+	if g.Mode == LiquidityGateOff || g.Mode == "" {
+		return nil
+	}
+
+	issues := make([]string, 0)
+	if g.MinOpenInterest > 0 && contract.OpenInterest < g.MinOpenInterest {
+		issues = append(issues, fmt.Sprintf("open interest %d below %d", contract.OpenInterest, g.MinOpenInterest))
+	}
+	if !quoted {
+		return issues
+	}
+
+	if g.MinVolume > 0 && contract.Volume < g.MinVolume {
+		issues = append(issues, fmt.Sprintf("volume %d below %d", contract.Volume, g.MinVolume))
+	}
+	if g.MaxSpreadPercent > 0 {
+		mid := (contract.Bid + contract.Ask) / 2
+		if contract.Bid <= 0 || contract.Ask <= 0 || mid <= 0 {
+			issues = append(issues, "no two-sided quote")
+		} else if spread := (contract.Ask - contract.Bid) / mid * 100; spread > g.MaxSpreadPercent {
+			issues = append(issues, fmt.Sprintf("spread %.1f%% above %.1f%%", spread, g.MaxSpreadPercent))
+		}
+	}
+	return issues
+}
+
+// Apply gates contracts: illiquid ones are dropped in exclude mode or kept
+// with LiquidityIssues set in flag mode. It returns the contracts kept and how
+// many were illiquid.
+func (g OptionLiquidityGate) Apply(contracts []*interfaces.OptionContract, quoted bool) ([]*interfaces.OptionContract, int) {
+	if g.Mode == LiquidityGateOff || g.Mode == "" {
+		return contracts, 0
+	}
+
+	kept := make([]*interfaces.OptionContract, 0, len(contracts))
+	illiquid := 0
+	for _, contract := range contracts {
+		issues := g.Issues(contract, quoted)
+		if len(issues) > 0 {
+			illiquid++
+			if g.Mode == LiquidityGateExclude {
+				continue
+			}
+			contract.LiquidityIssues = issues
+		}
+		kept = append(kept, contract)
+	}
+	return kept, illiquid
+}
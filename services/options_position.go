@@ -0,0 +1,426 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"prophet-trader/apperrors"
+	"prophet-trader/interfaces"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// AssetClassOption marks a managed position holding options contracts
+const AssetClassOption = "option"
+
+// Stop bases for managed options positions
+const (
+	StopBasisPremium    = "premium"    // stop when the option's premium falls to the stop price
+	StopBasisUnderlying = "underlying" // stop when the underlying crosses its stop price
+)
+
+// Options sizing modes
+const (
+	OptionsSizingPremium = "premium" // spend the allocation on premium
+	OptionsSizingDelta   = "delta"   // match the allocation in delta-adjusted underlying exposure
+)
+
+// maxOptionQuoteAge is how old an option quote may be during market hours
+// before its premium is treated as stale
+const maxOptionQuoteAge = 5 * time.Minute
+
+// occSuffixLength is the length of the expiry/type/strike suffix of an OCC symbol
+const occSuffixLength = 15
+
+// PlaceManagedOptionsPositionRequest opens a long options position with a
+// stop on the premium or the underlying and a profit target on the premium
+type PlaceManagedOptionsPositionRequest struct {
+	Symbol            string  `json:"symbol" binding:"required"` // OCC format, e.g. TSLA251219C00400000
+	Strategy          string  `json:"strategy"`                  // "SWING_TRADE", "LONG_TERM", "DAY_TRADE"
+	AllocationDollars float64 `json:"allocation_dollars" binding:"required,gt=0"`
+	SizingMode        string  `json:"sizing_mode,omitempty"` // "premium" (default) or "delta"
+
+	// Entry configuration
+	EntryStrategy string   `json:"entry_strategy"`        // "market", "limit"
+	EntryPrice    *float64 `json:"entry_price,omitempty"` // premium per share; required for limit orders
+
+	// Stop (one of these required for the chosen basis)
+	StopBasis           string   `json:"stop_basis,omitempty"` // "premium" (default) or "underlying"
+	StopLossPrice       *float64 `json:"stop_loss_price,omitempty"`
+	StopLossPercent     *float64 `json:"stop_loss_percent,omitempty"`
+	UnderlyingStopPrice *float64 `json:"underlying_stop_price,omitempty"`
+
+	// Profit target on the premium (one of these required)
+	TakeProfitPrice   *float64 `json:"take_profit_price,omitempty"`
+	TakeProfitPercent *float64 `json:"take_profit_percent,omitempty"`
+
+	Notes string   `json:"notes,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// SetOptionsData enables managed options positions using provider for
+// premiums and greeks
+func (pm *PositionManager) SetOptionsData(provider interfaces.OptionSnapshotProvider) {
+	pm.optionsData = provider
+}
+
+// PlaceManagedOptionsPosition buys options contracts and manages the exit.
+// Stops and targets are not resting broker orders: the monitor checks them
+// each cycle during market hours and closes with a market order.
+func (pm *PositionManager) PlaceManagedOptionsPosition(ctx context.Context, req *PlaceManagedOptionsPositionRequest) (*ManagedPosition, error) {
+	if pm.optionsData == nil {
+		return nil, fmt.Errorf("options data not configured")
+	}
+	if pm.dryRun {
+		return nil, apperrors.Validation("options positions are not simulated: dry run is enabled")
+	}
+
+	symbol := strings.ToUpper(req.Symbol)
+	if !isOptionSymbol(symbol) {
+		return nil, apperrors.Validation("invalid request: %s is not an OCC option symbol", req.Symbol)
+	}
+	if err := validateOptionsRequest(req); err != nil {
+		return nil, apperrors.Validation("invalid request: %w", err)
+	}
+	if err := pm.checkOpenPositionLimit(1); err != nil {
+		return nil, err
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"symbol":     symbol,
+		"allocation": req.AllocationDollars,
+		"sizing":     req.SizingMode,
+		"stop_basis": req.StopBasis,
+	}).Info("Placing managed options position")
+
+	contract, err := pm.optionsData.GetOptionSnapshot(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get option snapshot: %w", err)
+	}
+	if contract.Premium <= 0 {
+		return nil, fmt.Errorf("no quote for %s", symbol)
+	}
+
+	underlying := symbol[:len(symbol)-occSuffixLength]
+	underlyingPrice, err := pm.getCurrentPrice(ctx, underlying)
+	if err != nil && (req.SizingMode == OptionsSizingDelta || req.StopBasis == StopBasisUnderlying) {
+		return nil, fmt.Errorf("failed to get underlying price: %w", err)
+	}
+
+	entryPrice := contract.Premium
+	if req.EntryPrice != nil {
+		entryPrice = *req.EntryPrice
+	}
+	entryPrice = RoundMoney(entryPrice)
+
+	// Whole contracts only
+	var contracts float64
+	switch req.SizingMode {
+	case OptionsSizingDelta:
+		exposure := math.Abs(contract.Delta) * underlyingPrice * optionContractMultiplier
+		if exposure <= 0 {
+			return nil, fmt.Errorf("no delta for %s", symbol)
+		}
+		contracts = math.Floor(req.AllocationDollars / exposure)
+	default:
+		contracts = math.Floor(req.AllocationDollars / (entryPrice * optionContractMultiplier))
+	}
+	if contracts < 1 {
+		return nil, apperrors.Validation("allocation $%.2f buys less than one contract of %s", req.AllocationDollars, symbol)
+	}
+
+	position := &ManagedPosition{
+		ID:                pm.generatePositionID(),
+		Symbol:            symbol,
+		Side:              "buy",
+		Strategy:          req.Strategy,
+		Quantity:          contracts,
+		RemainingQty:      contracts,
+		EntryPrice:        entryPrice,
+		EntryOrderType:    req.EntryStrategy,
+		AllocationDollars: RoundMoney(contracts * entryPrice * optionContractMultiplier),
+		AssetClass:        AssetClassOption,
+		Underlying:        underlying,
+		UnderlyingPrice:   underlyingPrice,
+		StopBasis:         req.StopBasis,
+		EntryDelta:        contract.Delta,
+		Delta:             contract.Delta,
+		Status:            "PENDING",
+		CurrentPrice:      contract.Premium,
+		EntryTimeInForce:  "day",
+		StopTimeInForce:   "day",
+		TargetTimeInForce: "day",
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+		Notes:             req.Notes,
+		Tags:              req.Tags,
+	}
+
+	if req.StopBasis == StopBasisUnderlying {
+		position.UnderlyingStopPrice = RoundPrice(*req.UnderlyingStopPrice)
+		if underlyingStopHit(position, underlyingPrice) {
+			return nil, fmt.Errorf("underlying %s at %.2f is already through the stop %.2f", underlying, underlyingPrice, position.UnderlyingStopPrice)
+		}
+		position.ApproxPremiumStop = approxPremiumStop(position)
+	} else {
+		position.StopLossPrice = RoundMoney(pm.calculateStopLoss(entryPrice, req.StopLossPrice, req.StopLossPercent, "buy"))
+		position.StopLossPercent = math.Abs((position.StopLossPrice - entryPrice) / entryPrice * 100)
+	}
+	position.TakeProfitPrice = RoundMoney(pm.calculateTakeProfit(entryPrice, req.TakeProfitPrice, req.TakeProfitPercent, "buy"))
+	position.TakeProfitPercent = math.Abs((position.TakeProfitPrice - entryPrice) / entryPrice * 100)
+
+	if err := pm.checkRiskReward(position.StopLossPercent, position.TakeProfitPercent); err != nil {
+		return nil, apperrors.Validation("invalid request: %w", err)
+	}
+
+	if req.Notes != "" {
+		position.Journal = []PositionNote{{Timestamp: position.CreatedAt, Note: req.Notes}}
+	}
+
+	if err := pm.placeOptionsEntryOrder(ctx, position); err != nil {
+		return nil, fmt.Errorf("failed to place entry order: %w", err)
+	}
+
+	position.EntryReasoning = pm.generateReasoning(ctx, position, "ENTRY")
+
+	pm.mu.Lock()
+	pm.positions[position.ID] = position
+	pm.mu.Unlock()
+
+	if err := pm.savePositionToDB(position); err != nil {
+		pm.logger.WithError(err).Error("Failed to save position to database")
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id":     position.ID,
+		"entry_order_id":  position.EntryOrderID,
+		"contracts":       contracts,
+		"entry_premium":   entryPrice,
+		"stop_basis":      position.StopBasis,
+		"stop_loss":       position.StopLossPrice,
+		"underlying_stop": position.UnderlyingStopPrice,
+		"approx_premium":  position.ApproxPremiumStop,
+		"take_profit":     position.TakeProfitPrice,
+	}).Info("Managed options position created")
+
+	return position, nil
+}
+
+// validateOptionsRequest checks a managed options request, filling defaults
+func validateOptionsRequest(req *PlaceManagedOptionsPositionRequest) error {
+	if req.SizingMode == "" {
+		req.SizingMode = OptionsSizingPremium
+	}
+	if req.SizingMode != OptionsSizingPremium && req.SizingMode != OptionsSizingDelta {
+		return fmt.Errorf("sizing_mode must be 'premium' or 'delta'")
+	}
+
+	if req.EntryStrategy == "" {
+		req.EntryStrategy = "market"
+	}
+	if req.EntryStrategy != "market" && req.EntryStrategy != "limit" {
+		return fmt.Errorf("entry_strategy must be 'market' or 'limit'")
+	}
+	if req.EntryStrategy == "limit" && (req.EntryPrice == nil || *req.EntryPrice <= 0) {
+		return fmt.Errorf("entry_price required for limit orders")
+	}
+
+	if req.StopBasis == "" {
+		req.StopBasis = StopBasisPremium
+	}
+	switch req.StopBasis {
+	case StopBasisPremium:
+		if req.StopLossPrice == nil && req.StopLossPercent == nil {
+			return fmt.Errorf("one of stop_loss_price or stop_loss_percent required for a premium stop")
+		}
+	case StopBasisUnderlying:
+		if req.UnderlyingStopPrice == nil || *req.UnderlyingStopPrice <= 0 {
+			return fmt.Errorf("underlying_stop_price required for an underlying stop")
+		}
+	default:
+		return fmt.Errorf("stop_basis must be 'premium' or 'underlying'")
+	}
+
+	if req.TakeProfitPrice == nil && req.TakeProfitPercent == nil {
+		return fmt.Errorf("one of take_profit_price or take_profit_percent required")
+	}
+
+	return nil
+}
+
+// placeOptionsEntryOrder places the buy-to-open order for an options position
+func (pm *PositionManager) placeOptionsEntryOrder(ctx context.Context, position *ManagedPosition) error {
+	optionsOrder := &interfaces.OptionsOrder{
+		Symbol:         position.Symbol,
+		Underlying:     position.Underlying,
+		Qty:            position.Quantity,
+		Side:           "buy",
+		PositionIntent: "buy_to_open",
+		Type:           position.EntryOrderType,
+		TimeInForce:    position.EntryTimeInForce,
+	}
+	if position.EntryOrderType == "limit" {
+		optionsOrder.LimitPrice = &position.EntryPrice
+	}
+
+	result, err := pm.tradingService.PlaceOptionsOrder(ctx, optionsOrder)
+	if err != nil {
+		return err
+	}
+
+	position.EntryOrderID = result.OrderID
+	position.Status = "PENDING"
+
+	pm.saveOrder(position, OrderRoleEntry, &interfaces.Order{
+		ID:          result.OrderID,
+		Symbol:      position.Symbol,
+		Qty:         position.Quantity,
+		Side:        "buy",
+		Type:        position.EntryOrderType,
+		TimeInForce: position.EntryTimeInForce,
+		LimitPrice:  optionsOrder.LimitPrice,
+		Status:      "pending",
+		SubmittedAt: time.Now(),
+	})
+
+	return nil
+}
+
+// optionQuote returns the option's current snapshot, rejecting missing or
+// (during market hours) stale quotes
+func (pm *PositionManager) optionQuote(ctx context.Context, symbol string) (*interfaces.OptionContract, error) {
+	if pm.optionsData == nil {
+		return nil, fmt.Errorf("options data not configured")
+	}
+
+	contract, err := pm.optionsData.GetOptionSnapshot(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	if contract.Premium <= 0 {
+		return nil, fmt.Errorf("no quote for %s", symbol)
+	}
+
+	now := time.Now()
+	if !contract.QuoteTime.IsZero() && IsMarketOpen(now) && now.Sub(contract.QuoteTime) > maxOptionQuoteAge {
+		return nil, fmt.Errorf("stale quote for %s from %s", symbol, contract.QuoteTime.Format(time.RFC3339))
+	}
+	return contract, nil
+}
+
+// approxPremiumStop estimates the premium at the underlying stop with a
+// first-order delta move from the current prices. Display only: gamma and
+// time decay make the real premium differ.
+func approxPremiumStop(position *ManagedPosition) float64 {
+	if position.UnderlyingStopPrice <= 0 || position.UnderlyingPrice <= 0 || position.Delta == 0 {
+		return 0
+	}
+	estimate := position.CurrentPrice + position.Delta*(position.UnderlyingStopPrice-position.UnderlyingPrice)
+	return RoundMoney(math.Max(estimate, 0))
+}
+
+// manageOptionsExits closes an options position once its stop or profit
+// target is reached. Only checked during market hours so stale overnight
+// quotes don't trigger exits. Without a fresh premium only an underlying
+// stop is checked.
+func (pm *PositionManager) manageOptionsExits(ctx context.Context, position *ManagedPosition, premiumFresh bool) {
+	if position.Status != "ACTIVE" || !IsMarketOpen(time.Now()) {
+		return
+	}
+
+	var reason string
+	status := "CLOSED"
+	closeReason := CloseReasonTakeProfit
+	switch {
+	case position.StopBasis == StopBasisUnderlying && position.UnderlyingStopPrice > 0:
+		price, err := pm.getCurrentPrice(ctx, position.Underlying)
+		if err != nil {
+			pm.logger.WithError(err).WithField("symbol", position.Underlying).Warn("Failed to get underlying price")
+			break
+		}
+		position.UnderlyingPrice = price
+		position.ApproxPremiumStop = approxPremiumStop(position)
+		if underlyingStopHit(position, price) {
+			reason = fmt.Sprintf("Underlying %s at %.2f crossed stop %.2f", position.Underlying, price, position.UnderlyingStopPrice)
+			status = "STOPPED_OUT"
+			closeReason = CloseReasonStopLoss
+		}
+	case premiumFresh && position.StopLossPrice > 0 && position.CurrentPrice <= position.StopLossPrice:
+		reason = fmt.Sprintf("Premium %.2f at or below stop %.2f", position.CurrentPrice, position.StopLossPrice)
+		status = "STOPPED_OUT"
+		closeReason = CloseReasonStopLoss
+	}
+
+	if reason == "" && premiumFresh && position.TakeProfitPrice > 0 && position.CurrentPrice >= position.TakeProfitPrice {
+		reason = fmt.Sprintf("Premium %.2f reached target %.2f", position.CurrentPrice, position.TakeProfitPrice)
+	}
+	if reason == "" {
+		return
+	}
+
+	optionsOrder := &interfaces.OptionsOrder{
+		Symbol:         position.Symbol,
+		Underlying:     position.Underlying,
+		Qty:            position.RemainingQty,
+		Side:           "sell",
+		PositionIntent: "sell_to_close",
+		Type:           "market",
+		TimeInForce:    "day",
+	}
+	result, err := pm.tradingService.PlaceOptionsOrder(ctx, optionsOrder)
+	if err != nil {
+		// Leave the position active so the next cycle retries
+		pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to place options exit order")
+		return
+	}
+
+	pm.saveOrder(position, OrderRoleExit, &interfaces.Order{
+		ID:          result.OrderID,
+		Symbol:      position.Symbol,
+		Qty:         position.RemainingQty,
+		Side:        "sell",
+		Type:        "market",
+		TimeInForce: "day",
+		Status:      "pending",
+		SubmittedAt: time.Now(),
+	})
+
+	now := time.Now()
+	position.Status = status
+	position.CloseReason = closeReason
+	position.ClosedAt = &now
+	position.UpdatedAt = now
+
+	pm.logPositionEvent(position, "OPTIONS_EXIT", reason, map[string]interface{}{
+		"exit_order_id":    result.OrderID,
+		"premium":          position.CurrentPrice,
+		"underlying_price": position.UnderlyingPrice,
+	})
+	pm.recordExit(ctx, position, nil)
+
+	if err := pm.savePositionToDB(position); err != nil {
+		pm.logger.WithError(err).Error("Failed to save position to database")
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id": position.ID,
+		"symbol":      position.Symbol,
+		"status":      status,
+	}).Info(reason)
+}
+
+// underlyingStopHit reports whether the underlying crossed the stop against
+// the option: below it for calls, above it for puts
+func underlyingStopHit(position *ManagedPosition, underlyingPrice float64) bool {
+	if isPutSymbol(position.Symbol) {
+		return underlyingPrice >= position.UnderlyingStopPrice
+	}
+	return underlyingPrice <= position.UnderlyingStopPrice
+}
+
+// isPutSymbol reports whether an OCC symbol is a put
+func isPutSymbol(symbol string) bool {
+	return len(symbol) > occSuffixLength && symbol[len(symbol)-occSuffixLength+6] == 'P'
+}
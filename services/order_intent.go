@@ -0,0 +1,66 @@
+package services
+
+import (
+	"math"
+	"prophet-trader/apperrors"
+	"strings"
+
+	"prophet-trader/interfaces"
+)
+
+// Order intents: whether an order opens new exposure or closes an existing position
+const (
+	OrderIntentOpen  = "open"
+	OrderIntentClose = "close"
+)
+
+// ResolveOrderIntent decides whether a buy/sell of qty shares of symbol opens
+// or closes exposure, given the account's current positions. A sell against a
+// long (or a buy against a short) closes; anything else opens. A requested
+// intent is checked against the positions rather than trusted, and a close
+// larger than the position is rejected since the broker won't cross zero in
+// one order. qty <= 0 skips the size check.
+func ResolveOrderIntent(positions []*interfaces.Position, symbol, side string, qty float64, requested string) (string, error) {
+	requested = strings.ToLower(strings.TrimSpace(requested))
+	if requested != "" && requested != OrderIntentOpen && requested != OrderIntentClose {
+		return "", apperrors.Validation("invalid intent %q: use open or close", requested)
+	}
+
+	// Signed holding: positive long, negative short
+	held := 0.0
+	for _, position := range positions {
+		if !strings.EqualFold(position.Symbol, symbol) {
+			continue
+		}
+		held = math.Abs(position.Qty)
+		if position.Side == "short" || position.Qty < 0 {
+			held = -held
+		}
+		break
+	}
+
+	closing := (side == "sell" && held > 0) || (side == "buy" && held < 0)
+
+	switch {
+	case requested == OrderIntentClose && !closing:
+		if side == "sell" {
+			return "", apperrors.Validation("no long %s position to sell to close", symbol)
+		}
+		return "", apperrors.Validation("no short %s position to buy to close", symbol)
+	case requested == OrderIntentOpen && closing:
+		if side == "sell" {
+			return "", apperrors.Validation("%s is held long: sell with intent close first, then sell short", symbol)
+		}
+		return "", apperrors.Validation("%s is held short: buy with intent close first, then buy to open", symbol)
+	}
+
+	if closing && qty > 0 && qty > math.Abs(held)+1e-9 {
+		return "", apperrors.Validation("%s qty %.4g exceeds the %.4g shares held: close the position, then open the other side separately",
+			side, qty, math.Abs(held))
+	}
+
+	if closing {
+		return OrderIntentClose, nil
+	}
+	return OrderIntentOpen, nil
+}
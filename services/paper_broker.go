@@ -0,0 +1,454 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"prophet-trader/apperrors"
+	"prophet-trader/database"
+	"prophet-trader/interfaces"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// simulatedTag marks positions, trades and activity of dry-run positions
+const simulatedTag = "simulated"
+
+// simulatedOrderPrefix starts the ID of every order the paper broker accepts
+const simulatedOrderPrefix = "sim_"
+
+// PaperBroker executes dry-run orders against live quotes. Orders are held in
+// memory and evaluated whenever they are looked up, which the position
+// monitor does every cycle:
+//
+//   - market orders fill at once at the ask (buys) or bid (sells)
+//   - limit orders fill at the quote once it reaches the limit
+//   - stop orders fill at the quote once it crosses the stop; stop-limits
+//     then rest as limits
+//   - day orders expire when the trading date changes
+//
+// Equities only fill during the regular session; crypto pairs fill at any
+// time. Account, asset and options data pass through to the live broker, and
+// options orders are not simulated.
+type PaperBroker struct {
+	live    interfaces.TradingService
+	data    interfaces.DataService
+	storage *database.LocalStorage // open orders are restored from here after a restart
+	logger  *logrus.Logger
+
+	mu        sync.Mutex
+	orders    map[string]*interfaces.Order
+	triggered map[string]bool // stop-limit orders whose stop has been hit
+	nextID    int64
+}
+
+// NewPaperBroker creates a paper broker on top of the live trading and data services
+func NewPaperBroker(live interfaces.TradingService, data interfaces.DataService, storage *database.LocalStorage) *PaperBroker {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+	})
+
+	return &PaperBroker{
+		live:      live,
+		data:      data,
+		storage:   storage,
+		logger:    logger,
+		orders:    make(map[string]*interfaces.Order),
+		triggered: make(map[string]bool),
+	}
+}
+
+// PlaceOrder accepts a simulated order, filling it right away if it is marketable
+func (pb *PaperBroker) PlaceOrder(ctx context.Context, order *interfaces.Order) (*interfaces.OrderResult, error) {
+	if order.Qty <= 0 {
+		return nil, apperrors.Validation("qty must be positive")
+	}
+	switch order.Type {
+	case "market":
+	case "limit":
+		if order.LimitPrice == nil {
+			return nil, apperrors.Validation("limit order requires a limit price")
+		}
+	case "stop":
+		if order.StopPrice == nil {
+			return nil, apperrors.Validation("stop order requires a stop price")
+		}
+	case "stop_limit":
+		if order.StopPrice == nil || order.LimitPrice == nil {
+			return nil, apperrors.Validation("stop_limit order requires stop and limit prices")
+		}
+	default:
+		return nil, apperrors.Validation("order type %q is not simulated", order.Type)
+	}
+
+	pb.mu.Lock()
+	pb.nextID++
+	simulated := *order
+	simulated.ID = fmt.Sprintf("%s%d_%d", simulatedOrderPrefix, time.Now().UnixNano(), pb.nextID)
+	simulated.Status = "new"
+	simulated.FilledQty = 0
+	simulated.FilledAvgPrice = nil
+	simulated.FilledAt = nil
+	simulated.SubmittedAt = time.Now()
+	pb.orders[simulated.ID] = &simulated
+	pb.mu.Unlock()
+
+	pb.evaluate(ctx, &simulated)
+	placed := pb.copyOrder(&simulated)
+
+	pb.logger.WithFields(logrus.Fields{
+		"order_id": placed.ID,
+		"symbol":   placed.Symbol,
+		"side":     placed.Side,
+		"type":     placed.Type,
+		"qty":      placed.Qty,
+		"status":   placed.Status,
+	}).Info("Simulated order placed")
+
+	return &interfaces.OrderResult{
+		OrderID: placed.ID,
+		Status:  placed.Status,
+		Message: "simulated order",
+	}, nil
+}
+
+// CancelOrder cancels an open simulated order
+func (pb *PaperBroker) CancelOrder(ctx context.Context, orderID string) error {
+	order, err := pb.lookup(orderID)
+	if err != nil {
+		return err
+	}
+
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	if terminalOrderStatuses[order.Status] {
+		return apperrors.Validation("order %s is already %s", orderID, order.Status)
+	}
+	now := time.Now()
+	order.Status = "canceled"
+	order.CanceledAt = &now
+	return nil
+}
+
+// GetOrder returns a simulated order after checking it against the latest quote
+func (pb *PaperBroker) GetOrder(ctx context.Context, orderID string) (*interfaces.Order, error) {
+	order, err := pb.lookup(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	pb.evaluate(ctx, order)
+	return pb.copyOrder(order), nil
+}
+
+// ListOrders lists simulated orders: "open", "closed", or anything else for all
+func (pb *PaperBroker) ListOrders(ctx context.Context, status string) ([]*interfaces.Order, error) {
+	pb.mu.Lock()
+	orders := make([]*interfaces.Order, 0, len(pb.orders))
+	for _, order := range pb.orders {
+		orders = append(orders, order)
+	}
+	pb.mu.Unlock()
+
+	result := make([]*interfaces.Order, 0, len(orders))
+	for _, order := range orders {
+		pb.evaluate(ctx, order)
+		copied := pb.copyOrder(order)
+		closed := terminalOrderStatuses[copied.Status]
+		if (status == "open" && closed) || (status == "closed" && !closed) {
+			continue
+		}
+		result = append(result, copied)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SubmittedAt.After(result[j].SubmittedAt)
+	})
+	return result, nil
+}
+
+// GetPositions nets the filled simulated orders per symbol. Shorts have a negative quantity.
+func (pb *PaperBroker) GetPositions(ctx context.Context) ([]*interfaces.Position, error) {
+	type book struct {
+		qty  float64
+		cost float64 // signed cost of the open quantity at average price
+	}
+
+	pb.mu.Lock()
+	fills := make([]*interfaces.Order, 0, len(pb.orders))
+	for _, order := range pb.orders {
+		if order.FilledQty > 0 && order.FilledAvgPrice != nil {
+			fills = append(fills, pb.copyOrderLocked(order))
+		}
+	}
+	pb.mu.Unlock()
+
+	sort.Slice(fills, func(i, j int) bool {
+		return fills[i].FilledAt != nil && fills[j].FilledAt != nil && fills[i].FilledAt.Before(*fills[j].FilledAt)
+	})
+
+	books := make(map[string]*book)
+	for _, fill := range fills {
+		b, ok := books[fill.Symbol]
+		if !ok {
+			b = &book{}
+			books[fill.Symbol] = b
+		}
+
+		qty := fill.FilledQty
+		if fill.Side == "sell" {
+			qty = -qty
+		}
+		price := *fill.FilledAvgPrice
+
+		switch {
+		case b.qty == 0 || (b.qty > 0) == (qty > 0):
+			// Opening or adding: average in
+			b.cost += qty * price
+			b.qty += qty
+		case math.Abs(qty) <= math.Abs(b.qty):
+			// Reducing: the average price is unchanged
+			b.cost -= b.cost * (math.Abs(qty) / math.Abs(b.qty))
+			b.qty += qty
+		default:
+			// Flipping: the remainder opens at the fill price
+			b.qty += qty
+			b.cost = b.qty * price
+		}
+	}
+
+	positions := make([]*interfaces.Position, 0, len(books))
+	for symbol, b := range books {
+		if math.Abs(b.qty) < 1e-9 {
+			continue
+		}
+
+		avg := b.cost / b.qty
+		current := avg
+		if quote, err := pb.data.GetLatestQuote(ctx, symbol); err == nil {
+			if mid := quoteMid(quote); mid > 0 {
+				current = mid
+			}
+		}
+
+		side := "long"
+		if b.qty < 0 {
+			side = "short"
+		}
+		unrealized := (current - avg) * b.qty
+		position := &interfaces.Position{
+			Symbol:        symbol,
+			Qty:           b.qty,
+			AvgEntryPrice: avg,
+			MarketValue:   current * b.qty,
+			CostBasis:     b.cost,
+			UnrealizedPL:  unrealized,
+			CurrentPrice:  current,
+			Side:          side,
+		}
+		if b.cost != 0 {
+			position.UnrealizedPLPC = unrealized / math.Abs(b.cost)
+		}
+		positions = append(positions, position)
+	}
+
+	return positions, nil
+}
+
+// GetAccount returns the live account; simulated fills don't touch it
+func (pb *PaperBroker) GetAccount(ctx context.Context) (*interfaces.Account, error) {
+	return pb.live.GetAccount(ctx)
+}
+
+// GetAssetInfo passes through to the live broker when it provides asset info
+func (pb *PaperBroker) GetAssetInfo(ctx context.Context, symbol string) (*interfaces.AssetInfo, error) {
+	provider, ok := pb.live.(interfaces.AssetInfoProvider)
+	if !ok {
+		return nil, fmt.Errorf("broker does not provide asset info")
+	}
+	return provider.GetAssetInfo(ctx, symbol)
+}
+
+// PlaceOptionsOrder is not simulated
+func (pb *PaperBroker) PlaceOptionsOrder(ctx context.Context, order *interfaces.OptionsOrder) (*interfaces.OrderResult, error) {
+	return nil, apperrors.Validation("options orders are not simulated in dry run")
+}
+
+// GetOptionsChain passes through to the live broker
+func (pb *PaperBroker) GetOptionsChain(ctx context.Context, underlying string, expiration time.Time) ([]*interfaces.OptionContract, error) {
+	return pb.live.GetOptionsChain(ctx, underlying, expiration)
+}
+
+// GetOptionExpirations passes through to the live broker
+func (pb *PaperBroker) GetOptionExpirations(ctx context.Context, underlying string) ([]time.Time, error) {
+	return pb.live.GetOptionExpirations(ctx, underlying)
+}
+
+// GetOptionsQuote passes through to the live broker
+func (pb *PaperBroker) GetOptionsQuote(ctx context.Context, symbol string) (*interfaces.OptionsQuote, error) {
+	return pb.live.GetOptionsQuote(ctx, symbol)
+}
+
+// GetOptionsPosition passes through to the live broker
+func (pb *PaperBroker) GetOptionsPosition(ctx context.Context, symbol string) (*interfaces.OptionsPosition, error) {
+	return pb.live.GetOptionsPosition(ctx, symbol)
+}
+
+// ListOptionsPositions passes through to the live broker
+func (pb *PaperBroker) ListOptionsPositions(ctx context.Context) ([]*interfaces.OptionsPosition, error) {
+	return pb.live.ListOptionsPositions(ctx)
+}
+
+// lookup finds a simulated order, restoring it from storage if it was placed
+// before a restart. Restored orders keep the status they were stored with.
+func (pb *PaperBroker) lookup(orderID string) (*interfaces.Order, error) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	if order, ok := pb.orders[orderID]; ok {
+		return order, nil
+	}
+	if !strings.HasPrefix(orderID, simulatedOrderPrefix) || pb.storage == nil {
+		return nil, apperrors.NotFound("simulated order not found: %s", orderID)
+	}
+
+	stored, err := pb.storage.GetOrder(orderID)
+	if err != nil {
+		return nil, apperrors.NotFound("simulated order not found: %s", orderID)
+	}
+	pb.orders[orderID] = stored
+	return stored, nil
+}
+
+// evaluate fills or expires an open order against the latest quote
+func (pb *PaperBroker) evaluate(ctx context.Context, order *interfaces.Order) {
+	pb.mu.Lock()
+	status, symbol, submitted, tif := order.Status, order.Symbol, order.SubmittedAt, order.TimeInForce
+	pb.mu.Unlock()
+
+	if terminalOrderStatuses[status] {
+		return
+	}
+
+	now := time.Now()
+	crypto := DetectAssetClass(symbol, nil) == AssetClassCrypto
+	if tif == "day" && !crypto && now.In(marketLocation).Format("2006-01-02") != submitted.In(marketLocation).Format("2006-01-02") {
+		pb.mu.Lock()
+		order.Status = "expired"
+		order.CanceledAt = &now
+		pb.mu.Unlock()
+		return
+	}
+	if !crypto && !IsMarketOpen(now) {
+		return
+	}
+
+	quote, err := pb.data.GetLatestQuote(ctx, symbol)
+	if err != nil {
+		pb.logger.WithError(err).WithField("order_id", order.ID).Debug("No quote to evaluate simulated order")
+		return
+	}
+	bid, ask := quote.BidPrice, quote.AskPrice
+	if bid <= 0 {
+		bid = ask
+	}
+	if ask <= 0 {
+		ask = bid
+	}
+	if bid <= 0 {
+		return
+	}
+
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	if terminalOrderStatuses[order.Status] {
+		return
+	}
+
+	buy := order.Side == "buy"
+	price := bid
+	if buy {
+		price = ask
+	}
+
+	fillable := false
+	switch {
+	case order.Type == "market":
+		fillable = true
+	case order.Type == "limit" && order.LimitPrice != nil:
+		fillable = limitReached(buy, price, *order.LimitPrice)
+	case order.Type == "stop" && order.StopPrice != nil:
+		fillable = stopReached(buy, price, *order.StopPrice)
+	case order.Type == "stop_limit" && order.StopPrice != nil && order.LimitPrice != nil:
+		if !pb.triggered[order.ID] && stopReached(buy, price, *order.StopPrice) {
+			pb.triggered[order.ID] = true
+		}
+		fillable = pb.triggered[order.ID] && limitReached(buy, price, *order.LimitPrice)
+	}
+	if !fillable {
+		return
+	}
+
+	order.Status = "filled"
+	order.FilledQty = order.Qty
+	order.FilledAvgPrice = &price
+	order.FilledAt = &now
+	delete(pb.triggered, order.ID)
+
+	pb.logger.WithFields(logrus.Fields{
+		"order_id": order.ID,
+		"symbol":   order.Symbol,
+		"side":     order.Side,
+		"type":     order.Type,
+		"price":    price,
+	}).Info("Simulated order filled")
+}
+
+// copyOrder returns a snapshot of an order safe to hand to callers
+func (pb *PaperBroker) copyOrder(order *interfaces.Order) *interfaces.Order {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	return pb.copyOrderLocked(order)
+}
+
+func (pb *PaperBroker) copyOrderLocked(order *interfaces.Order) *interfaces.Order {
+	copied := *order
+	if order.FilledAvgPrice != nil {
+		price := *order.FilledAvgPrice
+		copied.FilledAvgPrice = &price
+	}
+	return &copied
+}
+
+// limitReached reports whether a limit order at limit is marketable at price
+func limitReached(buy bool, price, limit float64) bool {
+	if buy {
+		return price <= limit
+	}
+	return price >= limit
+}
+
+// stopReached reports whether price has crossed a stop
+func stopReached(buy bool, price, stop float64) bool {
+	if buy {
+		return price >= stop
+	}
+	return price <= stop
+}
+
+// quoteMid is the midpoint of a quote, or whichever side is present
+func quoteMid(quote *interfaces.Quote) float64 {
+	switch {
+	case quote.BidPrice > 0 && quote.AskPrice > 0:
+		return (quote.BidPrice + quote.AskPrice) / 2
+	case quote.BidPrice > 0:
+		return quote.BidPrice
+	default:
+		return quote.AskPrice
+	}
+}
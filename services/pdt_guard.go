@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"prophet-trader/apperrors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PDT guard modes
+const (
+	PDTGuardOff    = "off"
+	PDTGuardWarn   = "warn"
+	PDTGuardReject = "reject"
+)
+
+// PDTGuardConfig configures the pattern-day-trader guard applied to day trades
+type PDTGuardConfig struct {
+	Mode         string  // "off", "warn" or "reject"
+	MaxDayTrades int     // day trades allowed in the rolling 5-day window below MinEquity
+	MinEquity    float64 // equity at or above which the PDT limit doesn't apply
+}
+
+// DefaultPDTGuardConfig mirrors FINRA's rule: 3 day trades per 5 days under $25k
+var DefaultPDTGuardConfig = PDTGuardConfig{
+	Mode:         PDTGuardReject,
+	MaxDayTrades: 3,
+	MinEquity:    25000,
+}
+
+// SetPDTGuard configures the pattern-day-trader guard
+func (pm *PositionManager) SetPDTGuard(config PDTGuardConfig) error {
+	switch config.Mode {
+	case PDTGuardOff, PDTGuardWarn, PDTGuardReject:
+	default:
+		return fmt.Errorf("invalid PDT guard mode %q: use off, warn or reject", config.Mode)
+	}
+	if config.MaxDayTrades < 0 || config.MinEquity < 0 {
+		return fmt.Errorf("PDT guard limits must be non-negative")
+	}
+
+	pm.pdtGuard = config
+	return nil
+}
+
+// checkPDT guards same-day round trips against the pattern-day-trader limit.
+// Accounts at or above the equity threshold are not limited.
+func (pm *PositionManager) checkPDT(ctx context.Context, req *PlaceManagedPositionRequest) error {
+	if pm.pdtGuard.Mode == PDTGuardOff || req.Strategy != "DAY_TRADE" {
+		return nil
+	}
+
+	account, err := pm.tradingService.GetAccount(ctx)
+	if err != nil {
+		pm.logger.WithError(err).Warn("Failed to get account for PDT check, allowing day trade")
+		return nil
+	}
+
+	if account.PortfolioValue >= pm.pdtGuard.MinEquity || account.DayTradeCount < pm.pdtGuard.MaxDayTrades {
+		return nil
+	}
+
+	message := fmt.Sprintf("day trade would exceed the pattern day trader limit: %d day trades in the last 5 days with equity $%.2f below $%.2f",
+		account.DayTradeCount, account.PortfolioValue, pm.pdtGuard.MinEquity)
+
+	if pm.pdtGuard.Mode == PDTGuardWarn {
+		pm.logger.WithFields(logrus.Fields{
+			"symbol":          req.Symbol,
+			"day_trade_count": account.DayTradeCount,
+			"equity":          account.PortfolioValue,
+		}).Warn("PDT guard: " + message)
+		return nil
+	}
+
+	return apperrors.Validation("%s", message)
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"prophet-trader/apperrors"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Batch placement modes
+const (
+	BatchModeAllOrNone  = "all_or_none" // place nothing unless every request is valid and the basket fits
+	BatchModeBestEffort = "best_effort" // place the valid requests that fit, in order
+)
+
+// maxBatchSize caps the number of positions in one batch
+const maxBatchSize = 50
+
+// BatchPositionResult is the outcome of one request in a batch
+type BatchPositionResult struct {
+	Index    int              `json:"index"`
+	Symbol   string           `json:"symbol"`
+	Placed   bool             `json:"placed"`
+	Position *ManagedPosition `json:"position,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// BatchPlacementResult summarizes a batch placement
+type BatchPlacementResult struct {
+	Mode            string                 `json:"mode"`
+	Placed          int                    `json:"placed"`
+	Failed          int                    `json:"failed"`
+	TotalAllocation float64                `json:"total_allocation"` // allocation of the placed positions
+	Results         []*BatchPositionResult `json:"results"`
+}
+
+// PlaceManagedPositions opens a basket of managed positions. Every request is
+// validated and the basket is checked against buying power and the open
+// position limit before anything is placed; account state is fetched once. In
+// all_or_none mode any failure rejects the whole batch with nothing placed;
+// in best_effort mode invalid requests are skipped and valid ones are placed
+// in order while they fit. A broker rejection mid-batch does not undo the
+// positions already placed.
+func (pm *PositionManager) PlaceManagedPositions(ctx context.Context, reqs []*PlaceManagedPositionRequest, mode string) (*BatchPlacementResult, error) {
+	if mode == "" {
+		mode = BatchModeAllOrNone
+	}
+	if mode != BatchModeAllOrNone && mode != BatchModeBestEffort {
+		return nil, apperrors.Validation("invalid batch mode %q: use %s or %s", mode, BatchModeAllOrNone, BatchModeBestEffort)
+	}
+	if len(reqs) == 0 {
+		return nil, apperrors.Validation("batch contains no positions")
+	}
+	if len(reqs) > maxBatchSize {
+		return nil, apperrors.Validation("batch of %d positions exceeds the maximum of %d", len(reqs), maxBatchSize)
+	}
+
+	result := &BatchPlacementResult{
+		Mode:    mode,
+		Results: make([]*BatchPositionResult, len(reqs)),
+	}
+
+	// Validate each request on its own
+	valid := make([]int, 0, len(reqs))
+	for i, req := range reqs {
+		result.Results[i] = &BatchPositionResult{Index: i, Symbol: req.Symbol}
+		if req.AllocationDollars <= 0 {
+			result.Results[i].Error = "invalid request: allocation_dollars must be positive"
+			continue
+		}
+		if err := pm.validateRequest(ctx, req); err != nil {
+			result.Results[i].Error = fmt.Sprintf("invalid request: %v", err)
+			continue
+		}
+		valid = append(valid, i)
+	}
+
+	// Check the basket against aggregate limits
+	account, err := pm.tradingService.GetAccount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	slots := -1 // unlimited
+	if pm.maxOpenPositions > 0 {
+		slots = pm.maxOpenPositions - pm.openPositionCount()
+		if slots < 0 {
+			slots = 0
+		}
+	}
+
+	buyingPower := pm.availableBuyingPower(account)
+	accepted := make([]int, 0, len(valid))
+	committed := 0.0
+	for _, i := range valid {
+		req := reqs[i]
+		switch {
+		case slots >= 0 && len(accepted) >= slots:
+			result.Results[i].Error = fmt.Sprintf("open position limit of %d reached", pm.maxOpenPositions)
+		case !req.DryRun && !pm.dryRun && committed+req.AllocationDollars > buyingPower:
+			result.Results[i].Error = fmt.Sprintf("allocation $%.2f exceeds remaining buying power $%.2f",
+				req.AllocationDollars, buyingPower-committed)
+		default:
+			accepted = append(accepted, i)
+			if !req.DryRun && !pm.dryRun {
+				committed += req.AllocationDollars
+			}
+		}
+	}
+
+	if mode == BatchModeAllOrNone && len(accepted) < len(reqs) {
+		for _, r := range result.Results {
+			if r.Error == "" {
+				r.Error = "not placed: another position in the batch was rejected"
+			}
+		}
+		result.Failed = len(reqs)
+		return result, fmt.Errorf("batch rejected: %d of %d positions failed validation or limits", len(reqs)-len(accepted), len(reqs))
+	}
+
+	for _, i := range accepted {
+		position, err := pm.PlaceManagedPosition(ctx, reqs[i])
+		if err != nil {
+			result.Results[i].Error = err.Error()
+			continue
+		}
+		result.Results[i].Placed = true
+		result.Results[i].Position = position
+		result.Placed++
+		result.TotalAllocation += position.AllocationDollars
+	}
+	result.Failed = len(reqs) - result.Placed
+	result.TotalAllocation = RoundMoney(result.TotalAllocation)
+
+	pm.logger.WithFields(logrus.Fields{
+		"mode":       mode,
+		"requested":  len(reqs),
+		"placed":     result.Placed,
+		"failed":     result.Failed,
+		"allocation": result.TotalAllocation,
+	}).Info("Batch placement complete")
+
+	return result, nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultEntryOrderTimeout is how long an entry order may stay unfilled before it is cancelled
+const DefaultEntryOrderTimeout = 24 * time.Hour
+
+// terminalPositionStatuses are position states that are no longer managed
+var terminalPositionStatuses = map[string]bool{
+	"CLOSED":      true,
+	"STOPPED_OUT": true,
+	"CANCELED":    true,
+	"EXPIRED":     true,
+	"FAILED":      true,
+}
+
+// isTerminalStatus reports whether a position is finished
+func isTerminalStatus(status string) bool {
+	return terminalPositionStatuses[status]
+}
+
+// SetEntryOrderTimeout sets how long entry orders may stay unfilled before they
+// are cancelled and the position marked EXPIRED. Zero disables the timeout.
+func (pm *PositionManager) SetEntryOrderTimeout(timeout time.Duration) {
+	pm.entryTimeout = timeout
+}
+
+// expireStaleEntry cancels a pending entry order that has outlived the entry
+// timeout. Any shares filled before the cancel are kept and managed normally.
+func (pm *PositionManager) expireStaleEntry(ctx context.Context, position *ManagedPosition) {
+	if pm.entryTimeout <= 0 || time.Since(position.CreatedAt) < pm.entryTimeout {
+		return
+	}
+
+	if err := pm.broker(position).CancelOrder(ctx, position.EntryOrderID); err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Warn("Failed to cancel stale entry order")
+		return
+	}
+
+	// The order may have partially filled before the cancel went through
+	order, err := pm.broker(position).GetOrder(ctx, position.EntryOrderID)
+	if err == nil && order.FilledQty > 0 && order.FilledAvgPrice != nil {
+		pm.logger.WithFields(logrus.Fields{
+			"position_id": position.ID,
+			"filled_qty":  order.FilledQty,
+		}).Info("Stale entry order cancelled after partial fill - managing filled shares")
+		pm.activateEntry(ctx, position, order)
+		return
+	}
+
+	pm.markOrderCanceled(position.EntryOrderID)
+	position.CloseReason = CloseReasonExpired
+	pm.endPendingPosition(position, "EXPIRED",
+		fmt.Sprintf("Entry order unfilled after %s - cancelled", pm.entryTimeout))
+}
+
+// endPendingPosition closes out a position whose entry never filled
+func (pm *PositionManager) endPendingPosition(position *ManagedPosition, status, reason string) {
+	now := time.Now()
+	position.Status = status
+	position.ClosedAt = &now
+	position.RemainingQty = 0
+	position.ExitReasoning = reason
+	position.UpdatedAt = now
+
+	if err := pm.savePositionToDB(position); err != nil {
+		pm.logger.WithError(err).Error("Failed to save position to database")
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id": position.ID,
+		"symbol":      position.Symbol,
+		"status":      status,
+	}).Info(reason)
+	pm.logPositionEvent(position, "ENTRY_"+status, reason, map[string]interface{}{
+		"entry_order_id": position.EntryOrderID,
+		"age_hours":      now.Sub(position.CreatedAt).Hours(),
+	})
+}
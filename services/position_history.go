@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"prophet-trader/apperrors"
+	"prophet-trader/interfaces"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Order roles within a managed position's lifecycle
+const (
+	OrderRoleEntry       = "entry"
+	OrderRoleStopLoss    = "stop_loss"
+	OrderRoleTakeProfit  = "take_profit"
+	OrderRolePartialExit = "partial_exit"
+	OrderRoleExit        = "exit"
+	OrderRoleReduce      = "reduce"
+)
+
+// terminalOrderStatuses are broker order states that will not change again
+var terminalOrderStatuses = map[string]bool{
+	"filled":   true,
+	"canceled": true,
+	"expired":  true,
+	"rejected": true,
+	"replaced": true,
+}
+
+// PositionHistory is the complete story of one managed position
+type PositionHistory struct {
+	Position       *ManagedPosition `json:"position"`
+	Orders         []*PositionOrder `json:"orders"`
+	Timeline       []PositionEvent  `json:"timeline"`
+	RealizedPL     float64          `json:"realized_pl"`
+	RealizedPLPC   float64          `json:"realized_pl_percent"`
+	ExitedQuantity float64          `json:"exited_quantity"`
+}
+
+// PositionOrder is an order placed on behalf of a managed position
+type PositionOrder struct {
+	OrderID        string     `json:"order_id"`
+	Role           string     `json:"role"`
+	Side           string     `json:"side"`
+	Type           string     `json:"type"`
+	Qty            float64    `json:"qty"`
+	LimitPrice     *float64   `json:"limit_price,omitempty"`
+	StopPrice      *float64   `json:"stop_price,omitempty"`
+	Status         string     `json:"status"`
+	FilledQty      float64    `json:"filled_qty"`
+	FilledAvgPrice *float64   `json:"filled_avg_price,omitempty"`
+	SubmittedAt    time.Time  `json:"submitted_at"`
+	FilledAt       *time.Time `json:"filled_at,omitempty"`
+	CanceledAt     *time.Time `json:"canceled_at,omitempty"`
+}
+
+// PositionEvent is a single entry in a position's timeline
+type PositionEvent struct {
+	Timestamp   time.Time              `json:"timestamp"`
+	Type        string                 `json:"type"` // CREATED, ORDER_SUBMITTED, ORDER_FILLED, ORDER_CANCELED, NOTE, ACTIVITY, CLOSED
+	Description string                 `json:"description"`
+	OrderID     string                 `json:"order_id,omitempty"`
+	Role        string                 `json:"role,omitempty"`
+	Price       *float64               `json:"price,omitempty"`
+	Quantity    float64                `json:"quantity,omitempty"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+}
+
+// saveOrder links an order to a position and persists it
+func (pm *PositionManager) saveOrder(position *ManagedPosition, role string, order *interfaces.Order) {
+	order.PositionID = position.ID
+	order.PositionRole = role
+	if order.Intent == "" {
+		// Only the entry adds exposure; every other managed order reduces it
+		order.Intent = OrderIntentClose
+		if role == "entry" {
+			order.Intent = OrderIntentOpen
+		}
+	}
+
+	if err := pm.storageService.SaveOrder(order); err != nil {
+		pm.logger.WithError(err).WithFields(logrus.Fields{
+			"position_id": position.ID,
+			"order_id":    order.ID,
+		}).Warn("Failed to save position order")
+	}
+}
+
+// markOrderCanceled records a cancellation for a stored order
+func (pm *PositionManager) markOrderCanceled(orderID string) {
+	order, err := pm.storageService.GetOrder(orderID)
+	if err != nil {
+		return
+	}
+
+	order.Status = "canceled"
+	now := time.Now()
+	order.CanceledAt = &now
+
+	if err := pm.storageService.SaveOrder(order); err != nil {
+		pm.logger.WithError(err).WithField("order_id", orderID).Warn("Failed to update canceled order")
+	}
+}
+
+// logPositionEvent records a lifecycle event in the activity log, tagged with the position ID
+func (pm *PositionManager) logPositionEvent(position *ManagedPosition, action, reasoning string, details map[string]interface{}) {
+	if pm.activityLogger == nil {
+		return
+	}
+
+	if details == nil {
+		details = make(map[string]interface{})
+	}
+	details["position_id"] = position.ID
+
+	if err := pm.activityLogger.LogActivity("POSITION", action, position.Symbol, reasoning, details); err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Debug("Failed to log position event")
+	}
+}
+
+// GetManagedPositionHistory assembles the full lifecycle of a managed position
+// from the position itself, its linked orders and tagged activity-log entries
+func (pm *PositionManager) GetManagedPositionHistory(ctx context.Context, positionID string) (*PositionHistory, error) {
+	pm.mu.RLock()
+	position, exists := pm.positions[positionID]
+	pm.mu.RUnlock()
+
+	if !exists {
+		// Closed positions are not kept in memory
+		dbPos, err := pm.storageService.GetManagedPosition(positionID)
+		if err != nil {
+			return nil, apperrors.NotFound("position not found: %s", positionID)
+		}
+		position = pm.dbToManagedPosition(dbPos)
+	}
+
+	orders, err := pm.collectPositionOrders(ctx, position)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &PositionHistory{
+		Position: position.rounded(),
+		Orders:   make([]*PositionOrder, 0, len(orders)),
+		Timeline: make([]PositionEvent, 0),
+	}
+
+	history.Timeline = append(history.Timeline, PositionEvent{
+		Timestamp:   position.CreatedAt,
+		Type:        "CREATED",
+		Description: fmt.Sprintf("Managed %s position created for %s", position.Side, position.Symbol),
+		Quantity:    position.Quantity,
+		Details: map[string]interface{}{
+			"allocation_dollars": position.AllocationDollars,
+			"stop_loss_price":    position.StopLossPrice,
+			"take_profit_price":  position.TakeProfitPrice,
+			"strategy":           position.Strategy,
+		},
+	})
+
+	direction := 1.0
+	if position.Side == "sell" {
+		direction = -1.0
+	}
+
+	for _, order := range orders {
+		history.Orders = append(history.Orders, &PositionOrder{
+			OrderID:        order.ID,
+			Role:           order.PositionRole,
+			Side:           order.Side,
+			Type:           order.Type,
+			Qty:            order.Qty,
+			LimitPrice:     order.LimitPrice,
+			StopPrice:      order.StopPrice,
+			Status:         order.Status,
+			FilledQty:      order.FilledQty,
+			FilledAvgPrice: order.FilledAvgPrice,
+			SubmittedAt:    order.SubmittedAt,
+			FilledAt:       order.FilledAt,
+			CanceledAt:     order.CanceledAt,
+		})
+
+		price := order.LimitPrice
+		if order.StopPrice != nil {
+			price = order.StopPrice
+		}
+
+		history.Timeline = append(history.Timeline, PositionEvent{
+			Timestamp:   order.SubmittedAt,
+			Type:        "ORDER_SUBMITTED",
+			Description: fmt.Sprintf("%s %s order submitted", order.PositionRole, order.Type),
+			OrderID:     order.ID,
+			Role:        order.PositionRole,
+			Price:       price,
+			Quantity:    order.Qty,
+		})
+
+		if order.FilledAt != nil && order.FilledQty > 0 {
+			history.Timeline = append(history.Timeline, PositionEvent{
+				Timestamp:   *order.FilledAt,
+				Type:        "ORDER_FILLED",
+				Description: fmt.Sprintf("%s order filled", order.PositionRole),
+				OrderID:     order.ID,
+				Role:        order.PositionRole,
+				Price:       order.FilledAvgPrice,
+				Quantity:    order.FilledQty,
+			})
+
+			// Realized P&L from exit fills
+			if order.PositionRole != OrderRoleEntry && order.FilledAvgPrice != nil {
+				history.RealizedPL += direction * (*order.FilledAvgPrice - position.EntryPrice) * order.FilledQty
+				history.ExitedQuantity += order.FilledQty
+			}
+		}
+
+		if order.CanceledAt != nil {
+			history.Timeline = append(history.Timeline, PositionEvent{
+				Timestamp:   *order.CanceledAt,
+				Type:        "ORDER_CANCELED",
+				Description: fmt.Sprintf("%s order canceled", order.PositionRole),
+				OrderID:     order.ID,
+				Role:        order.PositionRole,
+			})
+		}
+	}
+
+	history.RealizedPL = RoundMoney(history.RealizedPL)
+	if history.ExitedQuantity > 0 && position.EntryPrice > 0 {
+		history.RealizedPLPC = RoundPercent(history.RealizedPL / (position.EntryPrice * history.ExitedQuantity) * 100)
+	}
+
+	for _, note := range position.Journal {
+		history.Timeline = append(history.Timeline, PositionEvent{
+			Timestamp:   note.Timestamp,
+			Type:        "NOTE",
+			Description: note.Note,
+		})
+	}
+
+	history.Timeline = append(history.Timeline, pm.positionActivities(position)...)
+
+	if position.ClosedAt != nil {
+		history.Timeline = append(history.Timeline, PositionEvent{
+			Timestamp:   *position.ClosedAt,
+			Type:        "CLOSED",
+			Description: fmt.Sprintf("Position closed with status %s", position.Status),
+			Details: map[string]interface{}{
+				"realized_pl":    history.RealizedPL,
+				"exit_reasoning": position.ExitReasoning,
+				"close_reason":   position.CloseReason,
+			},
+		})
+	}
+
+	sort.SliceStable(history.Timeline, func(i, j int) bool {
+		return history.Timeline[i].Timestamp.Before(history.Timeline[j].Timestamp)
+	})
+
+	return history, nil
+}
+
+// collectPositionOrders loads a position's linked orders, adds any known order IDs
+// that were never linked, and refreshes orders that may still change at the broker
+func (pm *PositionManager) collectPositionOrders(ctx context.Context, position *ManagedPosition) ([]*interfaces.Order, error) {
+	orders, err := pm.storageService.GetOrdersForPosition(position.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(orders))
+	for _, order := range orders {
+		known[order.ID] = true
+	}
+
+	// Positions created before orders were linked only know their current order IDs
+	roles := map[string]string{
+		position.EntryOrderID:      OrderRoleEntry,
+		position.StopLossOrderID:   OrderRoleStopLoss,
+		position.TakeProfitOrderID: OrderRoleTakeProfit,
+	}
+	for _, orderID := range position.PartialExitOrders {
+		roles[orderID] = OrderRolePartialExit
+	}
+	delete(roles, "")
+
+	for orderID, role := range roles {
+		if known[orderID] {
+			continue
+		}
+		order, err := pm.broker(position).GetOrder(ctx, orderID)
+		if err != nil {
+			continue
+		}
+		pm.saveOrder(position, role, order)
+		orders = append(orders, order)
+	}
+
+	for i, order := range orders {
+		if terminalOrderStatuses[order.Status] {
+			continue
+		}
+		refreshed, err := pm.broker(position).GetOrder(ctx, order.ID)
+		if err != nil {
+			continue
+		}
+		pm.saveOrder(position, order.PositionRole, refreshed)
+		orders[i] = refreshed
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].SubmittedAt.Before(orders[j].SubmittedAt)
+	})
+
+	return orders, nil
+}
+
+// positionActivities returns activity-log entries tagged with the position
+func (pm *PositionManager) positionActivities(position *ManagedPosition) []PositionEvent {
+	events := make([]PositionEvent, 0)
+	if pm.activityLogger == nil {
+		return events
+	}
+
+	end := time.Now()
+	if position.ClosedAt != nil {
+		end = *position.ClosedAt
+	}
+
+	for day := position.CreatedAt; !day.After(end.AddDate(0, 0, 1)); day = day.AddDate(0, 0, 1) {
+		log, err := pm.activityLogger.GetLogForDate(day.Format("2006-01-02"))
+		if err != nil {
+			continue
+		}
+
+		for _, activity := range log.Activities {
+			if id, ok := activity.Details["position_id"].(string); !ok || id != position.ID {
+				continue
+			}
+			description := activity.Action
+			if activity.Reasoning != "" {
+				description = fmt.Sprintf("%s: %s", activity.Action, activity.Reasoning)
+			}
+			events = append(events, PositionEvent{
+				Timestamp:   activity.Timestamp,
+				Type:        "ACTIVITY",
+				Description: description,
+				Details:     activity.Details,
+			})
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"prophet-trader/apperrors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ImportManagedPositionRequest wraps an existing broker position in managed
+// risk orders. Percent levels are measured from the broker's average entry.
+type ImportManagedPositionRequest struct {
+	Symbol   string `json:"symbol" binding:"required"`
+	Strategy string `json:"strategy"` // "SWING_TRADE", "LONG_TERM", "DAY_TRADE"
+
+	// Risk management (one of these required)
+	StopLossPrice       *float64 `json:"stop_loss_price,omitempty"`
+	StopLossPercent     *float64 `json:"stop_loss_percent,omitempty"`
+	StopLossLimitOffset *float64 `json:"stop_loss_limit_offset,omitempty"`
+	TrailingStop        bool     `json:"trailing_stop"`
+	TrailingPercent     float64  `json:"trailing_percent,omitempty"`
+
+	// Profit targets (one of these required)
+	TakeProfitPrice   *float64 `json:"take_profit_price,omitempty"`
+	TakeProfitPercent *float64 `json:"take_profit_percent,omitempty"`
+
+	StopTimeInForce   string `json:"stop_time_in_force,omitempty"`   // "day", "gtc"
+	TargetTimeInForce string `json:"target_time_in_force,omitempty"` // "day", "gtc"
+
+	Notes string   `json:"notes,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// ImportExistingPosition creates an ACTIVE managed position for shares the
+// broker already holds and places the requested stop and target on them
+func (pm *PositionManager) ImportExistingPosition(ctx context.Context, req *ImportManagedPositionRequest) (*ManagedPosition, error) {
+	symbol := strings.ToUpper(req.Symbol)
+
+	if req.StopLossPrice == nil && req.StopLossPercent == nil {
+		return nil, apperrors.Validation("invalid request: one of stop_loss_price or stop_loss_percent required")
+	}
+	if req.TakeProfitPrice == nil && req.TakeProfitPercent == nil {
+		return nil, apperrors.Validation("invalid request: one of take_profit_price or take_profit_percent required")
+	}
+	assetClass := pm.assetClass(ctx, symbol)
+	riskTIFs := rulesFor(assetClass).riskTimeInForces
+	if req.StopTimeInForce != "" && !riskTIFs[req.StopTimeInForce] {
+		return nil, apperrors.Validation("invalid request: invalid stop_time_in_force %q: allowed %s", req.StopTimeInForce, timeInForceList(riskTIFs))
+	}
+	if req.TargetTimeInForce != "" && !riskTIFs[req.TargetTimeInForce] {
+		return nil, apperrors.Validation("invalid request: invalid target_time_in_force %q: allowed %s", req.TargetTimeInForce, timeInForceList(riskTIFs))
+	}
+	if req.StopLossLimitOffset != nil && (*req.StopLossLimitOffset <= 0 || *req.StopLossLimitOffset >= 100) {
+		return nil, apperrors.Validation("invalid request: stop_loss_limit_offset must be between 0 and 100")
+	}
+	if req.TrailingStop && req.TrailingPercent <= 0 {
+		return nil, apperrors.Validation("invalid request: trailing_percent required for trailing_stop")
+	}
+
+	// Two managed positions on the same shares would double the exit orders
+	pm.mu.RLock()
+	for _, existing := range pm.positions {
+		if existing.Symbol == symbol && !isTerminalStatus(existing.Status) {
+			pm.mu.RUnlock()
+			return nil, apperrors.Validation("%s is already managed by position %s", symbol, existing.ID)
+		}
+	}
+	pm.mu.RUnlock()
+
+	brokerPositions, err := pm.tradingService.GetPositions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get broker positions: %w", err)
+	}
+
+	var position *ManagedPosition
+	for _, bp := range brokerPositions {
+		if bp.Symbol != symbol || bp.Qty == 0 {
+			continue
+		}
+
+		side := "buy"
+		if bp.Side == "short" || bp.Qty < 0 {
+			side = "sell"
+		}
+		position = &ManagedPosition{
+			Symbol:            symbol,
+			Side:              side,
+			Quantity:          math.Abs(bp.Qty),
+			RemainingQty:      math.Abs(bp.Qty),
+			EntryPrice:        bp.AvgEntryPrice,
+			AllocationDollars: RoundMoney(math.Abs(bp.CostBasis)),
+			CurrentPrice:      bp.CurrentPrice,
+		}
+		break
+	}
+	if position == nil {
+		return nil, apperrors.NotFound("no broker position position in %s", symbol)
+	}
+
+	if price, err := pm.getCurrentPrice(ctx, symbol); err == nil {
+		position.CurrentPrice = price
+	}
+
+	stopLossPrice := pm.calculateStopLoss(position.EntryPrice, req.StopLossPrice, req.StopLossPercent, position.Side)
+	takeProfitPrice := pm.calculateTakeProfit(position.EntryPrice, req.TakeProfitPrice, req.TakeProfitPercent, position.Side)
+
+	// Levels computed from an old entry may already be through the market
+	if position.CurrentPrice > 0 {
+		if position.Side == "buy" && (stopLossPrice >= position.CurrentPrice || takeProfitPrice <= position.CurrentPrice) {
+			return nil, apperrors.Validation("stop %.2f must be below and target %.2f above the current price %.2f", stopLossPrice, takeProfitPrice, position.CurrentPrice)
+		}
+		if position.Side == "sell" && (stopLossPrice <= position.CurrentPrice || takeProfitPrice >= position.CurrentPrice) {
+			return nil, apperrors.Validation("stop %.2f must be above and target %.2f below the current price %.2f", stopLossPrice, takeProfitPrice, position.CurrentPrice)
+		}
+	}
+
+	now := time.Now()
+	position.ID = pm.generatePositionID()
+	position.Strategy = req.Strategy
+	position.EntryOrderType = "import"
+	position.StopLossPrice = stopLossPrice
+	position.StopLossPercent = math.Abs((stopLossPrice - position.EntryPrice) / position.EntryPrice * 100)
+	position.TrailingStop = req.TrailingStop
+	position.TrailingPercent = req.TrailingPercent
+	position.TakeProfitPrice = takeProfitPrice
+	position.TakeProfitPercent = math.Abs((takeProfitPrice - position.EntryPrice) / position.EntryPrice * 100)
+	position.Status = "ACTIVE"
+	position.CreatedAt = now
+	position.UpdatedAt = now
+	position.Notes = req.Notes
+	position.Tags = req.Tags
+
+	position.AssetClass = assetClass
+	position.EntryTimeInForce, position.StopTimeInForce, position.TargetTimeInForce = resolveTimeInForce(&PlaceManagedPositionRequest{
+		Strategy:          req.Strategy,
+		StopTimeInForce:   req.StopTimeInForce,
+		TargetTimeInForce: req.TargetTimeInForce,
+	}, assetClass)
+
+	if req.StopLossLimitOffset != nil {
+		position.StopLossLimitOffset = *req.StopLossLimitOffset
+	}
+	if req.Notes != "" {
+		position.Journal = []PositionNote{{Timestamp: now, Note: req.Notes}}
+	}
+
+	pm.placeRiskOrders(ctx, position)
+	if position.StopLossOrderID == "" && position.TakeProfitOrderID == "" {
+		return nil, apperrors.Broker("failed to place stop or target orders for %s", symbol)
+	}
+
+	pm.mu.Lock()
+	pm.positions[position.ID] = position
+	pm.mu.Unlock()
+
+	pm.logPositionOpened(position)
+	pm.logPositionEvent(position, "IMPORTED", "Existing broker position imported", map[string]interface{}{
+		"quantity":    position.Quantity,
+		"entry_price": position.EntryPrice,
+		"stop_loss":   stopLossPrice,
+		"take_profit": takeProfitPrice,
+	})
+
+	if err := pm.savePositionToDB(position); err != nil {
+		pm.logger.WithError(err).Error("Failed to save position to database")
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id": position.ID,
+		"symbol":      symbol,
+		"quantity":    position.Quantity,
+		"entry_price": position.EntryPrice,
+		"stop_loss":   stopLossPrice,
+		"take_profit": takeProfitPrice,
+	}).Info("Broker position imported")
+
+	return position, nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"fmt"
+	"prophet-trader/apperrors"
+)
+
+// SetMaxOpenPositions caps how many managed positions may be open (pending,
+// active or partial) at once. Zero means no cap.
+func (pm *PositionManager) SetMaxOpenPositions(max int) error {
+	if max < 0 {
+		return fmt.Errorf("max open positions must not be negative")
+	}
+	pm.maxOpenPositions = max
+	return nil
+}
+
+// openPositionCount counts managed positions that are not yet closed
+func (pm *PositionManager) openPositionCount() int {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	count := 0
+	for _, position := range pm.positions {
+		if !isTerminalStatus(position.Status) {
+			count++
+		}
+	}
+	return count
+}
+
+// checkOpenPositionLimit rejects opening `adding` positions when that would pass the cap
+func (pm *PositionManager) checkOpenPositionLimit(adding int) error {
+	if pm.maxOpenPositions <= 0 {
+		return nil
+	}
+
+	open := pm.openPositionCount()
+	if open+adding > pm.maxOpenPositions {
+		return apperrors.Validation("%d open positions plus %d new would exceed the limit of %d", open, adding, pm.maxOpenPositions)
+	}
+	return nil
+}
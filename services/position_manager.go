@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"prophet-trader/apperrors"
 	"prophet-trader/database"
 	"prophet-trader/interfaces"
 	"prophet-trader/models"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -32,31 +35,74 @@ type ManagedPosition struct {
 	StopLossPrice     float64                `json:"stop_loss_price"`
 	StopLossPercent   float64                `json:"stop_loss_percent"`
 	StopLossOrderID   string                 `json:"stop_loss_order_id,omitempty"`
+	StopLossLimitOffset float64              `json:"stop_loss_limit_offset,omitempty"` // >0 = stop_limit orders
 	TrailingStop      bool                   `json:"trailing_stop"`
 	TrailingPercent   float64                `json:"trailing_percent,omitempty"`
+	TrailingReference float64                `json:"trailing_reference,omitempty"` // high-water mark (low for shorts) the trailing stop follows
+	SARStop           bool                   `json:"sar_stop"`
+	SARStep           float64                `json:"sar_step,omitempty"`
+	SARMax            float64                `json:"sar_max,omitempty"`
 
 	// Profit targets
 	TakeProfitPrice   float64                `json:"take_profit_price"`
 	TakeProfitPercent float64                `json:"take_profit_percent"`
 	TakeProfitOrderID string                 `json:"take_profit_order_id,omitempty"`
+	TakeProfitType    string                 `json:"take_profit_order_type,omitempty"` // "limit" ("" on older positions), "stop" or "stop_limit"
+	TakeProfitLimitOffset float64            `json:"take_profit_limit_offset,omitempty"` // % beyond the target for stop_limit
 
 	// Partial exit strategy
 	PartialExit       *PartialExitConfig     `json:"partial_exit,omitempty"`
 	PartialExitOrders []string               `json:"partial_exit_orders,omitempty"`
 
+	// Reduce order awaiting its fill and the shares it holds; the risk
+	// orders cover only the rest until it fills
+	ReduceOrderID     string                 `json:"reduce_order_id,omitempty"`
+	ReduceQty         float64                `json:"reduce_qty,omitempty"`
+
+	// Time in force per order type
+	EntryTimeInForce  string                 `json:"entry_time_in_force"`
+	StopTimeInForce   string                 `json:"stop_time_in_force"`
+	TargetTimeInForce string                 `json:"target_time_in_force"`
+
+	// Options positions: stops trigger on the premium or the underlying's
+	// price and are enforced by the monitor, not resting broker orders
+	AssetClass          string               `json:"asset_class,omitempty"` // "equity", "crypto" or "option" ("" on older equity positions)
+	Underlying          string               `json:"underlying,omitempty"`
+	UnderlyingPrice     float64              `json:"underlying_price,omitempty"`
+	StopBasis           string               `json:"stop_basis,omitempty"` // "premium" or "underlying"
+	UnderlyingStopPrice float64              `json:"underlying_stop_price,omitempty"`
+	ApproxPremiumStop   float64              `json:"approx_premium_stop,omitempty"` // underlying stop translated by delta, for display
+	EntryDelta          float64              `json:"entry_delta,omitempty"`
+	Delta               float64              `json:"delta,omitempty"`
+
 	// Status tracking
-	Status            string                 `json:"status"` // "PENDING", "ACTIVE", "PARTIAL", "CLOSED", "STOPPED_OUT", "FAILED"
+	Status            string                 `json:"status"` // "PENDING", "ACTIVE", "PARTIAL", "CLOSED", "STOPPED_OUT", "CANCELED", "EXPIRED", "FAILED"
 	CurrentPrice      float64                `json:"current_price"`
 	UnrealizedPL      float64                `json:"unrealized_pl"`
 	UnrealizedPLPC    float64                `json:"unrealized_pl_percent"`
 	RemainingQty      float64                `json:"remaining_qty"`
 
+	// Worst and best unrealized P&L percent seen over the position's life
+	MaxAdverseExcursion  float64             `json:"max_adverse_excursion_percent"`
+	MaxFavorableExcursion float64            `json:"max_favorable_excursion_percent"`
+
 	// Metadata
 	CreatedAt         time.Time              `json:"created_at"`
 	UpdatedAt         time.Time              `json:"updated_at"`
 	ClosedAt          *time.Time             `json:"closed_at,omitempty"`
 	Notes             string                 `json:"notes,omitempty"`
+	Journal           []PositionNote         `json:"journal,omitempty"`
 	Tags              []string               `json:"tags,omitempty"`
+	Simulated         bool                   `json:"simulated,omitempty"` // dry run: orders are filled by the paper broker
+	EntryReasoning    string                 `json:"entry_reasoning,omitempty"`
+	ExitReasoning     string                 `json:"exit_reasoning,omitempty"`
+	CloseReason       string                 `json:"close_reason,omitempty"` // TAKE_PROFIT, STOP_LOSS, TRAILING_STOP, TIME_EXIT, MANUAL, REVERSED, EXPIRED, DAILY_LOSS_LIMIT
+
+	// Set once risk orders have been placed so they're never placed twice
+	riskOrdersPlaced  bool
+
+	// Last time the SAR stop was recalculated
+	sarCheckedAt      time.Time
 }
 
 // PartialExitConfig defines partial profit taking strategy
@@ -64,7 +110,9 @@ type PartialExitConfig struct {
 	Enabled       bool    `json:"enabled"`
 	Percent       float64 `json:"percent"`        // % of position to exit
 	TargetPercent float64 `json:"target_percent"` // % gain to trigger partial exit
-	TargetPrice   float64 `json:"target_price"`   // Calculated target price
+	TargetPrice   float64 `json:"target_price"`   // Absolute exit price (e.g. a resistance level); calculated from target_percent when omitted
+	BreakevenStop bool    `json:"breakeven_stop"` // move the stop to the entry price once the partial exit fills
+	TrailingReset string  `json:"trailing_reset,omitempty"` // "peak" (default), "current" or "breakeven": what a trailing stop follows after the fill
 }
 
 // PlaceManagedPositionRequest represents request to open a managed position
@@ -81,19 +129,97 @@ type PlaceManagedPositionRequest struct {
 	// Risk management (one of these required)
 	StopLossPrice     *float64            `json:"stop_loss_price,omitempty"`
 	StopLossPercent   *float64            `json:"stop_loss_percent,omitempty"`
+	StopATRMultiple   *float64            `json:"stop_atr_multiple,omitempty"` // e.g. 1.5 = stop 1.5 ATR from entry
+	StopLossLimitOffset *float64          `json:"stop_loss_limit_offset,omitempty"` // % beyond the stop for a stop_limit order (omit for a plain stop)
 	TrailingStop      bool                `json:"trailing_stop"`
 	TrailingPercent   float64             `json:"trailing_percent,omitempty"`
+	SARStop           bool                `json:"sar_stop"` // Trail the stop at the daily Parabolic SAR
+	SARStep           float64             `json:"sar_step,omitempty"` // Acceleration step (default 0.02)
+	SARMax            float64             `json:"sar_max,omitempty"`  // Acceleration ceiling (default 0.2)
 
 	// Profit targets (one of these required)
 	TakeProfitPrice   *float64            `json:"take_profit_price,omitempty"`
 	TakeProfitPercent *float64            `json:"take_profit_percent,omitempty"`
+	TargetATRMultiple *float64            `json:"target_atr_multiple,omitempty"` // e.g. 3 = target 3 ATR from entry
+	TakeProfitOrderType string            `json:"take_profit_order_type,omitempty"` // "limit" (default), "stop" (market-on-touch) or "stop_limit"
+	TakeProfitLimitOffset *float64        `json:"take_profit_limit_offset,omitempty"` // % beyond the target for a stop_limit target (required for it)
 
 	// Partial exit (optional)
 	PartialExit       *PartialExitConfig  `json:"partial_exit,omitempty"`
 
+	// Time in force per order type (optional). Defaults: "gtc" for all,
+	// or "day" for DAY_TRADE so risk orders don't carry overnight.
+	EntryTimeInForce  string              `json:"entry_time_in_force,omitempty"` // "day", "gtc", "opg", "cls", "ioc", "fok"
+	StopTimeInForce   string              `json:"stop_time_in_force,omitempty"`  // "day", "gtc"
+	TargetTimeInForce string              `json:"target_time_in_force,omitempty"` // "day", "gtc" (also used for partial exits)
+
 	// Metadata
 	Notes             string              `json:"notes,omitempty"`
 	Tags              []string            `json:"tags,omitempty"`
+
+	// Simulate the position against live quotes instead of sending orders to the broker
+	DryRun            bool                `json:"dry_run,omitempty"`
+
+	// Current price used for quantity, stop and target when there is no
+	// entry_price: "bid", "ask", "mid" or "last" (default from config, mid)
+	PriceReference    string              `json:"price_reference,omitempty"`
+}
+
+// PositionNote is a timestamped trade journal entry
+type PositionNote struct {
+	Timestamp time.Time `json:"timestamp"`
+	Note      string    `json:"note"`
+}
+
+// Allowed time-in-force values for entry and risk (stop/target) orders
+var (
+	entryTimeInForces = map[string]bool{"day": true, "gtc": true, "opg": true, "cls": true, "ioc": true, "fok": true}
+	riskTimeInForces  = map[string]bool{"day": true, "gtc": true}
+)
+
+// resolveTimeInForce returns the entry, stop and target time in force for a
+// request. Day trades default to "day" so nothing leaks into the next session,
+// unless the asset class has its own default (crypto has no sessions).
+func resolveTimeInForce(req *PlaceManagedPositionRequest, assetClass string) (entry, stop, target string) {
+	defaultTIF := rulesFor(assetClass).defaultTimeInForce
+	if defaultTIF == "" {
+		defaultTIF = "gtc"
+		if req.Strategy == "DAY_TRADE" {
+			defaultTIF = "day"
+		}
+	}
+
+	entry, stop, target = req.EntryTimeInForce, req.StopTimeInForce, req.TargetTimeInForce
+	if entry == "" {
+		entry = defaultTIF
+	}
+	if stop == "" {
+		stop = defaultTIF
+	}
+	if target == "" {
+		target = defaultTIF
+	}
+
+	return entry, stop, target
+}
+
+// StopLimitWarning explains the tradeoff of stop_limit risk orders
+const StopLimitWarning = "Stop-limit orders bound the fill price but may not fill at all if price gaps or moves through the limit quickly; the position can then remain open below the stop"
+
+// UpdateTrailingStopRequest represents request to enable/disable trailing on a managed position
+type UpdateTrailingStopRequest struct {
+	Enabled         bool    `json:"enabled"`
+	TrailingPercent float64 `json:"trailing_percent,omitempty"` // Required when enabling
+}
+
+// ReduceManagedPositionRequest trims part of a live managed position.
+// Exactly one of Quantity or Percent is required.
+type ReduceManagedPositionRequest struct {
+	Quantity   float64  `json:"quantity,omitempty"`    // Shares to sell (or buy back for shorts)
+	Percent    float64  `json:"percent,omitempty"`     // % of the remaining quantity
+	OrderType  string   `json:"order_type,omitempty"`  // "market" (default) or "limit"
+	LimitPrice *float64 `json:"limit_price,omitempty"` // Required for limit orders
+	Reason     string   `json:"reason,omitempty"`
 }
 
 // PositionManager handles automated position management
@@ -106,6 +232,31 @@ type PositionManager struct {
 	mu             sync.RWMutex
 	logger         *logrus.Logger
 
+	// Optional integrations (nil = disabled)
+	activityLogger *ActivityLogger
+	reasoning      *tradeReasoner
+	fees           FeeModel
+	pdtGuard       PDTGuardConfig
+	entryTimeout   time.Duration // cancel entry orders unfilled for this long (0 = never)
+	flattenBeforeClose int       // minutes before the close to flatten day trades (0 = never)
+	assets         *AssetCache
+	optionsData    interfaces.OptionSnapshotProvider // premium and greeks for options positions
+	minRiskReward  float64                           // 0 = no minimum
+	stopFloor      StopFloorConfig
+	spreadGuard    SpreadGuardConfig
+	priceReference string // default reference price for new entries (bid, ask, mid, last)
+	maxOpenPositions int // 0 = no cap
+	assetClasses   map[string]string // symbol -> asset class overriding detection
+	stopPlacement  StopPlacementConfig
+	alerts         ReportSender // urgent alerts such as an unprotected position (nil = log only)
+	monitorConcurrency int         // positions checked in parallel per monitoring cycle
+	closedMonitorInterval time.Duration // cadence outside the regular session (0 = full cadence)
+	paper          *PaperBroker // fills orders of simulated (dry-run) positions
+	dryRun         bool         // every new position is simulated
+	buyingPowerMultiplier float64 // 0 = broker buying power, else cash × multiplier
+	monitorRunning     atomic.Bool // a monitoring cycle is in progress
+	positionLocks  sync.Map    // position ID -> *sync.Mutex, see positionLock
+
 	ctx            context.Context
 	cancel         context.CancelFunc
 }
@@ -129,6 +280,14 @@ func NewPositionManager(
 		storageService: storageService,
 		positions:      make(map[string]*ManagedPosition),
 		logger:         logger,
+		pdtGuard:       DefaultPDTGuardConfig,
+		entryTimeout:   DefaultEntryOrderTimeout,
+		stopPlacement:  DefaultStopPlacementConfig,
+		priceReference: DefaultPriceReference,
+		monitorConcurrency: DefaultMonitorConcurrency,
+		closedMonitorInterval: DefaultClosedMonitorInterval,
+		assets:         NewAssetCache(tradingService, DefaultAssetCacheTTL),
+		paper:          NewPaperBroker(tradingService, dataService, storageService),
 		ctx:            ctx,
 		cancel:         cancel,
 	}
@@ -150,12 +309,33 @@ func (pm *PositionManager) PlaceManagedPosition(ctx context.Context, req *PlaceM
 	}).Info("Placing managed position")
 
 	// Validate request
-	if err := pm.validateRequest(req); err != nil {
-		return nil, fmt.Errorf("invalid request: %w", err)
+	if err := pm.validateRequest(ctx, req); err != nil {
+		return nil, apperrors.Validation("invalid request: %w", err)
+	}
+
+	if err := pm.checkOpenPositionLimit(1); err != nil {
+		return nil, err
+	}
+
+	// Protect small accounts from pattern-day-trader restrictions
+	if err := pm.checkPDT(ctx, req); err != nil {
+		return nil, err
+	}
+
+	// Reject what the broker would, before creating local state
+	if err := pm.checkBuyingPower(ctx, req); err != nil {
+		return nil, err
 	}
 
-	// Get current price for calculations
-	currentPrice, err := pm.getCurrentPrice(ctx, req.Symbol)
+	// Don't cross a wide spread with a market order
+	if err := pm.checkSpread(ctx, req); err != nil {
+		return nil, err
+	}
+
+	// Get current price for calculations. Quantity, stop and target below
+	// all derive from this one reference price.
+	priceReference := pm.resolvePriceReference(req)
+	currentPrice, err := pm.referencePrice(ctx, req.Symbol, priceReference)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get current price: %w", err)
 	}
@@ -166,19 +346,42 @@ func (pm *PositionManager) PlaceManagedPosition(ctx context.Context, req *PlaceM
 		entryPrice = *req.EntryPrice
 	}
 
-	quantity := pm.calculateQuantity(req.AllocationDollars, entryPrice)
+	assetClass := pm.assetClass(ctx, req.Symbol)
+	quantity := pm.calculateQuantity(assetClass, req.AllocationDollars, entryPrice)
+
+	// Convert ATR multiples into absolute stop/target prices
+	if req.StopATRMultiple != nil || req.TargetATRMultiple != nil {
+		if err := pm.applyATRLevels(ctx, req, entryPrice); err != nil {
+			return nil, err
+		}
+	}
 
 	// Calculate stop loss
 	stopLossPrice := pm.calculateStopLoss(entryPrice, req.StopLossPrice, req.StopLossPercent, req.Side)
+	stopLossPrice, err = pm.applyStopFloor(ctx, req.Symbol, req.Side, entryPrice, stopLossPrice)
+	if err != nil {
+		return nil, apperrors.Validation("invalid request: %w", err)
+	}
 	stopLossPercent := math.Abs((stopLossPrice - entryPrice) / entryPrice * 100)
 
 	// Calculate take profit
 	takeProfitPrice := pm.calculateTakeProfit(entryPrice, req.TakeProfitPrice, req.TakeProfitPercent, req.Side)
 	takeProfitPercent := math.Abs((takeProfitPrice - entryPrice) / entryPrice * 100)
 
-	// Calculate partial exit if configured
+	if err := pm.checkRiskReward(stopLossPercent, takeProfitPercent); err != nil {
+		return nil, apperrors.Validation("invalid request: %w", err)
+	}
+
+	// Calculate partial exit if configured; an absolute price is used as given
 	if req.PartialExit != nil && req.PartialExit.Enabled {
-		req.PartialExit.TargetPrice = pm.calculatePartialExitPrice(entryPrice, req.PartialExit.TargetPercent, req.Side)
+		if req.PartialExit.TargetPrice > 0 {
+			if err := checkPartialExitPrice(req.PartialExit.TargetPrice, entryPrice, takeProfitPrice, req.Side); err != nil {
+				return nil, apperrors.Validation("invalid request: %w", err)
+			}
+			req.PartialExit.TargetPercent = math.Abs((req.PartialExit.TargetPrice - entryPrice) / entryPrice * 100)
+		} else {
+			req.PartialExit.TargetPrice = pm.calculatePartialExitPrice(entryPrice, req.PartialExit.TargetPercent, req.Side)
+		}
 	}
 
 	// Create managed position
@@ -195,6 +398,7 @@ func (pm *PositionManager) PlaceManagedPosition(ctx context.Context, req *PlaceM
 		StopLossPercent:   stopLossPercent,
 		TrailingStop:      req.TrailingStop,
 		TrailingPercent:   req.TrailingPercent,
+		SARStop:           req.SARStop,
 		TakeProfitPrice:   takeProfitPrice,
 		TakeProfitPercent: takeProfitPercent,
 		PartialExit:       req.PartialExit,
@@ -207,11 +411,47 @@ func (pm *PositionManager) PlaceManagedPosition(ctx context.Context, req *PlaceM
 		Tags:              req.Tags,
 	}
 
+	position.AssetClass = assetClass
+	if req.DryRun || pm.dryRun {
+		markSimulated(position)
+	}
+	position.EntryTimeInForce, position.StopTimeInForce, position.TargetTimeInForce = resolveTimeInForce(req, assetClass)
+
+	if req.SARStop {
+		position.SARStep = DefaultSARStep
+		if req.SARStep > 0 {
+			position.SARStep = req.SARStep
+		}
+		position.SARMax = DefaultSARMax
+		if req.SARMax > 0 {
+			position.SARMax = req.SARMax
+		}
+	}
+
+	if req.StopLossLimitOffset != nil {
+		position.StopLossLimitOffset = *req.StopLossLimitOffset
+		pm.logger.WithFields(logrus.Fields{
+			"symbol":       req.Symbol,
+			"limit_offset": position.StopLossLimitOffset,
+		}).Warn(StopLimitWarning)
+	}
+
+	position.TakeProfitType = defaultString(req.TakeProfitOrderType, TakeProfitTypeLimit)
+	if req.TakeProfitLimitOffset != nil {
+		position.TakeProfitLimitOffset = *req.TakeProfitLimitOffset
+	}
+
+	if req.Notes != "" {
+		position.Journal = []PositionNote{{Timestamp: position.CreatedAt, Note: req.Notes}}
+	}
+
 	// Place entry order
 	if err := pm.placeEntryOrder(ctx, position); err != nil {
 		return nil, fmt.Errorf("failed to place entry order: %w", err)
 	}
 
+	position.EntryReasoning = pm.generateReasoning(ctx, position, "ENTRY")
+
 	// Store position
 	pm.mu.Lock()
 	pm.positions[position.ID] = position
@@ -227,6 +467,7 @@ func (pm *PositionManager) PlaceManagedPosition(ctx context.Context, req *PlaceM
 		"entry_order_id":    position.EntryOrderID,
 		"quantity":          quantity,
 		"entry_price":       entryPrice,
+		"price_reference":   priceReference,
 		"stop_loss":         stopLossPrice,
 		"take_profit":       takeProfitPrice,
 		"risk_reward_ratio": takeProfitPercent / stopLossPercent,
@@ -247,16 +488,17 @@ func (pm *PositionManager) placeEntryOrder(ctx context.Context, position *Manage
 		Qty:         position.Quantity,
 		Side:        position.Side,
 		Type:        orderType,
-		TimeInForce: "gtc",
+		TimeInForce: position.EntryTimeInForce,
 		Status:      "pending",
 		SubmittedAt: time.Now(),
 	}
 
 	if orderType == "limit" {
+		position.EntryPrice = pm.roundPrice(ctx, position.Symbol, position.EntryPrice)
 		order.LimitPrice = &position.EntryPrice
 	}
 
-	result, err := pm.tradingService.PlaceOrder(ctx, order)
+	result, err := pm.broker(position).PlaceOrder(ctx, order)
 	if err != nil {
 		return err
 	}
@@ -264,97 +506,214 @@ func (pm *PositionManager) placeEntryOrder(ctx context.Context, position *Manage
 	position.EntryOrderID = result.OrderID
 	position.Status = "PENDING"
 
+	order.ID = result.OrderID
+	pm.saveOrder(position, OrderRoleEntry, order)
+
 	return nil
 }
 
-// MonitorPositions monitors all active positions and manages risk
+// MonitorPositions monitors all active positions and manages risk, slowing
+// down while the market is closed (see SetClosedMonitorInterval)
 func (pm *PositionManager) MonitorPositions(ctx context.Context) {
-	ticker := time.NewTicker(10 * time.Second) // Check every 10 seconds
+	ticker := time.NewTicker(monitorInterval)
 	defer ticker.Stop()
 
 	pm.logger.Info("Position monitoring started")
 
+	var lastCycle time.Time
+	slowed := false
 	for {
 		select {
 		case <-ctx.Done():
 			pm.logger.Info("Position monitoring stopped")
 			return
-		case <-ticker.C:
-			pm.checkPositions(ctx)
+		case now := <-ticker.C:
+			if !pm.monitorDue(now, lastCycle) {
+				if !slowed {
+					slowed = true
+					pm.logger.WithField("interval", pm.closedMonitorInterval).Info("Market closed, slowing position monitoring")
+				}
+				continue
+			}
+			if slowed && IsMarketOpen(now) {
+				slowed = false
+				pm.logger.Info("Market open, resuming full position monitoring")
+			}
+			lastCycle = now
+			pm.startMonitorCycle(ctx)
 		}
 	}
 }
 
-// checkPositions checks all positions and manages their risk orders
+// checkPositions checks all positions and manages their risk orders, spread
+// over up to monitorConcurrency workers. Each position is handled by a single
+// worker per cycle.
 func (pm *PositionManager) checkPositions(ctx context.Context) {
 	pm.mu.RLock()
 	positions := make([]*ManagedPosition, 0, len(pm.positions))
 	for _, pos := range pm.positions {
-		positions = append(positions, pos)
+		if !isTerminalStatus(pos.Status) {
+			positions = append(positions, pos)
+		}
 	}
 	pm.mu.RUnlock()
 
+	workers := pm.monitorConcurrency
+	if workers > len(positions) {
+		workers = len(positions)
+	}
+
+	work := make(chan *ManagedPosition)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for position := range work {
+				// A position being updated through the API is checked next cycle
+				lock := pm.positionLock(position.ID)
+				if !lock.TryLock() {
+					pm.logger.WithField("position_id", position.ID).Debug("Position update in progress - skipping this cycle")
+					continue
+				}
+				pm.checkPosition(ctx, position)
+				lock.Unlock()
+			}
+		}()
+	}
+
 	for _, position := range positions {
-		if position.Status == "CLOSED" || position.Status == "STOPPED_OUT" {
-			continue
-		}
+		work <- position
+	}
+	close(work)
+	wg.Wait()
+}
+
+// positionLock returns the mutex serializing changes to a position's orders.
+// The monitor skips a position whose lock is held, and API updates that
+// replace its orders hold the lock, so the two never replace a stop at once.
+func (pm *PositionManager) positionLock(positionID string) *sync.Mutex {
+	lock, _ := pm.positionLocks.LoadOrStore(positionID, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
 
-		// Check if entry order filled
+// checkPosition runs one monitoring pass over a single position
+func (pm *PositionManager) checkPosition(ctx context.Context, position *ManagedPosition) {
+	// Check if entry order filled, expiring it if it has gone stale
+	if position.Status == "PENDING" {
+		pm.checkEntryOrder(ctx, position)
 		if position.Status == "PENDING" {
-			pm.checkEntryOrder(ctx, position)
-			continue
+			pm.expireStaleEntry(ctx, position)
 		}
+		return
+	}
 
-		// Update current price and P&L
-		if err := pm.updatePositionPrice(ctx, position); err != nil {
-			pm.logger.WithError(err).WithField("symbol", position.Symbol).Error("Failed to update position price")
-			continue
+	// Update current price and P&L
+	if err := pm.updatePositionPrice(ctx, position); err != nil {
+		pm.logger.WithError(err).WithField("symbol", position.Symbol).Error("Failed to update position price")
+		// An underlying stop doesn't depend on the option's own quote
+		if position.AssetClass == AssetClassOption && position.StopBasis == StopBasisUnderlying {
+			pm.manageOptionsExits(ctx, position, false)
 		}
+		return
+	}
 
-		// Check if we need to place/update risk orders
-		if position.Status == "ACTIVE" {
-			pm.manageRiskOrders(ctx, position)
-		}
+	// Check if we need to place/update risk orders
+	if position.AssetClass == AssetClassOption {
+		pm.manageOptionsExits(ctx, position, true)
+		return
+	}
+	if position.Status == "ACTIVE" || position.Status == "PARTIAL" {
+		pm.manageRiskOrders(ctx, position)
+	}
+	// A risk order or reduce may have closed the position
+	if position.Status != "ACTIVE" && position.Status != "PARTIAL" {
+		return
+	}
 
-		// Check trailing stop
-		if position.TrailingStop {
-			pm.updateTrailingStop(ctx, position)
+	// A triggered touch target owns the shares: leave the stop alone
+	if isTouchTarget(position) {
+		if pm.checkTouchTarget(ctx, position) {
+			return
 		}
 	}
+
+	// Check trailing stop
+	if position.TrailingStop {
+		pm.updateTrailingStop(ctx, position)
+	}
+
+	// Check SAR stop
+	if position.SARStop {
+		pm.updateSARStop(ctx, position)
+	}
 }
 
 // checkEntryOrder checks if entry order has filled
 func (pm *PositionManager) checkEntryOrder(ctx context.Context, position *ManagedPosition) {
-	order, err := pm.tradingService.GetOrder(ctx, position.EntryOrderID)
+	order, err := pm.broker(position).GetOrder(ctx, position.EntryOrderID)
 	if err != nil {
 		pm.logger.WithError(err).Error("Failed to get entry order")
 		return
 	}
 
-	if order.Status == "filled" {
-		position.Status = "ACTIVE"
-		position.EntryPrice = *order.FilledAvgPrice
-		position.UpdatedAt = time.Now()
+	switch order.Status {
+	case "filled":
+		pm.activateEntry(ctx, position, order)
+	case "canceled", "expired", "rejected":
+		// The broker ended the order (e.g. a day order at the close)
+		pm.saveOrder(position, OrderRoleEntry, order)
+		if order.FilledQty > 0 && order.FilledAvgPrice != nil {
+			pm.activateEntry(ctx, position, order)
+			return
+		}
+		pm.endPendingPosition(position, "CANCELED", fmt.Sprintf("Entry order %s at broker", order.Status))
+	}
+}
 
-		pm.logger.WithFields(logrus.Fields{
-			"position_id": position.ID,
-			"symbol":      position.Symbol,
-			"fill_price":  position.EntryPrice,
-		}).Info("Entry order filled - position now active")
+// activateEntry marks a position active from its (possibly partially) filled entry order
+func (pm *PositionManager) activateEntry(ctx context.Context, position *ManagedPosition, order *interfaces.Order) {
+	position.Status = "ACTIVE"
+	position.EntryPrice = *order.FilledAvgPrice
+	if order.FilledQty > 0 {
+		position.Quantity = order.FilledQty
+		position.RemainingQty = order.FilledQty
+	}
+	position.UpdatedAt = time.Now()
 
-		// Place risk management orders
-		pm.placeRiskOrders(ctx, position)
+	pm.logger.WithFields(logrus.Fields{
+		"position_id": position.ID,
+		"symbol":      position.Symbol,
+		"fill_price":  position.EntryPrice,
+	}).Info("Entry order filled - position now active")
 
-		// Save to database
-		pm.savePositionToDB(position)
+	pm.saveOrder(position, OrderRoleEntry, order)
+
+	// Place risk management orders; options exits are enforced by the monitor
+	if position.AssetClass != AssetClassOption {
+		pm.placeRiskOrders(ctx, position)
 	}
+
+	pm.logPositionOpened(position)
+
+	// Save to database
+	pm.savePositionToDB(position)
 }
 
 // placeRiskOrders places stop loss and take profit orders
 func (pm *PositionManager) placeRiskOrders(ctx context.Context, position *ManagedPosition) {
-	// Place stop loss order
-	if err := pm.placeStopLossOrder(ctx, position); err != nil {
-		pm.logger.WithError(err).Error("Failed to place stop loss order")
+	pm.mu.Lock()
+	if position.riskOrdersPlaced {
+		pm.mu.Unlock()
+		pm.logger.WithField("position_id", position.ID).Warn("Risk orders already placed - skipping")
+		return
+	}
+	position.riskOrdersPlaced = true
+	pm.mu.Unlock()
+
+	// Place stop loss order once the broker has registered the shares
+	if err := pm.placeProtectiveStop(ctx, position); err != nil {
+		pm.alertUnprotected(ctx, position, err)
 	}
 
 	// Place take profit order
@@ -370,30 +729,63 @@ func (pm *PositionManager) placeRiskOrders(ctx context.Context, position *Manage
 	}
 }
 
-// placeStopLossOrder places or updates stop loss order
+// placeStopLossOrder places the stop loss order. It is a no-op while a stop
+// order is already tracked; callers replacing the stop must clear the ID first.
 func (pm *PositionManager) placeStopLossOrder(ctx context.Context, position *ManagedPosition) error {
+	if position.StopLossOrderID != "" {
+		pm.logger.WithFields(logrus.Fields{
+			"position_id": position.ID,
+			"order_id":    position.StopLossOrderID,
+		}).Debug("Stop loss order already exists - not placing another")
+		return nil
+	}
+
 	exitSide := "sell"
 	if position.Side == "sell" {
 		exitSide = "buy"
 	}
 
+	orderType := "stop"
+	if position.StopLossLimitOffset > 0 {
+		orderType = "stop_limit"
+	}
+
+	qty := pm.normalizeQty(ctx, position.Symbol, riskQty(position), orderType, position.StopTimeInForce)
+	if qty <= 0 {
+		return fmt.Errorf("remaining quantity %.6f is below the minimum for a %s order", riskQty(position), orderType)
+	}
+
+	position.StopLossPrice = pm.roundPrice(ctx, position.Symbol, position.StopLossPrice)
+
 	order := &interfaces.Order{
 		Symbol:      position.Symbol,
-		Qty:         position.RemainingQty,
+		Qty:         qty,
 		Side:        exitSide,
-		Type:        "stop",
-		TimeInForce: "gtc",
+		Type:        orderType,
+		TimeInForce: position.StopTimeInForce,
 		StopPrice:   &position.StopLossPrice,
 		Status:      "pending",
 		SubmittedAt: time.Now(),
 	}
 
-	result, err := pm.tradingService.PlaceOrder(ctx, order)
+	// Bound the fill with a limit beyond the stop (below for longs, above for shorts)
+	if position.StopLossLimitOffset > 0 {
+		limitPrice := position.StopLossPrice * (1 - position.StopLossLimitOffset/100.0)
+		if position.Side == "sell" {
+			limitPrice = position.StopLossPrice * (1 + position.StopLossLimitOffset/100.0)
+		}
+		limitPrice = pm.roundPrice(ctx, position.Symbol, limitPrice)
+		order.LimitPrice = &limitPrice
+	}
+
+	result, err := pm.broker(position).PlaceOrder(ctx, order)
 	if err != nil {
 		return err
 	}
 
 	position.StopLossOrderID = result.OrderID
+	order.ID = result.OrderID
+	pm.saveOrder(position, OrderRoleStopLoss, order)
 	pm.logger.WithFields(logrus.Fields{
 		"position_id": position.ID,
 		"order_id":    result.OrderID,
@@ -403,30 +795,58 @@ func (pm *PositionManager) placeStopLossOrder(ctx context.Context, position *Man
 	return nil
 }
 
-// placeTakeProfitOrder places take profit limit order
+// riskQty is the quantity the stop and target cover: the remaining shares
+// less those held by a pending reduce order
+func riskQty(position *ManagedPosition) float64 {
+	return math.Max(position.RemainingQty-position.ReduceQty, 0)
+}
+
+// placeTakeProfitOrder places take profit limit order (no-op if one exists)
 func (pm *PositionManager) placeTakeProfitOrder(ctx context.Context, position *ManagedPosition) error {
+	if position.TakeProfitOrderID != "" {
+		pm.logger.WithFields(logrus.Fields{
+			"position_id": position.ID,
+			"order_id":    position.TakeProfitOrderID,
+		}).Debug("Take profit order already exists - not placing another")
+		return nil
+	}
+
+	// Touch targets are triggered by the monitor, see checkTouchTarget
+	if isTouchTarget(position) {
+		return nil
+	}
+
 	exitSide := "sell"
 	if position.Side == "sell" {
 		exitSide = "buy"
 	}
 
+	qty := pm.normalizeQty(ctx, position.Symbol, riskQty(position), "limit", position.TargetTimeInForce)
+	if qty <= 0 {
+		return fmt.Errorf("remaining quantity %.6f is below the minimum for a limit order", riskQty(position))
+	}
+
+	position.TakeProfitPrice = pm.roundPrice(ctx, position.Symbol, position.TakeProfitPrice)
+
 	order := &interfaces.Order{
 		Symbol:      position.Symbol,
-		Qty:         position.RemainingQty,
+		Qty:         qty,
 		Side:        exitSide,
 		Type:        "limit",
-		TimeInForce: "gtc",
+		TimeInForce: position.TargetTimeInForce,
 		LimitPrice:  &position.TakeProfitPrice,
 		Status:      "pending",
 		SubmittedAt: time.Now(),
 	}
 
-	result, err := pm.tradingService.PlaceOrder(ctx, order)
+	result, err := pm.broker(position).PlaceOrder(ctx, order)
 	if err != nil {
 		return err
 	}
 
 	position.TakeProfitOrderID = result.OrderID
+	order.ID = result.OrderID
+	pm.saveOrder(position, OrderRoleTakeProfit, order)
 	pm.logger.WithFields(logrus.Fields{
 		"position_id": position.ID,
 		"order_id":    result.OrderID,
@@ -436,32 +856,46 @@ func (pm *PositionManager) placeTakeProfitOrder(ctx context.Context, position *M
 	return nil
 }
 
-// placePartialExitOrder places partial exit order
+// placePartialExitOrder places partial exit order (no-op if one exists)
 func (pm *PositionManager) placePartialExitOrder(ctx context.Context, position *ManagedPosition) error {
+	if len(position.PartialExitOrders) > 0 {
+		pm.logger.WithField("position_id", position.ID).Debug("Partial exit order already exists - not placing another")
+		return nil
+	}
+
 	exitSide := "sell"
 	if position.Side == "sell" {
 		exitSide = "buy"
 	}
 
-	partialQty := position.Quantity * (position.PartialExit.Percent / 100.0)
+	// Never exit more than what is still held
+	partialQty := math.Min(position.Quantity*(position.PartialExit.Percent/100.0), position.RemainingQty)
+	partialQty = pm.normalizeQty(ctx, position.Symbol, partialQty, "limit", position.TargetTimeInForce)
+	if partialQty <= 0 {
+		return fmt.Errorf("partial exit quantity rounds to zero")
+	}
+
+	position.PartialExit.TargetPrice = pm.roundPrice(ctx, position.Symbol, position.PartialExit.TargetPrice)
 
 	order := &interfaces.Order{
 		Symbol:      position.Symbol,
 		Qty:         partialQty,
 		Side:        exitSide,
 		Type:        "limit",
-		TimeInForce: "gtc",
+		TimeInForce: position.TargetTimeInForce,
 		LimitPrice:  &position.PartialExit.TargetPrice,
 		Status:      "pending",
 		SubmittedAt: time.Now(),
 	}
 
-	result, err := pm.tradingService.PlaceOrder(ctx, order)
+	result, err := pm.broker(position).PlaceOrder(ctx, order)
 	if err != nil {
 		return err
 	}
 
 	position.PartialExitOrders = append(position.PartialExitOrders, result.OrderID)
+	order.ID = result.OrderID
+	pm.saveOrder(position, OrderRolePartialExit, order)
 	pm.logger.WithFields(logrus.Fields{
 		"position_id": position.ID,
 		"order_id":    result.OrderID,
@@ -474,14 +908,22 @@ func (pm *PositionManager) placePartialExitOrder(ctx context.Context, position *
 
 // manageRiskOrders checks and updates risk management orders
 func (pm *PositionManager) manageRiskOrders(ctx context.Context, position *ManagedPosition) {
+	// Apply a pending reduce once it reports its fill
+	if position.ReduceOrderID != "" && pm.checkReduceOrder(ctx, position) {
+		return
+	}
+
 	// Check stop loss order status
 	if position.StopLossOrderID != "" {
-		order, err := pm.tradingService.GetOrder(ctx, position.StopLossOrderID)
+		order, err := pm.broker(position).GetOrder(ctx, position.StopLossOrderID)
 		if err == nil && order.Status == "filled" {
 			position.Status = "STOPPED_OUT"
+			position.CloseReason = stopCloseReason(position)
 			now := time.Now()
 			position.ClosedAt = &now
 			pm.logger.WithField("position_id", position.ID).Info("Position stopped out")
+			pm.saveOrder(position, OrderRoleStopLoss, order)
+			pm.recordExit(ctx, position, order.FilledAvgPrice)
 			pm.savePositionToDB(position)
 			return
 		}
@@ -489,29 +931,101 @@ func (pm *PositionManager) manageRiskOrders(ctx context.Context, position *Manag
 
 	// Check take profit order status
 	if position.TakeProfitOrderID != "" {
-		order, err := pm.tradingService.GetOrder(ctx, position.TakeProfitOrderID)
+		order, err := pm.broker(position).GetOrder(ctx, position.TakeProfitOrderID)
 		if err == nil && order.Status == "filled" {
-			position.Status = "CLOSED"
-			now := time.Now()
-			position.ClosedAt = &now
-			pm.logger.WithField("position_id", position.ID).Info("Position closed at profit target")
-			pm.savePositionToDB(position)
+			pm.closeAtTarget(ctx, position, order)
 			return
 		}
 	}
 
-	// Check partial exit orders
-	for _, orderID := range position.PartialExitOrders {
-		order, err := pm.tradingService.GetOrder(ctx, orderID)
-		if err == nil && order.Status == "filled" {
-			position.Status = "PARTIAL"
-			position.RemainingQty -= order.FilledQty
-			pm.logger.WithFields(logrus.Fields{
-				"position_id":   position.ID,
-				"filled_qty":    order.FilledQty,
-				"remaining_qty": position.RemainingQty,
-			}).Info("Partial exit filled")
-			pm.savePositionToDB(position)
+	// Check partial exit orders. A filled order is dropped from the list once
+	// applied so it is never subtracted twice.
+	orderIDs := position.PartialExitOrders
+	for i, orderID := range orderIDs {
+		order, err := pm.broker(position).GetOrder(ctx, orderID)
+		if err != nil || order.Status != "filled" {
+			continue
+		}
+		position.PartialExitOrders = append(append([]string{}, orderIDs[:i]...), orderIDs[i+1:]...)
+		position.Status = "PARTIAL"
+		position.RemainingQty = math.Max(position.RemainingQty-order.FilledQty, 0)
+		pm.logger.WithFields(logrus.Fields{
+			"position_id":   position.ID,
+			"filled_qty":    order.FilledQty,
+			"remaining_qty": position.RemainingQty,
+		}).Info("Partial exit filled")
+		pm.saveOrder(position, OrderRolePartialExit, order)
+		pm.resetTrailingAfterPartial(ctx, position)
+		if position.PartialExit != nil && position.PartialExit.BreakevenStop {
+			pm.moveStopToBreakeven(ctx, position)
+		}
+		pm.resizeRiskOrders(ctx, position)
+		pm.savePositionToDB(position)
+		return
+	}
+}
+
+// closeAtTarget closes a position whose take profit order filled
+func (pm *PositionManager) closeAtTarget(ctx context.Context, position *ManagedPosition, order *interfaces.Order) {
+	position.Status = "CLOSED"
+	position.CloseReason = CloseReasonTakeProfit
+	now := time.Now()
+	position.ClosedAt = &now
+	pm.logger.WithField("position_id", position.ID).Info("Position closed at profit target")
+	pm.saveOrder(position, OrderRoleTakeProfit, order)
+	pm.recordExit(ctx, position, order.FilledAvgPrice)
+	pm.savePositionToDB(position)
+}
+
+// moveStopToBreakeven sets the stop price to the entry price when that
+// tightens it. The stop order itself is replaced by resizeRiskOrders.
+func (pm *PositionManager) moveStopToBreakeven(ctx context.Context, position *ManagedPosition) {
+	breakeven := pm.roundPrice(ctx, position.Symbol, position.EntryPrice)
+	if position.Side == "buy" && breakeven <= position.StopLossPrice {
+		return
+	}
+	if position.Side == "sell" && position.StopLossPrice > 0 && breakeven >= position.StopLossPrice {
+		return
+	}
+
+	oldStopPrice := position.StopLossPrice
+	position.StopLossPrice = breakeven
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id":    position.ID,
+		"new_stop_price": breakeven,
+	}).Info("Stop moved to break-even after partial exit")
+	pm.logPositionEvent(position, "STOP_MOVED", "Stop moved to break-even after partial exit", map[string]interface{}{
+		"old_stop_price": oldStopPrice,
+		"new_stop_price": breakeven,
+	})
+}
+
+// resizeRiskOrders replaces the stop and target so they cover only the
+// remaining quantity after a partial exit. The old orders are cancelled and
+// the cancels confirmed first because the broker holds their shares until
+// they are released.
+func (pm *PositionManager) resizeRiskOrders(ctx context.Context, position *ManagedPosition) {
+	if position.StopLossOrderID != "" {
+		if err := pm.cancelConfirmed(ctx, position, position.StopLossOrderID); err != nil {
+			pm.logger.WithError(err).WithField("order_id", position.StopLossOrderID).Warn("Failed to cancel stop loss for resize")
+		} else {
+			position.StopLossOrderID = ""
+			if err := pm.placeStopLossOrder(ctx, position); err != nil {
+				pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to resize stop loss after partial exit")
+			}
+		}
+	}
+
+	if position.TakeProfitOrderID != "" {
+		if err := pm.cancelConfirmed(ctx, position, position.TakeProfitOrderID); err != nil {
+			pm.logger.WithError(err).WithField("order_id", position.TakeProfitOrderID).Warn("Failed to cancel take profit for resize")
+			return
+		}
+
+		position.TakeProfitOrderID = ""
+		if err := pm.placeTakeProfitOrder(ctx, position); err != nil {
+			pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to resize take profit after partial exit")
 		}
 	}
 }
@@ -519,100 +1033,639 @@ func (pm *PositionManager) manageRiskOrders(ctx context.Context, position *Manag
 // updateTrailingStop updates trailing stop loss based on current price
 func (pm *PositionManager) updateTrailingStop(ctx context.Context, position *ManagedPosition) {
 	if position.Side == "buy" {
-		// For long positions, raise stop as price rises
-		newStopPrice := position.CurrentPrice * (1 - position.TrailingPercent/100.0)
+		// For long positions, raise stop as the high-water mark rises
+		if position.CurrentPrice > position.TrailingReference {
+			position.TrailingReference = position.CurrentPrice
+		}
+		newStopPrice := pm.roundPrice(ctx, position.Symbol, trailingStopFor(position, position.TrailingReference))
 		if newStopPrice > position.StopLossPrice {
-			// Cancel old stop loss order
-			if position.StopLossOrderID != "" {
-				pm.tradingService.CancelOrder(ctx, position.StopLossOrderID)
-			}
-
-			// Update stop price and place new order
-			position.StopLossPrice = newStopPrice
-			pm.placeStopLossOrder(ctx, position)
-
-			pm.logger.WithFields(logrus.Fields{
-				"position_id":    position.ID,
-				"new_stop_price": newStopPrice,
-			}).Info("Trailing stop updated")
+			pm.moveTrailingStop(ctx, position, newStopPrice, "Trailing stop raised")
 		}
 	} else {
-		// For short positions, lower stop as price falls
-		newStopPrice := position.CurrentPrice * (1 + position.TrailingPercent/100.0)
+		// For short positions, lower stop as the low-water mark falls
+		if position.TrailingReference <= 0 || position.CurrentPrice < position.TrailingReference {
+			position.TrailingReference = position.CurrentPrice
+		}
+		newStopPrice := pm.roundPrice(ctx, position.Symbol, trailingStopFor(position, position.TrailingReference))
 		if newStopPrice < position.StopLossPrice {
-			if position.StopLossOrderID != "" {
-				pm.tradingService.CancelOrder(ctx, position.StopLossOrderID)
-			}
+			pm.moveTrailingStop(ctx, position, newStopPrice, "Trailing stop lowered")
+		}
+	}
+}
 
-			position.StopLossPrice = newStopPrice
-			pm.placeStopLossOrder(ctx, position)
+// moveTrailingStop replaces the stop order at a new trailing price (see
+// replaceStopLossOrder). If the stop can't be moved the old one is kept and
+// the move is retried on the next cycle.
+func (pm *PositionManager) moveTrailingStop(ctx context.Context, position *ManagedPosition, newStopPrice float64, message string) {
+	oldStopPrice := position.StopLossPrice
+	if err := pm.replaceStopLossOrder(ctx, position, newStopPrice); err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Warn("Trailing stop not moved, retrying next cycle")
+		pm.savePositionToDB(position)
+		return
+	}
 
-			pm.logger.WithFields(logrus.Fields{
-				"position_id":    position.ID,
-				"new_stop_price": newStopPrice,
-			}).Info("Trailing stop updated")
-		}
+	if err := pm.savePositionToDB(position); err != nil {
+		pm.logger.WithError(err).Error("Failed to save position to database")
 	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id":    position.ID,
+		"new_stop_price": newStopPrice,
+	}).Info("Trailing stop updated")
+	pm.logPositionEvent(position, "STOP_MOVED", message, map[string]interface{}{
+		"old_stop_price": oldStopPrice,
+		"new_stop_price": newStopPrice,
+	})
 }
 
-// updatePositionPrice updates current price and unrealized P&L
-func (pm *PositionManager) updatePositionPrice(ctx context.Context, position *ManagedPosition) error {
-	currentPrice, err := pm.getCurrentPrice(ctx, position.Symbol)
+// SAR stop settings: daily bars are used, and the SAR only changes once per bar,
+// so it is recalculated at most every sarRefreshInterval
+const (
+	sarLookbackDays    = 90
+	sarRefreshInterval = 5 * time.Minute
+)
+
+// updateSARStop moves the stop to the current Parabolic SAR. The stop is only
+// ever tightened, and is left alone while the SAR trend opposes the position.
+func (pm *PositionManager) updateSARStop(ctx context.Context, position *ManagedPosition) {
+	if time.Since(position.sarCheckedAt) < sarRefreshInterval {
+		return
+	}
+	position.sarCheckedAt = time.Now()
+
+	end := time.Now()
+	start := end.AddDate(0, 0, -sarLookbackDays)
+
+	bars, err := pm.dataService.GetHistoricalBars(ctx, position.Symbol, start, end, "1Day")
 	if err != nil {
-		return err
+		pm.logger.WithError(err).WithField("symbol", position.Symbol).Warn("Failed to get bars for SAR stop")
+		return
+	}
+	if len(bars) < indicatorMinBars[IndicatorSAR] {
+		return
 	}
 
-	position.CurrentPrice = currentPrice
+	sar := CalculateParabolicSAR(bars, position.SARStep, position.SARMax)
+	if sar == nil {
+		return
+	}
+
+	sarStop := pm.roundPrice(ctx, position.Symbol, sar.SAR)
 
+	var improves bool
 	if position.Side == "buy" {
-		position.UnrealizedPL = (currentPrice - position.EntryPrice) * position.RemainingQty
-		position.UnrealizedPLPC = ((currentPrice - position.EntryPrice) / position.EntryPrice) * 100
+		improves = sar.Trend == "up" && sarStop > position.StopLossPrice && sarStop < position.CurrentPrice
 	} else {
-		position.UnrealizedPL = (position.EntryPrice - currentPrice) * position.RemainingQty
-		position.UnrealizedPLPC = ((position.EntryPrice - currentPrice) / position.EntryPrice) * 100
+		improves = sar.Trend == "down" && sarStop < position.StopLossPrice && sarStop > position.CurrentPrice
+	}
+	if !improves {
+		return
 	}
 
-	position.UpdatedAt = time.Now()
+	oldStopPrice := position.StopLossPrice
+	if err := pm.replaceStopLossOrder(ctx, position, sarStop); err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to move stop to SAR")
+		pm.savePositionToDB(position)
+		return
+	}
 
-	return nil
+	if err := pm.savePositionToDB(position); err != nil {
+		pm.logger.WithError(err).Error("Failed to save position to database")
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id":    position.ID,
+		"new_stop_price": sarStop,
+		"accel_factor":   sar.AccelFactor,
+	}).Info("SAR stop updated")
+	pm.logPositionEvent(position, "STOP_MOVED", "Stop moved to Parabolic SAR", map[string]interface{}{
+		"old_stop_price": oldStopPrice,
+		"new_stop_price": sarStop,
+		"accel_factor":   sar.AccelFactor,
+	})
 }
 
-// GetManagedPosition retrieves a managed position by ID
-func (pm *PositionManager) GetManagedPosition(positionID string) (*ManagedPosition, error) {
-	pm.mu.RLock()
-	defer pm.mu.RUnlock()
+// AppendPositionNote adds a timestamped journal entry to a managed position.
+// The entry is also appended to Notes so older clients still see it.
+func (pm *PositionManager) AppendPositionNote(positionID, note string) (*ManagedPosition, error) {
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, apperrors.Validation("note cannot be empty")
+	}
 
+	pm.mu.Lock()
 	position, exists := pm.positions[positionID]
 	if !exists {
-		return nil, fmt.Errorf("position not found: %s", positionID)
+		pm.mu.Unlock()
+		return nil, apperrors.NotFound("position not found: %s", positionID)
+	}
+
+	now := time.Now()
+	position.Journal = append(position.Journal, PositionNote{Timestamp: now, Note: note})
+
+	entry := fmt.Sprintf("[%s] %s", now.Format("2006-01-02 15:04"), note)
+	if position.Notes == "" {
+		position.Notes = entry
+	} else {
+		position.Notes = position.Notes + "\n" + entry
+	}
+	position.UpdatedAt = now
+	pm.mu.Unlock()
+
+	if err := pm.savePositionToDB(position); err != nil {
+		return nil, fmt.Errorf("failed to save note: %w", err)
 	}
 
+	pm.logger.WithField("position_id", positionID).Info("Note appended to position journal")
+
 	return position, nil
 }
 
-// ListManagedPositions returns all managed positions
-// Filters out PENDING positions older than 24 hours (stale orders)
-func (pm *PositionManager) ListManagedPositions(status string) []*ManagedPosition {
+// SetTrailingStop enables or disables trailing on an existing managed position.
+// When enabling, the stop is ratcheted from the current price immediately if
+// that tightens it. The old stop holds the position's shares, so it is
+// cancelled and the cancel confirmed before the replacement is placed (see
+// replaceStopLossOrder): the position has no stop at the broker for that short
+// window, and the old stop is placed again if the replacement fails.
+func (pm *PositionManager) SetTrailingStop(ctx context.Context, positionID string, req *UpdateTrailingStopRequest) (*ManagedPosition, error) {
+	lock := pm.positionLock(positionID)
+	lock.Lock()
+	defer lock.Unlock()
+
 	pm.mu.RLock()
-	defer pm.mu.RUnlock()
+	position, exists := pm.positions[positionID]
+	pm.mu.RUnlock()
 
-	positions := make([]*ManagedPosition, 0)
-	now := time.Now()
+	if !exists {
+		return nil, apperrors.NotFound("position not found: %s", positionID)
+	}
 
-	for _, pos := range pm.positions {
-		// Filter out stale PENDING orders (>24 hours old)
-		if pos.Status == "PENDING" {
-			age := now.Sub(pos.CreatedAt)
-			if age > 24*time.Hour {
-				pm.logger.WithFields(logrus.Fields{
-					"position_id": pos.ID,
-					"symbol":      pos.Symbol,
-					"age_hours":   age.Hours(),
-				}).Debug("Skipping stale PENDING position")
-				continue
+	if position.Status != "ACTIVE" && position.Status != "PARTIAL" {
+		return nil, apperrors.Validation("position %s is %s - trailing can only be changed on active positions", positionID, position.Status)
+	}
+
+	if position.AssetClass == AssetClassOption {
+		return nil, apperrors.Validation("position %s holds options - trailing stops are not supported", positionID)
+	}
+
+	if !req.Enabled {
+		// Keep the current stop order in place as a static stop
+		position.TrailingStop = false
+		position.UpdatedAt = time.Now()
+
+		if err := pm.savePositionToDB(position); err != nil {
+			pm.logger.WithError(err).Error("Failed to save position to database")
+		}
+
+		pm.logger.WithField("position_id", position.ID).Info("Trailing stop disabled")
+		return position, nil
+	}
+
+	if req.TrailingPercent <= 0 || req.TrailingPercent >= 100 {
+		return nil, apperrors.Validation("trailing_percent must be between 0 and 100")
+	}
+
+	if err := pm.updatePositionPrice(ctx, position); err != nil {
+		return nil, fmt.Errorf("failed to get current price: %w", err)
+	}
+
+	newStopPrice := position.CurrentPrice * (1 - req.TrailingPercent/100.0)
+	if position.Side == "sell" {
+		newStopPrice = position.CurrentPrice * (1 + req.TrailingPercent/100.0)
+	}
+	newStopPrice = pm.roundPrice(ctx, position.Symbol, newStopPrice)
+	improves := newStopPrice > position.StopLossPrice
+	if position.Side == "sell" {
+		improves = newStopPrice < position.StopLossPrice
+	}
+
+	// Only move the stop if the trailing level is tighter than the current stop;
+	// otherwise the existing stop stays and trailing takes over as price moves
+	if improves || position.StopLossOrderID == "" {
+		if err := pm.replaceStopLossOrder(ctx, position, newStopPrice); err != nil {
+			pm.savePositionToDB(position)
+			return nil, fmt.Errorf("failed to place trailing stop order: %w", err)
+		}
+	}
+
+	position.TrailingStop = true
+	position.TrailingPercent = req.TrailingPercent
+	position.TrailingReference = position.CurrentPrice
+	position.UpdatedAt = time.Now()
+
+	if err := pm.savePositionToDB(position); err != nil {
+		pm.logger.WithError(err).Error("Failed to save position to database")
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id":      position.ID,
+		"trailing_percent": position.TrailingPercent,
+		"stop_price":       position.StopLossPrice,
+	}).Info("Trailing stop enabled")
+	pm.logPositionEvent(position, "TRAILING_ENABLED", "", map[string]interface{}{
+		"trailing_percent": position.TrailingPercent,
+		"stop_price":       position.StopLossPrice,
+	})
+
+	return position, nil
+}
+
+// ReduceManagedPosition exits part of an active position on demand. The stop,
+// target and any partial exit are cancelled first so the broker releases
+// their shares; the stop and target are then re-placed for the shares the
+// reduce order doesn't cover. The remaining quantity is lowered once the
+// reduce order fills (see checkReduceOrder), and a reduce supersedes the
+// configured partial exit, which isn't re-placed.
+func (pm *PositionManager) ReduceManagedPosition(ctx context.Context, positionID string, req *ReduceManagedPositionRequest) (*ManagedPosition, error) {
+	lock := pm.positionLock(positionID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	pm.mu.RLock()
+	position, exists := pm.positions[positionID]
+	pm.mu.RUnlock()
+
+	if !exists {
+		return nil, apperrors.NotFound("position not found: %s", positionID)
+	}
+
+	if position.Status != "ACTIVE" && position.Status != "PARTIAL" {
+		return nil, apperrors.Validation("position %s is %s - only active positions can be reduced", positionID, position.Status)
+	}
+	if position.ReduceOrderID != "" {
+		return nil, apperrors.Validation("position %s already has reduce order %s pending", positionID, position.ReduceOrderID)
+	}
+	if isTouchTarget(position) && position.TakeProfitOrderID != "" {
+		return nil, apperrors.Validation("position %s is already exiting at its target", positionID)
+	}
+
+	if (req.Quantity > 0) == (req.Percent > 0) {
+		return nil, apperrors.Validation("exactly one of quantity or percent is required")
+	}
+	if req.Percent > 100 {
+		return nil, apperrors.Validation("percent must be between 0 and 100")
+	}
+
+	orderType := defaultString(req.OrderType, "market")
+	if orderType != "market" && orderType != "limit" {
+		return nil, apperrors.Validation("order_type must be 'market' or 'limit'")
+	}
+	if orderType == "limit" && req.LimitPrice == nil {
+		return nil, apperrors.Validation("limit_price required for limit orders")
+	}
+
+	timeInForce := "day"
+	if orderType == "limit" {
+		timeInForce = position.TargetTimeInForce
+	}
+
+	qty := req.Quantity
+	if req.Percent > 0 {
+		qty = position.RemainingQty * req.Percent / 100.0
+	}
+	if qty > position.RemainingQty {
+		return nil, apperrors.Validation("reduction of %.6f exceeds remaining quantity %.6f", qty, position.RemainingQty)
+	}
+	qty = pm.normalizeQty(ctx, position.Symbol, qty, orderType, timeInForce)
+	if qty <= 0 {
+		return nil, apperrors.Validation("reduction quantity rounds to zero for a %s order", orderType)
+	}
+
+	// Release the shares held by the risk orders
+	if err := pm.releaseRiskOrders(ctx, position); err != nil {
+		pm.restoreRiskOrders(ctx, position)
+		pm.savePositionToDB(position)
+		return nil, apperrors.Broker("failed to release risk orders for reduce: %v", err)
+	}
+
+	exitSide := "sell"
+	if position.Side == "sell" {
+		exitSide = "buy"
+	}
+
+	if req.LimitPrice != nil {
+		limitPrice := pm.roundPrice(ctx, position.Symbol, *req.LimitPrice)
+		req.LimitPrice = &limitPrice
+	}
+
+	order := &interfaces.Order{
+		Symbol:      position.Symbol,
+		Qty:         qty,
+		Side:        exitSide,
+		Type:        orderType,
+		TimeInForce: timeInForce,
+		LimitPrice:  req.LimitPrice,
+		Status:      "pending",
+		SubmittedAt: time.Now(),
+	}
+
+	result, err := pm.broker(position).PlaceOrder(ctx, order)
+	if err != nil {
+		// Restore protection for the full position
+		pm.restoreRiskOrders(ctx, position)
+		pm.savePositionToDB(position)
+		return nil, fmt.Errorf("failed to place reduce order: %w", err)
+	}
+
+	order.ID = result.OrderID
+	pm.saveOrder(position, OrderRoleReduce, order)
+
+	position.ReduceOrderID = result.OrderID
+	position.ReduceQty = qty
+	position.UpdatedAt = time.Now()
+
+	// Protect the shares the reduce order doesn't cover
+	if riskQty(position) > 0 {
+		if err := pm.placeStopLossOrder(ctx, position); err != nil {
+			pm.alertUnprotected(ctx, position, err)
+		}
+		if err := pm.placeTakeProfitOrder(ctx, position); err != nil {
+			pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to re-place take profit after reduce")
+		}
+	}
+
+	if err := pm.savePositionToDB(position); err != nil {
+		pm.logger.WithError(err).Error("Failed to save position to database")
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id": position.ID,
+		"order_id":    result.OrderID,
+		"quantity":    qty,
+	}).Info("Reduce order placed")
+	pm.logPositionEvent(position, "REDUCE_ORDERED", req.Reason, map[string]interface{}{
+		"order_id":   result.OrderID,
+		"order_type": orderType,
+		"quantity":   qty,
+	})
+
+	return position, nil
+}
+
+// checkReduceOrder applies a pending reduce order once it reaches a final
+// status: the remaining quantity drops by what filled and the stop and
+// target are resized to cover the rest. A reduce that sells everything left
+// closes the position. It reports whether the position closed.
+func (pm *PositionManager) checkReduceOrder(ctx context.Context, position *ManagedPosition) bool {
+	order, err := pm.broker(position).GetOrder(ctx, position.ReduceOrderID)
+	if err != nil {
+		pm.logger.WithError(err).WithField("order_id", position.ReduceOrderID).Warn("Failed to check reduce order")
+		return false
+	}
+	switch order.Status {
+	case "filled", "canceled", "expired", "rejected":
+	default:
+		return false
+	}
+
+	reduceQty := position.ReduceQty
+	filled := math.Min(order.FilledQty, position.RemainingQty)
+	position.ReduceOrderID = ""
+	position.ReduceQty = 0
+	position.UpdatedAt = time.Now()
+	pm.saveOrder(position, OrderRoleReduce, order)
+
+	details := map[string]interface{}{
+		"order_id":   order.ID,
+		"status":     order.Status,
+		"filled_qty": filled,
+	}
+
+	if filled > 0 && position.RemainingQty-filled <= 0 {
+		position.Status = "CLOSED"
+		position.CloseReason = CloseReasonManual
+		now := time.Now()
+		position.ClosedAt = &now
+		pm.recordExit(ctx, position, order.FilledAvgPrice)
+		position.RemainingQty = 0
+
+		pm.logger.WithField("position_id", position.ID).Info("Reduce filled the remaining quantity, position closed")
+		details["remaining_qty"] = 0.0
+		pm.logPositionEvent(position, "REDUCED", "Reduce filled the remaining quantity", details)
+		pm.savePositionToDB(position)
+		return true
+	}
+
+	if filled > 0 {
+		position.RemainingQty -= filled
+		position.Status = "PARTIAL"
+	}
+
+	// The risk orders were sized for a full fill
+	if filled < reduceQty {
+		pm.resizeRiskOrders(ctx, position)
+	}
+	if err := pm.placeStopLossOrder(ctx, position); err != nil {
+		pm.alertUnprotected(ctx, position, err)
+	}
+	if err := pm.placeTakeProfitOrder(ctx, position); err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to place take profit after reduce")
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id":   position.ID,
+		"order_id":      order.ID,
+		"filled_qty":    filled,
+		"remaining_qty": position.RemainingQty,
+	}).Info("Reduce order completed")
+	details["remaining_qty"] = position.RemainingQty
+	if filled > 0 {
+		pm.logPositionEvent(position, "REDUCED", fmt.Sprintf("Reduce order %s", order.Status), details)
+	} else {
+		pm.logPositionEvent(position, "REDUCE_UNFILLED", fmt.Sprintf("Reduce order %s without a fill", order.Status), details)
+	}
+
+	pm.savePositionToDB(position)
+	return false
+}
+
+// releaseRiskOrders cancels the stop, target and partial exits so the broker
+// releases their shares. Each order ID is cleared only once its cancel is
+// confirmed, so an order that may still be live stays tracked.
+func (pm *PositionManager) releaseRiskOrders(ctx context.Context, position *ManagedPosition) error {
+	if position.StopLossOrderID != "" {
+		if err := pm.cancelConfirmed(ctx, position, position.StopLossOrderID); err != nil {
+			return fmt.Errorf("stop loss: %w", err)
+		}
+		position.StopLossOrderID = ""
+	}
+
+	if position.TakeProfitOrderID != "" {
+		if err := pm.cancelConfirmed(ctx, position, position.TakeProfitOrderID); err != nil {
+			return fmt.Errorf("take profit: %w", err)
+		}
+		position.TakeProfitOrderID = ""
+	}
+
+	for len(position.PartialExitOrders) > 0 {
+		if err := pm.cancelConfirmed(ctx, position, position.PartialExitOrders[0]); err != nil {
+			return fmt.Errorf("partial exit: %w", err)
+		}
+		position.PartialExitOrders = position.PartialExitOrders[1:]
+	}
+
+	return nil
+}
+
+// restoreRiskOrders re-places the risk orders a failed reduce released
+func (pm *PositionManager) restoreRiskOrders(ctx context.Context, position *ManagedPosition) {
+	if err := pm.placeStopLossOrder(ctx, position); err != nil {
+		pm.alertUnprotected(ctx, position, err)
+	}
+	if err := pm.placeTakeProfitOrder(ctx, position); err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to restore take profit")
+	}
+	if position.Status == "ACTIVE" && position.PartialExit != nil && position.PartialExit.Enabled {
+		if err := pm.placePartialExitOrder(ctx, position); err != nil {
+			pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to restore partial exit")
+		}
+	}
+}
+
+// replaceStopLossOrder moves the stop to a new price. The old stop holds the
+// position's shares, so the broker would reject a second stop beside it: the
+// old stop is cancelled and the cancel confirmed before the new one is
+// placed. If the new stop can't be placed, the old stop is placed again.
+func (pm *PositionManager) replaceStopLossOrder(ctx context.Context, position *ManagedPosition, newStopPrice float64) error {
+	oldOrderID := position.StopLossOrderID
+	oldStopPrice := position.StopLossPrice
+
+	if oldOrderID != "" {
+		if err := pm.cancelConfirmed(ctx, position, oldOrderID); err != nil {
+			return fmt.Errorf("previous stop not canceled, keeping it: %w", err)
+		}
+		position.StopLossOrderID = ""
+	}
+
+	position.StopLossPrice = newStopPrice
+	if err := pm.placeStopLossOrder(ctx, position); err != nil {
+		position.StopLossPrice = oldStopPrice
+		if oldOrderID != "" {
+			if restoreErr := pm.placeStopLossOrder(ctx, position); restoreErr != nil {
+				pm.alertUnprotected(ctx, position, restoreErr)
 			}
 		}
+		return err
+	}
+
+	return nil
+}
 
+// Cancels are confirmed by polling the order at cancelPollInterval for up to
+// cancelConfirmTimeout
+const (
+	cancelConfirmTimeout = 5 * time.Second
+	cancelPollInterval   = 250 * time.Millisecond
+)
+
+// cancelConfirmed cancels an order and waits for the broker to confirm it
+// closed unfilled
+func (pm *PositionManager) cancelConfirmed(ctx context.Context, position *ManagedPosition, orderID string) error {
+	cancelErr := pm.broker(position).CancelOrder(ctx, orderID)
+	if err := pm.confirmCanceled(ctx, position, orderID); err != nil {
+		if cancelErr != nil {
+			err = fmt.Errorf("%w (cancel request: %v)", err, cancelErr)
+		}
+		return err
+	}
+	pm.markOrderCanceled(orderID)
+	return nil
+}
+
+// confirmCanceled polls an order until the broker reports it closed without
+// filling. It fails if the order filled (the exit is then handled as a stop
+// fill) or is still open when cancelConfirmTimeout runs out.
+func (pm *PositionManager) confirmCanceled(ctx context.Context, position *ManagedPosition, orderID string) error {
+	deadline := time.Now().Add(cancelConfirmTimeout)
+
+	for {
+		order, err := pm.broker(position).GetOrder(ctx, orderID)
+		if err == nil {
+			switch order.Status {
+			case "canceled", "expired", "rejected", "replaced":
+				return nil
+			case "filled":
+				return apperrors.Broker("order %s filled before it could be canceled", orderID)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("cancel of order %s not confirmed within %s: %w", orderID, cancelConfirmTimeout, err)
+			}
+			return fmt.Errorf("cancel of order %s not confirmed within %s (status %s)", orderID, cancelConfirmTimeout, order.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(cancelPollInterval):
+		}
+	}
+}
+
+// updatePositionPrice updates current price and unrealized P&L
+func (pm *PositionManager) updatePositionPrice(ctx context.Context, position *ManagedPosition) error {
+	var currentPrice float64
+	var err error
+	if position.AssetClass == AssetClassOption {
+		var quote *interfaces.OptionContract
+		quote, err = pm.optionQuote(ctx, position.Symbol)
+		if err == nil {
+			currentPrice = quote.Premium
+			position.Delta = quote.Delta
+			position.ApproxPremiumStop = approxPremiumStop(position)
+		}
+	} else {
+		currentPrice, err = pm.getCurrentPrice(ctx, position.Symbol)
+	}
+	if err != nil {
+		return err
+	}
+
+	position.CurrentPrice = currentPrice
+	multiplier := contractMultiplier(position.Symbol)
+
+	if position.Side == "buy" {
+		position.UnrealizedPL = RoundMoney((currentPrice - position.EntryPrice) * position.RemainingQty * multiplier)
+		position.UnrealizedPLPC = RoundPercent(((currentPrice - position.EntryPrice) / position.EntryPrice) * 100)
+	} else {
+		position.UnrealizedPL = RoundMoney((position.EntryPrice - currentPrice) * position.RemainingQty * multiplier)
+		position.UnrealizedPLPC = RoundPercent(((position.EntryPrice - currentPrice) / position.EntryPrice) * 100)
+	}
+
+	// Excursions start at zero (the entry) so MAE never goes positive nor MFE negative
+	position.MaxAdverseExcursion = math.Min(position.MaxAdverseExcursion, position.UnrealizedPLPC)
+	position.MaxFavorableExcursion = math.Max(position.MaxFavorableExcursion, position.UnrealizedPLPC)
+
+	position.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// GetManagedPosition retrieves a managed position by ID
+func (pm *PositionManager) GetManagedPosition(positionID string) (*ManagedPosition, error) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	position, exists := pm.positions[positionID]
+	if !exists {
+		return nil, apperrors.NotFound("position not found: %s", positionID)
+	}
+
+	return position, nil
+}
+
+// ListManagedPositions returns the managed positions with status, or all of
+// them when status is empty. Stale PENDING entries aren't filtered here: the
+// monitor expires them once their entry order times out (see expireStaleEntry).
+func (pm *PositionManager) ListManagedPositions(status string) []*ManagedPosition {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	positions := make([]*ManagedPosition, 0)
+
+	for _, pos := range pm.positions {
 		if status == "" || pos.Status == status {
 			positions = append(positions, pos)
 		}
@@ -623,47 +1676,74 @@ func (pm *PositionManager) ListManagedPositions(status string) []*ManagedPositio
 
 // CloseManagedPosition manually closes a managed position
 func (pm *PositionManager) CloseManagedPosition(ctx context.Context, positionID string) error {
+	_, err := pm.closeManagedPosition(ctx, positionID, CloseReasonManual)
+	return err
+}
+
+// closeManagedPosition closes a position for reason (a CloseReason code) and
+// returns the ID of the market exit order, empty when none was placed
+func (pm *PositionManager) closeManagedPosition(ctx context.Context, positionID, reason string) (string, error) {
 	pm.mu.RLock()
 	position, exists := pm.positions[positionID]
 	pm.mu.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("position not found: %s", positionID)
+		return "", apperrors.NotFound("position not found: %s", positionID)
 	}
 
 	// Cancel all open orders (ignore errors - orders may already be cancelled or market closed)
 
 	// Cancel entry order if still pending
 	if position.EntryOrderID != "" {
-		if err := pm.tradingService.CancelOrder(ctx, position.EntryOrderID); err != nil {
+		if err := pm.broker(position).CancelOrder(ctx, position.EntryOrderID); err != nil {
 			pm.logger.WithError(err).Warn("Failed to cancel entry order (may already be filled/cancelled)")
 		} else {
 			pm.logger.WithField("order_id", position.EntryOrderID).Info("Cancelled entry order")
+			pm.markOrderCanceled(position.EntryOrderID)
 		}
 	}
 
 	if position.StopLossOrderID != "" {
-		if err := pm.tradingService.CancelOrder(ctx, position.StopLossOrderID); err != nil {
+		if err := pm.broker(position).CancelOrder(ctx, position.StopLossOrderID); err != nil {
 			pm.logger.WithError(err).Warn("Failed to cancel stop loss order (may already be cancelled)")
 		} else {
 			pm.logger.WithField("order_id", position.StopLossOrderID).Info("Cancelled stop loss order")
+			pm.markOrderCanceled(position.StopLossOrderID)
 		}
 	}
 	if position.TakeProfitOrderID != "" {
-		if err := pm.tradingService.CancelOrder(ctx, position.TakeProfitOrderID); err != nil {
+		if err := pm.broker(position).CancelOrder(ctx, position.TakeProfitOrderID); err != nil {
 			pm.logger.WithError(err).Warn("Failed to cancel take profit order (may already be cancelled)")
 		} else {
 			pm.logger.WithField("order_id", position.TakeProfitOrderID).Info("Cancelled take profit order")
+			pm.markOrderCanceled(position.TakeProfitOrderID)
 		}
 	}
+	if position.ReduceOrderID != "" {
+		if err := pm.broker(position).CancelOrder(ctx, position.ReduceOrderID); err != nil {
+			pm.logger.WithError(err).Warn("Failed to cancel reduce order (may already be filled)")
+		} else {
+			pm.logger.WithField("order_id", position.ReduceOrderID).Info("Cancelled reduce order")
+			pm.markOrderCanceled(position.ReduceOrderID)
+		}
+		// Shares the reduce already sold aren't sold again by the exit
+		if order, err := pm.broker(position).GetOrder(ctx, position.ReduceOrderID); err == nil && order.FilledQty > 0 {
+			position.RemainingQty = math.Max(position.RemainingQty-order.FilledQty, 0)
+		}
+		position.ReduceOrderID = ""
+		position.ReduceQty = 0
+	}
 	for _, orderID := range position.PartialExitOrders {
-		if err := pm.tradingService.CancelOrder(ctx, orderID); err != nil {
+		if err := pm.broker(position).CancelOrder(ctx, orderID); err != nil {
 			pm.logger.WithError(err).Warn("Failed to cancel partial exit order (may already be cancelled)")
 		} else {
 			pm.logger.WithField("order_id", orderID).Info("Cancelled partial exit order")
+			pm.markOrderCanceled(orderID)
 		}
 	}
 
+	exitOrderID := ""
+
 	// Place market order to close remaining position (ONLY if position is ACTIVE/PARTIAL - i.e., entry was filled)
 	if position.Status == "ACTIVE" || position.Status == "PARTIAL" {
 		if position.RemainingQty > 0 {
@@ -682,12 +1762,15 @@ func (pm *PositionManager) CloseManagedPosition(ctx context.Context, positionID
 				SubmittedAt: time.Now(),
 			}
 
-			_, err := pm.tradingService.PlaceOrder(ctx, order)
+			result, err := pm.broker(position).PlaceOrder(ctx, order)
 			if err != nil {
 				// Log error but still close the position in our system
 				pm.logger.WithError(err).Error("Failed to place exit order (market may be closed)")
 				pm.logger.Info("Closing position in database despite order error")
 			} else {
+				order.ID = result.OrderID
+				exitOrderID = result.OrderID
+				pm.saveOrder(position, OrderRoleExit, order)
 				pm.logger.WithField("quantity", position.RemainingQty).Info("Placed market exit order")
 			}
 		}
@@ -696,21 +1779,31 @@ func (pm *PositionManager) CloseManagedPosition(ctx context.Context, positionID
 		pm.logger.WithField("position_id", position.ID).Info("Closed pending position (entry order was never filled)")
 	}
 
+	wasOpen := position.Status == "ACTIVE" || position.Status == "PARTIAL"
+
 	position.Status = "CLOSED"
+	position.CloseReason = reason
 	now := time.Now()
 	position.ClosedAt = &now
 
+	if wasOpen {
+		pm.recordExit(ctx, position, nil)
+	}
+
 	// Save to database
 	pm.savePositionToDB(position)
 
-	pm.logger.WithField("position_id", positionID).Info("Position manually closed")
+	pm.logger.WithFields(logrus.Fields{
+		"position_id":  positionID,
+		"close_reason": reason,
+	}).Info("Position closed")
 
-	return nil
+	return exitOrderID, nil
 }
 
 // Helper functions
 
-func (pm *PositionManager) validateRequest(req *PlaceManagedPositionRequest) error {
+func (pm *PositionManager) validateRequest(ctx context.Context, req *PlaceManagedPositionRequest) error {
 	if req.Side != "buy" && req.Side != "sell" {
 		return fmt.Errorf("side must be 'buy' or 'sell'")
 	}
@@ -719,12 +1812,114 @@ func (pm *PositionManager) validateRequest(req *PlaceManagedPositionRequest) err
 		return fmt.Errorf("entry_price required for limit orders")
 	}
 
-	if req.StopLossPrice == nil && req.StopLossPercent == nil {
-		return fmt.Errorf("either stop_loss_price or stop_loss_percent required")
+	if req.PriceReference != "" && !validPriceReference(req.PriceReference) {
+		return fmt.Errorf("price_reference must be 'bid', 'ask', 'mid' or 'last'")
+	}
+
+	if req.StopLossPrice == nil && req.StopLossPercent == nil && req.StopATRMultiple == nil {
+		return fmt.Errorf("one of stop_loss_price, stop_loss_percent or stop_atr_multiple required")
+	}
+
+	if req.TakeProfitPrice == nil && req.TakeProfitPercent == nil && req.TargetATRMultiple == nil {
+		return fmt.Errorf("one of take_profit_price, take_profit_percent or target_atr_multiple required")
+	}
+
+	assetClass := pm.assetClass(ctx, req.Symbol)
+	if assetClass == AssetClassOption {
+		return fmt.Errorf("%s is an option contract: use the managed options endpoint", req.Symbol)
+	}
+	rules := rulesFor(assetClass)
+
+	if req.EntryTimeInForce != "" && !rules.entryTimeInForces[req.EntryTimeInForce] {
+		return fmt.Errorf("invalid entry_time_in_force %q for %s: allowed %s", req.EntryTimeInForce, assetClass, timeInForceList(rules.entryTimeInForces))
+	}
+
+	if req.StopTimeInForce != "" && !rules.riskTimeInForces[req.StopTimeInForce] {
+		return fmt.Errorf("invalid stop_time_in_force %q for %s: allowed %s", req.StopTimeInForce, assetClass, timeInForceList(rules.riskTimeInForces))
+	}
+
+	if req.TargetTimeInForce != "" && !rules.riskTimeInForces[req.TargetTimeInForce] {
+		return fmt.Errorf("invalid target_time_in_force %q for %s: allowed %s", req.TargetTimeInForce, assetClass, timeInForceList(rules.riskTimeInForces))
+	}
+
+	if req.StopLossLimitOffset != nil && (*req.StopLossLimitOffset <= 0 || *req.StopLossLimitOffset >= 100) {
+		return fmt.Errorf("stop_loss_limit_offset must be between 0 and 100")
 	}
 
-	if req.TakeProfitPrice == nil && req.TakeProfitPercent == nil {
-		return fmt.Errorf("either take_profit_price or take_profit_percent required")
+	if !validTakeProfitType(req.TakeProfitOrderType) {
+		return fmt.Errorf("take_profit_order_type must be 'limit', 'stop' or 'stop_limit'")
+	}
+
+	if req.TakeProfitOrderType == TakeProfitTypeStopLimit {
+		if req.TakeProfitLimitOffset == nil || *req.TakeProfitLimitOffset <= 0 || *req.TakeProfitLimitOffset >= 100 {
+			return fmt.Errorf("take_profit_limit_offset between 0 and 100 required for a stop_limit target")
+		}
+	} else if req.TakeProfitLimitOffset != nil {
+		return fmt.Errorf("take_profit_limit_offset only applies to a stop_limit target")
+	}
+
+	if req.StopATRMultiple != nil && *req.StopATRMultiple <= 0 {
+		return fmt.Errorf("stop_atr_multiple must be positive")
+	}
+
+	if req.TargetATRMultiple != nil && *req.TargetATRMultiple <= 0 {
+		return fmt.Errorf("target_atr_multiple must be positive")
+	}
+
+	if req.SARStop && req.TrailingStop {
+		return fmt.Errorf("trailing_stop and sar_stop cannot both be enabled")
+	}
+
+	if req.PartialExit != nil && req.PartialExit.Enabled {
+		// The partial exit rests alongside the full-size stop and target, so
+		// more than the whole position would oversell into a short
+		if req.PartialExit.Percent <= 0 || req.PartialExit.Percent > 100 {
+			return fmt.Errorf("partial_exit percent must be greater than 0 and at most 100 (got %.2f)", req.PartialExit.Percent)
+		}
+		if req.PartialExit.TargetPercent > 0 && req.PartialExit.TargetPrice > 0 {
+			return fmt.Errorf("partial_exit takes one of target_percent or target_price, not both")
+		}
+		if req.PartialExit.TargetPercent <= 0 && req.PartialExit.TargetPrice <= 0 {
+			return fmt.Errorf("partial_exit requires target_percent or target_price")
+		}
+		if !validTrailingReset(req.PartialExit.TrailingReset) {
+			return fmt.Errorf("invalid partial_exit trailing_reset %q: use peak, current or breakeven", req.PartialExit.TrailingReset)
+		}
+	}
+
+	if err := checkSARParams(req.SARStep, req.SARMax); err != nil {
+		return err
+	}
+
+	if req.Side == "sell" {
+		if err := pm.checkShortable(ctx, req.Symbol); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkShortable rejects a sell that would open a short in a symbol the broker
+// can't short. Sells against an existing long position are closing and always
+// allowed; brokers without a shortability check are allowed with a warning.
+func (pm *PositionManager) checkShortable(ctx context.Context, symbol string) error {
+	positions, err := pm.tradingService.GetPositions(ctx)
+	if err == nil {
+		if intent, _ := ResolveOrderIntent(positions, symbol, "sell", 0, ""); intent == OrderIntentClose {
+			return nil
+		}
+	}
+
+	info, err := pm.assets.Get(ctx, symbol)
+	if err != nil {
+		pm.logger.WithError(err).WithField("symbol", symbol).Warn("Shortability check unavailable, allowing short sale")
+		return nil
+	}
+
+	// The broker only lends tradable, easy-to-borrow shares
+	if !info.Tradable || !info.Shortable || !info.EasyToBorrow {
+		return apperrors.Validation("%s cannot be sold short: the broker reports it as not shortable or not easy to borrow", symbol)
 	}
 
 	return nil
@@ -743,8 +1938,91 @@ func (pm *PositionManager) getCurrentPrice(ctx context.Context, symbol string) (
 	return quote.BidPrice, nil
 }
 
-func (pm *PositionManager) calculateQuantity(allocation, price float64) float64 {
-	return math.Floor(allocation / price)
+// calculateQuantity sizes a position from its allocation: whole shares for
+// equities, fractional quantities for crypto
+func (pm *PositionManager) calculateQuantity(assetClass string, allocation, price float64) float64 {
+	return floorToIncrement(allocation/price, rulesFor(assetClass).qtyIncrement)
+}
+
+// fractionalQtyIncrement is the smallest fractional share quantity the broker accepts
+const fractionalQtyIncrement = 0.000000001
+
+// normalizeOrderQty rounds a quantity down to the broker's allowed increment for
+// the order type. Fractional shares are only accepted for fractionable assets on
+// market orders and day limit orders; stops and GTC orders require whole shares.
+func normalizeOrderQty(qty float64, orderType, timeInForce string, fractionable bool) float64 {
+	increment := 1.0
+	if fractionable && (orderType == "market" || (orderType == "limit" && timeInForce == "day")) {
+		increment = fractionalQtyIncrement
+	}
+
+	return floorToIncrement(qty, increment)
+}
+
+// atrLookbackDays is how far back daily bars are fetched for ATR calculation
+const atrLookbackDays = 45
+
+// applyATRLevels derives absolute stop and target prices from ATR multiples.
+// Explicit prices or percents in the request take precedence.
+func (pm *PositionManager) applyATRLevels(ctx context.Context, req *PlaceManagedPositionRequest, entryPrice float64) error {
+	atr, err := pm.dailyATR(ctx, req.Symbol)
+	if err != nil {
+		return err
+	}
+
+	direction := 1.0
+	if req.Side == "sell" {
+		direction = -1.0
+	}
+
+	if req.StopATRMultiple != nil && req.StopLossPrice == nil && req.StopLossPercent == nil {
+		stop := entryPrice - direction*(*req.StopATRMultiple)*atr
+		if stop <= 0 {
+			return apperrors.Validation("ATR stop %.2f is not a valid price (ATR %.2f, entry %.2f)", stop, atr, entryPrice)
+		}
+		if (req.Side == "buy" && stop >= entryPrice) || (req.Side == "sell" && stop <= entryPrice) {
+			return apperrors.Validation("ATR stop %.2f is on the wrong side of entry %.2f", stop, entryPrice)
+		}
+		req.StopLossPrice = &stop
+	}
+
+	if req.TargetATRMultiple != nil && req.TakeProfitPrice == nil && req.TakeProfitPercent == nil {
+		target := entryPrice + direction*(*req.TargetATRMultiple)*atr
+		if target <= 0 {
+			return apperrors.Validation("ATR target %.2f is not a valid price (ATR %.2f, entry %.2f)", target, atr, entryPrice)
+		}
+		req.TakeProfitPrice = &target
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"symbol":      req.Symbol,
+		"atr":         atr,
+		"entry_price": entryPrice,
+	}).Info("Derived stop/target from ATR")
+
+	return nil
+}
+
+// dailyATR returns the 14-day ATR for symbol
+func (pm *PositionManager) dailyATR(ctx context.Context, symbol string) (float64, error) {
+	end := time.Now()
+	start := end.AddDate(0, 0, -atrLookbackDays)
+
+	bars, err := pm.dataService.GetHistoricalBars(ctx, symbol, start, end, "1Day")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get bars for ATR: %w", err)
+	}
+
+	required := indicatorMinBars[IndicatorATR14]
+	if len(bars) < required {
+		return 0, fmt.Errorf("insufficient data for ATR: need %d bars, have %d", required, len(bars))
+	}
+
+	atr := CalculateATR(bars, 14)
+	if atr <= 0 {
+		return 0, fmt.Errorf("ATR is zero for %s", symbol)
+	}
+	return atr, nil
 }
 
 func (pm *PositionManager) calculateStopLoss(entryPrice float64, stopPrice *float64, stopPercent *float64, side string) float64 {
@@ -771,6 +2049,38 @@ func (pm *PositionManager) calculateTakeProfit(entryPrice float64, profitPrice *
 	return entryPrice * (1 - *profitPercent/100.0)
 }
 
+// checkSARParams validates the SAR acceleration step and ceiling, either of
+// which may be 0 for its default. The ceiling is checked as it will be used,
+// so a step above the default ceiling is rejected when sar_max is omitted.
+func checkSARParams(step, max float64) error {
+	if step < 0 || step >= 1 || max < 0 || max >= 1 {
+		return fmt.Errorf("sar_step and sar_max must be between 0 and 1")
+	}
+
+	if step == 0 {
+		step = DefaultSARStep
+	}
+	if max == 0 {
+		max = DefaultSARMax
+	}
+	if max < step {
+		return fmt.Errorf("sar_max %.4g must be at least sar_step %.4g (sar_max defaults to %.2g)", max, step, DefaultSARMax)
+	}
+	return nil
+}
+
+// checkPartialExitPrice requires an absolute partial exit price to lie between
+// the entry and the profit target
+func checkPartialExitPrice(price, entryPrice, takeProfitPrice float64, side string) error {
+	if side == "buy" && (price <= entryPrice || price >= takeProfitPrice) {
+		return fmt.Errorf("partial_exit target_price %.2f must be above entry %.2f and below take profit %.2f", price, entryPrice, takeProfitPrice)
+	}
+	if side == "sell" && (price >= entryPrice || price <= takeProfitPrice) {
+		return fmt.Errorf("partial_exit target_price %.2f must be below entry %.2f and above take profit %.2f", price, entryPrice, takeProfitPrice)
+	}
+	return nil
+}
+
 func (pm *PositionManager) calculatePartialExitPrice(entryPrice, targetPercent float64, side string) float64 {
 	if side == "buy" {
 		return entryPrice * (1 + targetPercent/100.0)
@@ -799,7 +2109,7 @@ func (pm *PositionManager) loadPositionsFromDB() error {
 	loaded := 0
 	for _, dbPos := range dbPositions {
 		// Skip closed positions
-		if dbPos.Status == "CLOSED" || dbPos.Status == "STOPPED_OUT" {
+		if isTerminalStatus(dbPos.Status) {
 			continue
 		}
 
@@ -829,32 +2139,59 @@ func (pm *PositionManager) managedPositionToDB(pos *ManagedPosition) *models.DBM
 	// Convert tags to JSON
 	tagsJSON, _ := json.Marshal(pos.Tags)
 
+	// Convert journal to JSON
+	journalJSON, _ := json.Marshal(pos.Journal)
+
 	dbPos := &models.DBManagedPosition{
 		PositionID:        pos.ID,
 		Symbol:            pos.Symbol,
 		Side:              pos.Side,
 		Strategy:          pos.Strategy,
 		Quantity:          pos.Quantity,
-		EntryPrice:        pos.EntryPrice,
+		EntryPrice:        RoundPrice(pos.EntryPrice),
 		EntryOrderID:      pos.EntryOrderID,
 		EntryOrderType:    pos.EntryOrderType,
-		AllocationDollars: pos.AllocationDollars,
-		StopLossPrice:     pos.StopLossPrice,
-		StopLossPercent:   pos.StopLossPercent,
+		AllocationDollars: RoundMoney(pos.AllocationDollars),
+		StopLossPrice:     RoundPrice(pos.StopLossPrice),
+		StopLossPercent:   RoundPercent(pos.StopLossPercent),
 		StopLossOrderID:   pos.StopLossOrderID,
+		StopLossLimitOffset: pos.StopLossLimitOffset,
 		TrailingStop:      pos.TrailingStop,
 		TrailingPercent:   pos.TrailingPercent,
-		TakeProfitPrice:   pos.TakeProfitPrice,
-		TakeProfitPercent: pos.TakeProfitPercent,
+		TrailingReference: RoundPrice(pos.TrailingReference),
+		SARStop:           pos.SARStop,
+		SARStep:           pos.SARStep,
+		SARMax:            pos.SARMax,
+		TakeProfitPrice:   RoundPrice(pos.TakeProfitPrice),
+		TakeProfitPercent: RoundPercent(pos.TakeProfitPercent),
 		TakeProfitOrderID: pos.TakeProfitOrderID,
+		TakeProfitType:    pos.TakeProfitType,
+		TakeProfitLimitOffset: pos.TakeProfitLimitOffset,
 		Status:            pos.Status,
-		CurrentPrice:      pos.CurrentPrice,
-		UnrealizedPL:      pos.UnrealizedPL,
-		UnrealizedPLPC:    pos.UnrealizedPLPC,
+		CurrentPrice:      RoundPrice(pos.CurrentPrice),
+		UnrealizedPL:      RoundMoney(pos.UnrealizedPL),
+		UnrealizedPLPC:    RoundPercent(pos.UnrealizedPLPC),
 		RemainingQty:      pos.RemainingQty,
+		MaxAdverseExcursion:   RoundPercent(pos.MaxAdverseExcursion),
+		MaxFavorableExcursion: RoundPercent(pos.MaxFavorableExcursion),
 		Notes:             pos.Notes,
+		Journal:           string(journalJSON),
 		Tags:              string(tagsJSON),
+		Simulated:         pos.Simulated,
+		EntryReasoning:    pos.EntryReasoning,
+		ExitReasoning:     pos.ExitReasoning,
+		CloseReason:       pos.CloseReason,
 		PartialExitOrders: string(partialExitOrdersJSON),
+		ReduceOrderID:     pos.ReduceOrderID,
+		ReduceQty:         pos.ReduceQty,
+		EntryTimeInForce:  pos.EntryTimeInForce,
+		StopTimeInForce:   pos.StopTimeInForce,
+		TargetTimeInForce: pos.TargetTimeInForce,
+		AssetClass:          pos.AssetClass,
+		Underlying:          pos.Underlying,
+		StopBasis:           pos.StopBasis,
+		UnderlyingStopPrice: RoundPrice(pos.UnderlyingStopPrice),
+		EntryDelta:          pos.EntryDelta,
 		ClosedAt:          pos.ClosedAt,
 	}
 
@@ -862,7 +2199,9 @@ func (pm *PositionManager) managedPositionToDB(pos *ManagedPosition) *models.DBM
 		dbPos.PartialExitEnabled = pos.PartialExit.Enabled
 		dbPos.PartialExitPercent = pos.PartialExit.Percent
 		dbPos.PartialExitTargetPercent = pos.PartialExit.TargetPercent
-		dbPos.PartialExitTargetPrice = pos.PartialExit.TargetPrice
+		dbPos.PartialExitTargetPrice = RoundPrice(pos.PartialExit.TargetPrice)
+		dbPos.PartialExitBreakevenStop = pos.PartialExit.BreakevenStop
+		dbPos.PartialExitTrailingReset = pos.PartialExit.TrailingReset
 	}
 
 	return dbPos
@@ -882,6 +2221,12 @@ func (pm *PositionManager) dbToManagedPosition(dbPos *models.DBManagedPosition)
 		json.Unmarshal([]byte(dbPos.Tags), &tags)
 	}
 
+	// Parse journal from JSON
+	var journal []PositionNote
+	if dbPos.Journal != "" {
+		json.Unmarshal([]byte(dbPos.Journal), &journal)
+	}
+
 	pos := &ManagedPosition{
 		ID:                dbPos.PositionID,
 		Symbol:            dbPos.Symbol,
@@ -895,32 +2240,68 @@ func (pm *PositionManager) dbToManagedPosition(dbPos *models.DBManagedPosition)
 		StopLossPrice:     dbPos.StopLossPrice,
 		StopLossPercent:   dbPos.StopLossPercent,
 		StopLossOrderID:   dbPos.StopLossOrderID,
+		StopLossLimitOffset: dbPos.StopLossLimitOffset,
 		TrailingStop:      dbPos.TrailingStop,
 		TrailingPercent:   dbPos.TrailingPercent,
+		TrailingReference: dbPos.TrailingReference,
+		SARStop:           dbPos.SARStop,
+		SARStep:           dbPos.SARStep,
+		SARMax:            dbPos.SARMax,
 		TakeProfitPrice:   dbPos.TakeProfitPrice,
 		TakeProfitPercent: dbPos.TakeProfitPercent,
 		TakeProfitOrderID: dbPos.TakeProfitOrderID,
+		TakeProfitType:    dbPos.TakeProfitType,
+		TakeProfitLimitOffset: dbPos.TakeProfitLimitOffset,
 		Status:            dbPos.Status,
 		CurrentPrice:      dbPos.CurrentPrice,
 		UnrealizedPL:      dbPos.UnrealizedPL,
 		UnrealizedPLPC:    dbPos.UnrealizedPLPC,
 		RemainingQty:      dbPos.RemainingQty,
+		MaxAdverseExcursion:   dbPos.MaxAdverseExcursion,
+		MaxFavorableExcursion: dbPos.MaxFavorableExcursion,
 		Notes:             dbPos.Notes,
+		Journal:           journal,
 		Tags:              tags,
+		Simulated:         dbPos.Simulated,
+		EntryReasoning:    dbPos.EntryReasoning,
+		ExitReasoning:     dbPos.ExitReasoning,
+		CloseReason:       dbPos.CloseReason,
 		PartialExitOrders: partialExitOrders,
+		ReduceOrderID:     dbPos.ReduceOrderID,
+		ReduceQty:         dbPos.ReduceQty,
+		EntryTimeInForce:  defaultString(dbPos.EntryTimeInForce, "gtc"),
+		StopTimeInForce:   defaultString(dbPos.StopTimeInForce, "gtc"),
+		TargetTimeInForce: defaultString(dbPos.TargetTimeInForce, "gtc"),
+		AssetClass:          dbPos.AssetClass,
+		Underlying:          dbPos.Underlying,
+		StopBasis:           dbPos.StopBasis,
+		UnderlyingStopPrice: dbPos.UnderlyingStopPrice,
+		EntryDelta:          dbPos.EntryDelta,
 		CreatedAt:         dbPos.CreatedAt,
 		UpdatedAt:         dbPos.UpdatedAt,
 		ClosedAt:          dbPos.ClosedAt,
 	}
 
+	pos.riskOrdersPlaced = pos.StopLossOrderID != "" || pos.TakeProfitOrderID != ""
+
 	if dbPos.PartialExitEnabled {
 		pos.PartialExit = &PartialExitConfig{
 			Enabled:       dbPos.PartialExitEnabled,
 			Percent:       dbPos.PartialExitPercent,
 			TargetPercent: dbPos.PartialExitTargetPercent,
 			TargetPrice:   dbPos.PartialExitTargetPrice,
+			BreakevenStop: dbPos.PartialExitBreakevenStop,
+			TrailingReset: dbPos.PartialExitTrailingReset,
 		}
 	}
 
 	return pos
 }
+
+// defaultString returns value, or fallback when value is empty
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
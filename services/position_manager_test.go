@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prophet-trader/interfaces"
+)
+
+func TestPartialExitFillResizesRiskOrders(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeTrading()
+	pm := newTestPositionManager(t, broker)
+
+	// A fractional market entry: gtc stop and limit orders take whole shares
+	position := addActivePosition(t, pm, 10.5)
+	position.PartialExit = &PartialExitConfig{Enabled: true, Percent: 50, TargetPrice: 105, BreakevenStop: true}
+	if err := pm.placePartialExitOrder(ctx, position); err != nil {
+		t.Fatalf("placePartialExitOrder: %v", err)
+	}
+	oldStop, oldTarget := position.StopLossOrderID, position.TakeProfitOrderID
+	partialID := position.PartialExitOrders[0]
+
+	if order, _ := broker.GetOrder(ctx, oldStop); order.Qty != 10 {
+		t.Fatalf("initial stop qty = %v, want 10", order.Qty)
+	}
+	if order, _ := broker.GetOrder(ctx, partialID); order.Qty != 5 {
+		t.Fatalf("partial exit qty = %v, want 5", order.Qty)
+	}
+
+	broker.fill(partialID, 5, 105, "filled")
+	pm.manageRiskOrders(ctx, position)
+
+	if position.RemainingQty != 5.5 || position.Status != "PARTIAL" {
+		t.Errorf("remaining %v status %s, want 5.5 PARTIAL", position.RemainingQty, position.Status)
+	}
+	for _, id := range []string{oldStop, oldTarget} {
+		if order, _ := broker.GetOrder(ctx, id); order.Status != "canceled" {
+			t.Errorf("old order %s status = %s, want canceled", id, order.Status)
+		}
+	}
+
+	stops := broker.open("stop")
+	if len(stops) != 1 {
+		t.Fatalf("open stops = %d, want 1", len(stops))
+	}
+	if stops[0].ID != position.StopLossOrderID {
+		t.Errorf("tracked stop %s, open stop %s", position.StopLossOrderID, stops[0].ID)
+	}
+	if stops[0].Qty != 5 {
+		t.Errorf("new stop qty = %v, want 5 (5.5 floored to whole shares)", stops[0].Qty)
+	}
+	if *stops[0].StopPrice != 100 {
+		t.Errorf("new stop price = %v, want breakeven 100", *stops[0].StopPrice)
+	}
+
+	targets := broker.open("limit")
+	if len(targets) != 1 || targets[0].Qty != 5 {
+		t.Errorf("open targets = %+v, want one for 5 shares", targets)
+	}
+
+	// The fill is applied once
+	pm.manageRiskOrders(ctx, position)
+	if position.RemainingQty != 5.5 {
+		t.Errorf("remaining qty after second check = %v, want 5.5", position.RemainingQty)
+	}
+}
+
+func TestCheckSARParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		step    float64
+		max     float64
+		wantErr bool
+	}{
+		{"defaults", 0, 0, false},
+		{"custom step under default max", 0.05, 0, false},
+		{"step above default max", 0.3, 0, true},
+		{"step above explicit max", 0.1, 0.05, true},
+		{"max below default step", 0, 0.01, true},
+		{"step equals max", 0.2, 0.2, false},
+		{"negative step", -0.02, 0, true},
+		{"max out of range", 0.02, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSARParams(tt.step, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkSARParams(%v, %v) = %v, want error %v", tt.step, tt.max, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMoveTrailingStopRestoresStopWhenPlaceFails(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeTrading()
+	pm := newTestPositionManager(t, broker)
+	position := addActivePosition(t, pm, 10)
+	oldStop := position.StopLossOrderID
+
+	// The old stop cancels, then the new stop is rejected
+	broker.failNext(1)
+	pm.moveTrailingStop(ctx, position, 98, "Trailing stop raised")
+
+	if order, _ := broker.GetOrder(ctx, oldStop); order.Status != "canceled" {
+		t.Errorf("old stop status = %s, want canceled", order.Status)
+	}
+	if position.StopLossPrice != 95 {
+		t.Errorf("stop price = %v, want 95 kept", position.StopLossPrice)
+	}
+	stops := broker.open("stop")
+	if len(stops) != 1 || *stops[0].StopPrice != 95 || stops[0].ID != position.StopLossOrderID {
+		t.Fatalf("open stops = %+v, want the 95 stop restored and tracked", stops)
+	}
+
+	// The next cycle moves it
+	pm.moveTrailingStop(ctx, position, 98, "Trailing stop raised")
+	if stops := broker.open("stop"); len(stops) != 1 || *stops[0].StopPrice != 98 || position.StopLossPrice != 98 {
+		t.Errorf("open stops = %+v, stop price %v, want one stop at 98", stops, position.StopLossPrice)
+	}
+}
+
+func TestMoveTrailingStopKeepsPriceWhenRestoreFails(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeTrading()
+	pm := newTestPositionManager(t, broker)
+	position := addActivePosition(t, pm, 10)
+
+	// Both the new stop and the restored old stop are rejected
+	broker.failNext(2)
+	pm.moveTrailingStop(ctx, position, 98, "Trailing stop raised")
+
+	if position.StopLossPrice != 95 {
+		t.Errorf("stop price = %v, want 95: the move never took effect", position.StopLossPrice)
+	}
+	if position.StopLossOrderID != "" {
+		t.Errorf("stop order ID = %q, want cleared with no live stop", position.StopLossOrderID)
+	}
+}
+
+func TestMonitorSkipsPositionBeingUpdated(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeTrading()
+	pm := newTestPositionManager(t, broker)
+
+	result, _ := broker.PlaceOrder(ctx, &interfaces.Order{Symbol: "AAPL", Qty: 10, Side: "buy", Type: "limit"})
+	broker.CancelOrder(ctx, result.OrderID)
+	position := &ManagedPosition{ID: "POS-PENDING", Symbol: "AAPL", Side: "buy", Status: "PENDING", EntryOrderID: result.OrderID, CreatedAt: time.Now()}
+	pm.positions[position.ID] = position
+
+	// An API update holds the position: the monitor leaves it alone
+	lock := pm.positionLock(position.ID)
+	lock.Lock()
+	pm.checkPositions(ctx)
+	if position.Status != "PENDING" {
+		t.Errorf("status = %s while locked, want PENDING", position.Status)
+	}
+	lock.Unlock()
+
+	pm.checkPositions(ctx)
+	if position.Status != "CANCELED" {
+		t.Errorf("status = %s after the update, want CANCELED", position.Status)
+	}
+}
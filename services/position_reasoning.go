@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"prophet-trader/models"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// reasoningCacheTTL is how long generated reasoning is reused for the same symbol and action
+const reasoningCacheTTL = 10 * time.Minute
+
+// tradeReasoner generates short trade reasoning from stock analysis via Gemini
+type tradeReasoner struct {
+	stockAnalysisService *StockAnalysisService
+	geminiService        *GeminiService
+
+	cache map[string]cachedReasoning // symbol|action -> reasoning
+	mu    sync.Mutex
+}
+
+type cachedReasoning struct {
+	text        string
+	generatedAt time.Time
+}
+
+// SetActivityLogger records position opens and closes in the activity log
+func (pm *PositionManager) SetActivityLogger(activityLogger *ActivityLogger) {
+	pm.activityLogger = activityLogger
+}
+
+// SetFeeModel sets the fee model used for net P&L on recorded trades
+func (pm *PositionManager) SetFeeModel(fees FeeModel) {
+	pm.fees = fees
+}
+
+// SetTradeReasoning enables Gemini-generated reasoning at entry and exit.
+// Passing a nil service disables it. Generation is synchronous, so it adds
+// one Gemini call (cached per symbol for a short window) to each entry/exit.
+func (pm *PositionManager) SetTradeReasoning(stockAnalysisService *StockAnalysisService, geminiService *GeminiService) {
+	if stockAnalysisService == nil || geminiService == nil {
+		pm.reasoning = nil
+		return
+	}
+
+	pm.reasoning = &tradeReasoner{
+		stockAnalysisService: stockAnalysisService,
+		geminiService:        geminiService,
+		cache:                make(map[string]cachedReasoning),
+	}
+}
+
+// generateReasoning returns Gemini reasoning for an entry or exit, or "" if disabled or failed
+func (pm *PositionManager) generateReasoning(ctx context.Context, position *ManagedPosition, action string) string {
+	if pm.reasoning == nil {
+		return ""
+	}
+
+	key := position.Symbol + "|" + action
+
+	pm.reasoning.mu.Lock()
+	if cached, ok := pm.reasoning.cache[key]; ok && time.Since(cached.generatedAt) < reasoningCacheTTL {
+		pm.reasoning.mu.Unlock()
+		return cached.text
+	}
+	pm.reasoning.mu.Unlock()
+
+	analysis, err := pm.reasoning.stockAnalysisService.AnalyzeStock(ctx, position.Symbol)
+	if err != nil {
+		pm.logger.WithError(err).WithField("symbol", position.Symbol).Warn("Failed to analyze stock for trade reasoning")
+		return ""
+	}
+
+	text, err := pm.reasoning.geminiService.GenerateTradeReasoning(action, position.Side, analysis)
+	if err != nil {
+		pm.logger.WithError(err).WithField("symbol", position.Symbol).Warn("Failed to generate trade reasoning")
+		return ""
+	}
+
+	pm.reasoning.mu.Lock()
+	pm.reasoning.cache[key] = cachedReasoning{text: text, generatedAt: time.Now()}
+	pm.reasoning.mu.Unlock()
+
+	return text
+}
+
+// logPositionOpened records a filled entry in the activity log
+func (pm *PositionManager) logPositionOpened(position *ManagedPosition) {
+	if pm.activityLogger == nil {
+		return
+	}
+
+	reasoning := position.Notes
+	if reasoning == "" {
+		reasoning = position.EntryReasoning
+	}
+
+	if err := pm.activityLogger.LogPositionOpened(
+		position.Symbol,
+		position.Side,
+		position.Strategy,
+		position.Quantity,
+		position.EntryPrice,
+		position.AllocationDollars,
+		position.StopLossPrice,
+		position.TakeProfitPrice,
+		0,
+		reasoning,
+		position.Tags,
+	); err != nil {
+		pm.logger.WithError(err).Warn("Failed to log position opened")
+	}
+}
+
+// recordExit generates exit reasoning and records the close in the activity log.
+// exitPrice falls back to the last known price when the fill price is unknown.
+func (pm *PositionManager) recordExit(ctx context.Context, position *ManagedPosition, exitPrice *float64) {
+	position.ExitReasoning = pm.generateReasoning(ctx, position, "EXIT")
+
+	price := position.CurrentPrice
+	if exitPrice != nil {
+		price = *exitPrice
+	}
+
+	pm.saveTrade(position, price)
+
+	if pm.activityLogger == nil {
+		return
+	}
+
+	holdDays := int(time.Since(position.CreatedAt).Hours() / 24)
+
+	if err := pm.activityLogger.LogPositionClosed(
+		position.Symbol,
+		position.Side,
+		position.Strategy,
+		position.RemainingQty,
+		position.EntryPrice,
+		price,
+		position.AllocationDollars,
+		holdDays,
+		position.ExitReasoning,
+		position.Tags,
+	); err != nil {
+		pm.logger.WithError(err).WithFields(logrus.Fields{
+			"position_id": position.ID,
+		}).Warn("Failed to log position closed")
+	}
+}
+
+// saveTrade writes the closed position to the trades table with gross and net P&L
+func (pm *PositionManager) saveTrade(position *ManagedPosition, exitPrice float64) {
+	qty := position.RemainingQty
+	multiplier := contractMultiplier(position.Symbol)
+	direction := 1.0
+	if position.Side == "sell" {
+		direction = -1.0
+	}
+
+	grossPnL := RoundMoney(direction * (exitPrice - position.EntryPrice) * qty * multiplier)
+	fees := RoundMoney(pm.fees.RoundTripFees(position.Symbol, qty, position.EntryPrice, exitPrice))
+	pnl := RoundMoney(grossPnL - fees)
+
+	pnlPercent := 0.0
+	if position.EntryPrice > 0 && qty > 0 {
+		pnlPercent = RoundPercent(pnl / (position.EntryPrice * qty * multiplier) * 100)
+	}
+
+	exitTime := time.Now()
+	if position.ClosedAt != nil {
+		exitTime = *position.ClosedAt
+	}
+
+	trade := &models.DBTrade{
+		Symbol:       position.Symbol,
+		EntryPrice:   RoundPrice(position.EntryPrice),
+		ExitPrice:    RoundPrice(exitPrice),
+		Qty:          qty,
+		Side:         position.Side,
+		PnL:          pnl,
+		PnLPercent:   pnlPercent,
+		GrossPnL:     grossPnL,
+		Fees:         fees,
+		MAEPercent:   RoundPercent(position.MaxAdverseExcursion),
+		MFEPercent:   RoundPercent(position.MaxFavorableExcursion),
+		Simulated:    position.Simulated,
+		EntryTime:    position.CreatedAt,
+		ExitTime:     exitTime,
+		Duration:     int64(exitTime.Sub(position.CreatedAt).Seconds()),
+		StrategyName: position.Strategy,
+		CloseReason:  position.CloseReason,
+	}
+	if metadata, err := json.Marshal(map[string]string{"position_id": position.ID}); err == nil {
+		trade.Metadata = string(metadata)
+	}
+
+	if err := pm.storageService.SaveTrade(trade); err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Warn("Failed to save trade")
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReduceManagedPositionAppliesOnFill(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeTrading()
+	pm := newTestPositionManager(t, broker)
+	position := addActivePosition(t, pm, 10)
+	oldStop := position.StopLossOrderID
+
+	if _, err := pm.ReduceManagedPosition(ctx, position.ID, &ReduceManagedPositionRequest{Quantity: 4}); err != nil {
+		t.Fatalf("ReduceManagedPosition: %v", err)
+	}
+
+	// Nothing changes until the reduce fills
+	if position.RemainingQty != 10 {
+		t.Errorf("remaining qty before fill = %v, want 10", position.RemainingQty)
+	}
+	if position.ReduceOrderID == "" || position.ReduceQty != 4 {
+		t.Fatalf("reduce not tracked: order %q qty %v", position.ReduceOrderID, position.ReduceQty)
+	}
+	if order, _ := broker.GetOrder(ctx, oldStop); order.Status != "canceled" {
+		t.Errorf("old stop status = %s, want canceled", order.Status)
+	}
+	stops := broker.open("stop")
+	if len(stops) != 1 || stops[0].Qty != 6 {
+		t.Fatalf("open stops = %+v, want one for 6 shares", stops)
+	}
+
+	// A second reduce is refused while one is pending
+	if _, err := pm.ReduceManagedPosition(ctx, position.ID, &ReduceManagedPositionRequest{Quantity: 1}); err == nil {
+		t.Error("second reduce accepted while the first is pending")
+	}
+
+	broker.fill(position.ReduceOrderID, 4, 101, "filled")
+	pm.manageRiskOrders(ctx, position)
+
+	if position.RemainingQty != 6 || position.Status != "PARTIAL" {
+		t.Errorf("after fill: remaining %v status %s, want 6 PARTIAL", position.RemainingQty, position.Status)
+	}
+	if position.ReduceOrderID != "" || position.ReduceQty != 0 {
+		t.Errorf("reduce still tracked after fill: %q %v", position.ReduceOrderID, position.ReduceQty)
+	}
+	if stops := broker.open("stop"); len(stops) != 1 || stops[0].Qty != 6 {
+		t.Errorf("open stops after fill = %+v, want one for 6 shares", stops)
+	}
+
+	// The fill is applied once
+	pm.manageRiskOrders(ctx, position)
+	if position.RemainingQty != 6 {
+		t.Errorf("remaining qty after second check = %v, want 6", position.RemainingQty)
+	}
+}
+
+func TestReduceManagedPositionPartialFillResizes(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeTrading()
+	pm := newTestPositionManager(t, broker)
+	position := addActivePosition(t, pm, 10)
+
+	limit := 102.0
+	if _, err := pm.ReduceManagedPosition(ctx, position.ID, &ReduceManagedPositionRequest{Quantity: 4, OrderType: "limit", LimitPrice: &limit}); err != nil {
+		t.Fatalf("ReduceManagedPosition: %v", err)
+	}
+
+	// Only 1 of 4 shares fills before the order expires
+	broker.fill(position.ReduceOrderID, 1, 102, "expired")
+	pm.manageRiskOrders(ctx, position)
+
+	if position.RemainingQty != 9 {
+		t.Errorf("remaining qty = %v, want 9", position.RemainingQty)
+	}
+	if stops := broker.open("stop"); len(stops) != 1 || stops[0].Qty != 9 {
+		t.Errorf("open stops = %+v, want one for 9 shares", stops)
+	}
+	if targets := broker.open("limit"); len(targets) != 1 || targets[0].Qty != 9 {
+		t.Errorf("open targets = %+v, want one for 9 shares", targets)
+	}
+}
+
+func TestReduceManagedPositionFullReduceCloses(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeTrading()
+	pm := newTestPositionManager(t, broker)
+	position := addActivePosition(t, pm, 10)
+
+	if _, err := pm.ReduceManagedPosition(ctx, position.ID, &ReduceManagedPositionRequest{Percent: 100}); err != nil {
+		t.Fatalf("ReduceManagedPosition: %v", err)
+	}
+	if n := len(broker.open("stop")) + len(broker.open("limit")); n != 0 {
+		t.Errorf("%d risk orders left open for a full reduce", n)
+	}
+
+	broker.fill(position.ReduceOrderID, 10, 99, "filled")
+	pm.manageRiskOrders(ctx, position)
+
+	if position.Status != "CLOSED" || position.CloseReason != CloseReasonManual {
+		t.Errorf("status %s reason %s, want CLOSED %s", position.Status, position.CloseReason, CloseReasonManual)
+	}
+	if position.RemainingQty != 0 {
+		t.Errorf("remaining qty = %v, want 0", position.RemainingQty)
+	}
+}
+
+func TestReduceManagedPositionKeepsStopWhenCancelFails(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeTrading()
+	pm := newTestPositionManager(t, broker)
+	position := addActivePosition(t, pm, 10)
+	stopID := position.StopLossOrderID
+
+	// A stop that filled can't be cancelled
+	broker.fill(stopID, 10, 95, "filled")
+
+	if _, err := pm.ReduceManagedPosition(ctx, position.ID, &ReduceManagedPositionRequest{Quantity: 4}); err == nil {
+		t.Fatal("reduce accepted although the stop could not be cancelled")
+	}
+	if position.StopLossOrderID != stopID {
+		t.Errorf("stop order ID = %q, want %q kept", position.StopLossOrderID, stopID)
+	}
+	if position.ReduceOrderID != "" {
+		t.Errorf("reduce order placed: %q", position.ReduceOrderID)
+	}
+}
+
+func TestReduceManagedPositionWaitsForPositionLock(t *testing.T) {
+	ctx := context.Background()
+	broker := newFakeTrading()
+	pm := newTestPositionManager(t, broker)
+	position := addActivePosition(t, pm, 10)
+
+	// Another update holds the position, so the reduce waits for it
+	lock := pm.positionLock(position.ID)
+	lock.Lock()
+	done := make(chan error, 1)
+	go func() {
+		_, err := pm.ReduceManagedPosition(ctx, position.ID, &ReduceManagedPositionRequest{Quantity: 4})
+		done <- err
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("reduce ran while the position was locked")
+	case <-time.After(50 * time.Millisecond):
+	}
+	lock.Unlock()
+
+	if err := <-done; err != nil {
+		t.Fatalf("ReduceManagedPosition: %v", err)
+	}
+	if stops := broker.open("stop"); len(stops) != 1 || stops[0].Qty != 6 {
+		t.Errorf("open stops = %+v, want one for 6 shares", stops)
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"math"
+	"time"
+)
+
+// ManagedPositionResponse is a managed position with read-only computed fields
+// for API consumers. The computed fields are never stored.
+type ManagedPositionResponse struct {
+	*ManagedPosition
+	Computed PositionComputed `json:"computed"`
+}
+
+// PositionComputed holds values derived from a managed position's raw fields
+type PositionComputed struct {
+	PercentFilled       float64  `json:"percent_filled"` // entry fill progress
+	PercentExited       float64  `json:"percent_exited"` // share of the position already closed
+	TimeInTradeSeconds  int64    `json:"time_in_trade_seconds"`
+	TimeInTrade         string   `json:"time_in_trade"`
+	DistanceToStopPct   float64  `json:"distance_to_stop_percent"`   // how far price can move against the position before the stop
+	DistanceToTargetPct float64  `json:"distance_to_target_percent"` // how far price must move to reach the target
+	RiskRewardRemaining *float64 `json:"risk_reward_remaining,omitempty"`
+}
+
+// ToResponse builds the API response for a managed position. Money and price
+// fields are rounded on a copy so float noise never reaches API consumers.
+func (p *ManagedPosition) ToResponse() *ManagedPositionResponse {
+	computed := PositionComputed{}
+
+	if p.Status != "PENDING" {
+		computed.PercentFilled = 100
+	}
+
+	if p.Quantity > 0 && p.Status != "PENDING" {
+		computed.PercentExited = math.Max(p.Quantity-p.RemainingQty, 0) / p.Quantity * 100
+	}
+
+	end := time.Now()
+	if p.ClosedAt != nil {
+		end = *p.ClosedAt
+	}
+	inTrade := end.Sub(p.CreatedAt).Truncate(time.Second)
+	computed.TimeInTradeSeconds = int64(inTrade.Seconds())
+	computed.TimeInTrade = inTrade.String()
+
+	if p.CurrentPrice > 0 {
+		if p.Side == "sell" {
+			computed.DistanceToStopPct = (p.StopLossPrice - p.CurrentPrice) / p.CurrentPrice * 100
+			computed.DistanceToTargetPct = (p.CurrentPrice - p.TakeProfitPrice) / p.CurrentPrice * 100
+		} else {
+			computed.DistanceToStopPct = (p.CurrentPrice - p.StopLossPrice) / p.CurrentPrice * 100
+			computed.DistanceToTargetPct = (p.TakeProfitPrice - p.CurrentPrice) / p.CurrentPrice * 100
+		}
+
+		if computed.DistanceToStopPct > 0 && computed.DistanceToTargetPct > 0 {
+			ratio := roundTo(computed.DistanceToTargetPct/computed.DistanceToStopPct, 2)
+			computed.RiskRewardRemaining = &ratio
+		}
+	}
+
+	computed.PercentExited = RoundPercent(computed.PercentExited)
+	computed.DistanceToStopPct = RoundPercent(computed.DistanceToStopPct)
+	computed.DistanceToTargetPct = RoundPercent(computed.DistanceToTargetPct)
+
+	return &ManagedPositionResponse{
+		ManagedPosition: p.rounded(),
+		Computed:        computed,
+	}
+}
+
+// rounded returns a copy of the position with money, price and percent fields rounded
+func (p *ManagedPosition) rounded() *ManagedPosition {
+	r := *p
+	r.EntryPrice = RoundPrice(r.EntryPrice)
+	r.StopLossPrice = RoundPrice(r.StopLossPrice)
+	r.TakeProfitPrice = RoundPrice(r.TakeProfitPrice)
+	r.CurrentPrice = RoundPrice(r.CurrentPrice)
+	r.AllocationDollars = RoundMoney(r.AllocationDollars)
+	r.UnrealizedPL = RoundMoney(r.UnrealizedPL)
+	r.UnrealizedPLPC = RoundPercent(r.UnrealizedPLPC)
+	r.MaxAdverseExcursion = RoundPercent(r.MaxAdverseExcursion)
+	r.MaxFavorableExcursion = RoundPercent(r.MaxFavorableExcursion)
+	r.StopLossPercent = RoundPercent(r.StopLossPercent)
+	r.TakeProfitPercent = RoundPercent(r.TakeProfitPercent)
+	if r.PartialExit != nil {
+		partial := *r.PartialExit
+		partial.TargetPrice = RoundPrice(partial.TargetPrice)
+		r.PartialExit = &partial
+	}
+	return &r
+}
+
+// ToPositionResponses builds API responses for a list of managed positions
+func ToPositionResponses(positions []*ManagedPosition) []*ManagedPositionResponse {
+	responses := make([]*ManagedPositionResponse, len(positions))
+	for i, position := range positions {
+		responses[i] = position.ToResponse()
+	}
+	return responses
+}
+
+// ManagedPositionCompact is the essential subset of a managed position:
+// what it holds, its risk levels and its current P&L
+type ManagedPositionCompact struct {
+	ID              string  `json:"id"`
+	Symbol          string  `json:"symbol"`
+	Side            string  `json:"side"`
+	Strategy        string  `json:"strategy"`
+	Status          string  `json:"status"`
+	Quantity        float64 `json:"quantity"`
+	RemainingQty    float64 `json:"remaining_qty"`
+	EntryPrice      float64 `json:"entry_price"`
+	CurrentPrice    float64 `json:"current_price"`
+	StopLossPrice   float64 `json:"stop_loss_price"`
+	TakeProfitPrice float64 `json:"take_profit_price"`
+	UnrealizedPL    float64 `json:"unrealized_pl"`
+	UnrealizedPLPC  float64 `json:"unrealized_pl_percent"`
+	Simulated       bool    `json:"simulated,omitempty"`
+}
+
+// ToCompact converts a managed position to a compact version, rounded like ToResponse
+func (p *ManagedPosition) ToCompact() ManagedPositionCompact {
+	r := p.rounded()
+	return ManagedPositionCompact{
+		ID:              r.ID,
+		Symbol:          r.Symbol,
+		Side:            r.Side,
+		Strategy:        r.Strategy,
+		Status:          r.Status,
+		Quantity:        r.Quantity,
+		RemainingQty:    r.RemainingQty,
+		EntryPrice:      r.EntryPrice,
+		CurrentPrice:    r.CurrentPrice,
+		StopLossPrice:   r.StopLossPrice,
+		TakeProfitPrice: r.TakeProfitPrice,
+		UnrealizedPL:    r.UnrealizedPL,
+		UnrealizedPLPC:  r.UnrealizedPLPC,
+		Simulated:       r.Simulated,
+	}
+}
+
+// ToCompactPositions builds compact responses for a list of managed positions
+func ToCompactPositions(positions []*ManagedPosition) []ManagedPositionCompact {
+	compact := make([]ManagedPositionCompact, len(positions))
+	for i, position := range positions {
+		compact[i] = position.ToCompact()
+	}
+	return compact
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"prophet-trader/apperrors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reversal waits this long, polling at reversePollInterval, for the close to
+// fill before giving up on the new entry
+const (
+	reverseFillTimeout  = 30 * time.Second
+	reversePollInterval = 500 * time.Millisecond
+)
+
+// CloseAndReverse closes an open position at market and, once the close has
+// filled, opens newReq on the opposite side of the same symbol. The new entry
+// is never placed while the close is unconfirmed: if the exit order is not
+// filled in time the position stays closed and no reversal is opened.
+func (pm *PositionManager) CloseAndReverse(ctx context.Context, positionID string, newReq *PlaceManagedPositionRequest) (closed *ManagedPosition, opened *ManagedPosition, err error) {
+	position, err := pm.GetManagedPosition(positionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if position.Status != "ACTIVE" && position.Status != "PARTIAL" {
+		return nil, nil, apperrors.Validation("position %s is %s - only open positions can be reversed", positionID, position.Status)
+	}
+	if position.AssetClass == AssetClassOption {
+		return nil, nil, apperrors.Validation("position %s holds options - reversal is only supported for stocks", positionID)
+	}
+
+	// The reversal inherits the symbol and flips the side
+	oppositeSide := "sell"
+	if position.Side == "sell" {
+		oppositeSide = "buy"
+	}
+	if newReq.Symbol != "" && !strings.EqualFold(newReq.Symbol, position.Symbol) {
+		return nil, nil, apperrors.Validation("reversal symbol %s does not match position symbol %s", newReq.Symbol, position.Symbol)
+	}
+	if newReq.Side != "" && newReq.Side != oppositeSide {
+		return nil, nil, apperrors.Validation("reversal of a %s position must be a %s", position.Side, oppositeSide)
+	}
+	newReq.Symbol = position.Symbol
+	newReq.Side = oppositeSide
+	newReq.DryRun = position.Simulated
+
+	// Reject a bad reversal before anything is closed
+	if newReq.AllocationDollars <= 0 {
+		return nil, nil, apperrors.Validation("invalid request: allocation_dollars must be positive")
+	}
+	if err := pm.validateRequest(ctx, newReq); err != nil {
+		return nil, nil, apperrors.Validation("invalid request: %w", err)
+	}
+	if !IsMarketOpen(time.Now()) {
+		return nil, nil, apperrors.Validation("market is closed - the close would not fill before the reversal")
+	}
+
+	exitOrderID, err := pm.closeManagedPosition(ctx, positionID, CloseReasonReversed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to close position: %w", err)
+	}
+	if exitOrderID == "" {
+		return position, nil, fmt.Errorf("position closed but no exit order was placed - reversal not opened")
+	}
+
+	if err := pm.waitForFill(ctx, position, exitOrderID); err != nil {
+		return position, nil, fmt.Errorf("reversal not opened: %w", err)
+	}
+
+	opened, err = pm.PlaceManagedPosition(ctx, newReq)
+	if err != nil {
+		return position, nil, fmt.Errorf("position closed but reversal failed: %w", err)
+	}
+
+	pm.logPositionEvent(position, "REVERSED", fmt.Sprintf("Reversed into %s position %s", opened.Side, opened.ID), map[string]interface{}{
+		"exit_order_id":   exitOrderID,
+		"new_position_id": opened.ID,
+	})
+
+	pm.logger.WithFields(logrus.Fields{
+		"closed_position": position.ID,
+		"new_position":    opened.ID,
+		"symbol":          position.Symbol,
+		"new_side":        opened.Side,
+	}).Info("Position reversed")
+
+	return position, opened, nil
+}
+
+// waitForFill polls an order until it fills, failing if it ends unfilled or
+// doesn't fill within reverseFillTimeout
+func (pm *PositionManager) waitForFill(ctx context.Context, position *ManagedPosition, orderID string) error {
+	deadline := time.Now().Add(reverseFillTimeout)
+
+	for {
+		order, err := pm.broker(position).GetOrder(ctx, orderID)
+		if err == nil {
+			switch order.Status {
+			case "filled":
+				return nil
+			case "canceled", "expired", "rejected":
+				return apperrors.Broker("exit order %s %s", orderID, order.Status)
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("exit order %s not filled within %s", orderID, reverseFillTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(reversePollInterval):
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import "sort"
+
+// RiskSummary aggregates the open risk across managed positions
+type RiskSummary struct {
+	OpenPositions     int            `json:"open_positions"`
+	PendingPositions  int            `json:"pending_positions"`
+	TotalMarketValue  float64        `json:"total_market_value"`
+	TotalUnrealizedPL float64        `json:"total_unrealized_pl"`
+	TotalRiskToStop   float64        `json:"total_risk_to_stop"` // loss if every stop fills at its price
+	Positions         []PositionRisk `json:"positions"`
+}
+
+// PositionRisk is the open risk of a single managed position
+type PositionRisk struct {
+	ID            string  `json:"id"`
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`
+	Quantity      float64 `json:"quantity"`
+	CurrentPrice  float64 `json:"current_price"`
+	StopLossPrice float64 `json:"stop_loss_price"`
+	MarketValue   float64 `json:"market_value"`
+	UnrealizedPL  float64 `json:"unrealized_pl"`
+	RiskToStop    float64 `json:"risk_to_stop"`
+	RiskToStopPct float64 `json:"risk_to_stop_percent"` // of market value
+}
+
+// GetRiskSummary summarizes the open risk of active and partially exited
+// positions, largest risk first. Pending entries are only counted.
+func (pm *PositionManager) GetRiskSummary() *RiskSummary {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+
+	summary := &RiskSummary{Positions: make([]PositionRisk, 0)}
+
+	for _, pos := range pm.positions {
+		switch pos.Status {
+		case "PENDING":
+			summary.PendingPositions++
+			continue
+		case "ACTIVE", "PARTIAL":
+		default:
+			continue
+		}
+
+		price := pos.CurrentPrice
+		if price <= 0 {
+			price = pos.EntryPrice
+		}
+
+		perShare := price - pos.StopLossPrice
+		if pos.Side == "sell" {
+			perShare = pos.StopLossPrice - price
+		}
+
+		risk := PositionRisk{
+			ID:            pos.ID,
+			Symbol:        pos.Symbol,
+			Side:          pos.Side,
+			Quantity:      pos.RemainingQty,
+			CurrentPrice:  RoundPrice(price),
+			StopLossPrice: RoundPrice(pos.StopLossPrice),
+			MarketValue:   RoundMoney(price * pos.RemainingQty * contractMultiplier(pos.Symbol)),
+			UnrealizedPL:  RoundMoney(pos.UnrealizedPL),
+		}
+		// No stop, or one the price has already crossed, has no distance left to lose
+		if pos.StopLossPrice > 0 && perShare > 0 {
+			risk.RiskToStop = RoundMoney(perShare * pos.RemainingQty * contractMultiplier(pos.Symbol))
+		}
+		if risk.MarketValue > 0 {
+			risk.RiskToStopPct = RoundPercent(risk.RiskToStop / risk.MarketValue * 100)
+		}
+
+		summary.OpenPositions++
+		summary.TotalMarketValue += risk.MarketValue
+		summary.TotalUnrealizedPL += risk.UnrealizedPL
+		summary.TotalRiskToStop += risk.RiskToStop
+		summary.Positions = append(summary.Positions, risk)
+	}
+
+	sort.Slice(summary.Positions, func(i, j int) bool {
+		return summary.Positions[i].RiskToStop > summary.Positions[j].RiskToStop
+	})
+
+	summary.TotalMarketValue = RoundMoney(summary.TotalMarketValue)
+	summary.TotalUnrealizedPL = RoundMoney(summary.TotalUnrealizedPL)
+	summary.TotalRiskToStop = RoundMoney(summary.TotalRiskToStop)
+
+	return summary
+}
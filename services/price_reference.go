@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+)
+
+// Reference prices an entry's quantity, stop and target can be computed from
+const (
+	PriceReferenceBid  = "bid"
+	PriceReferenceAsk  = "ask"
+	PriceReferenceMid  = "mid"
+	PriceReferenceLast = "last"
+)
+
+// DefaultPriceReference is used when neither the request nor the config picks one
+const DefaultPriceReference = PriceReferenceMid
+
+func validPriceReference(ref string) bool {
+	switch ref {
+	case PriceReferenceBid, PriceReferenceAsk, PriceReferenceMid, PriceReferenceLast:
+		return true
+	}
+	return false
+}
+
+// SetPriceReference sets the default reference price for new positions
+// without an entry price: bid, ask, mid or last (trade). The ask overstates a
+// long's entry and the bid a short's, skewing quantity, stop and target; the
+// mid splits the difference.
+func (pm *PositionManager) SetPriceReference(ref string) error {
+	if !validPriceReference(ref) {
+		return fmt.Errorf("invalid price reference %q: use bid, ask, mid or last", ref)
+	}
+	pm.priceReference = ref
+	return nil
+}
+
+// resolvePriceReference returns the request's reference price, or the default
+func (pm *PositionManager) resolvePriceReference(req *PlaceManagedPositionRequest) string {
+	if req.PriceReference != "" {
+		return req.PriceReference
+	}
+	if pm.priceReference != "" {
+		return pm.priceReference
+	}
+	return DefaultPriceReference
+}
+
+// referencePrice reads the current price of symbol by ref. A one-sided quote
+// falls back to the side that is quoted, and the last trade falls back to
+// the quote when no trade can be read.
+func (pm *PositionManager) referencePrice(ctx context.Context, symbol, ref string) (float64, error) {
+	if ref == PriceReferenceLast {
+		trade, err := pm.dataService.GetLatestTrade(ctx, symbol)
+		if err == nil && trade.Price > 0 {
+			return trade.Price, nil
+		}
+		if err != nil {
+			pm.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to get last trade, using the quote mid")
+		}
+		ref = PriceReferenceMid
+	}
+
+	quote, err := pm.dataService.GetLatestQuote(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	bid, ask := quote.BidPrice, quote.AskPrice
+	switch {
+	case bid <= 0 && ask <= 0:
+		return 0, fmt.Errorf("no quote for %s", symbol)
+	case bid <= 0:
+		return ask, nil
+	case ask <= 0:
+		return bid, nil
+	}
+
+	switch ref {
+	case PriceReferenceBid:
+		return bid, nil
+	case PriceReferenceAsk:
+		return ask, nil
+	default:
+		return (bid + ask) / 2, nil
+	}
+}
@@ -0,0 +1,28 @@
+package services
+
+import "fmt"
+
+// SetMinRiskReward rejects new positions whose target/stop distance ratio is
+// below min. Zero disables the check.
+func (pm *PositionManager) SetMinRiskReward(min float64) error {
+	if min < 0 {
+		return fmt.Errorf("minimum risk/reward must not be negative")
+	}
+	pm.minRiskReward = min
+	return nil
+}
+
+// checkRiskReward enforces the minimum risk/reward on the percent distances
+// from entry to stop and target
+func (pm *PositionManager) checkRiskReward(stopPercent, targetPercent float64) error {
+	if pm.minRiskReward <= 0 || stopPercent <= 0 {
+		return nil
+	}
+
+	ratio := targetPercent / stopPercent
+	if ratio < pm.minRiskReward {
+		return fmt.Errorf("risk/reward %.2f:1 (target %.2f%%, stop %.2f%%) is below the minimum %.2f:1",
+			ratio, targetPercent, stopPercent, pm.minRiskReward)
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultScreenConcurrency is how many symbols a screen analyzes at once
+const DefaultScreenConcurrency = 4
+
+// ScreenComparator holds the comparisons applied to one analysis field. All
+// set comparisons must pass. Eq takes a number for numeric fields and a
+// string (case-insensitive) for trend, price_strength, confluence and
+// data_quality.
+type ScreenComparator struct {
+	Lt  *float64    `json:"lt,omitempty"`
+	Lte *float64    `json:"lte,omitempty"`
+	Gt  *float64    `json:"gt,omitempty"`
+	Gte *float64    `json:"gte,omitempty"`
+	Eq  interface{} `json:"eq,omitempty"`
+}
+
+// ScreenCriteria maps analysis fields to comparators, e.g.
+// {"rsi": {"lt": 30}, "trend": {"eq": "BULLISH"}}
+type ScreenCriteria map[string]ScreenComparator
+
+// ScreenResult is the outcome of screening a symbol list
+type ScreenResult struct {
+	Matches  []*StockAnalysis  `json:"matches"` // sorted by composite score, best first
+	Scanned  int               `json:"scanned"`
+	Failures map[string]string `json:"failures"`
+}
+
+// screenNumericFields extract numeric analysis fields; ok is false when the
+// value couldn't be computed, so it never matches
+var screenNumericFields = map[string]func(a *StockAnalysis) (float64, bool){
+	"price":                 func(a *StockAnalysis) (float64, bool) { return a.CurrentPrice, a.CurrentPrice > 0 },
+	"day_change":            func(a *StockAnalysis) (float64, bool) { return a.Technical.DayChange, true },
+	"rsi":                   func(a *StockAnalysis) (float64, bool) { return a.Technical.RSI, a.Technical.PriceStrength != "UNKNOWN" },
+	"volume_ratio":          func(a *StockAnalysis) (float64, bool) { return a.Technical.VolumeRatio, a.Technical.AvgVolume > 0 },
+	"volatility":            func(a *StockAnalysis) (float64, bool) { return a.Technical.Volatility, true },
+	"volatility_annualized": func(a *StockAnalysis) (float64, bool) { return a.Technical.VolatilityAnnualized, true },
+	"technical_score":       func(a *StockAnalysis) (float64, bool) { return float64(a.TradeSetup.TechnicalScore), true },
+	"catalyst_score":        func(a *StockAnalysis) (float64, bool) { return float64(a.TradeSetup.CatalystScore), true },
+	"volume_score":          func(a *StockAnalysis) (float64, bool) { return float64(a.TradeSetup.VolumeScore), true },
+	"composite_score":       func(a *StockAnalysis) (float64, bool) { return a.TradeSetup.CompositeScore, true },
+	"risk_reward":           func(a *StockAnalysis) (float64, bool) { return a.TradeSetup.RiskReward, true },
+}
+
+// screenStringFields extract categorical analysis fields
+var screenStringFields = map[string]func(a *StockAnalysis) string{
+	"trend":          func(a *StockAnalysis) string { return a.Technical.Trend },
+	"price_strength": func(a *StockAnalysis) string { return a.Technical.PriceStrength },
+	"confluence":     func(a *StockAnalysis) string { return a.TradeSetup.Confluence },
+	"data_quality":   func(a *StockAnalysis) string { return a.DataQuality },
+}
+
+// Validate checks that every field is known and its comparators fit its type
+func (c ScreenCriteria) Validate() error {
+	if len(c) == 0 {
+		return fmt.Errorf("at least one criterion required")
+	}
+
+	for field, cmp := range c {
+		if _, ok := screenNumericFields[field]; ok {
+			if cmp.Lt == nil && cmp.Lte == nil && cmp.Gt == nil && cmp.Gte == nil && cmp.Eq == nil {
+				return fmt.Errorf("%s: no comparator set (lt, lte, gt, gte, eq)", field)
+			}
+			if cmp.Eq != nil {
+				if _, ok := cmp.Eq.(float64); !ok {
+					return fmt.Errorf("%s: eq must be a number", field)
+				}
+			}
+			continue
+		}
+
+		if _, ok := screenStringFields[field]; ok {
+			if cmp.Lt != nil || cmp.Lte != nil || cmp.Gt != nil || cmp.Gte != nil {
+				return fmt.Errorf("%s: only eq is supported", field)
+			}
+			if _, ok := cmp.Eq.(string); !ok {
+				return fmt.Errorf("%s: eq must be a string", field)
+			}
+			continue
+		}
+
+		return fmt.Errorf("unknown field %q", field)
+	}
+	return nil
+}
+
+// Matches reports whether an analysis passes every criterion
+func (c ScreenCriteria) Matches(analysis *StockAnalysis) bool {
+	for field, cmp := range c {
+		if get, ok := screenNumericFields[field]; ok {
+			value, known := get(analysis)
+			if !known || !cmp.matchNumber(value) {
+				return false
+			}
+			continue
+		}
+
+		get := screenStringFields[field]
+		want, _ := cmp.Eq.(string)
+		if get == nil || !strings.EqualFold(get(analysis), want) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchNumber applies the numeric comparators to value
+func (cmp ScreenComparator) matchNumber(value float64) bool {
+	if cmp.Lt != nil && !(value < *cmp.Lt) {
+		return false
+	}
+	if cmp.Lte != nil && !(value <= *cmp.Lte) {
+		return false
+	}
+	if cmp.Gt != nil && !(value > *cmp.Gt) {
+		return false
+	}
+	if cmp.Gte != nil && !(value >= *cmp.Gte) {
+		return false
+	}
+	if eq, ok := cmp.Eq.(float64); ok && value != eq {
+		return false
+	}
+	return true
+}
+
+// SetScreenConcurrency sets how many symbols Screen analyzes at once
+func (sas *StockAnalysisService) SetScreenConcurrency(workers int) error {
+	if workers <= 0 {
+		return fmt.Errorf("screen concurrency must be positive")
+	}
+	sas.screenWorkers = workers
+	return nil
+}
+
+// Screen analyzes symbols with a bounded worker pool (reusing cached
+// analyses) and returns those matching criteria, best composite score first
+func (sas *StockAnalysisService) Screen(ctx context.Context, symbols []string, criteria ScreenCriteria) (*ScreenResult, error) {
+	if err := criteria.Validate(); err != nil {
+		return nil, err
+	}
+
+	unique := make([]string, 0, len(symbols))
+	seen := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		unique = append(unique, symbol)
+	}
+
+	result := &ScreenResult{
+		Matches:  []*StockAnalysis{},
+		Scanned:  len(unique),
+		Failures: make(map[string]string),
+	}
+
+	workers := sas.screenWorkers
+	if workers > len(unique) {
+		workers = len(unique)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for symbol := range jobs {
+				analysis, err := sas.AnalyzeStock(ctx, symbol)
+
+				mu.Lock()
+				if err != nil {
+					result.Failures[symbol] = err.Error()
+				} else if criteria.Matches(analysis) {
+					result.Matches = append(result.Matches, analysis)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, symbol := range unique {
+		jobs <- symbol
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(result.Matches, func(i, j int) bool {
+		if result.Matches[i].TradeSetup.CompositeScore != result.Matches[j].TradeSetup.CompositeScore {
+			return result.Matches[i].TradeSetup.CompositeScore > result.Matches[j].TradeSetup.CompositeScore
+		}
+		return result.Matches[i].Symbol < result.Matches[j].Symbol
+	})
+
+	sas.logger.WithFields(logrus.Fields{
+		"scanned": result.Scanned,
+		"matched": len(result.Matches),
+		"failed":  len(result.Failures),
+	}).Info("Stock screen completed")
+
+	return result, nil
+}
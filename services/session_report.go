@@ -0,0 +1,251 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"net/http"
+	"sort"
+	"text/template"
+	"time"
+)
+
+// sessionReportIntelLimit caps how many intelligence notes a report lists
+const sessionReportIntelLimit = 10
+
+// SessionReport is the end-of-day recap of one activity log
+type SessionReport struct {
+	Date            string             `json:"date"`
+	SessionStart    time.Time          `json:"session_start"`
+	SessionEnd      time.Time          `json:"session_end,omitempty"`
+	StartingCapital float64            `json:"starting_capital"`
+	EndingCapital   float64            `json:"ending_capital"`
+	Opened          []PositionActivity `json:"opened"`
+	Closed          []PositionActivity `json:"closed"`
+	RealizedPnL     float64            `json:"realized_pnl"` // net of estimated fees
+	TotalFees       float64            `json:"total_fees"`
+	Wins            int                `json:"wins"`
+	Losses          int                `json:"losses"`
+	WinRate         float64            `json:"win_rate"` // percent of closed trades
+	BiggestWinner   *PositionActivity  `json:"biggest_winner,omitempty"`
+	BiggestLoser    *PositionActivity  `json:"biggest_loser,omitempty"`
+	Intelligence    []IntelligenceNote `json:"intelligence"` // most recent first
+	OpenPositions   []*ManagedPosition `json:"open_positions"`
+	GeneratedAt     time.Time          `json:"generated_at"`
+}
+
+// ReportSender delivers a rendered session report (email, webhook, chat...)
+type ReportSender interface {
+	SendReport(ctx context.Context, subject, body, contentType string) error
+}
+
+// SetReportSender sets where session reports are delivered. Nil disables delivery.
+func (al *ActivityLogger) SetReportSender(sender ReportSender) {
+	al.reportSender = sender
+}
+
+// HasReportSender reports whether session reports can be delivered
+func (al *ActivityLogger) HasReportSender() bool {
+	return al.reportSender != nil
+}
+
+// GenerateSessionReport builds the recap for date (YYYY-MM-DD) from its
+// activity log. openPositions are listed as still open at report time.
+func (al *ActivityLogger) GenerateSessionReport(date string, openPositions []*ManagedPosition) (*SessionReport, error) {
+	var log *DailyActivityLog
+	if al.currentLog != nil && al.currentLog.Date == date {
+		log = al.currentLog
+	} else {
+		var err error
+		if log, err = al.GetLogForDate(date); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &SessionReport{
+		Date:            log.Date,
+		SessionStart:    log.SessionStart,
+		SessionEnd:      log.SessionEnd,
+		StartingCapital: log.Summary.StartingCapital,
+		EndingCapital:   log.Summary.EndingCapital,
+		Opened:          log.PositionsOpened,
+		Closed:          log.PositionsClosed,
+		Intelligence:    []IntelligenceNote{},
+		OpenPositions:   openPositions,
+		GeneratedAt:     time.Now(),
+	}
+	if report.OpenPositions == nil {
+		report.OpenPositions = []*ManagedPosition{}
+	}
+
+	for i := range log.PositionsClosed {
+		closed := &log.PositionsClosed[i]
+		report.RealizedPnL += closed.PnL
+		report.TotalFees += closed.Fees
+		if closed.PnL > 0 {
+			report.Wins++
+		} else {
+			report.Losses++
+		}
+		if closed.PnL > 0 && (report.BiggestWinner == nil || closed.PnL > report.BiggestWinner.PnL) {
+			report.BiggestWinner = closed
+		}
+		if closed.PnL < 0 && (report.BiggestLoser == nil || closed.PnL < report.BiggestLoser.PnL) {
+			report.BiggestLoser = closed
+		}
+	}
+	report.RealizedPnL = RoundMoney(report.RealizedPnL)
+	report.TotalFees = RoundMoney(report.TotalFees)
+	if len(log.PositionsClosed) > 0 {
+		report.WinRate = RoundPercent(float64(report.Wins) / float64(len(log.PositionsClosed)) * 100)
+	}
+
+	for i := len(log.MarketIntelligence) - 1; i >= 0 && len(report.Intelligence) < sessionReportIntelLimit; i-- {
+		report.Intelligence = append(report.Intelligence, log.MarketIntelligence[i])
+	}
+
+	sort.Slice(report.OpenPositions, func(i, j int) bool {
+		return report.OpenPositions[i].Symbol < report.OpenPositions[j].Symbol
+	})
+
+	return report, nil
+}
+
+// sessionReportText is the plain-text report layout
+const sessionReportText = `Trading session report for {{.Date}}
+{{if .StartingCapital}}Capital: ${{money .StartingCapital}}{{if .EndingCapital}} -> ${{money .EndingCapital}}{{end}}
+{{end}}
+Trades: {{len .Opened}} opened, {{len .Closed}} closed
+Realized P&L: ${{money .RealizedPnL}} (fees ${{money .TotalFees}})
+Win rate: {{printf "%.1f" .WinRate}}% ({{.Wins}}W / {{.Losses}}L)
+{{with .BiggestWinner}}Biggest winner: {{.Symbol}} ${{money .PnL}} ({{printf "%.2f" .PnLPercent}}%)
+{{end}}{{with .BiggestLoser}}Biggest loser: {{.Symbol}} ${{money .PnL}} ({{printf "%.2f" .PnLPercent}}%)
+{{end}}
+Closed trades:
+{{range .Closed}}  {{.Symbol}} {{.Side}} {{.Quantity}} @ {{.EntryPrice}} -> {{.ExitPrice}}: ${{money .PnL}}
+{{else}}  none
+{{end}}
+Open positions:
+{{range .OpenPositions}}  {{.Symbol}} {{.Side}} {{.RemainingQty}} @ {{.EntryPrice}} (now {{.CurrentPrice}}, unrealized ${{money .UnrealizedPL}})
+{{else}}  none
+{{end}}
+Notable intelligence:
+{{range .Intelligence}}  [{{.Source}}] {{.Topic}}: {{.Summary}}
+{{else}}  none
+{{end}}`
+
+// sessionReportHTML is the HTML report layout
+const sessionReportHTML = `<html><body>
+<h2>Trading session report for {{.Date}}</h2>
+{{if .StartingCapital}}<p>Capital: ${{money .StartingCapital}}{{if .EndingCapital}} &rarr; ${{money .EndingCapital}}{{end}}</p>{{end}}
+<ul>
+<li>Trades: {{len .Opened}} opened, {{len .Closed}} closed</li>
+<li>Realized P&amp;L: ${{money .RealizedPnL}} (fees ${{money .TotalFees}})</li>
+<li>Win rate: {{printf "%.1f" .WinRate}}% ({{.Wins}}W / {{.Losses}}L)</li>
+{{with .BiggestWinner}}<li>Biggest winner: {{.Symbol}} ${{money .PnL}} ({{printf "%.2f" .PnLPercent}}%)</li>{{end}}
+{{with .BiggestLoser}}<li>Biggest loser: {{.Symbol}} ${{money .PnL}} ({{printf "%.2f" .PnLPercent}}%)</li>{{end}}
+</ul>
+<h3>Closed trades</h3>
+<table border="1" cellpadding="4">
+<tr><th>Symbol</th><th>Side</th><th>Qty</th><th>Entry</th><th>Exit</th><th>P&amp;L</th></tr>
+{{range .Closed}}<tr><td>{{.Symbol}}</td><td>{{.Side}}</td><td>{{.Quantity}}</td><td>{{.EntryPrice}}</td><td>{{.ExitPrice}}</td><td>${{money .PnL}}</td></tr>
+{{end}}</table>
+<h3>Open positions</h3>
+<table border="1" cellpadding="4">
+<tr><th>Symbol</th><th>Side</th><th>Qty</th><th>Entry</th><th>Current</th><th>Unrealized</th></tr>
+{{range .OpenPositions}}<tr><td>{{.Symbol}}</td><td>{{.Side}}</td><td>{{.RemainingQty}}</td><td>{{.EntryPrice}}</td><td>{{.CurrentPrice}}</td><td>${{money .UnrealizedPL}}</td></tr>
+{{end}}</table>
+<h3>Notable intelligence</h3>
+<ul>
+{{range .Intelligence}}<li>[{{.Source}}] <b>{{.Topic}}</b>: {{.Summary}}</li>
+{{else}}<li>none</li>
+{{end}}</ul>
+</body></html>`
+
+var (
+	reportFuncs = map[string]interface{}{
+		"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	}
+	sessionReportTextTemplate = template.Must(template.New("report").Funcs(reportFuncs).Parse(sessionReportText))
+	sessionReportHTMLTemplate = htmltemplate.Must(htmltemplate.New("report").Funcs(reportFuncs).Parse(sessionReportHTML))
+)
+
+// Render formats the report as "text" or "html" and returns the body with
+// its content type
+func (r *SessionReport) Render(format string) (string, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "", "text":
+		if err := sessionReportTextTemplate.Execute(&buf, r); err != nil {
+			return "", "", fmt.Errorf("failed to render report: %w", err)
+		}
+		return buf.String(), "text/plain; charset=utf-8", nil
+	case "html":
+		if err := sessionReportHTMLTemplate.Execute(&buf, r); err != nil {
+			return "", "", fmt.Errorf("failed to render report: %w", err)
+		}
+		return buf.String(), "text/html; charset=utf-8", nil
+	default:
+		return "", "", fmt.Errorf("unknown report format %q: use text or html", format)
+	}
+}
+
+// SendSessionReport renders the report and delivers it through the report sender
+func (al *ActivityLogger) SendSessionReport(ctx context.Context, report *SessionReport, format string) error {
+	if al.reportSender == nil {
+		return fmt.Errorf("no report sender configured")
+	}
+
+	body, contentType, err := report.Render(format)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("Trading session report %s: $%.2f realized", report.Date, report.RealizedPnL)
+	return al.reportSender.SendReport(ctx, subject, body, contentType)
+}
+
+// WebhookReportSender posts reports as JSON {subject, body, content_type} to a URL
+type WebhookReportSender struct {
+	URL    string
+	client *http.Client
+}
+
+// NewWebhookReportSender creates a sender posting to url
+func NewWebhookReportSender(url string) *WebhookReportSender {
+	return &WebhookReportSender{
+		URL:    url,
+		client: &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// SendReport posts the report to the webhook
+func (s *WebhookReportSender) SendReport(ctx context.Context, subject, body, contentType string) error {
+	payload, err := json.Marshal(map[string]string{
+		"subject":      subject,
+		"body":         body,
+		"content_type": contentType,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("report webhook returned %d", resp.StatusCode)
+	}
+	return nil
+}
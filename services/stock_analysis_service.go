@@ -2,9 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"net"
+	"prophet-trader/database"
 	"prophet-trader/interfaces"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -12,10 +17,46 @@ import (
 
 // StockAnalysisService provides comprehensive stock analysis
 type StockAnalysisService struct {
-	dataService   interfaces.DataService
-	newsService   *NewsService
-	geminiService *GeminiService
-	logger        *logrus.Logger
+	dataService    interfaces.DataService
+	newsService    *NewsService
+	geminiService  *GeminiService
+	logger         *logrus.Logger
+	multiTimeframe bool // also analyze weekly bars resampled from daily
+	yearRange      bool // also compute 52-week high/low proximity
+	barStorage     *database.LocalStorage // optional daily bar cache
+	weights        CompositeWeights       // sub-score weights for the composite score
+	neutralScore   int                    // sub-score used when its indicators are unavailable
+	headlines      map[string]cachedHeadlines
+	headlinesMu    sync.Mutex
+	analyses       map[string]cachedAnalysis // recent AnalyzeStock results by symbol
+	analysisTTL    time.Duration
+	barFreshness   bool // a newer latest bar also invalidates a cached analysis
+	includeNews    bool // analyses search news for catalysts unless a request opts out
+	analysisMu     sync.Mutex
+	screenWorkers  int // symbols analyzed at once by Screen
+	periods        map[string]IndicatorPeriods // per-timeframe overrides of defaultIndicatorPeriods
+	fees           FeeModel // backtest fees when a request sets none
+}
+
+// CompositeWeights are the relative weights of the sub-scores in the composite score
+type CompositeWeights struct {
+	Technical float64 `json:"technical"`
+	Catalyst  float64 `json:"catalyst"`
+	Volume    float64 `json:"volume"`
+}
+
+// DefaultCompositeWeights weights all sub-scores equally
+var DefaultCompositeWeights = CompositeWeights{Technical: 1, Catalyst: 1, Volume: 1}
+
+// Score returns the weighted average of the sub-scores, rounded to one decimal
+func (w CompositeWeights) Score(technical, catalyst, volume int) float64 {
+	total := w.Technical + w.Catalyst + w.Volume
+	if total <= 0 {
+		w, total = DefaultCompositeWeights, 3
+	}
+
+	score := (w.Technical*float64(technical) + w.Catalyst*float64(catalyst) + w.Volume*float64(volume)) / total
+	return math.Round(score*10) / 10
 }
 
 // NewStockAnalysisService creates a new stock analysis service
@@ -30,7 +71,55 @@ func NewStockAnalysisService(dataService interfaces.DataService, newsService *Ne
 		newsService:   newsService,
 		geminiService: geminiService,
 		logger:        logger,
+		weights:       DefaultCompositeWeights,
+		neutralScore:  DefaultNeutralScore,
+		headlines:     make(map[string]cachedHeadlines),
+		analyses:      make(map[string]cachedAnalysis),
+		analysisTTL:   DefaultAnalysisCacheTTL,
+		includeNews:   true,
+		screenWorkers: DefaultScreenConcurrency,
+		periods:       make(map[string]IndicatorPeriods),
+	}
+}
+
+// SetMultiTimeframe enables weekly confluence analysis alongside the daily indicators
+func (sas *StockAnalysisService) SetMultiTimeframe(enabled bool) {
+	sas.multiTimeframe = enabled
+}
+
+// SetYearRange enables 52-week high/low analysis. When storage is non-nil,
+// completed daily bars are cached there so only recent days are fetched.
+func (sas *StockAnalysisService) SetYearRange(enabled bool, storage *database.LocalStorage) {
+	sas.yearRange = enabled
+	sas.barStorage = storage
+}
+
+// SetCompositeWeights sets the sub-score weights used for the composite score.
+// Weights are relative; they must be finite, non-negative and not all zero.
+func (sas *StockAnalysisService) SetCompositeWeights(weights CompositeWeights) error {
+	for _, w := range []float64{weights.Technical, weights.Catalyst, weights.Volume} {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return fmt.Errorf("composite weights must be finite and non-negative")
+		}
+	}
+	if weights.Technical+weights.Catalyst+weights.Volume == 0 {
+		return fmt.Errorf("at least one composite weight must be positive")
+	}
+	sas.weights = weights
+	return nil
+}
+
+// DefaultNeutralScore is the sub-score given when its indicators can't be computed
+const DefaultNeutralScore = 5
+
+// SetNeutralScore sets the technical/volume sub-score used when the bars needed
+// for those indicators are unavailable, so missing data is never scored as a signal
+func (sas *StockAnalysisService) SetNeutralScore(score int) error {
+	if score < 0 || score > 10 {
+		return fmt.Errorf("neutral score must be between 0 and 10")
 	}
+	sas.neutralScore = score
+	return nil
 }
 
 // StockAnalysis represents comprehensive analysis of a stock
@@ -39,11 +128,63 @@ type StockAnalysis struct {
 	CurrentPrice    float64                `json:"current_price"`
 	MarketCap       string                 `json:"market_cap_estimate"`
 	Technical       TechnicalAnalysis      `json:"technical"`
+	HigherTimeframe *TimeframeSummary      `json:"higher_timeframe,omitempty"`
 	NewsSummary     string                 `json:"news_summary"` // Just summary, not full articles
+	NewsSkipped     bool                   `json:"news_skipped,omitempty"` // analyzed without searching news
 	TradeSetup      TradeSetup             `json:"trade_setup"`
+	DataQuality     string                 `json:"data_quality"` // "FULL", or "PARTIAL" when price history was unavailable
+	DataIssues      []string               `json:"data_issues,omitempty"`
+	LatestBarTime   *time.Time             `json:"latest_bar_time,omitempty"` // latest bar the analysis was computed from
+	AsOf            *time.Time             `json:"as_of,omitempty"` // session close a historical analysis was recomputed for
+	Signal          *AnalysisResult        `json:"signal,omitempty"` // indicator signal as of AsOf (historical analyses only)
 	Timestamp       time.Time              `json:"timestamp"`
 }
 
+// StockAnalysisCompact is the essential subset of a StockAnalysis: price,
+// trend, the trade setup levels and scores, without news or indicator detail
+type StockAnalysisCompact struct {
+	Symbol         string    `json:"symbol"`
+	Price          float64   `json:"price"`
+	DayChange      float64   `json:"day_change_percent"`
+	Trend          string    `json:"trend"`
+	RSI            float64   `json:"rsi_14"`
+	VolumeRatio    float64   `json:"volume_ratio"`
+	Support        float64   `json:"support_level"`
+	Resistance     float64   `json:"resistance_level"`
+	StopLoss       float64   `json:"stop_loss"`
+	TakeProfit     float64   `json:"take_profit"`
+	CompositeScore float64   `json:"composite_score"`
+	Confluence     string    `json:"confluence,omitempty"`
+	DataQuality    string    `json:"data_quality"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// ToCompact converts a StockAnalysis to a compact version
+func (a *StockAnalysis) ToCompact() StockAnalysisCompact {
+	return StockAnalysisCompact{
+		Symbol:         a.Symbol,
+		Price:          a.CurrentPrice,
+		DayChange:      a.Technical.DayChange,
+		Trend:          a.Technical.Trend,
+		RSI:            a.Technical.RSI,
+		VolumeRatio:    a.Technical.VolumeRatio,
+		Support:        a.Technical.Support,
+		Resistance:     a.Technical.Resistance,
+		StopLoss:       a.TradeSetup.StopLoss,
+		TakeProfit:     a.TradeSetup.TakeProfit,
+		CompositeScore: a.TradeSetup.CompositeScore,
+		Confluence:     a.TradeSetup.Confluence,
+		DataQuality:    a.DataQuality,
+		Timestamp:      a.Timestamp,
+	}
+}
+
+// Data quality levels for a StockAnalysis
+const (
+	DataQualityFull    = "FULL"
+	DataQualityPartial = "PARTIAL"
+)
+
 // TechnicalAnalysis contains technical indicators
 type TechnicalAnalysis struct {
 	Price         float64  `json:"price"`
@@ -51,12 +192,34 @@ type TechnicalAnalysis struct {
 	Volume        int64    `json:"volume"`
 	AvgVolume     int64    `json:"avg_volume_30d"`
 	VolumeRatio   float64  `json:"volume_ratio"` // Current vs avg
-	Trend         string   `json:"trend"` // "BULLISH", "BEARISH", "NEUTRAL"
+	Trend         string   `json:"trend"` // "BULLISH", "BEARISH", "NEUTRAL", "UNKNOWN"
 	Support       float64  `json:"support_level"`
 	Resistance    float64  `json:"resistance_level"`
-	Volatility    float64  `json:"volatility_30d"`
+	Volatility    float64  `json:"volatility_30d"` // sample std dev of per-bar returns, %
+	VolatilityAnnualized float64 `json:"volatility_annualized"` // Volatility scaled by sqrt(bars per year), %
 	RSI           float64  `json:"rsi_14"` // 0-100
-	PriceStrength string   `json:"price_strength"` // "OVERSOLD", "NEUTRAL", "OVERBOUGHT"
+	PriceStrength string   `json:"price_strength"` // "OVERSOLD", "NEUTRAL", "OVERBOUGHT", "UNKNOWN"
+	YearRange     *YearRange `json:"year_range,omitempty"`
+}
+
+// YearRange describes where price sits within its 52-week range
+type YearRange struct {
+	High         float64 `json:"high_52w"`
+	Low          float64 `json:"low_52w"`
+	PctFromHigh  float64 `json:"pct_from_high"` // <= 0
+	PctFromLow   float64 `json:"pct_from_low"`  // >= 0
+	NewHigh      bool    `json:"new_52w_high"`
+	NewLow       bool    `json:"new_52w_low"`
+	Bars         int     `json:"bars"`
+}
+
+// TimeframeSummary contains trend data for a higher timeframe
+type TimeframeSummary struct {
+	Timeframe     string  `json:"timeframe"`
+	Bars          int     `json:"bars"`
+	Trend         string  `json:"trend"`
+	RSI           float64 `json:"rsi_14,omitempty"`
+	PriceStrength string  `json:"price_strength,omitempty"`
 }
 
 // TradeSetup provides neutral trading data for AI interpretation
@@ -77,35 +240,83 @@ type TradeSetup struct {
 	VolumeScore    int      `json:"volume_score"`    // 0-10 based on volume ratio
 
 	// Overall (NEUTRAL - composite)
-	CompositeScore int      `json:"composite_score"` // 0-10 (avg of above)
+	CompositeScore float64  `json:"composite_score"` // 0-10 (weighted avg of above)
+	Confidence     string   `json:"confidence"`      // "NORMAL", or "LOW" when built from partial data
+
+	// Multi-timeframe (FACTUAL - daily vs weekly agreement)
+	Confluence     string   `json:"confluence,omitempty"` // "ALIGNED", "CONFLICTING", "NEUTRAL"
 
 	// Notes (FACTUAL - no recommendation)
 	Notes          string   `json:"notes"`           // Factual observations only
 }
 
-// AnalyzeStocks analyzes multiple stocks and returns comprehensive analysis
-func (sas *StockAnalysisService) AnalyzeStocks(ctx context.Context, symbols []string) (map[string]*StockAnalysis, error) {
+// analysisRetryDelay is how long to wait before retrying a symbol after a transient error
+const analysisRetryDelay = time.Second
+
+// AnalyzeStocks analyzes multiple stocks and returns comprehensive analysis along
+// with the error for every symbol that could not be analyzed. Transient errors
+// (timeouts) are retried once before the symbol is reported as failed.
+func (sas *StockAnalysisService) AnalyzeStocks(ctx context.Context, symbols []string) (map[string]*StockAnalysis, map[string]error, error) {
+	return sas.AnalyzeStocksWith(ctx, symbols, sas.DefaultAnalysisOptions())
+}
+
+// AnalyzeStocksWith is AnalyzeStocks with explicit analysis options
+func (sas *StockAnalysisService) AnalyzeStocksWith(ctx context.Context, symbols []string, opts AnalysisOptions) (map[string]*StockAnalysis, map[string]error, error) {
 	sas.logger.WithField("symbols", symbols).Info("Starting comprehensive stock analysis")
 
 	results := make(map[string]*StockAnalysis)
+	failures := make(map[string]error)
 
 	for _, symbol := range symbols {
-		analysis, err := sas.AnalyzeStock(ctx, symbol)
+		analysis, err := sas.AnalyzeStockWith(ctx, symbol, opts)
+		if err != nil && isTransientError(err) && ctx.Err() == nil {
+			sas.logger.WithError(err).WithField("symbol", symbol).Info("Transient error analyzing stock, retrying")
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(analysisRetryDelay):
+				analysis, err = sas.AnalyzeStockWith(ctx, symbol, opts)
+			}
+		}
 		if err != nil {
 			sas.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to analyze stock")
+			failures[symbol] = err
 			continue
 		}
 		results[symbol] = analysis
 	}
 
-	return results, nil
+	if len(failures) > 0 {
+		sas.logger.WithFields(logrus.Fields{
+			"succeeded": len(results),
+			"failed":    len(failures),
+		}).Warn("Stock analysis completed with failures")
+	}
+
+	return results, failures, nil
+}
+
+// isTransientError reports whether an error is a timeout worth retrying
+func isTransientError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "timeout") || strings.Contains(message, "timed out")
 }
 
-// AnalyzeStock provides comprehensive analysis for a single stock
-func (sas *StockAnalysisService) AnalyzeStock(ctx context.Context, symbol string) (*StockAnalysis, error) {
+// analyzeStock runs the full analysis pipeline for a single stock
+func (sas *StockAnalysisService) analyzeStock(ctx context.Context, symbol string, opts AnalysisOptions) (*StockAnalysis, error) {
 	analysis := &StockAnalysis{
-		Symbol:    symbol,
-		Timestamp: time.Now(),
+		Symbol:      symbol,
+		DataQuality: DataQualityFull,
+		Timestamp:   time.Now(),
 	}
 
 	// Get current quote
@@ -120,62 +331,111 @@ func (sas *StockAnalysisService) AnalyzeStock(ctx context.Context, symbol string
 	if err == nil {
 		analysis.Technical.Price = bar.Close
 		analysis.Technical.Volume = bar.Volume
+		barTime := bar.Timestamp
+		analysis.LatestBarTime = &barTime
 	} else {
 		analysis.Technical.Price = quote.BidPrice
+		analysis.DataIssues = append(analysis.DataIssues, fmt.Sprintf("latest bar unavailable: %v", err))
 	}
 
 	// Get historical data for technical analysis (30 days)
 	endTime := time.Now()
 	startTime := endTime.AddDate(0, 0, -30)
 
-	bars, err := sas.dataService.GetHistoricalBars(ctx, symbol, startTime, endTime, "1Day")
+	// Weekly analysis needs ~6 months of daily bars; fetch once and slice for daily
+	fetchStart := startTime
+	if opts.MultiTimeframe {
+		fetchStart = endTime.AddDate(0, 0, -weeklyLookbackDays)
+	}
+	if opts.YearRange {
+		fetchStart = endTime.AddDate(0, 0, -yearLookbackDays)
+	}
+
+	bars, err := sas.analysisBars(ctx, symbol, fetchStart, endTime, opts)
 	if err == nil && len(bars) > 0 {
-		analysis.Technical = sas.calculateTechnicalIndicators(bars)
+		sas.applyHistory(analysis, bars, endTime, opts)
 	} else {
-		// Minimal analysis without historical data
-		analysis.Technical.Price = quote.BidPrice
+		// Minimal analysis from the latest bar/quote only; indicators that
+		// need history are left unset
 		analysis.Technical.Trend = "UNKNOWN"
 		analysis.Technical.PriceStrength = "UNKNOWN"
+		analysis.DataQuality = DataQualityPartial
+		if err != nil {
+			analysis.DataIssues = append(analysis.DataIssues, fmt.Sprintf("historical bars unavailable: %v", err))
+		} else {
+			analysis.DataIssues = append(analysis.DataIssues, "no historical bars returned")
+		}
+		sas.logger.WithField("symbol", symbol).Warn("Stock analysis degraded: no price history")
 	}
 
 	// Estimate market cap range
 	analysis.MarketCap = sas.estimateMarketCap(analysis.Technical.Price, symbol)
 
 	// Get recent news (summarize to save tokens)
-	newsSummary := ""
 	catalysts := []string{}
-	news, err := sas.newsService.GetGoogleNewsSearch(symbol)
-	if err == nil && len(news) > 0 {
-		// Get top 3 most recent headlines only
-		limit := 3
-		if len(news) < limit {
-			limit = len(news)
-		}
-		for i := 0; i < limit; i++ {
-			catalysts = append(catalysts, news[i].Title)
-		}
-		if len(news) > 0 {
-			newsSummary = fmt.Sprintf("%d recent articles (past 48h)", len(news))
+	if opts.IncludeNews {
+		var articleCount int
+		catalysts, articleCount = sas.recentHeadlines(symbol)
+		if articleCount > 0 {
+			analysis.NewsSummary = fmt.Sprintf("%d recent articles (past 48h)", articleCount)
 		}
+	} else {
+		analysis.NewsSkipped = true
+		analysis.NewsSummary = "news skipped"
 	}
-	analysis.NewsSummary = newsSummary
 
-	// Generate NEUTRAL trade setup (no recommendations, just data)
-	analysis.TradeSetup = sas.generateTradeSetup(analysis.Technical, catalysts, analysis.CurrentPrice)
+	sas.applyTradeSetup(analysis, catalysts)
 
 	return analysis, nil
 }
 
-// calculateTechnicalIndicators calculates technical indicators from historical bars
-func (sas *StockAnalysisService) calculateTechnicalIndicators(bars []*interfaces.Bar) TechnicalAnalysis {
+// applyHistory computes the daily indicators, and the weekly and 52-week
+// pictures when opts ask for them, from daily bars ending at endTime
+func (sas *StockAnalysisService) applyHistory(analysis *StockAnalysis, bars []*interfaces.Bar, endTime time.Time, opts AnalysisOptions) {
+	var yearRange *YearRange
+	if opts.YearRange {
+		yearRange = calculateYearRange(bars)
+	}
+	if opts.MultiTimeframe {
+		analysis.HigherTimeframe = sas.summarizeWeekly(barsSince(bars, endTime.AddDate(0, 0, -weeklyLookbackDays)))
+	}
+	bars = barsSince(bars, endTime.AddDate(0, 0, -30))
+	analysis.Technical = sas.calculateTechnicalIndicators(bars, "1Day")
+	analysis.Technical.YearRange = yearRange
+}
+
+// applyTradeSetup generates the NEUTRAL trade setup (no recommendations,
+// just data) and its scores
+func (sas *StockAnalysisService) applyTradeSetup(analysis *StockAnalysis, catalysts []string) {
+	analysis.TradeSetup = sas.generateTradeSetup(analysis.Technical, catalysts, analysis.CurrentPrice)
+	if analysis.NewsSkipped {
+		sas.applyNewsSkipped(&analysis.TradeSetup)
+	}
+	if analysis.HigherTimeframe != nil {
+		sas.applyConfluence(&analysis.TradeSetup, analysis.Technical, analysis.HigherTimeframe)
+	}
+	if analysis.Technical.YearRange != nil {
+		applyYearRange(&analysis.TradeSetup, analysis.Technical.YearRange)
+	}
+	if analysis.DataQuality == DataQualityPartial {
+		sas.applyPartialData(&analysis.TradeSetup)
+	}
+}
+
+// calculateTechnicalIndicators calculates technical indicators from historical
+// bars of timeframe tf, with lookbacks from its periods (see defaultIndicatorPeriods)
+func (sas *StockAnalysisService) calculateTechnicalIndicators(bars []*interfaces.Bar, tf string) TechnicalAnalysis {
+	periods := sas.periodsFor(tf)
 	if len(bars) == 0 {
-		return TechnicalAnalysis{}
+		return TechnicalAnalysis{Trend: "UNKNOWN", PriceStrength: "UNKNOWN"}
 	}
 
 	latest := bars[len(bars)-1]
 	tech := TechnicalAnalysis{
-		Price:  latest.Close,
-		Volume: latest.Volume,
+		Price:         latest.Close,
+		Volume:        latest.Volume,
+		Trend:         "UNKNOWN",
+		PriceStrength: "UNKNOWN",
 	}
 
 	// Calculate day change
@@ -209,18 +469,20 @@ func (sas *StockAnalysisService) calculateTechnicalIndicators(bars []*interfaces
 	tech.Resistance = high
 	tech.Support = low
 
-	// Calculate volatility (standard deviation of daily returns)
-	if len(bars) > 1 {
+	// Calculate volatility: sample standard deviation of per-bar returns,
+	// annualized by the square root of bars per year
+	if len(bars) > 2 {
 		returns := make([]float64, len(bars)-1)
 		for i := 1; i < len(bars); i++ {
 			returns[i-1] = (bars[i].Close - bars[i-1].Close) / bars[i-1].Close
 		}
-		tech.Volatility = sas.standardDeviation(returns) * 100 // Convert to percentage
+		tech.Volatility = StandardDeviation(returns, true) * 100 // Convert to percentage
+		tech.VolatilityAnnualized = tech.Volatility * math.Sqrt(barsPerYear[tf])
 	}
 
-	// Calculate RSI (14-period)
-	if len(bars) >= 14 {
-		tech.RSI = sas.calculateRSI(bars, 14)
+	// Calculate RSI (needs one bar more than the period for the first change)
+	if len(bars) > periods.RSI {
+		tech.RSI = CalculateRSI(bars, periods.RSI)
 
 		// Determine price strength
 		if tech.RSI < 30 {
@@ -233,17 +495,18 @@ func (sas *StockAnalysisService) calculateTechnicalIndicators(bars []*interfaces
 	}
 
 	// Determine trend
-	if len(bars) >= 10 {
-		// Simple trend: compare current price to 10-day average
+	if len(bars) >= periods.Trend {
+		// Simple trend: compare current price to the trend average
 		sum := 0.0
-		for i := len(bars) - 10; i < len(bars); i++ {
+		for i := len(bars) - periods.Trend; i < len(bars); i++ {
 			sum += bars[i].Close
 		}
-		avg10 := sum / 10.0
+		avg := sum / float64(periods.Trend)
 
-		if latest.Close > avg10*1.05 {
+		band := periods.TrendBand / 100
+		if latest.Close > avg*(1+band) {
 			tech.Trend = "BULLISH"
-		} else if latest.Close < avg10*0.95 {
+		} else if latest.Close < avg*(1-band) {
 			tech.Trend = "BEARISH"
 		} else {
 			tech.Trend = "NEUTRAL"
@@ -253,62 +516,6 @@ func (sas *StockAnalysisService) calculateTechnicalIndicators(bars []*interfaces
 	return tech
 }
 
-// calculateRSI calculates the Relative Strength Index
-func (sas *StockAnalysisService) calculateRSI(bars []*interfaces.Bar, period int) float64 {
-	if len(bars) < period+1 {
-		return 50.0 // Default neutral
-	}
-
-	gains := 0.0
-	losses := 0.0
-
-	// Calculate initial average gain/loss
-	for i := len(bars) - period; i < len(bars); i++ {
-		change := bars[i].Close - bars[i-1].Close
-		if change > 0 {
-			gains += change
-		} else {
-			losses += -change
-		}
-	}
-
-	avgGain := gains / float64(period)
-	avgLoss := losses / float64(period)
-
-	if avgLoss == 0 {
-		return 100.0
-	}
-
-	rs := avgGain / avgLoss
-	rsi := 100 - (100 / (1 + rs))
-
-	return rsi
-}
-
-// standardDeviation calculates standard deviation of a slice of floats
-func (sas *StockAnalysisService) standardDeviation(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-
-	// Calculate mean
-	sum := 0.0
-	for _, v := range values {
-		sum += v
-	}
-	mean := sum / float64(len(values))
-
-	// Calculate variance
-	variance := 0.0
-	for _, v := range values {
-		diff := v - mean
-		variance += diff * diff
-	}
-	variance /= float64(len(values))
-
-	return math.Sqrt(variance)
-}
-
 // estimateMarketCap provides rough market cap estimate based on symbol and price
 func (sas *StockAnalysisService) estimateMarketCap(price float64, symbol string) string {
 	// This is a rough estimate - in production you'd want to fetch actual market cap
@@ -335,34 +542,47 @@ func (sas *StockAnalysisService) generateTradeSetup(tech TechnicalAnalysis, cata
 		RiskReward:   2.0,
 		RecentNews:   catalysts,
 		KeyCatalysts: catalysts,
+		Confidence:   "NORMAL",
 	}
 
 	// Calculate NEUTRAL scores (0-10) based on data only
 
+	// Indicators that couldn't be computed are neutral, not zero readings
+	rsiKnown := tech.PriceStrength != "" && tech.PriceStrength != "UNKNOWN"
+	trendKnown := tech.Trend != "" && tech.Trend != "UNKNOWN"
+
 	// Technical Score (0-10)
-	technicalScore := 5 // Start neutral
-	if tech.Trend == "BULLISH" {
-		technicalScore += 2
-	} else if tech.Trend == "BEARISH" {
-		technicalScore -= 2
-	}
-	if tech.RSI > 30 && tech.RSI < 70 {
-		technicalScore += 1 // Healthy RSI range
-	}
-	if tech.RSI < 30 {
-		technicalScore += 2 // Oversold opportunity
-	}
-	if tech.RSI > 75 {
-		technicalScore -= 2 // Overbought risk
-	}
-	if tech.Volatility > 15 {
-		technicalScore += 1 // High volatility = opportunity for small-caps
+	if rsiKnown || trendKnown {
+		technicalScore := 5 // Start neutral
+		if tech.Trend == "BULLISH" {
+			technicalScore += 2
+		} else if tech.Trend == "BEARISH" {
+			technicalScore -= 2
+		}
+		if rsiKnown {
+			if tech.RSI > 30 && tech.RSI < 70 {
+				technicalScore += 1 // Healthy RSI range
+			}
+			if tech.RSI < 30 {
+				technicalScore += 2 // Oversold opportunity
+			}
+			if tech.RSI > 75 {
+				technicalScore -= 2 // Overbought risk
+			}
+		}
+		if tech.Volatility > 15 {
+			technicalScore += 1 // High volatility = opportunity for small-caps
+		}
+		setup.TechnicalScore = maxInt(0, minInt(10, technicalScore))
+	} else {
+		setup.TechnicalScore = sas.neutralScore
 	}
-	setup.TechnicalScore = maxInt(0, minInt(10, technicalScore))
 
 	// Volume Score (0-10)
 	volumeScore := 5 // Start neutral
-	if tech.VolumeRatio > 2.0 {
+	if tech.AvgVolume == 0 {
+		volumeScore = sas.neutralScore // No volume history
+	} else if tech.VolumeRatio > 2.0 {
 		volumeScore = 9 // Very high volume
 	} else if tech.VolumeRatio > 1.5 {
 		volumeScore = 7 // Good volume
@@ -388,17 +608,203 @@ func (sas *StockAnalysisService) generateTradeSetup(tech TechnicalAnalysis, cata
 	}
 	setup.CatalystScore = catalystScore
 
-	// Composite Score (simple average)
-	setup.CompositeScore = (setup.TechnicalScore + setup.VolumeScore + setup.CatalystScore) / 3
+	// Composite Score (weighted average)
+	setup.CompositeScore = sas.weights.Score(setup.TechnicalScore, setup.CatalystScore, setup.VolumeScore)
 
 	// Factual notes only
-	notes := fmt.Sprintf("Trend: %s | RSI: %.0f (%s) | Vol: %.1fx avg | Volatility: %.1f%%",
-		tech.Trend, tech.RSI, tech.PriceStrength, tech.VolumeRatio, tech.Volatility)
+	rsi := "n/a"
+	if rsiKnown {
+		rsi = fmt.Sprintf("%.0f", tech.RSI)
+	}
+	notes := fmt.Sprintf("Trend: %s | RSI: %s (%s) | Vol: %.1fx avg | Volatility: %.1f%%",
+		tech.Trend, rsi, tech.PriceStrength, tech.VolumeRatio, tech.Volatility)
+	if !rsiKnown && !trendKnown {
+		notes += " | Insufficient price history: technical score is neutral"
+	}
 	setup.Notes = notes
 
 	return setup
 }
 
+// applyPartialData marks a setup built without price history as low
+// confidence. The composite is capped at the neutral score so news alone can
+// never rank a symbol above ones with a full analysis.
+func (sas *StockAnalysisService) applyPartialData(setup *TradeSetup) {
+	setup.Confidence = "LOW"
+	setup.CompositeScore = math.Min(setup.CompositeScore, float64(sas.neutralScore))
+	setup.Notes += " | PARTIAL DATA: price history unavailable, scores are low confidence"
+}
+
+// weeklyLookbackDays is how much daily history is fetched for weekly analysis
+// (~26 weeks, enough for a 14-period weekly RSI)
+const weeklyLookbackDays = 182
+
+// summarizeWeekly computes trend and RSI on weekly bars built from daily bars
+func (sas *StockAnalysisService) summarizeWeekly(dailyBars []*interfaces.Bar) *TimeframeSummary {
+	// The current week is a partial bar built from the days traded so far
+	weekly, err := ResampleBars(dailyBars, "1Week")
+	if err != nil || len(weekly) == 0 {
+		return nil
+	}
+
+	tech := sas.calculateTechnicalIndicators(weekly, "1Week")
+	summary := &TimeframeSummary{
+		Timeframe:     "1Week",
+		Bars:          len(weekly),
+		Trend:         tech.Trend,
+		RSI:           tech.RSI,
+		PriceStrength: tech.PriceStrength,
+	}
+
+	return summary
+}
+
+// yearLookbackDays covers ~250 trading days for 52-week high/low analysis
+const yearLookbackDays = 365
+
+// fetchDailyBars returns daily bars for a range. With a bar cache configured,
+// stored completed days are reused and only the last stored day onwards is
+// fetched; the overlapping day detects splits since the bars were stored
+// (see bar_splits.go).
+func (sas *StockAnalysisService) fetchDailyBars(ctx context.Context, symbol string, start, end time.Time) ([]*interfaces.Bar, error) {
+	if sas.barStorage == nil {
+		return sas.dataService.GetHistoricalBars(ctx, symbol, start, end, "1Day")
+	}
+
+	stored, err := sas.barStorage.GetDailyBars(symbol, start, end)
+	covered := err == nil && len(stored) > 0 && stored[0].Timestamp.Sub(start) < 7*24*time.Hour
+
+	fetchStart := start
+	if covered {
+		fetchStart = stored[len(stored)-1].Timestamp
+	}
+
+	fresh, err := sas.dataService.GetHistoricalBars(ctx, symbol, fetchStart, end, "1Day")
+	if err != nil {
+		if covered {
+			return stored, nil
+		}
+		return nil, err
+	}
+
+	if covered {
+		if stored, covered = sas.reconcileStoredBars(symbol, stored, fresh); !covered {
+			fresh, err = sas.dataService.GetHistoricalBars(ctx, symbol, start, end, "1Day")
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Only cache completed days; today's bar is still changing
+	now := time.Now().In(marketLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, marketLocation)
+	completed := make([]*interfaces.Bar, 0, len(fresh))
+	for _, bar := range fresh {
+		if bar.Timestamp.Before(today) {
+			completed = append(completed, bar)
+		}
+	}
+	if err := sas.barStorage.SaveDailyBars(completed); err != nil {
+		sas.logger.WithError(err).WithField("symbol", symbol).Warn("Failed to cache daily bars")
+	}
+
+	if !covered {
+		return fresh, nil
+	}
+
+	// The refetched last stored day replaces the stored copy
+	if len(fresh) > 0 && fresh[0].Timestamp.Equal(stored[len(stored)-1].Timestamp) {
+		stored = stored[:len(stored)-1]
+	}
+	return append(stored, fresh...), nil
+}
+
+// calculateYearRange computes 52-week high/low proximity from up to the last
+// 250 daily bars. New highs/lows compare the latest bar with the prior bars.
+func calculateYearRange(bars []*interfaces.Bar) *YearRange {
+	if len(bars) < 2 {
+		return nil
+	}
+	if len(bars) > 250 {
+		bars = bars[len(bars)-250:]
+	}
+
+	latest := bars[len(bars)-1]
+	priorHigh := bars[0].High
+	priorLow := bars[0].Low
+	for _, bar := range bars[:len(bars)-1] {
+		priorHigh = math.Max(priorHigh, bar.High)
+		priorLow = math.Min(priorLow, bar.Low)
+	}
+
+	yr := &YearRange{
+		High:    math.Max(priorHigh, latest.High),
+		Low:     math.Min(priorLow, latest.Low),
+		NewHigh: latest.High > priorHigh,
+		NewLow:  latest.Low < priorLow,
+		Bars:    len(bars),
+	}
+	if yr.High > 0 {
+		yr.PctFromHigh = (latest.Close - yr.High) / yr.High * 100
+	}
+	if yr.Low > 0 {
+		yr.PctFromLow = (latest.Close - yr.Low) / yr.Low * 100
+	}
+
+	return yr
+}
+
+// applyYearRange adds 52-week context to the notes and flags new highs/lows as catalysts
+func applyYearRange(setup *TradeSetup, yr *YearRange) {
+	setup.Notes += fmt.Sprintf(" | 52w: %.1f%% from high, %.1f%% above low", yr.PctFromHigh, yr.PctFromLow)
+
+	if yr.NewHigh {
+		setup.KeyCatalysts = append(setup.KeyCatalysts, "New 52-week high")
+	}
+	if yr.NewLow {
+		setup.KeyCatalysts = append(setup.KeyCatalysts, "New 52-week low")
+	}
+}
+
+// barsSince returns the bars at or after the given time
+func barsSince(bars []*interfaces.Bar, since time.Time) []*interfaces.Bar {
+	for i, bar := range bars {
+		if !bar.Timestamp.Before(since) {
+			return bars[i:]
+		}
+	}
+	return bars[len(bars):]
+}
+
+// applyConfluence compares daily and weekly trends and adjusts the composite
+// score in the direction they confirm: +1 when both are bullish, -1 when both
+// are bearish, and -1 when they point opposite ways
+func (sas *StockAnalysisService) applyConfluence(setup *TradeSetup, daily TechnicalAnalysis, weekly *TimeframeSummary) {
+	directional := func(trend string) bool {
+		return trend == "BULLISH" || trend == "BEARISH"
+	}
+
+	adjustment := 0.0
+	switch {
+	case directional(daily.Trend) && daily.Trend == weekly.Trend:
+		setup.Confluence = "ALIGNED"
+		adjustment = 1
+		if daily.Trend == "BEARISH" {
+			adjustment = -1
+		}
+	case directional(daily.Trend) && directional(weekly.Trend):
+		setup.Confluence = "CONFLICTING"
+		adjustment = -1
+	default:
+		setup.Confluence = "NEUTRAL"
+	}
+
+	setup.CompositeScore = math.Max(0, math.Min(10, setup.CompositeScore+adjustment))
+	setup.Notes += fmt.Sprintf(" | Weekly: %s (RSI %.0f) - %s with daily",
+		weekly.Trend, weekly.RSI, setup.Confluence)
+}
+
 func maxInt(a, b int) int {
 	if a > b {
 		return a
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompositeWeightsScore(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights CompositeWeights
+		want    float64
+	}{
+		{"default weights average equally", DefaultCompositeWeights, 6},
+		{"technical only", CompositeWeights{Technical: 1}, 9},
+		{"catalyst heavy", CompositeWeights{Technical: 1, Catalyst: 2, Volume: 1}, 5.3},
+		{"scaled weights normalize to the same score", CompositeWeights{Technical: 10, Catalyst: 20, Volume: 10}, 5.3},
+		{"zero weights fall back to defaults", CompositeWeights{}, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// technical 9, catalyst 3, volume 6
+			if got := tt.weights.Score(9, 3, 6); got != tt.want {
+				t.Errorf("Score = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetCompositeWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		weights CompositeWeights
+		wantErr bool
+	}{
+		{"custom", CompositeWeights{Technical: 2, Catalyst: 1, Volume: 0}, false},
+		{"negative", CompositeWeights{Technical: 1, Catalyst: -1, Volume: 1}, true},
+		{"all zero", CompositeWeights{}, true},
+		{"NaN", CompositeWeights{Technical: math.NaN(), Catalyst: 1, Volume: 1}, true},
+		{"infinite", CompositeWeights{Technical: math.Inf(1), Catalyst: 1, Volume: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sas := NewStockAnalysisService(nil, nil, nil)
+			err := sas.SetCompositeWeights(tt.weights)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetCompositeWeights(%+v) = %v, want error %v", tt.weights, err, tt.wantErr)
+			}
+			if err != nil && sas.weights != DefaultCompositeWeights {
+				t.Errorf("rejected weights replaced the defaults: %+v", sas.weights)
+			}
+		})
+	}
+}
+
+func TestCustomWeightsChangeComposite(t *testing.T) {
+	sas := NewStockAnalysisService(nil, nil, nil)
+	before := sas.weights.Score(9, 3, 6)
+
+	if err := sas.SetCompositeWeights(CompositeWeights{Technical: 3, Catalyst: 1, Volume: 1}); err != nil {
+		t.Fatalf("SetCompositeWeights: %v", err)
+	}
+	after := sas.weights.Score(9, 3, 6)
+
+	// (27 + 3 + 6) / 5
+	if before != 6 || after != 7.2 {
+		t.Errorf("composite before %v after %v, want 6 then 7.2", before, after)
+	}
+}
+
+func TestApplyConfluenceFollowsDirection(t *testing.T) {
+	tests := []struct {
+		name       string
+		daily      string
+		weekly     string
+		confluence string
+		want       float64
+	}{
+		{"aligned bullish", "BULLISH", "BULLISH", "ALIGNED", 6},
+		{"aligned bearish", "BEARISH", "BEARISH", "ALIGNED", 4},
+		{"conflicting", "BULLISH", "BEARISH", "CONFLICTING", 4},
+		{"neutral weekly", "BULLISH", "NEUTRAL", "NEUTRAL", 5},
+		{"neutral daily", "NEUTRAL", "NEUTRAL", "NEUTRAL", 5},
+	}
+
+	sas := NewStockAnalysisService(nil, nil, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup := TradeSetup{CompositeScore: 5}
+			sas.applyConfluence(&setup, TechnicalAnalysis{Trend: tt.daily}, &TimeframeSummary{Trend: tt.weekly})
+			if setup.Confluence != tt.confluence || setup.CompositeScore != tt.want {
+				t.Errorf("got %s %v, want %s %v", setup.Confluence, setup.CompositeScore, tt.confluence, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// headlineCacheTTL bounds how long news headlines are reused across scoring calls
+const headlineCacheTTL = 15 * time.Minute
+
+// emptyHeadlineCacheTTL is shorter since an empty feed is often a throttled
+// response rather than a symbol without news
+const emptyHeadlineCacheTTL = 2 * time.Minute
+
+// StockScore is the lightweight scoring subset of a StockAnalysis
+type StockScore struct {
+	Symbol         string    `json:"symbol"`
+	Price          float64   `json:"price"`
+	TechnicalScore int       `json:"technical_score"`
+	VolumeScore    int       `json:"volume_score"`
+	CatalystScore  int       `json:"catalyst_score"`
+	CompositeScore float64   `json:"composite_score"`
+	Notes          string    `json:"notes"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// cachedHeadlines holds the top headlines and article count for a symbol
+type cachedHeadlines struct {
+	titles    []string
+	count     int
+	fetchedAt time.Time
+}
+
+// ScoreStock computes only the trade setup scores for a symbol. It uses the
+// last 30 daily bars (from the bar cache when configured) and cached news
+// headlines, skipping the quote, weekly and 52-week analysis.
+func (sas *StockAnalysisService) ScoreStock(ctx context.Context, symbol string) (*StockScore, error) {
+	endTime := time.Now()
+	bars, err := sas.fetchDailyBars(ctx, symbol, endTime.AddDate(0, 0, -30), endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bars: %w", err)
+	}
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("no daily bars for %s", symbol)
+	}
+
+	tech := sas.calculateTechnicalIndicators(bars, "1Day")
+	catalysts, _ := sas.recentHeadlines(symbol)
+	setup := sas.generateTradeSetup(tech, catalysts, tech.Price)
+
+	return &StockScore{
+		Symbol:         symbol,
+		Price:          tech.Price,
+		TechnicalScore: setup.TechnicalScore,
+		VolumeScore:    setup.VolumeScore,
+		CatalystScore:  setup.CatalystScore,
+		CompositeScore: setup.CompositeScore,
+		Notes:          setup.Notes,
+		Timestamp:      endTime,
+	}, nil
+}
+
+// recentHeadlines returns the top 3 headlines and the total article count for
+// symbol, reusing results fetched within headlineCacheTTL
+func (sas *StockAnalysisService) recentHeadlines(symbol string) ([]string, int) {
+	sas.headlinesMu.Lock()
+	cached, ok := sas.headlines[symbol]
+	sas.headlinesMu.Unlock()
+	ttl := headlineCacheTTL
+	if cached.count == 0 {
+		ttl = emptyHeadlineCacheTTL
+	}
+	if ok && time.Since(cached.fetchedAt) < ttl {
+		return cached.titles, cached.count
+	}
+
+	// News is optional: throttled or failed searches score without catalysts
+	news, err := sas.newsService.GetGoogleNewsSearch(symbol)
+	if err != nil {
+		sas.logger.WithError(err).WithField("symbol", symbol).Warn("News search failed, continuing without catalysts")
+		return []string{}, 0
+	}
+
+	limit := 3
+	if len(news) < limit {
+		limit = len(news)
+	}
+	titles := make([]string, 0, limit)
+	for i := 0; i < limit; i++ {
+		titles = append(titles, news[i].Title)
+	}
+
+	sas.headlinesMu.Lock()
+	sas.headlines[symbol] = cachedHeadlines{titles: titles, count: len(news), fetchedAt: time.Now()}
+	sas.headlinesMu.Unlock()
+
+	return titles, len(news)
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Stop floor modes
+const (
+	StopFloorOff    = "off"
+	StopFloorWiden  = "widen"
+	StopFloorReject = "reject"
+)
+
+// StopFloorConfig sets the minimum distance a new position's stop must keep
+// from entry. The floor is the larger of MinPercent and ATRMultiple x ATR.
+type StopFloorConfig struct {
+	Mode        string  // "off", "widen" or "reject"
+	MinPercent  float64 // minimum stop distance as a percent of entry (0 = none)
+	ATRMultiple float64 // minimum stop distance in daily ATR(14) units (0 = none)
+}
+
+// SetStopFloor configures the minimum stop distance for new positions
+func (pm *PositionManager) SetStopFloor(config StopFloorConfig) error {
+	switch config.Mode {
+	case StopFloorOff, StopFloorWiden, StopFloorReject:
+	default:
+		return fmt.Errorf("invalid stop floor mode %q: use off, widen or reject", config.Mode)
+	}
+	if config.MinPercent < 0 || config.ATRMultiple < 0 {
+		return fmt.Errorf("stop floor distances must be non-negative")
+	}
+
+	pm.stopFloor = config
+	return nil
+}
+
+// applyStopFloor checks the stop against the configured floor, returning the
+// stop to use: widened to the floor, or unchanged. In reject mode a too-tight
+// stop is an error naming the minimum.
+func (pm *PositionManager) applyStopFloor(ctx context.Context, symbol, side string, entryPrice, stopPrice float64) (float64, error) {
+	floor := pm.stopFloor
+	if floor.Mode == StopFloorOff || (floor.MinPercent <= 0 && floor.ATRMultiple <= 0) || entryPrice <= 0 {
+		return stopPrice, nil
+	}
+
+	minDistance := entryPrice * floor.MinPercent / 100.0
+	basis := "percent"
+	if floor.ATRMultiple > 0 {
+		atr, err := pm.dailyATR(ctx, symbol)
+		if err != nil {
+			// Fall back to the percent floor rather than block the trade
+			pm.logger.WithError(err).WithField("symbol", symbol).Warn("ATR unavailable for stop floor, using percent floor only")
+		} else if atrDistance := floor.ATRMultiple * atr; atrDistance > minDistance {
+			minDistance = atrDistance
+			basis = "atr"
+		}
+	}
+
+	distance := math.Abs(entryPrice - stopPrice)
+	if minDistance <= 0 || distance >= minDistance {
+		return stopPrice, nil
+	}
+
+	minPercent := minDistance / entryPrice * 100
+	widened := entryPrice - minDistance
+	if side == "sell" {
+		widened = entryPrice + minDistance
+	}
+
+	if floor.Mode == StopFloorReject || widened <= 0 {
+		return 0, fmt.Errorf("stop %.2f is %.2f%% from entry, tighter than the %.2f%% minimum (%s floor) - use a stop at or beyond %.2f",
+			stopPrice, distance/entryPrice*100, minPercent, basis, widened)
+	}
+
+	pm.logger.WithFields(logrus.Fields{
+		"symbol":      symbol,
+		"requested":   stopPrice,
+		"widened_to":  widened,
+		"min_percent": minPercent,
+		"floor_basis": basis,
+	}).Warn("Stop too tight, widened to the minimum distance")
+
+	return widened, nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"prophet-trader/apperrors"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StopPlacementConfig controls how protective orders wait for the broker to
+// register a freshly filled position. Right after a fill the broker can still
+// reject a stop for lack of shares; each attempt waits RetryDelay first.
+type StopPlacementConfig struct {
+	Attempts   int           // tries for the position check and the stop order (at least 1)
+	RetryDelay time.Duration // wait between tries
+}
+
+// DefaultStopPlacementConfig retries for about two seconds
+var DefaultStopPlacementConfig = StopPlacementConfig{
+	Attempts:   3,
+	RetryDelay: time.Second,
+}
+
+// positionNotReadyMessages are broker rejections meaning the shares aren't registered yet
+var positionNotReadyMessages = []string{
+	"insufficient qty",
+	"position not found",
+	"position does not exist",
+	"no position",
+}
+
+// SetStopPlacement configures the confirmation and retry before protective orders
+func (pm *PositionManager) SetStopPlacement(config StopPlacementConfig) error {
+	if config.Attempts < 1 {
+		return fmt.Errorf("stop placement attempts must be at least 1")
+	}
+	if config.RetryDelay < 0 {
+		return fmt.Errorf("stop placement retry delay must not be negative")
+	}
+
+	pm.stopPlacement = config
+	return nil
+}
+
+// SetAlertSender sets where urgent alerts, such as a position left without a
+// stop, are delivered. Nil leaves them in the log and activity log only.
+func (pm *PositionManager) SetAlertSender(sender ReportSender) {
+	pm.alerts = sender
+}
+
+// confirmBrokerPosition waits until the broker reports shares of the position,
+// reporting whether it did. A failed position lookup counts as unconfirmed.
+func (pm *PositionManager) confirmBrokerPosition(ctx context.Context, position *ManagedPosition) bool {
+	for attempt := 1; ; attempt++ {
+		positions, err := pm.broker(position).GetPositions(ctx)
+		if err == nil {
+			for _, p := range positions {
+				if strings.EqualFold(p.Symbol, position.Symbol) && math.Abs(p.Qty) > 0 {
+					return true
+				}
+			}
+		}
+
+		if attempt >= pm.stopPlacement.Attempts || !sleepContext(ctx, pm.stopPlacement.RetryDelay) {
+			return false
+		}
+	}
+}
+
+// placeProtectiveStop confirms the broker holds the position, then places the
+// stop loss, retrying while the broker rejects it as not yet holding the shares
+func (pm *PositionManager) placeProtectiveStop(ctx context.Context, position *ManagedPosition) error {
+	if !pm.confirmBrokerPosition(ctx, position) {
+		pm.logger.WithFields(logrus.Fields{
+			"position_id": position.ID,
+			"symbol":      position.Symbol,
+		}).Warn("Broker position not confirmed, placing stop loss anyway")
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = pm.placeStopLossOrder(ctx, position)
+		if err == nil || !isPositionNotReady(err) || attempt >= pm.stopPlacement.Attempts {
+			return err
+		}
+
+		pm.logger.WithError(err).WithFields(logrus.Fields{
+			"position_id": position.ID,
+			"attempt":     attempt,
+		}).Warn("Stop loss rejected before the broker registered the position, retrying")
+
+		if !sleepContext(ctx, pm.stopPlacement.RetryDelay) {
+			return err
+		}
+	}
+}
+
+// isPositionNotReady reports whether a rejection means the broker hasn't
+// registered the position's shares yet
+func isPositionNotReady(err error) bool {
+	if errors.Is(err, apperrors.ErrNotFound) {
+		return true
+	}
+	message := strings.ToLower(err.Error())
+	for _, text := range positionNotReadyMessages {
+		if strings.Contains(message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// alertUnprotected escalates a position whose stop loss could not be placed
+func (pm *PositionManager) alertUnprotected(ctx context.Context, position *ManagedPosition, cause error) {
+	message := fmt.Sprintf("%s %s position %s (%.4g shares) has no stop loss at %.2f: %v",
+		position.Symbol, position.Side, position.ID, position.RemainingQty, position.StopLossPrice, cause)
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id": position.ID,
+		"symbol":      position.Symbol,
+	}).Error("POSITION UNPROTECTED: " + message)
+
+	pm.logPositionEvent(position, "UNPROTECTED", message, map[string]interface{}{
+		"stop_loss_price": position.StopLossPrice,
+		"error":           cause.Error(),
+	})
+
+	if pm.alerts == nil {
+		return
+	}
+	subject := fmt.Sprintf("Position unprotected: %s", position.Symbol)
+	if err := pm.alerts.SendReport(ctx, subject, message, "text/plain"); err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Warn("Failed to send unprotected position alert")
+	}
+}
+
+// sleepContext waits for d, returning false if ctx ends first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
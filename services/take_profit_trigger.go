@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"prophet-trader/interfaces"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Take-profit order types. A resting limit fills at the target or better but
+// may not fill at all when a fast print gaps through it, leaving the position
+// open past the target. stop (market-on-touch) exits at market as soon as
+// price reaches the target, so a gap-through still exits but may fill worse
+// than the target; stop_limit exits with a limit offset beyond the target,
+// bounding the fill but risking no fill in a fast move.
+//
+// A sell stop above the market (or a buy stop below it, for shorts) isn't a
+// valid resting order, so touch targets are watched by the monitor instead
+// of resting at the broker, the way options exits are. Once the target is
+// touched the stop and any partial exit are cancelled to release the shares
+// and the exit order is tracked as the take-profit order.
+const (
+	TakeProfitTypeLimit     = "limit"
+	TakeProfitTypeStop      = "stop"
+	TakeProfitTypeStopLimit = "stop_limit"
+)
+
+// TakeProfitTouchWarning explains the tradeoff of stop and stop_limit targets
+const TakeProfitTouchWarning = "Stop (market-on-touch) targets exit once price reaches the target but may fill worse than it in a fast move; stop_limit targets bound the fill but may not fill at all if price moves through the limit. Both are triggered by the monitor, not resting broker orders."
+
+func validTakeProfitType(orderType string) bool {
+	switch orderType {
+	case "", TakeProfitTypeLimit, TakeProfitTypeStop, TakeProfitTypeStopLimit:
+		return true
+	}
+	return false
+}
+
+// isTouchTarget reports whether the position's target is triggered by the
+// monitor rather than a resting limit order
+func isTouchTarget(position *ManagedPosition) bool {
+	return position.TakeProfitType == TakeProfitTypeStop || position.TakeProfitType == TakeProfitTypeStopLimit
+}
+
+// targetTouched reports whether the current price has reached the target
+func targetTouched(position *ManagedPosition) bool {
+	if position.TakeProfitPrice <= 0 || position.CurrentPrice <= 0 {
+		return false
+	}
+	if position.Side == "sell" {
+		return position.CurrentPrice <= position.TakeProfitPrice
+	}
+	return position.CurrentPrice >= position.TakeProfitPrice
+}
+
+// checkTouchTarget triggers a touch target's exit once price reaches it and
+// follows the exit order until it fills. It reports whether an exit is under
+// way, in which case the stop must not be moved or re-placed.
+func (pm *PositionManager) checkTouchTarget(ctx context.Context, position *ManagedPosition) bool {
+	if position.TakeProfitOrderID != "" {
+		return pm.followTargetExit(ctx, position)
+	}
+
+	// A pending reduce holds part of the shares the exit would sell
+	if position.ReduceOrderID != "" {
+		return false
+	}
+
+	// Equity quotes outside the session are stale and exits wouldn't fill
+	if position.AssetClass != AssetClassCrypto && !IsMarketOpen(time.Now()) {
+		return false
+	}
+	if !targetTouched(position) {
+		return false
+	}
+
+	if err := pm.releaseForTargetExit(ctx, position); err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Warn("Target touched but risk orders not released, retrying next cycle")
+		return false
+	}
+
+	exitSide := "sell"
+	if position.Side == "sell" {
+		exitSide = "buy"
+	}
+
+	orderType := "market"
+	if position.TakeProfitType == TakeProfitTypeStopLimit {
+		orderType = "limit"
+	}
+	qty := pm.normalizeQty(ctx, position.Symbol, position.RemainingQty, orderType, position.TargetTimeInForce)
+
+	order := &interfaces.Order{
+		Symbol:      position.Symbol,
+		Qty:         qty,
+		Side:        exitSide,
+		Type:        orderType,
+		TimeInForce: position.TargetTimeInForce,
+		Status:      "pending",
+		SubmittedAt: time.Now(),
+	}
+	if orderType == "limit" {
+		// Bound the fill with a limit beyond the target (below for longs, above for shorts)
+		limitPrice := position.TakeProfitPrice * (1 - position.TakeProfitLimitOffset/100.0)
+		if position.Side == "sell" {
+			limitPrice = position.TakeProfitPrice * (1 + position.TakeProfitLimitOffset/100.0)
+		}
+		limitPrice = pm.roundPrice(ctx, position.Symbol, limitPrice)
+		order.LimitPrice = &limitPrice
+	}
+
+	var result *interfaces.OrderResult
+	var err error
+	if qty <= 0 {
+		err = fmt.Errorf("remaining quantity %.6f is below the minimum for a %s order", position.RemainingQty, orderType)
+	} else {
+		result, err = pm.broker(position).PlaceOrder(ctx, order)
+	}
+	if err != nil {
+		pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to place target exit order, restoring stop")
+		if err := pm.placeStopLossOrder(ctx, position); err != nil {
+			pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to restore stop loss")
+		}
+		pm.savePositionToDB(position)
+		return false
+	}
+
+	order.ID = result.OrderID
+	position.TakeProfitOrderID = result.OrderID
+	position.UpdatedAt = time.Now()
+	pm.saveOrder(position, OrderRoleTakeProfit, order)
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id":   position.ID,
+		"order_id":      result.OrderID,
+		"current_price": position.CurrentPrice,
+		"target_price":  position.TakeProfitPrice,
+	}).Info("Target touched, exit order placed")
+	pm.logPositionEvent(position, "TARGET_TOUCHED",
+		fmt.Sprintf("Price %.2f reached target %.2f - %s exit placed", position.CurrentPrice, position.TakeProfitPrice, orderType),
+		map[string]interface{}{
+			"order_id":    result.OrderID,
+			"order_type":  orderType,
+			"limit_price": order.LimitPrice,
+		})
+
+	if err := pm.savePositionToDB(position); err != nil {
+		pm.logger.WithError(err).Error("Failed to save position to database")
+	}
+	return true
+}
+
+// releaseForTargetExit cancels the open partial exits and the stop so their
+// shares can be sold by the target exit. Each cancel is confirmed first: an
+// order still live (or filled meanwhile) alongside the exit would oversell.
+func (pm *PositionManager) releaseForTargetExit(ctx context.Context, position *ManagedPosition) error {
+	for _, orderID := range position.PartialExitOrders {
+		order, err := pm.broker(position).GetOrder(ctx, orderID)
+		if err != nil {
+			return fmt.Errorf("failed to check partial exit order %s: %w", orderID, err)
+		}
+		switch order.Status {
+		case "filled", "canceled", "expired", "rejected", "replaced":
+			continue
+		}
+		if err := pm.cancelConfirmed(ctx, position, orderID); err != nil {
+			return err
+		}
+	}
+
+	if position.StopLossOrderID != "" {
+		if err := pm.cancelConfirmed(ctx, position, position.StopLossOrderID); err != nil {
+			return err
+		}
+		position.StopLossOrderID = ""
+	}
+
+	return nil
+}
+
+// followTargetExit closes the position once its target exit fills. An exit
+// that ends unfilled (e.g. a stop_limit the market ran through, or a day
+// order at the close) restores the stop so the position isn't left
+// unprotected, and the target can trigger again.
+func (pm *PositionManager) followTargetExit(ctx context.Context, position *ManagedPosition) bool {
+	order, err := pm.broker(position).GetOrder(ctx, position.TakeProfitOrderID)
+	if err != nil {
+		pm.logger.WithError(err).WithField("order_id", position.TakeProfitOrderID).Warn("Failed to check target exit order")
+		return true
+	}
+
+	switch order.Status {
+	case "filled":
+		pm.closeAtTarget(ctx, position, order)
+		return true
+	case "canceled", "expired", "rejected":
+		pm.logger.WithFields(logrus.Fields{
+			"position_id": position.ID,
+			"order_id":    order.ID,
+			"status":      order.Status,
+		}).Warn("Target exit order ended unfilled, restoring stop")
+		pm.logPositionEvent(position, "TARGET_EXIT_UNFILLED", fmt.Sprintf("Target exit order %s - stop restored", order.Status), map[string]interface{}{
+			"order_id": position.TakeProfitOrderID,
+		})
+		position.TakeProfitOrderID = ""
+		if err := pm.placeStopLossOrder(ctx, position); err != nil {
+			pm.logger.WithError(err).WithField("position_id", position.ID).Error("Failed to restore stop loss")
+		}
+		pm.savePositionToDB(position)
+		return false
+	}
+	return true
+}
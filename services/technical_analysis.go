@@ -5,20 +5,84 @@ import (
 	"fmt"
 	"math"
 	"prophet-trader/interfaces"
+	"time"
 )
 
-// TechnicalAnalysisService provides technical analysis calculations
+// Indicator names used for minimum-bar requirements and data quality reporting
+const (
+	IndicatorSMA20    = "sma_20"
+	IndicatorSMA50    = "sma_50"
+	IndicatorRSI14    = "rsi_14"
+	IndicatorMACD     = "macd"
+	IndicatorMomentum = "momentum"
+	IndicatorVolume   = "volume"
+	IndicatorATR14    = "atr_14"
+	IndicatorSAR      = "parabolic_sar"
+)
+
+// analysisIndicators is the fixed bundle Analyze computes; each result name
+// matches an Indicator constant
+var analysisIndicators = []IndicatorSpec{
+	{Name: "sma", Period: 20},
+	{Name: "sma", Period: 50},
+	{Name: "rsi", Period: 14},
+	{Name: "macd"},
+	{Name: "momentum"},
+	{Name: "volume"},
+	{Name: "atr", Period: 14},
+	{Name: "parabolic_sar"},
+}
+
+// indicatorMinBars is the fewest bars each indicator can be computed from
+var indicatorMinBars = map[string]int{
+	IndicatorSMA20:    20,
+	IndicatorSMA50:    50,
+	IndicatorRSI14:    15,
+	IndicatorMACD:     26,
+	IndicatorMomentum: 6,
+	IndicatorVolume:   20,
+	IndicatorATR14:    15,
+	IndicatorSAR:      5,
+}
+
+// TechnicalAnalysisService provides technical analysis calculations.
+//
+// Look-ahead contract: every indicator treats the last bar of the slice it is
+// given as "now" and reads no bar after it. Historical analyses go through
+// AnalyzeAt, which also checks bar order and caps the slice, so signals only
+// use bars that would have been known at the time.
 type TechnicalAnalysisService struct {
 	dataService interfaces.DataService
+	minBars     map[string]int // indicator -> required bars (overrides defaults)
 }
 
 // NewTechnicalAnalysisService creates a new technical analysis service
 func NewTechnicalAnalysisService(dataService interfaces.DataService) *TechnicalAnalysisService {
 	return &TechnicalAnalysisService{
 		dataService: dataService,
+		minBars:     make(map[string]int),
 	}
 }
 
+// SetMinBars sets the minimum number of bars required before an indicator is computed.
+// Values below the indicator's mathematical minimum are raised to that minimum.
+func (tas *TechnicalAnalysisService) SetMinBars(indicator string, bars int) error {
+	if _, ok := indicatorMinBars[indicator]; !ok {
+		return fmt.Errorf("unknown indicator: %s", indicator)
+	}
+	tas.minBars[indicator] = bars
+	return nil
+}
+
+// requiredBars returns the effective minimum bar count for an indicator
+func (tas *TechnicalAnalysisService) requiredBars(indicator string) int {
+	required := indicatorMinBars[indicator]
+	if configured, ok := tas.minBars[indicator]; ok && configured > required {
+		return configured
+	}
+	return required
+}
+
 // AnalysisResult contains comprehensive technical analysis
 type AnalysisResult struct {
 	Symbol      string           `json:"symbol"`
@@ -29,8 +93,32 @@ type AnalysisResult struct {
 	MACD        *MACDResult      `json:"macd,omitempty"`
 	Momentum    *MomentumResult  `json:"momentum,omitempty"`
 	Volume      *VolumeAnalysis  `json:"volume,omitempty"`
+	ATR         float64          `json:"atr,omitempty"`
+	SAR         *ParabolicSARResult `json:"parabolic_sar,omitempty"`
 	Signal      string           `json:"signal"` // "BUY", "SELL", "HOLD"
 	Confidence  float64          `json:"confidence"` // 0-100
+	DataQuality *DataQuality     `json:"data_quality"`
+}
+
+// DataQuality reports which indicators could be computed from the available bars
+type DataQuality struct {
+	BarCount int               `json:"bar_count"`
+	Computed []string          `json:"computed"`
+	Skipped  map[string]string `json:"skipped,omitempty"` // indicator -> reason
+	Coverage float64           `json:"coverage"`          // % of indicators computed
+}
+
+// Has reports whether an indicator was computed
+func (dq *DataQuality) Has(indicator string) bool {
+	if dq == nil {
+		return false
+	}
+	for _, name := range dq.Computed {
+		if name == indicator {
+			return true
+		}
+	}
+	return false
 }
 
 // MACDResult contains MACD indicator values
@@ -56,7 +144,8 @@ type VolumeAnalysis struct {
 	Trend        string  `json:"trend"` // "increasing", "decreasing", "stable"
 }
 
-// CalculateSMA calculates Simple Moving Average
+// CalculateSMA calculates Simple Moving Average.
+// Reads the last period closes.
 func CalculateSMA(bars []*interfaces.Bar, period int) float64 {
 	if len(bars) < period {
 		return 0
@@ -71,7 +160,8 @@ func CalculateSMA(bars []*interfaces.Bar, period int) float64 {
 	return sum / float64(period)
 }
 
-// CalculateRSI calculates Relative Strength Index
+// CalculateRSI calculates Relative Strength Index.
+// Reads the last period+1 closes.
 func CalculateRSI(bars []*interfaces.Bar, period int) float64 {
 	if len(bars) < period+1 {
 		return 50.0 // neutral
@@ -105,7 +195,8 @@ func CalculateRSI(bars []*interfaces.Bar, period int) float64 {
 	return rsi
 }
 
-// CalculateMACD calculates MACD indicator
+// CalculateMACD calculates MACD indicator.
+// Reads every close in the slice, oldest first.
 func CalculateMACD(bars []*interfaces.Bar) *MACDResult {
 	if len(bars) < 26 {
 		return nil
@@ -126,8 +217,258 @@ func CalculateMACD(bars []*interfaces.Bar) *MACDResult {
 	}
 }
 
-// Analyze performs comprehensive technical analysis
+// CalculateATR calculates Average True Range using Wilder's smoothing.
+// Reads every bar in the slice; each true range uses only the bar and the one before it.
+func CalculateATR(bars []*interfaces.Bar, period int) float64 {
+	if period <= 0 || len(bars) < period+1 {
+		return 0
+	}
+
+	trueRange := func(i int) float64 {
+		prevClose := bars[i-1].Close
+		return math.Max(bars[i].High-bars[i].Low,
+			math.Max(math.Abs(bars[i].High-prevClose), math.Abs(bars[i].Low-prevClose)))
+	}
+
+	// Seed with the simple average of the first period true ranges
+	atr := 0.0
+	for i := 1; i <= period; i++ {
+		atr += trueRange(i)
+	}
+	atr /= float64(period)
+
+	for i := period + 1; i < len(bars); i++ {
+		atr = (atr*float64(period-1) + trueRange(i)) / float64(period)
+	}
+
+	return atr
+}
+
+// BollingerResult contains Bollinger Band values
+type BollingerResult struct {
+	Middle    float64 `json:"middle"`
+	Upper     float64 `json:"upper"`
+	Lower     float64 `json:"lower"`
+	Bandwidth float64 `json:"bandwidth"` // (upper - lower) / middle * 100
+	PercentB  float64 `json:"percent_b"` // where the close sits: 0 = lower band, 1 = upper band
+}
+
+// CalculateBollinger calculates Bollinger Bands stdDev standard deviations
+// around the period SMA. Reads the last period closes.
+func CalculateBollinger(bars []*interfaces.Bar, period int, stdDev float64) *BollingerResult {
+	if period <= 0 || len(bars) < period {
+		return nil
+	}
+
+	middle := CalculateSMA(bars, period)
+	closes := make([]float64, 0, period)
+	for i := len(bars) - period; i < len(bars); i++ {
+		closes = append(closes, bars[i].Close)
+	}
+	// Bollinger bands are defined on the population deviation of the window
+	deviation := StandardDeviation(closes, false)
+
+	result := &BollingerResult{
+		Middle: middle,
+		Upper:  middle + stdDev*deviation,
+		Lower:  middle - stdDev*deviation,
+	}
+	if middle != 0 {
+		result.Bandwidth = (result.Upper - result.Lower) / middle * 100
+	}
+	if width := result.Upper - result.Lower; width > 0 {
+		result.PercentB = (bars[len(bars)-1].Close - result.Lower) / width
+	}
+	return result
+}
+
+// Default Parabolic SAR acceleration factor step and ceiling (Wilder)
+const (
+	DefaultSARStep = 0.02
+	DefaultSARMax  = 0.2
+)
+
+// ParabolicSARResult contains the Parabolic SAR series and its latest state
+type ParabolicSARResult struct {
+	Series       []float64 `json:"-"`             // SAR value for each bar
+	SAR          float64   `json:"sar"`           // latest SAR
+	Trend        string    `json:"trend"`         // "up" (SAR below price) or "down" (SAR above price)
+	AccelFactor  float64   `json:"accel_factor"`  // current acceleration factor
+	ExtremePoint float64   `json:"extreme_point"` // highest high (up) or lowest low (down) of the current trend
+}
+
+// CalculateParabolicSAR calculates Wilder's Parabolic SAR. The acceleration
+// factor starts at accelStep, grows by accelStep on each new extreme point up
+// to accelMax, and resets when price crosses the SAR and the trend flips.
+// Series[i] uses only bars[0..i].
+func CalculateParabolicSAR(bars []*interfaces.Bar, accelStep, accelMax float64) *ParabolicSARResult {
+	if len(bars) < 2 || accelStep <= 0 || accelMax < accelStep {
+		return nil
+	}
+
+	// Seed the trend from the first two closes
+	up := bars[1].Close >= bars[0].Close
+	af := accelStep
+	sar, ep := bars[0].High, bars[0].Low
+	if up {
+		sar, ep = bars[0].Low, bars[0].High
+	}
+
+	series := make([]float64, len(bars))
+	series[0] = sar
+
+	for i := 1; i < len(bars); i++ {
+		sar += af * (ep - sar)
+
+		if up {
+			// SAR may not rise above the prior two lows
+			sar = math.Min(sar, bars[i-1].Low)
+			if i >= 2 {
+				sar = math.Min(sar, bars[i-2].Low)
+			}
+
+			if bars[i].Low < sar {
+				// Trend flip: SAR jumps to the prior extreme point
+				up = false
+				sar = ep
+				ep = bars[i].Low
+				af = accelStep
+			} else if bars[i].High > ep {
+				ep = bars[i].High
+				af = math.Min(af+accelStep, accelMax)
+			}
+		} else {
+			// SAR may not fall below the prior two highs
+			sar = math.Max(sar, bars[i-1].High)
+			if i >= 2 {
+				sar = math.Max(sar, bars[i-2].High)
+			}
+
+			if bars[i].High > sar {
+				up = true
+				sar = ep
+				ep = bars[i].High
+				af = accelStep
+			} else if bars[i].Low < ep {
+				ep = bars[i].Low
+				af = math.Min(af+accelStep, accelMax)
+			}
+		}
+
+		series[i] = sar
+	}
+
+	trend := "down"
+	if up {
+		trend = "up"
+	}
+
+	return &ParabolicSARResult{
+		Series:       series,
+		SAR:          sar,
+		Trend:        trend,
+		AccelFactor:  af,
+		ExtremePoint: ep,
+	}
+}
+
+// AnchoredVWAPResult reports VWAP accumulated from an anchor bar to the latest bar
+type AnchoredVWAPResult struct {
+	AnchorTime   time.Time `json:"anchor_time"` // timestamp of the first bar included
+	VWAP         float64   `json:"vwap"`
+	CurrentPrice float64   `json:"current_price"`
+	DeviationPct float64   `json:"deviation_percent"` // (price - vwap) / vwap
+	AboveVWAP    bool      `json:"above_vwap"`
+	Bars         int       `json:"bars"`
+	Volume       int64     `json:"volume"`
+}
+
+// AnchoredVWAP calculates the volume-weighted average price from the first bar
+// at or after anchorTime through the latest bar. Each bar contributes its own
+// VWAP when available, otherwise its typical price (high+low+close)/3.
+// Reads bars from the anchor through the end of the slice.
+func AnchoredVWAP(bars []*interfaces.Bar, anchorTime time.Time) (*AnchoredVWAPResult, error) {
+	start := -1
+	for i, bar := range bars {
+		if !bar.Timestamp.Before(anchorTime) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, fmt.Errorf("no bars at or after anchor %s", anchorTime.Format(time.RFC3339))
+	}
+
+	priceVolume := 0.0
+	var volume int64
+	for _, bar := range bars[start:] {
+		price := bar.VWAP
+		if price <= 0 {
+			price = (bar.High + bar.Low + bar.Close) / 3
+		}
+		priceVolume += price * float64(bar.Volume)
+		volume += bar.Volume
+	}
+
+	if volume == 0 {
+		return nil, fmt.Errorf("no volume since anchor %s", anchorTime.Format(time.RFC3339))
+	}
+
+	latest := bars[len(bars)-1]
+	vwap := priceVolume / float64(volume)
+
+	return &AnchoredVWAPResult{
+		AnchorTime:   bars[start].Timestamp,
+		VWAP:         vwap,
+		CurrentPrice: latest.Close,
+		DeviationPct: (latest.Close - vwap) / vwap * 100,
+		AboveVWAP:    latest.Close > vwap,
+		Bars:         len(bars) - start,
+		Volume:       volume,
+	}, nil
+}
+
+// GetAnchoredVWAP fetches bars from the anchor to now and computes the anchored VWAP
+func (tas *TechnicalAnalysisService) GetAnchoredVWAP(ctx context.Context, symbol string, anchorTime time.Time, timeframe string) (*AnchoredVWAPResult, error) {
+	bars, err := tas.dataService.GetHistoricalBars(ctx, symbol, anchorTime, time.Now(), timeframe)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bars: %w", err)
+	}
+
+	return AnchoredVWAP(bars, anchorTime)
+}
+
+// AnalyzeAt analyzes as of a past point, using only bars[:current] so the
+// bar at index current-1 is "now". The slice's capacity is capped so no
+// indicator can reslice into bars[current:], and bars must be in strict time
+// order so a later bar can't leak into an earlier signal.
+func (tas *TechnicalAnalysisService) AnalyzeAt(ctx context.Context, symbol string, bars []*interfaces.Bar, current int) (*AnalysisResult, error) {
+	if current <= 0 || current > len(bars) {
+		return nil, fmt.Errorf("current bar index %d out of range for %d bars", current, len(bars))
+	}
+
+	visible := bars[:current:current]
+	if err := checkBarOrder(visible); err != nil {
+		return nil, err
+	}
+
+	result, err := tas.analyze(ctx, symbol, visible)
+	if err != nil {
+		return nil, err
+	}
+	if result.CurrentPrice != bars[current-1].Close {
+		return nil, fmt.Errorf("look-ahead check failed: analysis priced at %.4f, not bar %d's close %.4f", result.CurrentPrice, current-1, bars[current-1].Close)
+	}
+	return result, nil
+}
+
+// Analyze performs comprehensive technical analysis, treating the last bar as "now"
 func (tas *TechnicalAnalysisService) Analyze(ctx context.Context, symbol string, bars []*interfaces.Bar) (*AnalysisResult, error) {
+	return tas.analyze(ctx, symbol, bars)
+}
+
+// analyze computes every indicator from bars, whose last bar is "now"
+func (tas *TechnicalAnalysisService) analyze(ctx context.Context, symbol string, bars []*interfaces.Bar) (*AnalysisResult, error) {
 	if len(bars) == 0 {
 		return nil, fmt.Errorf("no bars data available")
 	}
@@ -138,27 +479,55 @@ func (tas *TechnicalAnalysisService) Analyze(ctx context.Context, symbol string,
 		CurrentPrice: currentBar.Close,
 	}
 
-	// Calculate SMAs
-	if len(bars) >= 20 {
-		result.SMA20 = CalculateSMA(bars, 20)
-	}
-	if len(bars) >= 50 {
-		result.SMA50 = CalculateSMA(bars, 50)
+	indicators, err := computeIndicators(bars, analysisIndicators, func(key string, required int) int {
+		if configured := tas.requiredBars(key); configured > required {
+			return configured
+		}
+		return required
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Calculate RSI
-	if len(bars) >= 15 {
-		result.RSI = CalculateRSI(bars, 14)
+	quality := &DataQuality{
+		BarCount: len(bars),
+		Computed: make([]string, 0),
+		Skipped:  indicators.Skipped,
+	}
+	for _, spec := range analysisIndicators {
+		if _, ok := indicators.Values[indicatorKey(spec)]; ok {
+			quality.Computed = append(quality.Computed, indicatorKey(spec))
+		}
 	}
 
-	// Calculate MACD
-	result.MACD = CalculateMACD(bars)
-
-	// Calculate Momentum
-	result.Momentum = calculateMomentum(bars)
+	values := indicators.Values
+	if v, ok := values[IndicatorSMA20].(float64); ok {
+		result.SMA20 = v
+	}
+	if v, ok := values[IndicatorSMA50].(float64); ok {
+		result.SMA50 = v
+	}
+	if v, ok := values[IndicatorRSI14].(float64); ok {
+		result.RSI = v
+	}
+	if v, ok := values[IndicatorMACD].(*MACDResult); ok {
+		result.MACD = v
+	}
+	if v, ok := values[IndicatorMomentum].(*MomentumResult); ok {
+		result.Momentum = v
+	}
+	if v, ok := values[IndicatorVolume].(*VolumeAnalysis); ok {
+		result.Volume = v
+	}
+	if v, ok := values[IndicatorATR14].(float64); ok {
+		result.ATR = v
+	}
+	if v, ok := values[IndicatorSAR].(*ParabolicSARResult); ok {
+		result.SAR = v
+	}
 
-	// Calculate Volume Analysis
-	result.Volume = analyzeVolume(bars)
+	quality.Coverage = float64(len(quality.Computed)) / float64(len(indicatorMinBars)) * 100
+	result.DataQuality = quality
 
 	// Generate trading signal
 	result.Signal, result.Confidence = generateSignal(result)
@@ -168,6 +537,41 @@ func (tas *TechnicalAnalysisService) Analyze(ctx context.Context, symbol string,
 
 // Helper functions
 
+// checkBarOrder verifies bars are strictly in time order
+func checkBarOrder(bars []*interfaces.Bar) error {
+	for i := 1; i < len(bars); i++ {
+		if !bars[i].Timestamp.After(bars[i-1].Timestamp) {
+			return fmt.Errorf("look-ahead check failed: bar %d (%s) is not after bar %d (%s)",
+				i, bars[i].Timestamp.Format(time.RFC3339), i-1, bars[i-1].Timestamp.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
+
+// StandardDeviation returns the population (divide by N) or sample (divide by
+// N-1) standard deviation. Use sample for volatility of returns, which
+// estimates the true volatility from a handful of observations; use
+// population for Bollinger bands, which describe the window itself and match
+// charting tools.
+func StandardDeviation(values []float64, sample bool) float64 {
+	n := len(values)
+	if n == 0 || (sample && n < 2) {
+		return 0
+	}
+
+	mean := average(values)
+	variance := 0.0
+	for _, v := range values {
+		diff := v - mean
+		variance += diff * diff
+	}
+
+	if sample {
+		return math.Sqrt(variance / float64(n-1))
+	}
+	return math.Sqrt(variance / float64(n))
+}
+
 func average(values []float64) float64 {
 	if len(values) == 0 {
 		return 0
@@ -179,6 +583,7 @@ func average(values []float64) float64 {
 	return sum / float64(len(values))
 }
 
+// calculateEMA reads every close in the slice, oldest first
 func calculateEMA(bars []*interfaces.Bar, period int) float64 {
 	if len(bars) < period {
 		return bars[len(bars)-1].Close
@@ -197,6 +602,7 @@ func calculateEMA(bars []*interfaces.Bar, period int) float64 {
 	return ema
 }
 
+// calculateMomentum reads the last 6 closes
 func calculateMomentum(bars []*interfaces.Bar) *MomentumResult {
 	if len(bars) < 6 {
 		return nil
@@ -214,6 +620,7 @@ func calculateMomentum(bars []*interfaces.Bar) *MomentumResult {
 	}
 }
 
+// analyzeVolume reads the last 20 volumes
 func analyzeVolume(bars []*interfaces.Bar) *VolumeAnalysis {
 	if len(bars) < 20 {
 		return nil
@@ -249,8 +656,11 @@ func generateSignal(result *AnalysisResult) (string, float64) {
 	signals := make(map[string]int)
 	confidence := 0.0
 
+	// Skipped indicators are ignored rather than treated as neutral readings
+	quality := result.DataQuality
+
 	// Price vs SMA signals
-	if result.SMA20 > 0 {
+	if quality.Has(IndicatorSMA20) {
 		if result.CurrentPrice > result.SMA20 {
 			signals["buy"]++
 			confidence += 15
@@ -260,7 +670,7 @@ func generateSignal(result *AnalysisResult) (string, float64) {
 		}
 	}
 
-	if result.SMA50 > 0 {
+	if quality.Has(IndicatorSMA20) && quality.Has(IndicatorSMA50) {
 		if result.SMA20 > result.SMA50 {
 			signals["buy"]++
 			confidence += 20
@@ -271,7 +681,7 @@ func generateSignal(result *AnalysisResult) (string, float64) {
 	}
 
 	// RSI signals
-	if result.RSI > 0 {
+	if quality.Has(IndicatorRSI14) {
 		if result.RSI < 30 {
 			signals["buy"] += 2
 			confidence += 25
@@ -284,7 +694,7 @@ func generateSignal(result *AnalysisResult) (string, float64) {
 	}
 
 	// MACD signals
-	if result.MACD != nil {
+	if quality.Has(IndicatorMACD) && result.MACD != nil {
 		if result.MACD.Histogram > 0 {
 			signals["buy"]++
 			confidence += 15
@@ -295,7 +705,7 @@ func generateSignal(result *AnalysisResult) (string, float64) {
 	}
 
 	// Momentum signals
-	if result.Momentum != nil {
+	if quality.Has(IndicatorMomentum) && result.Momentum != nil {
 		if result.Momentum.PercentChange5D > 5 {
 			signals["buy"]++
 			confidence += 10
@@ -306,7 +716,7 @@ func generateSignal(result *AnalysisResult) (string, float64) {
 	}
 
 	// Volume confirmation
-	if result.Volume != nil && result.Volume.Ratio > 1.2 {
+	if quality.Has(IndicatorVolume) && result.Volume != nil && result.Volume.Ratio > 1.2 {
 		confidence += 5
 	}
 
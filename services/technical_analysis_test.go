@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"prophet-trader/interfaces"
+)
+
+// testBars builds daily bars from high, low and close triples
+func testBars(hlc ...[3]float64) []*interfaces.Bar {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	bars := make([]*interfaces.Bar, len(hlc))
+	for i, v := range hlc {
+		bars[i] = &interfaces.Bar{
+			Symbol:    "TEST",
+			Timestamp: start.AddDate(0, 0, i),
+			Open:      v[2],
+			High:      v[0],
+			Low:       v[1],
+			Close:     v[2],
+			Volume:    1000,
+		}
+	}
+	return bars
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// sarFixture rises for four bars, making a new high each bar, then gaps
+// down through the SAR
+var sarFixture = testBars(
+	[3]float64{10, 9, 9.5},
+	[3]float64{11, 10, 10.5},
+	[3]float64{12, 11, 11.5},
+	[3]float64{13, 12, 12.5},
+	[3]float64{14, 13, 13.5},
+	[3]float64{12, 9, 9.5},
+)
+
+func TestCalculateParabolicSARStepsAcceleration(t *testing.T) {
+	// Each new high raises the factor by a step: 0.02 seed + 4 steps
+	result := CalculateParabolicSAR(sarFixture[:5], DefaultSARStep, DefaultSARMax)
+	if result == nil {
+		t.Fatal("CalculateParabolicSAR returned nil")
+	}
+	if result.Trend != "up" {
+		t.Errorf("trend = %s, want up", result.Trend)
+	}
+	if !approxEqual(result.AccelFactor, 0.10) {
+		t.Errorf("accel factor = %v, want 0.10", result.AccelFactor)
+	}
+	if result.ExtremePoint != 14 {
+		t.Errorf("extreme point = %v, want 14", result.ExtremePoint)
+	}
+
+	// SAR is held at the prior lows early on, then accelerates toward the highs
+	want := []float64{9, 9, 9, 9.18, 9.4856}
+	for i, w := range want {
+		if !approxEqual(result.Series[i], w) {
+			t.Errorf("series[%d] = %v, want %v", i, result.Series[i], w)
+		}
+	}
+
+	// The factor stops at the ceiling
+	capped := CalculateParabolicSAR(sarFixture[:5], DefaultSARStep, 0.05)
+	if !approxEqual(capped.AccelFactor, 0.05) {
+		t.Errorf("capped accel factor = %v, want 0.05", capped.AccelFactor)
+	}
+}
+
+func TestCalculateParabolicSARTrendFlip(t *testing.T) {
+	result := CalculateParabolicSAR(sarFixture, DefaultSARStep, DefaultSARMax)
+	if result == nil {
+		t.Fatal("CalculateParabolicSAR returned nil")
+	}
+
+	// The low of 9 crosses the SAR: it jumps to the prior high and the factor resets
+	if result.Trend != "down" {
+		t.Errorf("trend = %s, want down", result.Trend)
+	}
+	if result.SAR != 14 {
+		t.Errorf("SAR = %v, want prior extreme point 14", result.SAR)
+	}
+	if !approxEqual(result.AccelFactor, DefaultSARStep) {
+		t.Errorf("accel factor = %v, want reset to %v", result.AccelFactor, DefaultSARStep)
+	}
+	if result.ExtremePoint != 9 {
+		t.Errorf("extreme point = %v, want 9", result.ExtremePoint)
+	}
+}
+
+func TestCalculateParabolicSARRejectsBadParams(t *testing.T) {
+	if CalculateParabolicSAR(sarFixture, 0.3, DefaultSARMax) != nil {
+		t.Error("step above max accepted")
+	}
+	if CalculateParabolicSAR(sarFixture[:1], DefaultSARStep, DefaultSARMax) != nil {
+		t.Error("single bar accepted")
+	}
+}
+
+// wavyBars returns n daily bars trending up with a sine wave on top, enough
+// for every indicator
+func wavyBars(n int) []*interfaces.Bar {
+	hlc := make([][3]float64, n)
+	for i := range hlc {
+		close := 100 + 0.3*float64(i) + 4*math.Sin(float64(i)/3)
+		hlc[i] = [3]float64{close + 1, close - 1, close}
+	}
+	return testBars(hlc...)
+}
+
+func TestAnalyzeAtIgnoresLaterBars(t *testing.T) {
+	ctx := context.Background()
+	tas := NewTechnicalAnalysisService(nil)
+	bars := wavyBars(80)
+	const current = 60
+
+	want, err := tas.Analyze(ctx, "TEST", append([]*interfaces.Bar(nil), bars[:current]...))
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+
+	// Wildly different later bars must not change the result
+	for _, bar := range bars[current:] {
+		bar.High, bar.Low, bar.Close, bar.Volume = 1000, 1, 999, 1e9
+	}
+	got, err := tas.AnalyzeAt(ctx, "TEST", bars, current)
+	if err != nil {
+		t.Fatalf("AnalyzeAt: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeAt read past bar %d:\ngot  %+v\nwant %+v", current-1, got, want)
+	}
+	if got.CurrentPrice != bars[current-1].Close {
+		t.Errorf("current price = %v, want bar %d close %v", got.CurrentPrice, current-1, bars[current-1].Close)
+	}
+	if got.DataQuality.BarCount != current {
+		t.Errorf("bar count = %d, want %d", got.DataQuality.BarCount, current)
+	}
+}
+
+func TestAnalyzeAtRejectsOutOfOrderBars(t *testing.T) {
+	bars := wavyBars(30)
+	bars[10].Timestamp, bars[11].Timestamp = bars[11].Timestamp, bars[10].Timestamp
+
+	if _, err := NewTechnicalAnalysisService(nil).AnalyzeAt(context.Background(), "TEST", bars, 20); err == nil {
+		t.Error("out-of-order bars accepted")
+	}
+	// Disorder after the current bar is never read
+	if _, err := NewTechnicalAnalysisService(nil).AnalyzeAt(context.Background(), "TEST", bars, 10); err != nil {
+		t.Errorf("AnalyzeAt before the disorder: %v", err)
+	}
+}
+
+func TestAnalyzeAtRejectsBadIndex(t *testing.T) {
+	tas := NewTechnicalAnalysisService(nil)
+	bars := wavyBars(5)
+	for _, current := range []int{0, -1, 6} {
+		if _, err := tas.AnalyzeAt(context.Background(), "TEST", bars, current); err == nil {
+			t.Errorf("index %d accepted", current)
+		}
+	}
+}
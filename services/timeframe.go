@@ -0,0 +1,36 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// canonicalTimeframes are the timeframe values understood by the data provider
+var canonicalTimeframes = []string{"1Min", "5Min", "15Min", "30Min", "1Hour", "4Hour", "1Day", "1Week", "1Month"}
+
+// timeframeAliases maps common spellings (lower-cased) to canonical timeframes
+var timeframeAliases = map[string]string{
+	"1m": "1Min", "1min": "1Min", "minute": "1Min",
+	"5m": "5Min", "5min": "5Min",
+	"15m": "15Min", "15min": "15Min",
+	"30m": "30Min", "30min": "30Min",
+	"1h": "1Hour", "60m": "1Hour", "1hour": "1Hour", "hour": "1Hour", "hourly": "1Hour",
+	"4h": "4Hour", "4hour": "4Hour",
+	"1d": "1Day", "1day": "1Day", "d": "1Day", "day": "1Day", "daily": "1Day",
+	"1w": "1Week", "1wk": "1Week", "1week": "1Week", "w": "1Week", "week": "1Week", "weekly": "1Week",
+	"1mo": "1Month", "1month": "1Month", "month": "1Month", "monthly": "1Month",
+}
+
+// NormalizeTimeframe maps a timeframe or common alias ("1D", "daily", "1h") to
+// the canonical provider value, returning an error listing the allowed set
+func NormalizeTimeframe(tf string) (string, error) {
+	if canonical, ok := timeframeAliases[strings.ToLower(strings.TrimSpace(tf))]; ok {
+		return canonical, nil
+	}
+	return "", fmt.Errorf("unsupported timeframe %q (allowed: %s)", tf, strings.Join(canonicalTimeframes, ", "))
+}
+
+// AllowedTimeframes returns the canonical timeframe values
+func AllowedTimeframes() []string {
+	return append([]string(nil), canonicalTimeframes...)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"prophet-trader/database"
+	"prophet-trader/models"
+)
+
+// unspecifiedStrategy groups trades of positions opened without a strategy
+const unspecifiedStrategy = "UNSPECIFIED"
+
+// TradeStats summarizes the performance of a set of closed trades. P&L is
+// net of estimated fees; a trade at exactly break-even counts as a loss,
+// as it does in the activity log.
+type TradeStats struct {
+	Trades        int     `json:"trades"`
+	Wins          int     `json:"wins"`
+	Losses        int     `json:"losses"`
+	WinRate       float64 `json:"win_rate"` // percent of trades
+	NetPnL        float64 `json:"net_pnl"`
+	GrossPnL      float64 `json:"gross_pnl"`
+	Fees          float64 `json:"fees"`
+	AvgWin        float64 `json:"avg_win"`
+	AvgLoss       float64 `json:"avg_loss"`
+	ProfitFactor  float64 `json:"profit_factor"` // total won / total lost (0 without losses)
+	Expectancy    float64 `json:"expectancy"`    // average net P&L per trade
+	AvgPnLPercent float64 `json:"avg_pnl_percent"`
+	AvgHoldHours  float64 `json:"avg_hold_hours"`
+	AvgMAEPercent float64 `json:"avg_mae_percent"`
+	AvgMFEPercent float64 `json:"avg_mfe_percent"`
+}
+
+// TradeStatsReport is the overall, per-strategy and per-close-reason
+// performance of the trades matching a filter
+type TradeStatsReport struct {
+	Strategy         string                 `json:"strategy,omitempty"`
+	Symbol           string                 `json:"symbol,omitempty"`
+	IncludeSimulated bool                   `json:"include_simulated"`
+	Overall          *TradeStats            `json:"overall"`
+	ByStrategy       map[string]*TradeStats `json:"by_strategy"`
+	ByCloseReason    map[string]*TradeStats `json:"by_close_reason"`
+}
+
+// GetTradeStats computes performance statistics over closed trades, overall
+// and broken down by the strategy each position was opened with and by the
+// reason it was closed
+func (pm *PositionManager) GetTradeStats(filter database.TradeFilter) (*TradeStatsReport, error) {
+	trades, err := pm.storageService.GetTrades(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trades: %w", err)
+	}
+
+	byStrategy := make(map[string][]*models.DBTrade)
+	byCloseReason := make(map[string][]*models.DBTrade)
+	for _, trade := range trades {
+		strategy := trade.StrategyName
+		if strategy == "" {
+			strategy = unspecifiedStrategy
+		}
+		byStrategy[strategy] = append(byStrategy[strategy], trade)
+
+		reason := trade.CloseReason
+		if reason == "" {
+			reason = unspecifiedCloseReason
+		}
+		byCloseReason[reason] = append(byCloseReason[reason], trade)
+	}
+
+	report := &TradeStatsReport{
+		Strategy:         filter.Strategy,
+		Symbol:           filter.Symbol,
+		IncludeSimulated: filter.IncludeSimulated,
+		Overall:          computeTradeStats(trades),
+		ByStrategy:       make(map[string]*TradeStats, len(byStrategy)),
+		ByCloseReason:    make(map[string]*TradeStats, len(byCloseReason)),
+	}
+	for strategy, group := range byStrategy {
+		report.ByStrategy[strategy] = computeTradeStats(group)
+	}
+	for reason, group := range byCloseReason {
+		report.ByCloseReason[reason] = computeTradeStats(group)
+	}
+
+	return report, nil
+}
+
+// computeTradeStats aggregates a group of trades
+func computeTradeStats(trades []*models.DBTrade) *TradeStats {
+	stats := &TradeStats{Trades: len(trades)}
+	if len(trades) == 0 {
+		return stats
+	}
+
+	var won, lost, pnlPercent, holdSeconds, mae, mfe float64
+	for _, trade := range trades {
+		stats.NetPnL += trade.PnL
+		stats.GrossPnL += trade.GrossPnL
+		stats.Fees += trade.Fees
+		pnlPercent += trade.PnLPercent
+		holdSeconds += float64(trade.Duration)
+		mae += trade.MAEPercent
+		mfe += trade.MFEPercent
+
+		if trade.PnL > 0 {
+			stats.Wins++
+			won += trade.PnL
+		} else {
+			stats.Losses++
+			lost += math.Abs(trade.PnL)
+		}
+	}
+
+	n := float64(len(trades))
+	stats.WinRate = RoundPercent(float64(stats.Wins) / n * 100)
+	stats.NetPnL = RoundMoney(stats.NetPnL)
+	stats.GrossPnL = RoundMoney(stats.GrossPnL)
+	stats.Fees = RoundMoney(stats.Fees)
+	stats.Expectancy = RoundMoney(stats.NetPnL / n)
+	stats.AvgPnLPercent = RoundPercent(pnlPercent / n)
+	stats.AvgHoldHours = math.Round(holdSeconds/n/3600*10) / 10
+	stats.AvgMAEPercent = RoundPercent(mae / n)
+	stats.AvgMFEPercent = RoundPercent(mfe / n)
+	if stats.Wins > 0 {
+		stats.AvgWin = RoundMoney(won / float64(stats.Wins))
+	}
+	if stats.Losses > 0 {
+		stats.AvgLoss = RoundMoney(-lost / float64(stats.Losses))
+	}
+	if lost > 0 {
+		stats.ProfitFactor = math.Round(won/lost*100) / 100
+	}
+
+	return stats
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// What a trailing stop trails from once a partial exit fills (PartialExitConfig.TrailingReset).
+//
+//   - peak (default): the runner keeps trailing from the best price seen since
+//     entry. The partial fill doesn't touch the stop; it only ever tightens.
+//   - current: the high-water mark restarts at the price when the partial
+//     fill is seen and the stop is re-derived from it. This can loosen the
+//     stop, giving the runner room to breathe after profit has been banked.
+//     It then tightens again as price makes new highs from the partial point.
+//   - breakeven: the stop is reset to the entry price and the high-water mark
+//     restarts at the current price. The stop stays at break-even until the
+//     trail from new highs passes it.
+//
+// Positions without trailing enabled ignore the setting.
+const (
+	TrailingResetPeak      = "peak"
+	TrailingResetCurrent   = "current"
+	TrailingResetBreakeven = "breakeven"
+)
+
+// validTrailingReset reports whether mode is empty or a known reset mode
+func validTrailingReset(mode string) bool {
+	switch mode {
+	case "", TrailingResetPeak, TrailingResetCurrent, TrailingResetBreakeven:
+		return true
+	}
+	return false
+}
+
+// trailingStopFor returns the stop trailing percent behind the reference price
+func trailingStopFor(position *ManagedPosition, reference float64) float64 {
+	if position.Side == "sell" {
+		return reference * (1 + position.TrailingPercent/100.0)
+	}
+	return reference * (1 - position.TrailingPercent/100.0)
+}
+
+// resetTrailingAfterPartial applies the position's trailing reset mode after a
+// partial exit fills. It only sets the stop price; the stop order itself is
+// replaced by resizeRiskOrders.
+func (pm *PositionManager) resetTrailingAfterPartial(ctx context.Context, position *ManagedPosition) {
+	if !position.TrailingStop || position.PartialExit == nil || position.CurrentPrice <= 0 {
+		return
+	}
+
+	var newStopPrice float64
+	switch position.PartialExit.TrailingReset {
+	case TrailingResetCurrent:
+		newStopPrice = trailingStopFor(position, position.CurrentPrice)
+	case TrailingResetBreakeven:
+		newStopPrice = position.EntryPrice
+	default:
+		return
+	}
+
+	oldStopPrice := position.StopLossPrice
+	position.TrailingReference = position.CurrentPrice
+	position.StopLossPrice = pm.roundPrice(ctx, position.Symbol, newStopPrice)
+
+	pm.logger.WithFields(logrus.Fields{
+		"position_id":        position.ID,
+		"mode":               position.PartialExit.TrailingReset,
+		"trailing_reference": position.TrailingReference,
+		"new_stop_price":     position.StopLossPrice,
+	}).Info("Trailing reference reset after partial exit")
+	pm.logPositionEvent(position, "STOP_MOVED", "Trailing reference reset after partial exit ("+position.PartialExit.TrailingReset+")", map[string]interface{}{
+		"old_stop_price":     oldStopPrice,
+		"new_stop_price":     position.StopLossPrice,
+		"trailing_reference": position.TrailingReference,
+	})
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"prophet-trader/database"
+	"prophet-trader/models"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// symbolPattern matches equity tickers such as AAPL, BRK.B or BF-B
+var symbolPattern = regexp.MustCompile(`^[A-Z][A-Z0-9.\-]{0,9}$`)
+
+// Watchlist represents a named list of symbols
+type Watchlist struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Symbols     []string  `json:"symbols"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WatchlistService manages persisted watchlists
+type WatchlistService struct {
+	storageService *database.LocalStorage
+}
+
+// NewWatchlistService creates a new watchlist service
+func NewWatchlistService(storageService *database.LocalStorage) *WatchlistService {
+	return &WatchlistService{
+		storageService: storageService,
+	}
+}
+
+// SaveWatchlist validates and stores a watchlist, replacing any existing list with the same name
+func (ws *WatchlistService) SaveWatchlist(name, description string, symbols []string) (*Watchlist, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("watchlist name required")
+	}
+
+	normalized, err := NormalizeSymbols(symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	symbolsJSON, _ := json.Marshal(normalized)
+	dbWatchlist := &models.DBWatchlist{
+		Name:        name,
+		Description: description,
+		Symbols:     string(symbolsJSON),
+	}
+
+	if err := ws.storageService.SaveWatchlist(dbWatchlist); err != nil {
+		return nil, err
+	}
+
+	return ws.dbToWatchlist(dbWatchlist), nil
+}
+
+// GetWatchlist retrieves a watchlist by name
+func (ws *WatchlistService) GetWatchlist(name string) (*Watchlist, error) {
+	dbWatchlist, err := ws.storageService.GetWatchlist(name)
+	if err != nil {
+		return nil, fmt.Errorf("watchlist not found: %s", name)
+	}
+
+	return ws.dbToWatchlist(dbWatchlist), nil
+}
+
+// ListWatchlists returns all watchlists
+func (ws *WatchlistService) ListWatchlists() ([]*Watchlist, error) {
+	dbWatchlists, err := ws.storageService.GetAllWatchlists()
+	if err != nil {
+		return nil, err
+	}
+
+	watchlists := make([]*Watchlist, len(dbWatchlists))
+	for i, dbWatchlist := range dbWatchlists {
+		watchlists[i] = ws.dbToWatchlist(dbWatchlist)
+	}
+
+	return watchlists, nil
+}
+
+// DeleteWatchlist removes a watchlist by name
+func (ws *WatchlistService) DeleteWatchlist(name string) error {
+	return ws.storageService.DeleteWatchlist(name)
+}
+
+// NormalizeSymbols upper-cases, validates and de-duplicates a symbol list
+func NormalizeSymbols(symbols []string) ([]string, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("at least one symbol required")
+	}
+
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if !symbolPattern.MatchString(symbol) {
+			return nil, fmt.Errorf("invalid symbol: %q", symbol)
+		}
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		normalized = append(normalized, symbol)
+	}
+
+	return normalized, nil
+}
+
+// dbToWatchlist converts DBWatchlist to Watchlist
+func (ws *WatchlistService) dbToWatchlist(dbWatchlist *models.DBWatchlist) *Watchlist {
+	var symbols []string
+	if dbWatchlist.Symbols != "" {
+		json.Unmarshal([]byte(dbWatchlist.Symbols), &symbols)
+	}
+
+	return &Watchlist{
+		Name:        dbWatchlist.Name,
+		Description: dbWatchlist.Description,
+		Symbols:     symbols,
+		CreatedAt:   dbWatchlist.CreatedAt,
+		UpdatedAt:   dbWatchlist.UpdatedAt,
+	}
+}